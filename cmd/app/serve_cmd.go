@@ -1,6 +1,8 @@
 package main
 
 import (
+	"fmt"
+
 	"github.com/fmiskovic/new-amz/internal/server"
 	"github.com/urfave/cli/v2"
 )
@@ -10,8 +12,22 @@ func newServeCmd() *cli.Command {
 	return &cli.Command{
 		Name:  "serve",
 		Usage: "start the server",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "storage",
+				Usage: fmt.Sprintf("where entities are kept: %s, or %s for demos without a database (default: $STORAGE or %s)", server.StoragePostgres, server.StorageMemory, server.StoragePostgres),
+			},
+		},
 		Action: func(ctx *cli.Context) error {
-			server.NewBuilder().Build().Start()
+			storage := ctx.String("storage")
+			if storage != "" && storage != server.StoragePostgres && storage != server.StorageMemory {
+				return fmt.Errorf("unknown storage: %s", storage)
+			}
+			cfg := server.NewConfig().WithStorage(storage).Build()
+			if err := cfg.Validate(); err != nil {
+				return fmt.Errorf("invalid configuration: %w", err)
+			}
+			server.NewBuilder().WithConfig(cfg).Build().Start()
 			return nil
 		},
 	}
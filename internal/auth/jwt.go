@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fmiskovic/new-amz/internal/core"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const issuer = "new-amz"
+
+var ErrInvalidToken = errors.New("invalid access token")
+
+// claims are the custom JWT claims issued for an account.
+type claims struct {
+	Email string        `json:"email"`
+	Role  entities.Role `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// JWT issues and verifies HS256 signed access tokens.
+// It is the implementation of core.TokenIssuer interface.
+type JWT struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+// NewJWT instantiates new JWT with the signing secret and the token time to live.
+func NewJWT(secret string, ttl time.Duration) JWT {
+	return JWT{secret: []byte(secret), ttl: ttl}
+}
+
+// Issue signs a new access token for the given account and returns it along with its expiry time.
+func (j JWT) Issue(account entities.Account) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(j.ttl)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims{
+		Email: account.Email,
+		Role:  account.Role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			Subject:   account.ID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	})
+
+	signed, err := token.SignedString(j.secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// Verify parses the signed token, checks its signature and expiry and returns the principal it was issued for.
+func (j JWT) Verify(token string) (core.Principal, error) {
+	c := new(claims)
+	_, err := jwt.ParseWithClaims(token, c, func(t *jwt.Token) (interface{}, error) {
+		return j.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return core.Principal{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	accountId, err := uuid.Parse(c.Subject)
+	if err != nil {
+		return core.Principal{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	return core.Principal{AccountID: accountId, Role: c.Role}, nil
+}
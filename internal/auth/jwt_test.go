@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJWT(t *testing.T) {
+	account := entities.NewAccountBuilder().Email("fake@mail.com").Role(entities.ADMIN).Build()
+
+	t.Run("issued token should be verified", func(t *testing.T) {
+		j := NewJWT("secret", time.Minute)
+
+		token, expiresAt, err := j.Issue(*account)
+		assert.NoError(t, err)
+		assert.True(t, expiresAt.After(time.Now()))
+
+		principal, err := j.Verify(token)
+		assert.NoError(t, err)
+		assert.Equal(t, account.ID, principal.AccountID)
+		assert.True(t, principal.IsAdmin())
+	})
+
+	t.Run("token signed with another secret should not be verified", func(t *testing.T) {
+		token, _, err := NewJWT("another-secret", time.Minute).Issue(*account)
+		assert.NoError(t, err)
+
+		_, err = NewJWT("secret", time.Minute).Verify(token)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("expired token should not be verified", func(t *testing.T) {
+		j := NewJWT("secret", -time.Minute)
+
+		token, _, err := j.Issue(*account)
+		assert.NoError(t, err)
+
+		_, err = j.Verify(token)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("malformed token should not be verified", func(t *testing.T) {
+		_, err := NewJWT("secret", time.Minute).Verify("not-a-token")
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+}
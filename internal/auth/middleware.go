@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/fmiskovic/new-amz/internal/core"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+const bearerScheme = "Bearer"
+
+// Middleware authenticates requests by their bearer access token.
+// The principal the token was issued for is passed on through the request context,
+// so it is available to every core.ServiceFunc called from the handler.
+// Requests for which skipper returns true are passed through unauthenticated.
+func Middleware(j JWT, skipper middleware.Skipper) echo.MiddlewareFunc {
+	if skipper == nil {
+		skipper = middleware.DefaultSkipper
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if skipper(c) {
+				return next(c)
+			}
+
+			token, ok := bearerToken(c.Request())
+			if !ok {
+				return unauthorized(c, "missing access token")
+			}
+
+			principal, err := j.Verify(token)
+			if err != nil {
+				c.Logger().Warn(err)
+				return unauthorized(c, "invalid or expired access token")
+			}
+
+			ctx := core.WithPrincipal(c.Request().Context(), principal)
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			return next(c)
+		}
+	}
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get(echo.HeaderAuthorization)
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, bearerScheme) || strings.TrimSpace(token) == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+func unauthorized(c echo.Context, msg string) error {
+	c.Response().Header().Set(echo.HeaderWWWAuthenticate, bearerScheme)
+	return echo.NewHTTPError(http.StatusUnauthorized, msg)
+}
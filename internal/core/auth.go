@@ -0,0 +1,38 @@
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+)
+
+// Principal represents the authenticated account on whose behalf a service function is called.
+type Principal struct {
+	AccountID uuid.UUID
+	Role      entities.Role
+}
+
+// IsAdmin reports whether the principal has administrator privileges.
+func (p Principal) IsAdmin() bool {
+	return p.Role == entities.ADMIN
+}
+
+type principalCtxKey struct{}
+
+// WithPrincipal returns a copy of ctx that carries the given principal.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalCtxKey{}, p)
+}
+
+// PrincipalFrom returns the principal carried by ctx, if there is one.
+func PrincipalFrom(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalCtxKey{}).(Principal)
+	return p, ok
+}
+
+// TokenIssuer is a secondary port for issuing access tokens to authenticated accounts.
+type TokenIssuer interface {
+	Issue(account entities.Account) (string, time.Time, error)
+}
@@ -2,6 +2,7 @@ package dtos
 
 import (
 	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
 	"time"
 )
 
@@ -9,6 +10,7 @@ type AccountDto struct {
 	ID          string    `json:"id"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+	Version     int64     `json:"-"`
 	Email       string    `validate:"required,min=3" json:"email"`
 	FullName    string    `json:"full_name"`
 	DateOfBirth time.Time `json:"date_of_birth"`
@@ -37,6 +39,7 @@ func (g GenderDto) Numberfy() entities.Gender {
 func ToAccountDto(a entities.Account) AccountDto {
 	return AccountDto{
 		ID:          a.ID.String(),
+		Version:     a.Version,
 		Email:       a.Email,
 		FullName:    a.FullName,
 		DateOfBirth: a.DateOfBirth,
@@ -47,13 +50,56 @@ func ToAccountDto(a entities.Account) AccountDto {
 
 type CreateAccountCommand struct {
 	Email       string    `validate:"required,min=3" json:"email"`
+	Password    string    `validate:"required,min=8" json:"password"`
 	FullName    string    `json:"full_name"`
 	DateOfBirth time.Time `json:"date_of_birth"`
 	Location    string    `json:"location"`
 	Gender      GenderDto `json:"gender"`
+	// Order lists the items of the first order of the account, which is placed together with creating the account.
+	Order []OrderItemDto `json:"order,omitempty" validate:"omitempty,dive"`
 }
 
 // CreateAccountAnswer is a response to a create Command
 type CreateAccountAnswer struct {
 	AccountDto
+	Order *OrderDto `json:"order,omitempty"`
+}
+
+// UpdateAccountCommand replaces profile of an existing account.
+type UpdateAccountCommand struct {
+	ID          uuid.UUID `json:"-"`
+	Version     int64     `json:"-"`
+	Email       string    `validate:"required,min=3" json:"email"`
+	FullName    string    `json:"full_name"`
+	DateOfBirth time.Time `json:"date_of_birth"`
+	Location    string    `json:"location"`
+	Gender      GenderDto `json:"gender"`
+}
+
+// ToUpdateAccountCommand converts Account entity into a command replacing its profile with the same values.
+func ToUpdateAccountCommand(a entities.Account) UpdateAccountCommand {
+	return UpdateAccountCommand{
+		ID:          a.ID,
+		Version:     a.Version,
+		Email:       a.Email,
+		FullName:    a.FullName,
+		DateOfBirth: a.DateOfBirth,
+		Location:    a.Location,
+		Gender:      GenderDto(a.Gender.Stringify()),
+	}
+}
+
+// PatchAccountCommand changes profile of an existing account by JSON Merge Patch (RFC 7386) document.
+type PatchAccountCommand struct {
+	ID      uuid.UUID
+	Version int64
+	Patch   []byte
+}
+
+// DeleteAccountCommand closes an account.
+// Accounts are soft deleted unless Force is set.
+type DeleteAccountCommand struct {
+	ID      uuid.UUID
+	Version int64
+	Force   bool
 }
@@ -0,0 +1,16 @@
+package dtos
+
+import "time"
+
+// LoginCommand carries account credentials to be exchanged for an access token.
+type LoginCommand struct {
+	Email    string `validate:"required" json:"email"`
+	Password string `validate:"required" json:"password"`
+}
+
+// TokenDto is an access token issued to an authenticated account.
+type TokenDto struct {
+	AccessToken string    `json:"access_token"`
+	TokenType   string    `json:"token_type"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
@@ -0,0 +1,52 @@
+package dtos
+
+import (
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+)
+
+type AuthorDto struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Version   int64     `json:"-"`
+	Name      string    `json:"name" validate:"required,max=255"`
+	Biography string    `json:"biography,omitempty"`
+}
+
+func ToAuthorDto(author entities.Author) AuthorDto {
+	return AuthorDto{
+		ID:        author.ID.String(),
+		CreatedAt: author.CreatedAt,
+		UpdatedAt: author.UpdatedAt,
+		Version:   author.Version,
+		Name:      author.Name,
+		Biography: author.Biography,
+	}
+}
+
+// ToAuthorEntity converts Author DTO into a new Author entity.
+func ToAuthorEntity(dto AuthorDto) (*entities.Author, error) {
+	author := entities.NewAuthorBuilder().
+		Name(strings.TrimSpace(dto.Name)).
+		Biography(dto.Biography).
+		Build()
+
+	if err := identify(&author.Entity, dto.ID, dto.Version); err != nil {
+		return nil, err
+	}
+	return author, nil
+}
+
+// ToPageAuthorDto converts Author entities Page into a Author DTO Page.
+func ToPageAuthorDto(page entities.Page[entities.Author]) entities.Page[AuthorDto] {
+	return entities.MapPage(page, ToAuthorDto)
+}
+
+// AuthorItemsQuery asks for a page of items the author is credited on.
+type AuthorItemsQuery struct {
+	AuthorID    uuid.UUID `json:"-"`
+	PageRequest entities.Pageable
+}
@@ -0,0 +1,102 @@
+package dtos
+
+import (
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+	"time"
+)
+
+// CartDto is a cart with its items valued at their current prices.
+// The subtotal is left out for carts mixing currencies, which cannot be summed up.
+type CartDto struct {
+	ID        string          `json:"id"`
+	AccountID string          `json:"account_id,omitempty"`
+	Items     []CartItemDto   `json:"items"`
+	Subtotal  *entities.Money `json:"subtotal,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// CartItemDto is a line of a cart. InStock tells whether the item still has enough units in stock for the quantity.
+type CartItemDto struct {
+	ItemID    string         `json:"item_id"`
+	Title     string         `json:"title"`
+	Quantity  int            `json:"quantity"`
+	UnitPrice entities.Money `json:"unit_price"`
+	LineTotal entities.Money `json:"line_total"`
+	InStock   bool           `json:"in_stock"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+func ToCartDto(cart entities.Cart) CartDto {
+	items := make([]CartItemDto, 0, len(cart.Items))
+	for _, ci := range cart.Items {
+		if ci != nil {
+			items = append(items, ToCartItemDto(*ci))
+		}
+	}
+	dto := CartDto{
+		ID:        cart.ID.String(),
+		Items:     items,
+		CreatedAt: cart.CreatedAt,
+		UpdatedAt: cart.UpdatedAt,
+	}
+	if cart.AccountID != nil {
+		dto.AccountID = cart.AccountID.String()
+	}
+	if subtotal, err := cart.Subtotal(); err == nil {
+		dto.Subtotal = &subtotal
+	}
+	return dto
+}
+
+func ToCartItemDto(cartItem entities.CartItem) CartItemDto {
+	dto := CartItemDto{
+		ItemID:    cartItem.ItemID.String(),
+		Quantity:  cartItem.Quantity,
+		LineTotal: cartItem.LineTotal(),
+		InStock:   cartItem.InStock(),
+		CreatedAt: cartItem.CreatedAt,
+		UpdatedAt: cartItem.UpdatedAt,
+	}
+	if cartItem.Item != nil {
+		dto.Title = cartItem.Item.Title
+		dto.UnitPrice = cartItem.Item.Price
+	}
+	return dto
+}
+
+// ToOrderItemDtos converts cart items into the order items of an order placed from the cart.
+func ToOrderItemDtos(cart entities.Cart) []OrderItemDto {
+	items := make([]OrderItemDto, 0, len(cart.Items))
+	for _, ci := range cart.Items {
+		if ci != nil {
+			items = append(items, OrderItemDto{ItemID: ci.ItemID.String(), Quantity: ci.Quantity})
+		}
+	}
+	return items
+}
+
+// CartQuery addresses a cart. A zero CartID addresses the cart of the calling account, otherwise it is the id of a guest cart.
+type CartQuery struct {
+	CartID uuid.UUID `json:"-"`
+}
+
+// CartItemCommand adds an item to the cart or sets its quantity in the cart.
+type CartItemCommand struct {
+	CartID   uuid.UUID `json:"-"`
+	ItemID   string    `json:"item_id" validate:"required,uuid"`
+	Quantity int       `json:"quantity" validate:"required,gte=1"`
+}
+
+// RemoveCartItemCommand removes an item from the cart.
+type RemoveCartItemCommand struct {
+	CartID uuid.UUID `json:"-"`
+	ItemID uuid.UUID `json:"-"`
+}
+
+// MergeCartCommand merges the guest cart into the cart of the calling account.
+type MergeCartCommand struct {
+	CartID string `json:"cart_id" validate:"required,uuid"`
+}
@@ -0,0 +1,75 @@
+package dtos
+
+import (
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+)
+
+// CategoryDto is a category of items. Children are only listed with the category subtree.
+type CategoryDto struct {
+	ID          string        `json:"id"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+	Version     int64         `json:"-"`
+	Name        string        `json:"name" validate:"required,max=100"`
+	Description string        `json:"description"`
+	ParentID    string        `json:"parent_id,omitempty" validate:"omitempty,uuid"`
+	Children    []CategoryDto `json:"children,omitempty"`
+}
+
+func ToCategoryDto(category entities.Category) CategoryDto {
+	dto := CategoryDto{
+		ID:          category.ID.String(),
+		CreatedAt:   category.CreatedAt,
+		UpdatedAt:   category.UpdatedAt,
+		Version:     category.Version,
+		Name:        category.Name,
+		Description: category.Description,
+	}
+	if category.ParentID != nil {
+		dto.ParentID = category.ParentID.String()
+	}
+	for _, child := range category.Children {
+		if child != nil {
+			dto.Children = append(dto.Children, ToCategoryDto(*child))
+		}
+	}
+	return dto
+}
+
+// ToCategoryDtos converts Category entities into Category DTOs.
+func ToCategoryDtos(categories []entities.Category) []CategoryDto {
+	dtos := make([]CategoryDto, len(categories))
+	for i, c := range categories {
+		dtos[i] = ToCategoryDto(c)
+	}
+	return dtos
+}
+
+// ToCategoryEntity converts Category DTO into a new Category entity, children are ignored.
+func ToCategoryEntity(dto CategoryDto) (*entities.Category, error) {
+	builder := entities.NewCategoryBuilder().
+		Name(strings.TrimSpace(dto.Name)).
+		Description(dto.Description)
+	if dto.ParentID != "" {
+		parentId, err := uuid.Parse(dto.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		builder.Parent(parentId)
+	}
+	category := builder.Build()
+
+	if err := identify(&category.Entity, dto.ID, dto.Version); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// AssignItemCategoriesCommand replaces categories of the item, an empty list unassigns the item from all categories.
+type AssignItemCategoriesCommand struct {
+	ItemID      uuid.UUID `json:"-"`
+	CategoryIDs []string  `json:"category_ids" validate:"required,max=20,dive,uuid"`
+}
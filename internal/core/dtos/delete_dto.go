@@ -0,0 +1,10 @@
+package dtos
+
+import "github.com/google/uuid"
+
+// DeleteCommand deletes an entity by its id.
+// A non-zero Version deletes the entity only while it is still in that version.
+type DeleteCommand struct {
+	ID      uuid.UUID
+	Version int64
+}
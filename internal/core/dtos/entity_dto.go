@@ -0,0 +1,20 @@
+package dtos
+
+import (
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+)
+
+// identify sets the id and version a DTO carries on the entity converted from it.
+// If the DTO carries an id it is kept, otherwise the entity keeps the new id it was created with.
+func identify(entity *entities.Entity, id string, version int64) error {
+	if id != "" {
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			return err
+		}
+		entity.ID = parsed
+	}
+	entity.Version = version
+	return nil
+}
@@ -2,38 +2,183 @@ package dtos
 
 import (
 	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+	"strings"
 	"time"
 )
 
+// DateLayout formats dates without time of day, like publication dates.
+const DateLayout = "2006-01-02"
+
+// ItemDto is an item of the store. Books carry their ISBN-13, publisher and authors as well;
+// publisher and authors are assigned by their ids, which are listed next to them in responses.
 type ItemDto struct {
-	ID          string    `json:"id"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	Title       string    `json:"name"`
-	Description string    `json:"Description"`
-	Price       float32   `json:"Price"`
+	ID              string         `json:"id"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	Version         int64          `json:"-"`
+	Title           string         `json:"name" validate:"required,max=100"`
+	Description     string         `json:"Description"`
+	Price           entities.Money `json:"Price" validate:"required,gt=0"`
+	Stock           int            `json:"stock" validate:"gte=0"`
+	ISBN            string         `json:"isbn,omitempty" validate:"omitempty,isbn"`
+	PublisherID     string         `json:"publisher_id,omitempty" validate:"omitempty,uuid"`
+	Publisher       *PublisherDto  `json:"publisher,omitempty"`
+	Edition         string         `json:"edition,omitempty" validate:"max=50"`
+	PublicationDate string         `json:"publication_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	PageCount       int            `json:"page_count,omitempty" validate:"gte=0"`
+	Language        string         `json:"language,omitempty" validate:"omitempty,max=35,bcp47_language_tag"`
+	AuthorIDs       []string       `json:"author_ids,omitempty" validate:"max=20,dive,uuid"`
+	Authors         []AuthorDto    `json:"authors,omitempty"`
 }
 
 func ToItemDto(item entities.Item) ItemDto {
-	return ItemDto{
+	dto := ItemDto{
 		ID:          item.ID.String(),
 		CreatedAt:   item.CreatedAt,
 		UpdatedAt:   item.UpdatedAt,
+		Version:     item.Version,
 		Title:       item.Title,
 		Description: item.Description,
 		Price:       item.Price,
+		Stock:       item.Stock,
+		ISBN:        item.ISBN,
+		Edition:     item.Edition,
+		PageCount:   item.PageCount,
+		Language:    item.Language,
+	}
+	if item.PublisherID != nil {
+		dto.PublisherID = item.PublisherID.String()
+	}
+	if item.Publisher != nil {
+		publisher := ToPublisherDto(*item.Publisher)
+		dto.Publisher = &publisher
+	}
+	if !item.PublicationDate.IsZero() {
+		dto.PublicationDate = item.PublicationDate.Format(DateLayout)
+	}
+	for _, author := range item.Authors {
+		if author != nil {
+			dto.AuthorIDs = append(dto.AuthorIDs, author.ID.String())
+			dto.Authors = append(dto.Authors, ToAuthorDto(*author))
+		}
 	}
+	return dto
+}
+
+// ToItemEntity converts Item DTO into a new Item entity, the publisher and authors are taken from their ids.
+func ToItemEntity(dto ItemDto) (*entities.Item, error) {
+	builder := entities.NewItemBuilder().
+		Title(strings.TrimSpace(dto.Title)).
+		Description(dto.Description).
+		Price(dto.Price).
+		Stock(dto.Stock).
+		ISBN(dto.ISBN).
+		Edition(strings.TrimSpace(dto.Edition)).
+		PageCount(dto.PageCount).
+		Language(dto.Language)
+
+	if dto.PublisherID != "" {
+		publisherId, err := uuid.Parse(dto.PublisherID)
+		if err != nil {
+			return nil, err
+		}
+		builder.Publisher(publisherId)
+	}
+	if dto.PublicationDate != "" {
+		date, err := time.Parse(DateLayout, dto.PublicationDate)
+		if err != nil {
+			return nil, err
+		}
+		builder.PublicationDate(date)
+	}
+	authorIds := make([]uuid.UUID, len(dto.AuthorIDs))
+	for i, id := range dto.AuthorIDs {
+		authorId, err := uuid.Parse(id)
+		if err != nil {
+			return nil, err
+		}
+		authorIds[i] = authorId
+	}
+	item := builder.Authors(authorIds...).Build()
+
+	if err := identify(&item.Entity, dto.ID, dto.Version); err != nil {
+		return nil, err
+	}
+	return item, nil
 }
 
 // ToPageItemDto converts Item entities Page into a Item DTO Page.
 func ToPageItemDto(page entities.Page[entities.Item]) entities.Page[ItemDto] {
-	dtos := make([]ItemDto, len(page.Elements))
-	for i, item := range page.Elements {
-		dtos[i] = ToItemDto(item)
+	return entities.MapPage(page, ToItemDto)
+}
+
+// ItemIsbnQuery looks an item up by its ISBN, given as ISBN-10 or ISBN-13.
+type ItemIsbnQuery struct {
+	ISBN string `json:"isbn" validate:"required,isbn"`
+}
+
+// ItemFilter selects items of a listing. Items of all categories are listed if CategoryID is nil,
+// otherwise items of the category and all of its subcategories.
+type ItemFilter struct {
+	CategoryID  *uuid.UUID
+	PageRequest entities.Pageable
+}
+
+// Item search modes: full-text search over names and descriptions, or typo tolerant search by name similarity.
+const (
+	SearchModeFullText = "fulltext"
+	SearchModeFuzzy    = "fuzzy"
+)
+
+// ItemSearchQuery is a search for items. Mode defaults to SearchModeFullText.
+type ItemSearchQuery struct {
+	Query       string `json:"q" validate:"required,max=200"`
+	Mode        string `json:"mode" validate:"omitempty,oneof=fulltext fuzzy"`
+	PageRequest entities.Pageable
+}
+
+// ItemSuggestQuery asks for item names resembling the query.
+type ItemSuggestQuery struct {
+	Query string `json:"q" validate:"required,max=200"`
+	Limit int    `json:"limit" validate:"gte=1,lte=20"`
+}
+
+// ItemSuggestionsDto lists item names resembling the query, closest first.
+type ItemSuggestionsDto struct {
+	Query       string   `json:"q"`
+	Suggestions []string `json:"suggestions"`
+}
+
+// ItemMatchDto is an item found by search, with its relevance to the query.
+// Full-text search also highlights the matched terms of the item name and description.
+type ItemMatchDto struct {
+	ItemDto
+	Rank      float64           `json:"rank"`
+	Highlight *ItemHighlightDto `json:"highlight,omitempty"`
+}
+
+// ItemHighlightDto holds item texts with matched terms wrapped in <mark> tags.
+type ItemHighlightDto struct {
+	Title       string `json:"name"`
+	Description string `json:"description"`
+}
+
+func ToItemMatchDto(match entities.ItemMatch) ItemMatchDto {
+	dto := ItemMatchDto{
+		ItemDto: ToItemDto(match.Item),
+		Rank:    match.Rank,
 	}
-	return entities.Page[ItemDto]{
-		TotalPages:    page.TotalPages,
-		TotalElements: page.TotalElements,
-		Elements:      dtos,
+	if match.TitleHighlight != "" || match.DescriptionHighlight != "" {
+		dto.Highlight = &ItemHighlightDto{
+			Title:       match.TitleHighlight,
+			Description: match.DescriptionHighlight,
+		}
 	}
+	return dto
+}
+
+// ToPageItemMatchDto converts ItemMatch entities Page into a ItemMatch DTO Page.
+func ToPageItemMatchDto(page entities.Page[entities.ItemMatch]) entities.Page[ItemMatchDto] {
+	return entities.MapPage(page, ToItemMatchDto)
 }
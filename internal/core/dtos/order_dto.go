@@ -7,12 +7,16 @@ import (
 )
 
 type OrderDto struct {
-	ID           string         `json:"id"`
-	AccountID    string         `json:"account_id"`
-	AccountEmail string         `json:"account_email"`
-	Items        []OrderItemDto `json:"items"`
-	CreatedAt    time.Time      `json:"createdAt"`
-	UpdatedAt    time.Time      `json:"updatedAt"`
+	ID                 string          `json:"id"`
+	AccountID          string          `json:"account_id"`
+	AccountEmail       string          `json:"account_email"`
+	Status             string          `json:"status"`
+	CancellationReason string          `json:"cancellation_reason,omitempty"`
+	Items              []OrderItemDto  `json:"items"`
+	Subtotal           *entities.Money `json:"subtotal,omitempty"`
+	Total              *entities.Money `json:"total,omitempty"`
+	CreatedAt          time.Time       `json:"createdAt"`
+	UpdatedAt          time.Time       `json:"updatedAt"`
 }
 
 func ToOrderDto(order entities.Order) OrderDto {
@@ -23,18 +27,34 @@ func ToOrderDto(order entities.Order) OrderDto {
 		}
 		items[i] = ToOrderItemDto(*item)
 	}
-	return OrderDto{
-		ID:           order.ID.String(),
-		CreatedAt:    order.CreatedAt,
-		AccountID:    order.AccountID.String(),
-		AccountEmail: order.Account.Email,
-		Items:        items,
+	dto := OrderDto{
+		ID:                 order.ID.String(),
+		CreatedAt:          order.CreatedAt,
+		AccountID:          order.AccountID.String(),
+		AccountEmail:       order.Account.Email,
+		Status:             string(order.Status),
+		CancellationReason: order.CancellationReason,
+		Items:              items,
 	}
+	// totals are left out for orders mixing currencies, which cannot be summed up
+	if subtotal, err := order.Subtotal(); err == nil {
+		dto.Subtotal = &subtotal
+	}
+	if total, err := order.Total(); err == nil {
+		dto.Total = &total
+	}
+	return dto
 }
 
 type CreateOrderCommand struct {
 	AccountID string         `json:"account_id"`
-	Items     []OrderItemDto `json:"items"`
+	Items     []OrderItemDto `json:"items" validate:"required,min=1,dive"`
+}
+
+// CancelOrderCommand requests cancelling an order which has not been shipped yet.
+type CancelOrderCommand struct {
+	OrderID uuid.UUID `json:"-"`
+	Reason  string    `json:"reason" validate:"required,max=500"`
 }
 
 type CreateOrderAnswer struct {
@@ -48,13 +68,5 @@ type OrderFilter struct {
 
 // ToPageOrderDto converts Order entities Page into a Order DTO Page.
 func ToPageOrderDto(page entities.Page[entities.Order]) entities.Page[OrderDto] {
-	dtos := make([]OrderDto, len(page.Elements))
-	for i, order := range page.Elements {
-		dtos[i] = ToOrderDto(order)
-	}
-	return entities.Page[OrderDto]{
-		TotalPages:    page.TotalPages,
-		TotalElements: page.TotalElements,
-		Elements:      dtos,
-	}
+	return entities.MapPage(page, ToOrderDto)
 }
@@ -0,0 +1,47 @@
+package dtos
+
+import (
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+	"time"
+)
+
+type OrderTransitionDto struct {
+	ID         string    `json:"id"`
+	OrderID    string    `json:"order_id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	ActorID    string    `json:"actor_id,omitempty"`
+	Note       string    `json:"note,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func ToOrderTransitionDto(h entities.OrderHistory) OrderTransitionDto {
+	dto := OrderTransitionDto{
+		ID:         h.ID.String(),
+		OrderID:    h.OrderID.String(),
+		FromStatus: string(h.FromStatus),
+		ToStatus:   string(h.ToStatus),
+		Note:       h.Note,
+		CreatedAt:  h.CreatedAt,
+	}
+	if h.ActorID.Valid {
+		dto.ActorID = h.ActorID.UUID.String()
+	}
+	return dto
+}
+
+func ToOrderTransitionDtos(history []entities.OrderHistory) []OrderTransitionDto {
+	dtos := make([]OrderTransitionDto, len(history))
+	for i, h := range history {
+		dtos[i] = ToOrderTransitionDto(h)
+	}
+	return dtos
+}
+
+// TransitionOrderCommand requests moving an order into another status of its lifecycle.
+type TransitionOrderCommand struct {
+	OrderID uuid.UUID `json:"-"`
+	Status  string    `json:"status" validate:"required,oneof=pending paid shipped delivered cancelled refunded"`
+	Note    string    `json:"note" validate:"max=500"`
+}
@@ -1,24 +1,34 @@
 package dtos
 
 import (
+	"fmt"
 	"github.com/fmiskovic/new-amz/internal/core/entities"
 	"github.com/google/uuid"
 	"time"
 )
 
 type OrderItemDto struct {
-	OrderID   string    `json:"order_id"`
-	ItemID    string    `json:"item_id"`
-	Quantity  int       `json:"quantity" validate:"required,gte=1"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        string          `json:"id,omitempty"`
+	OrderID   string          `json:"order_id"`
+	ItemID    string          `json:"item_id"`
+	Quantity  int             `json:"quantity" validate:"required,gte=1"`
+	Title     string          `json:"title,omitempty"`
+	UnitPrice *entities.Money `json:"unit_price,omitempty"`
+	LineTotal *entities.Money `json:"line_total,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
 }
 
 func ToOrderItemDto(orderItem entities.OrderItem) OrderItemDto {
+	lineTotal := orderItem.LineTotal()
 	return OrderItemDto{
+		ID:        orderItem.ID.String(),
 		OrderID:   orderItem.OrderID.String(),
 		ItemID:    orderItem.ItemID.String(),
 		Quantity:  orderItem.Quantity,
+		Title:     orderItem.Title,
+		UnitPrice: &orderItem.UnitPrice,
+		LineTotal: &lineTotal,
 		CreatedAt: orderItem.CreatedAt,
 		UpdatedAt: orderItem.UpdatedAt,
 	}
@@ -30,6 +40,10 @@ func ToOrderItemEntity(dto OrderItemDto) (*entities.OrderItem, error) {
 		return nil, err
 	}
 
+	if dto.Quantity < 1 {
+		return nil, fmt.Errorf("invalid quantity %d of item %s", dto.Quantity, dto.ItemID)
+	}
+
 	return &entities.OrderItem{
 		ItemID:   itemId,
 		Quantity: dto.Quantity,
@@ -0,0 +1,79 @@
+package dtos
+
+import (
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+	"time"
+)
+
+// Types of payment events sent by payment providers.
+const (
+	PaymentEventCaptured = "payment.captured"
+	PaymentEventVoided   = "payment.voided"
+	PaymentEventFailed   = "payment.failed"
+	PaymentEventRefunded = "payment.refunded"
+)
+
+type PaymentDto struct {
+	ID            string         `json:"id"`
+	OrderID       string         `json:"order_id"`
+	Provider      string         `json:"provider"`
+	Reference     string         `json:"reference,omitempty"`
+	Status        string         `json:"status"`
+	Amount        entities.Money `json:"amount"`
+	Captured      entities.Money `json:"captured"`
+	Refunded      entities.Money `json:"refunded"`
+	FailureReason string         `json:"failure_reason,omitempty"`
+	// PendingOperation is the provider operation sent but not settled yet, if any.
+	PendingOperation string    `json:"pending_operation,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+func ToPaymentDto(p entities.Payment) PaymentDto {
+	return PaymentDto{
+		ID:               p.ID.String(),
+		OrderID:          p.OrderID.String(),
+		Provider:         p.Provider,
+		Reference:        p.Reference,
+		Status:           string(p.Status),
+		Amount:           p.Amount,
+		Captured:         p.CapturedAmount(),
+		Refunded:         p.RefundedAmount(),
+		FailureReason:    p.FailureReason,
+		PendingOperation: string(p.PendingOperation),
+		CreatedAt:        p.CreatedAt,
+		UpdatedAt:        p.UpdatedAt,
+	}
+}
+
+func ToPaymentDtos(payments []entities.Payment) []PaymentDto {
+	dtos := make([]PaymentDto, len(payments))
+	for i, p := range payments {
+		dtos[i] = ToPaymentDto(p)
+	}
+	return dtos
+}
+
+// AuthorizePaymentCommand requests paying the order with the payment method the token stands for.
+type AuthorizePaymentCommand struct {
+	OrderID uuid.UUID `json:"-"`
+	Token   string    `json:"token" validate:"required,max=255"`
+}
+
+// PaymentAmountCommand requests capturing or refunding an amount of the payment.
+// Without amount the whole authorized amount is captured or the whole captured amount not refunded yet is refunded.
+type PaymentAmountCommand struct {
+	PaymentID uuid.UUID       `json:"-"`
+	Amount    *entities.Money `json:"amount"`
+}
+
+// PaymentEventCommand is a payment event sent by the payment provider.
+// The amount of captured events is the captured amount, the amount of refunded events is the refunded amount in total.
+type PaymentEventCommand struct {
+	ID        string          `json:"id" validate:"required"`
+	Type      string          `json:"type" validate:"required,oneof=payment.captured payment.voided payment.failed payment.refunded"`
+	Reference string          `json:"reference" validate:"required"`
+	Amount    *entities.Money `json:"amount"`
+	Reason    string          `json:"reason"`
+}
@@ -0,0 +1,42 @@
+package dtos
+
+import (
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"strings"
+	"time"
+)
+
+type PublisherDto struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Version   int64     `json:"-"`
+	Name      string    `json:"name" validate:"required,max=255"`
+}
+
+func ToPublisherDto(publisher entities.Publisher) PublisherDto {
+	return PublisherDto{
+		ID:        publisher.ID.String(),
+		CreatedAt: publisher.CreatedAt,
+		UpdatedAt: publisher.UpdatedAt,
+		Version:   publisher.Version,
+		Name:      publisher.Name,
+	}
+}
+
+// ToPublisherEntity converts Publisher DTO into a new Publisher entity.
+func ToPublisherEntity(dto PublisherDto) (*entities.Publisher, error) {
+	publisher := entities.NewPublisherBuilder().
+		Name(strings.TrimSpace(dto.Name)).
+		Build()
+
+	if err := identify(&publisher.Entity, dto.ID, dto.Version); err != nil {
+		return nil, err
+	}
+	return publisher, nil
+}
+
+// ToPagePublisherDto converts Publisher entities Page into a Publisher DTO Page.
+func ToPagePublisherDto(page entities.Page[entities.Publisher]) entities.Page[PublisherDto] {
+	return entities.MapPage(page, ToPublisherDto)
+}
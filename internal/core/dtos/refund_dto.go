@@ -0,0 +1,84 @@
+package dtos
+
+import (
+	"fmt"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+	"time"
+)
+
+type RefundDto struct {
+	ID        string          `json:"id"`
+	OrderID   string          `json:"order_id"`
+	ActorID   string          `json:"actor_id,omitempty"`
+	Reason    string          `json:"reason,omitempty"`
+	Amount    entities.Money  `json:"amount"`
+	Items     []RefundItemDto `json:"items"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type RefundItemDto struct {
+	OrderItemID string         `json:"order_item_id"`
+	Quantity    int            `json:"quantity"`
+	Amount      entities.Money `json:"amount"`
+}
+
+func ToRefundDto(r entities.Refund) RefundDto {
+	items := make([]RefundItemDto, 0, len(r.Items))
+	for _, ri := range r.Items {
+		if ri == nil {
+			continue
+		}
+		items = append(items, RefundItemDto{
+			OrderItemID: ri.OrderItemID.String(),
+			Quantity:    ri.Quantity,
+			Amount:      ri.Amount,
+		})
+	}
+	dto := RefundDto{
+		ID:        r.ID.String(),
+		OrderID:   r.OrderID.String(),
+		Reason:    r.Reason,
+		Amount:    r.Amount,
+		Items:     items,
+		CreatedAt: r.CreatedAt,
+	}
+	if r.ActorID.Valid {
+		dto.ActorID = r.ActorID.UUID.String()
+	}
+	return dto
+}
+
+func ToRefundDtos(refunds []entities.Refund) []RefundDto {
+	dtos := make([]RefundDto, len(refunds))
+	for i, r := range refunds {
+		dtos[i] = ToRefundDto(r)
+	}
+	return dtos
+}
+
+// RefundOrderCommand requests a refund of the order.
+// Without items everything not refunded yet is refunded, otherwise the quantities of the listed order items.
+type RefundOrderCommand struct {
+	OrderID uuid.UUID           `json:"-"`
+	Reason  string              `json:"reason" validate:"max=500"`
+	Items   []RefundItemCommand `json:"items" validate:"dive"`
+}
+
+type RefundItemCommand struct {
+	OrderItemID string `json:"order_item_id" validate:"required,uuid"`
+	Quantity    int    `json:"quantity" validate:"required,gte=1"`
+}
+
+// ToRefundLines converts refunded order items into refund lines.
+func ToRefundLines(items []RefundItemCommand) ([]entities.RefundLine, error) {
+	lines := make([]entities.RefundLine, len(items))
+	for i, item := range items {
+		id, err := uuid.Parse(item.OrderItemID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid order item id %s: %w", item.OrderItemID, err)
+		}
+		lines[i] = entities.RefundLine{OrderItemID: id, Quantity: item.Quantity}
+	}
+	return lines, nil
+}
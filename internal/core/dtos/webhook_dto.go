@@ -0,0 +1,117 @@
+package dtos
+
+import (
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+)
+
+// WebhookSubscriptionDto describes a webhook subscription. The secret is written only,
+// it is never returned except by the creation of the subscription.
+type WebhookSubscriptionDto struct {
+	ID         string    `json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	Version    int64     `json:"-"`
+	URL        string    `json:"url" validate:"required,http_url,max=2048"`
+	EventTypes []string  `json:"event_types" validate:"required,min=1,dive,oneof=order.placed order.status_changed"`
+	Secret     string    `json:"secret,omitempty" validate:"omitempty,min=16,max=255"`
+	Disabled   bool      `json:"disabled"`
+}
+
+func ToWebhookSubscriptionDto(subscription entities.WebhookSubscription) WebhookSubscriptionDto {
+	eventTypes := make([]string, len(subscription.EventTypes))
+	for i, t := range subscription.EventTypes {
+		eventTypes[i] = string(t)
+	}
+	return WebhookSubscriptionDto{
+		ID:         subscription.ID.String(),
+		CreatedAt:  subscription.CreatedAt,
+		UpdatedAt:  subscription.UpdatedAt,
+		Version:    subscription.Version,
+		URL:        subscription.URL,
+		EventTypes: eventTypes,
+		Disabled:   subscription.Disabled,
+	}
+}
+
+// ToWebhookSubscriptionEntity converts webhook subscription DTO into a new webhook subscription entity.
+func ToWebhookSubscriptionEntity(dto WebhookSubscriptionDto) (*entities.WebhookSubscription, error) {
+	eventTypes := make([]entities.EventType, len(dto.EventTypes))
+	for i, t := range dto.EventTypes {
+		eventTypes[i] = entities.EventType(t)
+	}
+	subscription := &entities.WebhookSubscription{
+		Entity:     entities.Entity{ID: uuid.New(), CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		URL:        strings.TrimSpace(dto.URL),
+		EventTypes: eventTypes,
+		Secret:     dto.Secret,
+		Disabled:   dto.Disabled,
+	}
+
+	if err := identify(&subscription.Entity, dto.ID, dto.Version); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// ToPageWebhookSubscriptionDto converts webhook subscription entities Page into a webhook subscription DTO Page.
+func ToPageWebhookSubscriptionDto(page entities.Page[entities.WebhookSubscription]) entities.Page[WebhookSubscriptionDto] {
+	return entities.MapPage(page, ToWebhookSubscriptionDto)
+}
+
+type WebhookDeliveryDto struct {
+	ID             string     `json:"id"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	SubscriptionID string     `json:"subscription_id"`
+	EventID        string     `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+	LastStatusCode int        `json:"last_status_code,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+}
+
+func ToWebhookDeliveryDto(delivery entities.WebhookDelivery) WebhookDeliveryDto {
+	dto := WebhookDeliveryDto{
+		ID:             delivery.ID.String(),
+		CreatedAt:      delivery.CreatedAt,
+		UpdatedAt:      delivery.UpdatedAt,
+		SubscriptionID: delivery.SubscriptionID.String(),
+		EventID:        delivery.EventID.String(),
+		EventType:      string(delivery.EventType),
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+	}
+	if !delivery.NextAttemptAt.IsZero() {
+		dto.NextAttemptAt = &delivery.NextAttemptAt
+	}
+	if !delivery.DeliveredAt.IsZero() {
+		dto.DeliveredAt = &delivery.DeliveredAt
+	}
+	return dto
+}
+
+// ToPageWebhookDeliveryDto converts webhook delivery entities Page into a webhook delivery DTO Page.
+func ToPageWebhookDeliveryDto(page entities.Page[entities.WebhookDelivery]) entities.Page[WebhookDeliveryDto] {
+	return entities.MapPage(page, ToWebhookDeliveryDto)
+}
+
+// WebhookDeliveriesQuery asks for a page of deliveries to the webhook subscription, in the status if it is given.
+type WebhookDeliveriesQuery struct {
+	SubscriptionID uuid.UUID `json:"-"`
+	Status         string    `json:"status" validate:"omitempty,oneof=pending delivered dead"`
+	PageRequest    entities.Pageable
+}
+
+// RedeliverWebhookCommand asks to attempt the delivery to the webhook subscription once more.
+type RedeliverWebhookCommand struct {
+	SubscriptionID uuid.UUID `json:"-"`
+	DeliveryID     uuid.UUID `json:"-"`
+}
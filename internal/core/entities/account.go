@@ -6,27 +6,35 @@ import (
 	"time"
 )
 
+var ErrorEmailNotUnique = NewError(ErrorConflict, "email is not unique")
+
 // Account will store information about each user account.
+// Accounts are soft deleted, deleted accounts are excluded from all queries unless explicitly requested.
 type Account struct {
 	bun.BaseModel `bun:"table:accounts,alias:a"`
 
 	Entity
-	Email       string    `bun:"email,notnull,unique"`
-	FullName    string    `bun:"full_name,nullzero"`
-	DateOfBirth time.Time `bun:"date_of_birth,nullzero"`
-	Location    string    `bun:"location,nullzero"`
-	Gender      Gender    `bun:"gender,nullzero"`
+	Email        string    `bun:"email,notnull"`
+	PasswordHash string    `bun:"password_hash,nullzero"`
+	FullName     string    `bun:"full_name,nullzero"`
+	DateOfBirth  time.Time `bun:"date_of_birth,nullzero"`
+	Location     string    `bun:"location,nullzero"`
+	Gender       Gender    `bun:"gender,nullzero"`
+	Role         Role      `bun:"role,notnull,default:'customer'"`
+	DeletedAt    time.Time `bun:"deleted_at,soft_delete,nullzero"`
 
 	// one-to-many relation
 	Orders []*Order `bun:"rel:has-many,join:id=account_id"`
 }
 
 type AccountBuilder struct {
-	email       string
-	fullName    string
-	dateOfBirth time.Time
-	location    string
-	gender      Gender
+	email        string
+	passwordHash string
+	fullName     string
+	dateOfBirth  time.Time
+	location     string
+	gender       Gender
+	role         Role
 }
 
 func NewAccountBuilder() *AccountBuilder {
@@ -39,6 +47,12 @@ func (b *AccountBuilder) Email(email string) *AccountBuilder {
 	return b
 }
 
+// PasswordHash sets the password hash on the Builder.
+func (b *AccountBuilder) PasswordHash(passwordHash string) *AccountBuilder {
+	b.passwordHash = passwordHash
+	return b
+}
+
 // FullName sets the full name on the Builder.
 func (b *AccountBuilder) FullName(fullName string) *AccountBuilder {
 	b.fullName = fullName
@@ -63,15 +77,27 @@ func (b *AccountBuilder) Gender(gender Gender) *AccountBuilder {
 	return b
 }
 
+// Role sets the role on the Builder.
+func (b *AccountBuilder) Role(role Role) *AccountBuilder {
+	b.role = role
+	return b
+}
+
 // Build constructs an Account instance from the Builder.
+// If role is not set, account is built as a CUSTOMER.
 func (b *AccountBuilder) Build() *Account {
+	if b.role == "" {
+		b.role = CUSTOMER
+	}
 	return &Account{
-		Entity:      Entity{ID: uuid.New(), CreatedAt: time.Now(), UpdatedAt: time.Now()},
-		Email:       b.email,
-		FullName:    b.fullName,
-		DateOfBirth: b.dateOfBirth,
-		Location:    b.location,
-		Gender:      b.gender,
+		Entity:       Entity{ID: uuid.New(), CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		Email:        b.email,
+		PasswordHash: b.passwordHash,
+		FullName:     b.fullName,
+		DateOfBirth:  b.dateOfBirth,
+		Location:     b.location,
+		Gender:       b.gender,
+		Role:         b.role,
 	}
 }
 
@@ -97,3 +123,11 @@ const (
 	FEMALE
 	OTHER
 )
+
+// Role is either CUSTOMER or ADMIN.
+type Role string
+
+const (
+	CUSTOMER Role = "customer"
+	ADMIN    Role = "admin"
+)
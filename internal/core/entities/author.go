@@ -0,0 +1,62 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+var (
+	ErrorAuthorNotFound = NewError(ErrorNotFound, "author not found")
+	ErrorAuthorHasItems = NewError(ErrorConflict, "author is credited on items")
+)
+
+// Author wrote or contributed to books of the store.
+type Author struct {
+	bun.BaseModel `bun:"table:authors,alias:au"`
+
+	Entity
+	Name      string `bun:"name,notnull"`
+	Biography string `bun:"biography,nullzero"`
+}
+
+// ItemAuthor credits an author on an item. Authors of an item are ordered by their position.
+type ItemAuthor struct {
+	bun.BaseModel `bun:"table:item_authors,alias:ia"`
+
+	ItemID   uuid.UUID `bun:"item_id,pk,type:uuid"`
+	Item     *Item     `bun:"rel:belongs-to,join:item_id=id"`
+	AuthorID uuid.UUID `bun:"author_id,pk,type:uuid"`
+	Author   *Author   `bun:"rel:belongs-to,join:author_id=id"`
+	Position int       `bun:"position,notnull"`
+}
+
+type AuthorBuilder struct {
+	name      string
+	biography string
+}
+
+func NewAuthorBuilder() *AuthorBuilder {
+	return &AuthorBuilder{}
+}
+
+// Name sets the name on the Builder.
+func (b *AuthorBuilder) Name(name string) *AuthorBuilder {
+	b.name = name
+	return b
+}
+
+// Biography sets the biography on the Builder.
+func (b *AuthorBuilder) Biography(biography string) *AuthorBuilder {
+	b.biography = biography
+	return b
+}
+
+// Build creates a new Author entity.
+func (b *AuthorBuilder) Build() *Author {
+	return &Author{
+		Entity:    Entity{ID: uuid.New(), CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		Name:      b.name,
+		Biography: b.biography,
+	}
+}
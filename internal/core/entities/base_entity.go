@@ -7,15 +7,108 @@ import (
 )
 
 // Entity represents base for every persistent entity like Account or Item.
+// Version counts the updates of the entity. Updates and deletes expecting a non-zero version succeed only
+// while the entity is still in that version, otherwise ErrorVersionMismatch is returned.
 type Entity struct {
 	ID        uuid.UUID `bun:",pk,type:uuid,default:uuid_generate_v4()"`
 	CreatedAt time.Time `bun:"created_at,notnull,default:current_timestamp"`
 	UpdatedAt time.Time `bun:"updated_at,notnull,default:current_timestamp"`
+	Version   int64     `bun:"version,notnull,default:1"`
 }
 
 // Page is generic struct that represents response made by page request.
+// Number is the zero based index of the page of Size elements. TotalPages and TotalElements are nil
+// if the page request skipped counting, see Pageable.SkipCount.
 type Page[T any] struct {
-	TotalPages    int `json:"total_pages"`
-	TotalElements int `json:"total_elements"`
-	Elements      []T `json:"elements"`
+	TotalPages    *int `json:"total_pages,omitempty"`
+	TotalElements *int `json:"total_elements,omitempty"`
+	Number        int  `json:"number"`
+	Size          int  `json:"size"`
+	HasNext       bool `json:"has_next"`
+	HasPrev       bool `json:"has_prev"`
+	Elements      []T  `json:"elements"`
+	// Cursors are set only on pages requested in keyset mode, see Pageable.Keyset.
+	Cursors *PageCursors `json:"-"`
+}
+
+// NewPage creates the page of elements requested by offset page request p, out of total elements.
+// Whether there is a next page is given by hasNext, as total is not known if the page request skipped counting.
+func NewPage[T any](elements []T, p Pageable, total int, hasNext bool) Page[T] {
+	page := Page[T]{
+		Size:     p.Size,
+		HasNext:  hasNext,
+		HasPrev:  p.Offset > 0,
+		Elements: elements,
+	}
+	if p.Size > 0 {
+		page.Number = p.Offset / p.Size
+	}
+	if !p.SkipCount {
+		page.SetTotal(total)
+	}
+	return page
+}
+
+// SetTotal sets the number of all elements and the number of pages of Size elements they fill.
+func (p *Page[T]) SetTotal(total int) {
+	pages := 0
+	if p.Size > 0 {
+		pages = (total + p.Size - 1) / p.Size
+	} else if total > 0 {
+		// unlimited page holds all elements
+		pages = 1
+	}
+	p.TotalElements = &total
+	p.TotalPages = &pages
+}
+
+// MapPage converts elements of the page, keeping its metadata.
+func MapPage[T any, R any](page Page[T], convert func(T) R) Page[R] {
+	elements := make([]R, len(page.Elements))
+	for i, e := range page.Elements {
+		elements[i] = convert(e)
+	}
+	return Page[R]{
+		TotalPages:    page.TotalPages,
+		TotalElements: page.TotalElements,
+		Number:        page.Number,
+		Size:          page.Size,
+		HasNext:       page.HasNext,
+		HasPrev:       page.HasPrev,
+		Elements:      elements,
+		Cursors:       page.Cursors,
+	}
+}
+
+// PageCursors holds the cursors of the pages adjacent to a keyset page.
+// Next or Prev is empty if there is no such page.
+type PageCursors struct {
+	Next    string
+	Prev    string
+	Counted bool
+}
+
+// CursorPage is the response envelope of a page requested in keyset mode.
+type CursorPage[T any] struct {
+	Elements      []T    `json:"elements"`
+	Size          int    `json:"size"`
+	HasNext       bool   `json:"has_next"`
+	HasPrev       bool   `json:"has_prev"`
+	Next          string `json:"next,omitempty"`
+	Prev          string `json:"prev,omitempty"`
+	TotalElements *int   `json:"total_elements,omitempty"`
+}
+
+// CursorPage converts the keyset page into its response envelope.
+func (p Page[T]) CursorPage() CursorPage[T] {
+	cp := CursorPage[T]{Elements: p.Elements, Size: p.Size, HasNext: p.HasNext, HasPrev: p.HasPrev}
+	if p.Cursors == nil {
+		return cp
+	}
+	cp.Next = p.Cursors.Next
+	cp.Prev = p.Cursors.Prev
+	if p.Cursors.Counted {
+		cp.TotalElements = p.TotalElements
+	}
+	return cp
 }
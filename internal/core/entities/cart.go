@@ -0,0 +1,107 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+var (
+	ErrorCartNotFound  = NewError(ErrorNotFound, "cart not found")
+	ErrorCartEmpty     = NewError(ErrorValidation, "cart is empty")
+	ErrorCartNotGuest  = NewError(ErrorValidation, "only guest carts can be merged into an account cart")
+	ErrorGuestCheckout = NewError(ErrorValidation, "guest carts have to be merged into an account cart before checkout")
+)
+
+// Cart collects items an account or a guest intends to order. Every account has a single cart,
+// guest carts do not belong to any account until they are merged into an account cart.
+type Cart struct {
+	bun.BaseModel `bun:"table:carts,alias:ca"`
+
+	Entity
+
+	AccountID *uuid.UUID `bun:"account_id,type:uuid"`
+
+	Items []*CartItem `bun:"rel:has-many,join:id=cart_id"`
+}
+
+// IsGuest reports whether the cart belongs to a guest rather than an account.
+func (c Cart) IsGuest() bool {
+	return c.AccountID == nil
+}
+
+// Currency returns the currency of the cart, that is the currency of its items.
+func (c Cart) Currency() Currency {
+	for _, ci := range c.Items {
+		if ci != nil && ci.Item != nil && ci.Item.Price.Currency != "" {
+			return ci.Item.Price.Currency
+		}
+	}
+	return DefaultCurrency
+}
+
+// Subtotal returns the sum of all cart line totals at current prices of the items.
+// It returns ErrorCurrencyMismatch if the items are priced in different currencies.
+func (c Cart) Subtotal() (Money, error) {
+	subtotal := Zero(c.Currency())
+	for _, ci := range c.Items {
+		if ci == nil || ci.Item == nil {
+			continue
+		}
+		var err error
+		if subtotal, err = subtotal.Add(ci.LineTotal()); err != nil {
+			return Money{}, err
+		}
+	}
+	return subtotal, nil
+}
+
+// CartItem is a line of a cart, the quantity of an item in the cart.
+// Unlike order items, cart items do not capture the price of the item, they are always valued at its current price.
+type CartItem struct {
+	bun.BaseModel `bun:"table:cart_items,alias:ci"`
+
+	CartID uuid.UUID `bun:"cart_id,pk,type:uuid"`
+
+	ItemID uuid.UUID `bun:"item_id,pk,type:uuid"`
+	Item   *Item     `bun:"rel:belongs-to,join:item_id=id"`
+
+	CreatedAt time.Time `bun:"created_at,notnull,default:current_timestamp"`
+	UpdatedAt time.Time `bun:"updated_at,notnull,default:current_timestamp"`
+	Quantity  int       `bun:"quantity,notnull"`
+}
+
+// LineTotal returns the value of the cart line, that is current price of the item multiplied by quantity.
+func (ci CartItem) LineTotal() Money {
+	if ci.Item == nil {
+		return Money{}
+	}
+	return ci.Item.Price.Mul(int64(ci.Quantity))
+}
+
+// InStock reports whether the item has enough units in stock for the quantity in the cart.
+func (ci CartItem) InStock() bool {
+	return ci.Item != nil && ci.Item.Stock >= ci.Quantity
+}
+
+type CartBuilder struct {
+	accountID *uuid.UUID
+}
+
+func NewCartBuilder() *CartBuilder {
+	return &CartBuilder{}
+}
+
+// AccountID sets the account owning the cart on the Builder, carts without an account are guest carts.
+func (b *CartBuilder) AccountID(accountID uuid.UUID) *CartBuilder {
+	b.accountID = &accountID
+	return b
+}
+
+// Build creates a new Cart entity.
+func (b *CartBuilder) Build() *Cart {
+	return &Cart{
+		Entity:    Entity{ID: uuid.New(), CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		AccountID: b.accountID,
+	}
+}
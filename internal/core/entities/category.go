@@ -0,0 +1,101 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+var (
+	ErrorCategoryNotUnique      = NewError(ErrorConflict, "category name is not unique within its parent")
+	ErrorCategoryHasChildren    = NewError(ErrorConflict, "category has subcategories")
+	ErrorCategoryCycle          = NewError(ErrorValidation, "category can not be nested under itself or its subcategories")
+	ErrorCategoryNotFound       = NewError(ErrorNotFound, "category not found")
+	ErrorParentCategoryNotFound = NewError(ErrorNotFound, "parent category not found")
+)
+
+// Category groups items of the store, e.g. by genre. Categories are nested, a category without
+// a parent is a top level category. Items can be assigned to any number of categories.
+type Category struct {
+	bun.BaseModel `bun:"table:categories,alias:c"`
+
+	Entity
+	Name        string     `bun:"name,notnull"`
+	Description string     `bun:"description,nullzero"`
+	ParentID    *uuid.UUID `bun:"parent_id,type:uuid"`
+
+	// Children are the subcategories, they are only loaded with the category subtree.
+	Children []*Category `bun:"-"`
+}
+
+// ItemCategory assigns an item to a category.
+type ItemCategory struct {
+	bun.BaseModel `bun:"table:item_categories,alias:ic"`
+
+	ItemID     uuid.UUID `bun:"item_id,pk,type:uuid"`
+	CategoryID uuid.UUID `bun:"category_id,pk,type:uuid"`
+}
+
+type CategoryBuilder struct {
+	name        string
+	description string
+	parentID    *uuid.UUID
+}
+
+func NewCategoryBuilder() *CategoryBuilder {
+	return &CategoryBuilder{}
+}
+
+// Name sets the name on the Builder.
+func (b *CategoryBuilder) Name(name string) *CategoryBuilder {
+	b.name = name
+	return b
+}
+
+// Description sets the description on the Builder.
+func (b *CategoryBuilder) Description(description string) *CategoryBuilder {
+	b.description = description
+	return b
+}
+
+// Parent sets the parent category on the Builder.
+func (b *CategoryBuilder) Parent(parentID uuid.UUID) *CategoryBuilder {
+	b.parentID = &parentID
+	return b
+}
+
+// Build creates a new Category entity.
+func (b *CategoryBuilder) Build() *Category {
+	return &Category{
+		Entity:      Entity{ID: uuid.New(), CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		Name:        b.name,
+		Description: b.description,
+		ParentID:    b.parentID,
+	}
+}
+
+// NewCategoryTree nests the categories of a subtree under their parents and returns the root of the subtree.
+// Subcategories are kept in the order they are given. It returns false if the root is not among the categories.
+func NewCategoryTree(rootID uuid.UUID, categories []Category) (Category, bool) {
+	nodes := make(map[uuid.UUID]*Category, len(categories))
+	for i := range categories {
+		c := categories[i]
+		c.Children = nil
+		nodes[c.ID] = &c
+	}
+
+	root, ok := nodes[rootID]
+	if !ok {
+		return Category{}, false
+	}
+	for i := range categories {
+		c := nodes[categories[i].ID]
+		if c.ID == rootID || c.ParentID == nil {
+			continue
+		}
+		if parent, ok := nodes[*c.ParentID]; ok {
+			parent.Children = append(parent.Children, c)
+		}
+	}
+	return *root, true
+}
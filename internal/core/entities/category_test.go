@@ -0,0 +1,40 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCategoryTree(t *testing.T) {
+	fiction := NewCategoryBuilder().Name("Fiction").Build()
+	fantasy := NewCategoryBuilder().Name("Fantasy").Parent(fiction.ID).Build()
+	scifi := NewCategoryBuilder().Name("Science Fiction").Parent(fiction.ID).Build()
+	epic := NewCategoryBuilder().Name("Epic Fantasy").Parent(fantasy.ID).Build()
+	categories := []Category{*fiction, *fantasy, *scifi, *epic}
+
+	t.Run("given root should nest subcategories under their parents", func(t *testing.T) {
+		tree, ok := NewCategoryTree(fiction.ID, categories)
+		assert.True(t, ok)
+		assert.Equal(t, "Fiction", tree.Name)
+		assert.Len(t, tree.Children, 2)
+		assert.Equal(t, "Fantasy", tree.Children[0].Name)
+		assert.Equal(t, "Science Fiction", tree.Children[1].Name)
+		assert.Len(t, tree.Children[0].Children, 1)
+		assert.Equal(t, "Epic Fantasy", tree.Children[0].Children[0].Name)
+		assert.Empty(t, tree.Children[1].Children)
+	})
+
+	t.Run("given inner root should return its subtree only", func(t *testing.T) {
+		tree, ok := NewCategoryTree(fantasy.ID, categories)
+		assert.True(t, ok)
+		assert.Equal(t, "Fantasy", tree.Name)
+		assert.Len(t, tree.Children, 1)
+	})
+
+	t.Run("given unknown root should return false", func(t *testing.T) {
+		_, ok := NewCategoryTree(uuid.New(), categories)
+		assert.False(t, ok)
+	})
+}
@@ -2,6 +2,45 @@ package entities
 
 import "errors"
 
+// Kinds of domain errors. Every domain error belongs to one of them,
+// so callers can decide how to react with errors.Is, e.g. errors.Is(err, ErrorNotFound).
 var (
-	ErrorEntityNotFound = errors.New("entity not found")
+	ErrorNotFound    = errors.New("not found")
+	ErrorConflict    = errors.New("conflict")
+	ErrorValidation  = errors.New("validation failed")
+	ErrorForbidden   = errors.New("forbidden")
+	ErrorUnavailable = errors.New("temporarily unavailable")
+	// ErrorPreconditionFailed is the kind of errors of operations expecting a state the entity is not in anymore.
+	ErrorPreconditionFailed = errors.New("precondition failed")
 )
+
+var (
+	ErrorEntityNotFound  = NewError(ErrorNotFound, "entity not found")
+	ErrorVersionMismatch = NewError(ErrorPreconditionFailed, "entity was changed in the meantime")
+)
+
+// DomainError is an error of a specific kind.
+type DomainError struct {
+	kind    error
+	message string
+}
+
+// NewError creates new DomainError of the kind, which is one of
+// ErrorNotFound, ErrorConflict, ErrorValidation, ErrorForbidden, ErrorUnavailable or ErrorPreconditionFailed.
+func NewError(kind error, message string) DomainError {
+	return DomainError{kind: kind, message: message}
+}
+
+func (e DomainError) Error() string {
+	return e.message
+}
+
+// Kind returns the kind of the error.
+func (e DomainError) Kind() error {
+	return e.kind
+}
+
+// Is makes DomainError match its kind.
+func (e DomainError) Is(target error) bool {
+	return target == e.kind
+}
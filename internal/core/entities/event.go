@@ -0,0 +1,187 @@
+package entities
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type EventType string
+
+const (
+	EVENT_ACCOUNT_CREATED      EventType = "account.created"
+	EVENT_ORDER_PLACED         EventType = "order.placed"
+	EVENT_ORDER_STATUS_CHANGED EventType = "order.status_changed"
+)
+
+const (
+	// EventMaxAttempts is the number of failed attempts after which an event is parked and no longer relayed.
+	EventMaxAttempts = 10
+	// EventRetryDelay is the delay after the first failed attempt, it doubles with every further failed attempt.
+	EventRetryDelay = 5 * time.Second
+	// EventMaxRetryDelay caps the delay between attempts.
+	EventMaxRetryDelay = 30 * time.Minute
+	// EventClaimTimeout is how long a claimed event is skipped by other relays. An event whose outcome
+	// is not recorded within it, because the relay stopped, is claimed again.
+	EventClaimTimeout = 5 * time.Minute
+)
+
+// Event is a domain event kept in the outbox. It is stored in the same transaction as the change it describes,
+// so it exists if and only if the change was committed, and it is published from the outbox afterwards.
+// Events are published at least once, consumers tell duplicates apart by the ID.
+type Event struct {
+	bun.BaseModel `bun:"table:outbox_events,alias:ev"`
+
+	ID          uuid.UUID       `bun:",pk,type:uuid,default:uuid_generate_v4()" json:"id"`
+	CreatedAt   time.Time       `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
+	Type        EventType       `bun:"type,notnull" json:"type"`
+	AggregateID uuid.UUID       `bun:"aggregate_id,type:uuid,notnull" json:"aggregate_id"`
+	Payload     json.RawMessage `bun:"payload,type:jsonb,notnull" json:"payload"`
+
+	// Publishing state, it is not part of the published event.
+	PublishedAt   time.Time `bun:"published_at,nullzero" json:"-"`
+	Attempts      int       `bun:"attempts,notnull" json:"-"`
+	LastError     string    `bun:"last_error,nullzero" json:"-"`
+	NextAttemptAt time.Time `bun:"next_attempt_at,nullzero" json:"-"`
+	ParkedAt      time.Time `bun:"parked_at,nullzero" json:"-"`
+}
+
+// NewEvent creates the event of the aggregate with the payload encoded as JSON.
+func NewEvent(eventType EventType, aggregateId uuid.UUID, payload any) (*Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &Event{
+		ID:          uuid.New(),
+		CreatedAt:   time.Now(),
+		Type:        eventType,
+		AggregateID: aggregateId,
+		Payload:     data,
+	}, nil
+}
+
+// Claim counts the attempt and keeps the event from other relays for EventClaimTimeout.
+func (e *Event) Claim(now time.Time) {
+	e.Attempts++
+	e.NextAttemptAt = now.Add(EventClaimTimeout)
+}
+
+// Published records the event was delivered.
+func (e *Event) Published(now time.Time) {
+	e.PublishedAt = now
+	e.NextAttemptAt = time.Time{}
+	e.LastError = ""
+}
+
+// Failed records the failed attempt and schedules the next one with exponential backoff.
+// After EventMaxAttempts failed attempts the event is parked.
+func (e *Event) Failed(reason string, now time.Time) {
+	e.LastError = reason
+	if e.Attempts >= EventMaxAttempts {
+		e.ParkedAt = now
+		e.NextAttemptAt = time.Time{}
+		return
+	}
+	e.NextAttemptAt = now.Add(EventRetryAfter(e.Attempts))
+}
+
+// Parked reports whether the event is no longer relayed.
+func (e *Event) Parked() bool {
+	return !e.ParkedAt.IsZero()
+}
+
+// EventRetryAfter returns the delay before the next attempt after the number of failed attempts.
+func EventRetryAfter(attempts int) time.Duration {
+	delay := EventRetryDelay
+	for i := 1; i < attempts && delay < EventMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, EventMaxRetryDelay)
+}
+
+// AccountCreated is the payload of EVENT_ACCOUNT_CREATED.
+type AccountCreated struct {
+	AccountID uuid.UUID `json:"account_id"`
+	Email     string    `json:"email"`
+	FullName  string    `json:"full_name,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewAccountCreated returns the event of the account being created.
+func NewAccountCreated(account Account) (*Event, error) {
+	return NewEvent(EVENT_ACCOUNT_CREATED, account.ID, AccountCreated{
+		AccountID: account.ID,
+		Email:     account.Email,
+		FullName:  account.FullName,
+		CreatedAt: account.CreatedAt,
+	})
+}
+
+// OrderPlaced is the payload of EVENT_ORDER_PLACED.
+type OrderPlaced struct {
+	OrderID   uuid.UUID         `json:"order_id"`
+	AccountID uuid.UUID         `json:"account_id"`
+	Total     Money             `json:"total"`
+	Items     []OrderPlacedItem `json:"items"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// OrderPlacedItem is an order line of OrderPlaced.
+type OrderPlacedItem struct {
+	ItemID    uuid.UUID `json:"item_id"`
+	Title     string    `json:"title"`
+	Quantity  int       `json:"quantity"`
+	UnitPrice Money     `json:"unit_price"`
+}
+
+// NewOrderPlaced returns the event of the order being placed.
+// Prices of the order items must be captured already, see OrderItem.
+func NewOrderPlaced(order Order) (*Event, error) {
+	total, err := order.Total()
+	if err != nil {
+		return nil, err
+	}
+	items := make([]OrderPlacedItem, 0, len(order.OrderItems))
+	for _, oi := range order.OrderItems {
+		if oi == nil {
+			continue
+		}
+		items = append(items, OrderPlacedItem{
+			ItemID:    oi.ItemID,
+			Title:     oi.Title,
+			Quantity:  oi.Quantity,
+			UnitPrice: oi.UnitPrice,
+		})
+	}
+	return NewEvent(EVENT_ORDER_PLACED, order.ID, OrderPlaced{
+		OrderID:   order.ID,
+		AccountID: order.AccountID,
+		Total:     total,
+		Items:     items,
+		CreatedAt: order.CreatedAt,
+	})
+}
+
+// OrderStatusChanged is the payload of EVENT_ORDER_STATUS_CHANGED.
+type OrderStatusChanged struct {
+	OrderID    uuid.UUID   `json:"order_id"`
+	AccountID  uuid.UUID   `json:"account_id"`
+	FromStatus OrderStatus `json:"from_status"`
+	ToStatus   OrderStatus `json:"to_status"`
+	Note       string      `json:"note,omitempty"`
+	ChangedAt  time.Time   `json:"changed_at"`
+}
+
+// NewOrderStatusChanged returns the event of the order status change.
+func NewOrderStatusChanged(order Order, change OrderHistory) (*Event, error) {
+	return NewEvent(EVENT_ORDER_STATUS_CHANGED, order.ID, OrderStatusChanged{
+		OrderID:    order.ID,
+		AccountID:  order.AccountID,
+		FromStatus: change.FromStatus,
+		ToStatus:   change.ToStatus,
+		Note:       change.Note,
+		ChangedAt:  change.CreatedAt,
+	})
+}
@@ -0,0 +1,88 @@
+package entities
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewOrderPlaced(t *testing.T) {
+	order := NewOrderBuilder().AccountID(uuid.New()).Build()
+	dune := NewOrderItemBuilder().OrderID(order.ID).ItemID(uuid.New()).Quantity(2).Build()
+	dune.Title = "Dune"
+	dune.UnitPrice = NewMoney(1250, EUR)
+	order.OrderItems = []*OrderItem{dune, nil}
+
+	event, err := NewOrderPlaced(*order)
+
+	assert.Nil(t, err)
+	assert.Equal(t, EVENT_ORDER_PLACED, event.Type)
+	assert.Equal(t, order.ID, event.AggregateID)
+	assert.NotEqual(t, uuid.Nil, event.ID)
+	var payload OrderPlaced
+	assert.Nil(t, json.Unmarshal(event.Payload, &payload))
+	assert.Equal(t, order.AccountID, payload.AccountID)
+	assert.Equal(t, NewMoney(2500, EUR), payload.Total)
+	assert.Equal(t, []OrderPlacedItem{{ItemID: dune.ItemID, Title: "Dune", Quantity: 2, UnitPrice: NewMoney(1250, EUR)}}, payload.Items)
+}
+
+func TestNewAccountCreated(t *testing.T) {
+	account := NewAccountBuilder().Email("john@smith.com").PasswordHash("hash").Build()
+
+	event, err := NewAccountCreated(*account)
+
+	assert.Nil(t, err)
+	assert.Equal(t, EVENT_ACCOUNT_CREATED, event.Type)
+	assert.Equal(t, account.ID, event.AggregateID)
+	assert.NotContains(t, string(event.Payload), "hash")
+	var payload AccountCreated
+	assert.Nil(t, json.Unmarshal(event.Payload, &payload))
+	assert.Equal(t, "john@smith.com", payload.Email)
+}
+
+func TestEventRetries(t *testing.T) {
+	now := time.Now()
+
+	t.Run("should lease claimed event", func(t *testing.T) {
+		event := Event{}
+		event.Claim(now)
+		assert.Equal(t, 1, event.Attempts)
+		assert.Equal(t, now.Add(EventClaimTimeout), event.NextAttemptAt)
+	})
+
+	t.Run("should schedule retry of failed event", func(t *testing.T) {
+		event := Event{}
+		event.Claim(now)
+		event.Failed("connection refused", now)
+		assert.Equal(t, "connection refused", event.LastError)
+		assert.Equal(t, now.Add(EventRetryDelay), event.NextAttemptAt)
+		assert.False(t, event.Parked())
+	})
+
+	t.Run("should park event after max attempts", func(t *testing.T) {
+		event := Event{Attempts: EventMaxAttempts - 1}
+		event.Claim(now)
+		event.Failed("connection refused", now)
+		assert.True(t, event.Parked())
+		assert.True(t, event.NextAttemptAt.IsZero())
+	})
+
+	t.Run("should clear retry state of published event", func(t *testing.T) {
+		event := Event{Attempts: 2, LastError: "connection refused"}
+		event.Claim(now)
+		event.Published(now)
+		assert.Equal(t, now, event.PublishedAt)
+		assert.Equal(t, 3, event.Attempts)
+		assert.Empty(t, event.LastError)
+		assert.True(t, event.NextAttemptAt.IsZero())
+	})
+
+	t.Run("should double retry delay up to max", func(t *testing.T) {
+		assert.Equal(t, 5*time.Second, EventRetryAfter(1))
+		assert.Equal(t, 40*time.Second, EventRetryAfter(4))
+		assert.Equal(t, EventMaxRetryDelay, EventRetryAfter(100))
+	})
+}
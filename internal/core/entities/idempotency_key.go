@@ -0,0 +1,31 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+// IdempotencyKey records a request made with an idempotency key, so retries of the request are answered
+// with the response of the first request instead of being processed again. Requests are told apart by
+// their fingerprint, the key can not be used with a different request until it expires.
+// Keys are chosen by clients, so every account has keys of its own; anonymous callers share the nil account.
+type IdempotencyKey struct {
+	bun.BaseModel `bun:"table:idempotency_keys,alias:ik"`
+
+	AccountID   uuid.UUID `bun:"account_id,pk,type:uuid"`
+	Key         string    `bun:"key,pk"`
+	Fingerprint string    `bun:"fingerprint,notnull"`
+	CreatedAt   time.Time `bun:"created_at,notnull,default:current_timestamp"`
+	ExpiresAt   time.Time `bun:"expires_at,notnull"`
+
+	// Response of the request, it is stored once the request succeeded.
+	StatusCode  int    `bun:"status_code,nullzero"`
+	ContentType string `bun:"content_type,nullzero"`
+	Body        []byte `bun:"body,type:bytea"`
+}
+
+// Completed reports whether the request made with the key is done and its response is stored.
+func (k IdempotencyKey) Completed() bool {
+	return k.StatusCode != 0
+}
@@ -1,6 +1,7 @@
 package entities
 
 import (
+	"fmt"
 	"github.com/google/uuid"
 	"github.com/uptrace/bun"
 	"time"
@@ -11,17 +12,36 @@ type Item struct {
 	bun.BaseModel `bun:"table:items,alias:i"`
 
 	Entity
-	Title       string  `bun:"title,nullzero"`
-	Description string  `bun:"description,nullzero"`
-	Price       float32 `bun:"price,nullzero"`
+	Title       string `bun:"title,nullzero"`
+	Description string `bun:"description,nullzero"`
+	Price       Money  `bun:"embed:price_"`
+	Stock       int    `bun:"stock,notnull"`
+
+	// Book details, all of them are optional.
+	ISBN            string     `bun:"isbn,nullzero"`
+	PublisherID     *uuid.UUID `bun:"publisher_id,type:uuid"`
+	Publisher       *Publisher `bun:"rel:belongs-to,join:publisher_id=id"`
+	Edition         string     `bun:"edition,nullzero"`
+	PublicationDate time.Time  `bun:"publication_date,type:date,nullzero"`
+	PageCount       int        `bun:"page_count,nullzero"`
+	Language        string     `bun:"language,nullzero"`
+	Authors         []*Author  `bun:"m2m:item_authors,join:Item=Author"`
 
 	OrderItems []*OrderItem `bun:"rel:has-many,join:id=item_id"`
 }
 
 type ItemBuilder struct {
-	title       string
-	description string
-	price       float32
+	title           string
+	description     string
+	price           Money
+	stock           int
+	isbn            string
+	publisherID     *uuid.UUID
+	edition         string
+	publicationDate time.Time
+	pageCount       int
+	language        string
+	authorIDs       []uuid.UUID
 }
 
 func NewItemBuilder() *ItemBuilder {
@@ -41,17 +61,111 @@ func (b *ItemBuilder) Description(description string) *ItemBuilder {
 }
 
 // Price sets the price on the Builder.
-func (b *ItemBuilder) Price(price float32) *ItemBuilder {
+func (b *ItemBuilder) Price(price Money) *ItemBuilder {
 	b.price = price
 	return b
 }
 
-// Build creates a new Item entity.
+// Stock sets the stock on the Builder.
+func (b *ItemBuilder) Stock(stock int) *ItemBuilder {
+	b.stock = stock
+	return b
+}
+
+// ISBN sets the ISBN-13 on the Builder.
+func (b *ItemBuilder) ISBN(isbn string) *ItemBuilder {
+	b.isbn = isbn
+	return b
+}
+
+// Publisher sets the publisher on the Builder.
+func (b *ItemBuilder) Publisher(publisherID uuid.UUID) *ItemBuilder {
+	b.publisherID = &publisherID
+	return b
+}
+
+// Edition sets the edition on the Builder.
+func (b *ItemBuilder) Edition(edition string) *ItemBuilder {
+	b.edition = edition
+	return b
+}
+
+// PublicationDate sets the publication date on the Builder.
+func (b *ItemBuilder) PublicationDate(date time.Time) *ItemBuilder {
+	b.publicationDate = date
+	return b
+}
+
+// PageCount sets the page count on the Builder.
+func (b *ItemBuilder) PageCount(pageCount int) *ItemBuilder {
+	b.pageCount = pageCount
+	return b
+}
+
+// Language sets the BCP 47 language tag on the Builder.
+func (b *ItemBuilder) Language(language string) *ItemBuilder {
+	b.language = language
+	return b
+}
+
+// Authors sets the authors on the Builder, in the order they are credited.
+func (b *ItemBuilder) Authors(authorIDs ...uuid.UUID) *ItemBuilder {
+	b.authorIDs = authorIDs
+	return b
+}
+
+// Build creates a new Item entity. Its authors only carry their ids.
 func (b *ItemBuilder) Build() *Item {
-	return &Item{
-		Entity:      Entity{ID: uuid.New(), CreatedAt: time.Now(), UpdatedAt: time.Now()},
-		Title:       b.title,
-		Description: b.description,
-		Price:       b.price,
+	item := &Item{
+		Entity:          Entity{ID: uuid.New(), CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		Title:           b.title,
+		Description:     b.description,
+		Price:           b.price,
+		Stock:           b.stock,
+		ISBN:            b.isbn,
+		PublisherID:     b.publisherID,
+		Edition:         b.edition,
+		PublicationDate: b.publicationDate,
+		PageCount:       b.pageCount,
+		Language:        b.language,
 	}
+	for _, id := range b.authorIDs {
+		item.Authors = append(item.Authors, &Author{Entity: Entity{ID: id}})
+	}
+	return item
+}
+
+var (
+	ErrorInsufficientStock = NewError(ErrorConflict, "insufficient stock")
+	ErrorItemInUse         = NewError(ErrorConflict, "item is referenced by orders")
+	ErrorSearchPaging      = NewError(ErrorValidation, "search results are ranked and can only be paged by offset")
+	ErrorIsbnNotUnique     = NewError(ErrorConflict, "ISBN is not unique")
+	ErrorItemNotFound      = NewError(ErrorNotFound, "item not found")
+)
+
+// InsufficientStockError is returned when an item does not have enough units in stock to fulfill an order.
+type InsufficientStockError struct {
+	ItemID    uuid.UUID
+	Requested int
+	Available int
+}
+
+func (e InsufficientStockError) Error() string {
+	return fmt.Sprintf("insufficient stock of item %s: requested %d, available %d", e.ItemID, e.Requested, e.Available)
+}
+
+// Is makes InsufficientStockError match ErrorInsufficientStock and its kind.
+func (e InsufficientStockError) Is(target error) bool {
+	return target == ErrorInsufficientStock || target == ErrorConflict
+}
+
+// ItemMatch is an item found by full-text search, with its rank and the matched terms highlighted.
+type ItemMatch struct {
+	bun.BaseModel `bun:"table:items,alias:i"`
+
+	Item
+
+	Rank                 float64 `bun:"rank,scanonly"`
+	TitleHighlight       string  `bun:"title_highlight,scanonly"`
+	DescriptionHighlight string  `bun:"description_highlight,scanonly"`
 }
@@ -0,0 +1,224 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrorUnknownCurrency    = NewError(ErrorValidation, "unknown currency")
+	ErrorCurrencyMismatch   = NewError(ErrorValidation, "currency mismatch")
+	ErrorInvalidMoneyAmount = NewError(ErrorValidation, "invalid money amount")
+)
+
+// Currency is an ISO-4217 currency code.
+type Currency string
+
+const (
+	EUR Currency = "EUR"
+	USD Currency = "USD"
+	GBP Currency = "GBP"
+	CHF Currency = "CHF"
+	JPY Currency = "JPY"
+	KWD Currency = "KWD"
+)
+
+// DefaultCurrency is the currency used by the store when none is specified.
+const DefaultCurrency = EUR
+
+// currencyExponents maps supported currencies to their ISO-4217 minor unit exponent.
+var currencyExponents = map[Currency]int{
+	EUR: 2,
+	USD: 2,
+	GBP: 2,
+	CHF: 2,
+	JPY: 0,
+	KWD: 3,
+}
+
+// ParseCurrency returns the Currency for the given ISO-4217 code.
+func ParseCurrency(code string) (Currency, error) {
+	c := Currency(strings.ToUpper(strings.TrimSpace(code)))
+	if !c.IsValid() {
+		return "", fmt.Errorf("%w: %q", ErrorUnknownCurrency, code)
+	}
+	return c, nil
+}
+
+// IsValid reports whether the currency is supported.
+func (c Currency) IsValid() bool {
+	_, ok := currencyExponents[c]
+	return ok
+}
+
+// Exponent returns the number of minor unit digits of the currency.
+func (c Currency) Exponent() int {
+	return currencyExponents[c]
+}
+
+// Scan implements sql.Scanner.
+func (c *Currency) Scan(src any) error {
+	var code string
+	switch v := src.(type) {
+	case string:
+		code = v
+	case []byte:
+		code = string(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Currency", src)
+	}
+	parsed, err := ParseCurrency(code)
+	if err != nil {
+		return err
+	}
+	*c = parsed
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (c Currency) Value() (driver.Value, error) {
+	if !c.IsValid() {
+		return nil, fmt.Errorf("%w: %q", ErrorUnknownCurrency, string(c))
+	}
+	return string(c), nil
+}
+
+// Money is a monetary amount stored in minor units (e.g. cents) of its currency,
+// so that calculations are exact. Embed it into entities with the bun embed tag,
+// e.g. `bun:"embed:price_"`, which maps it onto price_amount and price_currency columns.
+type Money struct {
+	Amount   int64    `bun:"amount,notnull"`
+	Currency Currency `bun:"currency,notnull"`
+}
+
+// NewMoney creates Money from amount in minor units of the currency.
+func NewMoney(amount int64, currency Currency) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Zero creates Money with zero amount in the currency.
+func Zero(currency Currency) Money {
+	return Money{Currency: currency}
+}
+
+// ParseMoney creates Money from a decimal string amount such as "12.99".
+// Amounts with more fraction digits than the currency allows are rejected.
+func ParseMoney(amount string, currency Currency) (Money, error) {
+	if !currency.IsValid() {
+		return Money{}, fmt.Errorf("%w: %q", ErrorUnknownCurrency, string(currency))
+	}
+
+	s := strings.TrimSpace(amount)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+
+	whole, fraction, _ := strings.Cut(s, ".")
+	exp := currency.Exponent()
+	if whole == "" || len(fraction) > exp || !isDigits(whole) || !isDigits(fraction) {
+		return Money{}, fmt.Errorf("%w: %q", ErrorInvalidMoneyAmount, amount)
+	}
+
+	minor, err := strconv.ParseInt(whole+fraction+strings.Repeat("0", exp-len(fraction)), 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q", ErrorInvalidMoneyAmount, amount)
+	}
+	if negative {
+		minor = -minor
+	}
+	return Money{Amount: minor, Currency: currency}, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Add returns the sum of m and other. Both must be in the same currency.
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrorCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+// Sub returns the difference of m and other. Both must be in the same currency.
+func (m Money) Sub(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrorCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return Money{Amount: m.Amount - other.Amount, Currency: m.Currency}, nil
+}
+
+// Mul returns m multiplied by the quantity.
+func (m Money) Mul(quantity int64) Money {
+	return Money{Amount: m.Amount * quantity, Currency: m.Currency}
+}
+
+// IsZero reports whether the amount is zero.
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// IsNegative reports whether the amount is below zero.
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// Decimal returns the amount formatted as a decimal string with the currency's minor unit digits, e.g. "12.99".
+func (m Money) Decimal() string {
+	exp := m.Currency.Exponent()
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	digits := strconv.FormatInt(amount, 10)
+	if exp == 0 {
+		return sign + digits
+	}
+	if len(digits) <= exp {
+		digits = strings.Repeat("0", exp-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-exp] + "." + digits[len(digits)-exp:]
+}
+
+// String returns the amount followed by the currency code, e.g. "12.99 EUR".
+func (m Money) String() string {
+	return m.Decimal() + " " + string(m.Currency)
+}
+
+type moneyJSON struct {
+	Amount   string   `json:"amount"`
+	Currency Currency `json:"currency"`
+}
+
+// MarshalJSON encodes Money as {"amount":"12.99","currency":"EUR"}.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{Amount: m.Decimal(), Currency: m.Currency})
+}
+
+// UnmarshalJSON decodes Money from {"amount":"12.99","currency":"EUR"}.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var v moneyJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	currency, err := ParseCurrency(string(v.Currency))
+	if err != nil {
+		return err
+	}
+	parsed, err := ParseMoney(v.Amount, currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
@@ -0,0 +1,106 @@
+package entities
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   string
+		currency Currency
+		want     Money
+		wantErr  error
+	}{
+		{name: "given whole amount should return minor units", amount: "12", currency: EUR, want: NewMoney(1200, EUR)},
+		{name: "given decimal amount should return minor units", amount: "12.99", currency: EUR, want: NewMoney(1299, EUR)},
+		{name: "given single fraction digit should pad minor units", amount: "0.5", currency: USD, want: NewMoney(50, USD)},
+		{name: "given negative amount should return negative minor units", amount: "-1.05", currency: EUR, want: NewMoney(-105, EUR)},
+		{name: "given zero exponent currency should return whole units", amount: "500", currency: JPY, want: NewMoney(500, JPY)},
+		{name: "given three digit exponent currency should return minor units", amount: "1.234", currency: KWD, want: NewMoney(1234, KWD)},
+		{name: "given too many fraction digits should return error", amount: "1.999", currency: EUR, wantErr: ErrorInvalidMoneyAmount},
+		{name: "given fraction for zero exponent currency should return error", amount: "1.5", currency: JPY, wantErr: ErrorInvalidMoneyAmount},
+		{name: "given non numeric amount should return error", amount: "12,99", currency: EUR, wantErr: ErrorInvalidMoneyAmount},
+		{name: "given empty amount should return error", amount: "", currency: EUR, wantErr: ErrorInvalidMoneyAmount},
+		{name: "given unknown currency should return error", amount: "1", currency: "XXX", wantErr: ErrorUnknownCurrency},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMoney(tt.amount, tt.currency)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	t.Run("add money in the same currency should sum amounts exactly", func(t *testing.T) {
+		sum := Zero(EUR)
+		for i := 0; i < 10; i++ {
+			var err error
+			sum, err = sum.Add(NewMoney(10, EUR))
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, NewMoney(100, EUR), sum)
+	})
+
+	t.Run("sub money in the same currency should subtract amounts", func(t *testing.T) {
+		got, err := NewMoney(1000, EUR).Sub(NewMoney(1299, EUR))
+		assert.NoError(t, err)
+		assert.Equal(t, NewMoney(-299, EUR), got)
+		assert.True(t, got.IsNegative())
+	})
+
+	t.Run("add money in different currencies should return error", func(t *testing.T) {
+		_, err := NewMoney(100, EUR).Add(NewMoney(100, USD))
+		assert.ErrorIs(t, err, ErrorCurrencyMismatch)
+	})
+
+	t.Run("mul money should multiply amount by quantity", func(t *testing.T) {
+		assert.Equal(t, NewMoney(2997, EUR), NewMoney(999, EUR).Mul(3))
+	})
+}
+
+func TestMoneyFormat(t *testing.T) {
+	assert.Equal(t, "12.99 EUR", NewMoney(1299, EUR).String())
+	assert.Equal(t, "0.05", NewMoney(5, EUR).Decimal())
+	assert.Equal(t, "-0.05", NewMoney(-5, EUR).Decimal())
+	assert.Equal(t, "500", NewMoney(500, JPY).Decimal())
+	assert.Equal(t, "0.001", NewMoney(1, KWD).Decimal())
+}
+
+func TestMoneyJSON(t *testing.T) {
+	t.Run("marshal should encode amount as decimal string", func(t *testing.T) {
+		b, err := json.Marshal(NewMoney(1299, EUR))
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"amount":"12.99","currency":"EUR"}`, string(b))
+	})
+
+	t.Run("unmarshal should decode amount into minor units", func(t *testing.T) {
+		var m Money
+		err := json.Unmarshal([]byte(`{"amount":"7.50","currency":"usd"}`), &m)
+		assert.NoError(t, err)
+		assert.Equal(t, NewMoney(750, USD), m)
+	})
+
+	t.Run("unmarshal unknown currency should return error", func(t *testing.T) {
+		var m Money
+		err := json.Unmarshal([]byte(`{"amount":"7.50","currency":"ABC"}`), &m)
+		assert.ErrorIs(t, err, ErrorUnknownCurrency)
+	})
+}
+
+func TestCurrencyScan(t *testing.T) {
+	var c Currency
+	assert.NoError(t, c.Scan([]byte("EUR")))
+	assert.Equal(t, EUR, c)
+	assert.ErrorIs(t, c.Scan("ABC"), ErrorUnknownCurrency)
+	assert.Error(t, c.Scan(42))
+}
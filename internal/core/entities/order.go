@@ -12,6 +12,11 @@ type Order struct {
 
 	Entity
 
+	Status OrderStatus `bun:"status,notnull,default:'pending'"`
+
+	// CancellationReason tells why the order was cancelled.
+	CancellationReason string `bun:"cancellation_reason,nullzero"`
+
 	// many-to-one relation
 	AccountID uuid.UUID `bun:"account_id,notnull"`
 	Account   Account   `bun:"rel:belongs-to,join:account_id=id"`
@@ -19,6 +24,38 @@ type Order struct {
 	OrderItems []*OrderItem `bun:"rel:has-many,join:id=order_id"`
 }
 
+// Currency returns the currency of the order, that is the currency of its order items.
+func (o Order) Currency() Currency {
+	for _, item := range o.OrderItems {
+		if item != nil && item.UnitPrice.Currency != "" {
+			return item.UnitPrice.Currency
+		}
+	}
+	return DefaultCurrency
+}
+
+// Subtotal returns the sum of all order line totals.
+// It returns ErrorCurrencyMismatch if order items are priced in different currencies.
+func (o Order) Subtotal() (Money, error) {
+	subtotal := Zero(o.Currency())
+	for _, item := range o.OrderItems {
+		if item == nil {
+			continue
+		}
+		var err error
+		if subtotal, err = subtotal.Add(item.LineTotal()); err != nil {
+			return Money{}, err
+		}
+	}
+	return subtotal, nil
+}
+
+// Total returns the grand total payable for the order.
+// As long as no shipping, tax or discounts are applied to orders, it equals the subtotal.
+func (o Order) Total() (Money, error) {
+	return o.Subtotal()
+}
+
 // OrderBuilder is a builder pattern for creating new Order entities.
 type OrderBuilder struct {
 	accountID  uuid.UUID
@@ -47,6 +84,7 @@ func (b *OrderBuilder) OrderItems(orderItems []*OrderItem) *OrderBuilder {
 func (b *OrderBuilder) Build() *Order {
 	return &Order{
 		Entity:     Entity{ID: uuid.New(), CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		Status:     ORDER_PENDING,
 		AccountID:  b.accountID,
 		Account:    b.account,
 		OrderItems: b.orderItems,
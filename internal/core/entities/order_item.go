@@ -8,6 +8,8 @@ import (
 
 // OrderItem handles the many-to-many relationship between orders and items,
 // recording which items are in which orders and in what quantities.
+// Title and unit price of the item are captured when the order is placed,
+// so later changes of the item do not change the value of the order.
 type OrderItem struct {
 	bun.BaseModel `bun:"table:order_items,alias:oi"`
 
@@ -19,7 +21,14 @@ type OrderItem struct {
 	ItemID uuid.UUID `bun:"item_id,notnull"`
 	Item   *Item     `bun:"rel:belongs-to,join:item_id=id"`
 
-	Quantity int `bun:"quantity,notnull"`
+	Quantity  int    `bun:"quantity,notnull"`
+	Title     string `bun:"title,notnull"`
+	UnitPrice Money  `bun:"embed:unit_price_"`
+}
+
+// LineTotal returns the value of the order line, that is unit price multiplied by quantity.
+func (oi OrderItem) LineTotal() Money {
+	return oi.UnitPrice.Mul(int64(oi.Quantity))
 }
 
 type OrderItemBuilder struct {
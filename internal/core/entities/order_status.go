@@ -0,0 +1,95 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+var (
+	ErrorInvalidOrderTransition = NewError(ErrorConflict, "invalid order status transition")
+	ErrorOrderStatusChanged     = NewError(ErrorConflict, "order status has been changed in the meantime")
+	ErrorOrderNotCancellable    = NewError(ErrorConflict, "order can not be cancelled after it has been shipped")
+)
+
+// OrderStatus represents a stage of the order lifecycle.
+type OrderStatus string
+
+const (
+	ORDER_PENDING   OrderStatus = "pending"
+	ORDER_PAID      OrderStatus = "paid"
+	ORDER_SHIPPED   OrderStatus = "shipped"
+	ORDER_DELIVERED OrderStatus = "delivered"
+	ORDER_CANCELLED OrderStatus = "cancelled"
+	ORDER_REFUNDED  OrderStatus = "refunded"
+)
+
+// orderTransitions defines the order lifecycle as statuses reachable from each status.
+// Statuses without outgoing transitions are terminal.
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	ORDER_PENDING:   {ORDER_PAID, ORDER_CANCELLED},
+	ORDER_PAID:      {ORDER_SHIPPED, ORDER_CANCELLED, ORDER_REFUNDED},
+	ORDER_SHIPPED:   {ORDER_DELIVERED},
+	ORDER_DELIVERED: {ORDER_REFUNDED},
+}
+
+// IsCancellable reports whether an order in status s can still be cancelled, that is before it is shipped.
+func (s OrderStatus) IsCancellable() bool {
+	return s.CanTransitionTo(ORDER_CANCELLED)
+}
+
+// CanTransitionTo reports whether the order lifecycle allows moving from s to next status.
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range orderTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTerminal reports whether s is a final stage of the order lifecycle.
+func (s OrderStatus) IsTerminal() bool {
+	return len(orderTransitions[s]) == 0
+}
+
+// OrderHistory records a single status transition of an order, who made it and when.
+type OrderHistory struct {
+	bun.BaseModel `bun:"table:order_history,alias:oh"`
+
+	Entity
+
+	OrderID    uuid.UUID     `bun:"order_id,notnull"`
+	FromStatus OrderStatus   `bun:"from_status,notnull"`
+	ToStatus   OrderStatus   `bun:"to_status,notnull"`
+	ActorID    uuid.NullUUID `bun:"actor_id,type:uuid"`
+	Note       string        `bun:"note,nullzero"`
+}
+
+// TransitionTo moves the order into the next status and returns the history record of the transition.
+// The actor is the account that made the transition. The note of a cancellation is kept as its reason.
+func (o *Order) TransitionTo(next OrderStatus, actorId uuid.UUID, note string) (*OrderHistory, error) {
+	if !o.Status.CanTransitionTo(next) {
+		return nil, fmt.Errorf("%w: from %s to %s", ErrorInvalidOrderTransition, o.Status, next)
+	}
+
+	now := time.Now()
+	change := &OrderHistory{
+		Entity:     Entity{ID: uuid.New(), CreatedAt: now, UpdatedAt: now},
+		OrderID:    o.ID,
+		FromStatus: o.Status,
+		ToStatus:   next,
+		ActorID:    uuid.NullUUID{UUID: actorId, Valid: actorId != uuid.Nil},
+		Note:       note,
+	}
+
+	o.Status = next
+	o.UpdatedAt = now
+	if next == ORDER_CANCELLED {
+		o.CancellationReason = note
+	}
+
+	return change, nil
+}
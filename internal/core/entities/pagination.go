@@ -1,6 +1,24 @@
 package entities
 
-import "fmt"
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrorInvalidCursor      = NewError(ErrorValidation, "invalid cursor")
+	ErrorInvalidPageRequest = NewError(ErrorValidation, "invalid page request")
+)
+
+const (
+	// DefaultPageSize is the size of pages requested without size.
+	DefaultPageSize = 10
+	// MaxPageSize caps the size of requested pages.
+	MaxPageSize = 100
+)
 
 // Direction can be ASC, DESC, ASC_NULLS_FIRST, DESC_NULLS_FIRST, ASC_NULLS_LAST or DESC_NULLS_LAST.
 type Direction string
@@ -14,6 +32,25 @@ const (
 	DESC_NULLS_LAST  Direction = "DESC NULLS LAST"
 )
 
+// directions lists all supported directions.
+var directions = []Direction{ASC, DESC, ASC_NULLS_FIRST, DESC_NULLS_FIRST, ASC_NULLS_LAST, DESC_NULLS_LAST}
+
+// ParseDirection returns the Direction matching the string regardless of its case and spacing, e.g. "asc nulls last".
+func ParseDirection(s string) (Direction, error) {
+	d := Direction(strings.ToUpper(strings.Join(strings.Fields(s), " ")))
+	for _, allowed := range directions {
+		if d == allowed {
+			return d, nil
+		}
+	}
+	allowed := make([]string, len(directions))
+	for i, d := range directions {
+		allowed[i] = string(d)
+	}
+	return "", NewError(ErrorValidation,
+		fmt.Sprintf("invalid sort direction %q, allowed directions are: %s", s, strings.Join(allowed, ", ")))
+}
+
 // SortOrder represent single sort instruction.
 type SortOrder struct {
 	Property  string
@@ -59,13 +96,122 @@ type Pageable struct {
 	Size   int
 	Offset int
 	Sort   Sort
+	// Keyset switches from offset to cursor pagination, which seeks the page by the sort keys instead of skipping
+	// Offset elements. Cursor is empty for the first page and one of the cursors of the previous page afterwards.
+	Keyset bool
+	Cursor string
+	// SkipCount skips counting of all elements, leaving TotalElements and TotalPages of the page unset.
+	SkipCount bool
+}
+
+// Cursor points at an element of the listing a keyset page starts after.
+// Clients see it only as an opaque string created by Encode.
+type Cursor struct {
+	// Sort is the signature of the sort orders the cursor was created with.
+	Sort string `json:"s"`
+	// Keys are values of the sort keys of the element, the last one is its id.
+	Keys []string `json:"k"`
+	// Backward marks cursors of previous pages.
+	Backward bool `json:"b,omitempty"`
+}
+
+// Encode returns the cursor as an opaque URL safe string.
+func (c Cursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// ParseCursor decodes the cursor created by Cursor.Encode.
+func ParseCursor(s string) (Cursor, error) {
+	var c Cursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, ErrorInvalidCursor
+	}
+	if err := json.Unmarshal(b, &c); err != nil || len(c.Keys) == 0 {
+		return c, ErrorInvalidCursor
+	}
+	return c, nil
+}
+
+// Sortable declares the properties a resource can be sorted by, mapping their API names to database columns.
+// API names are snake_case and each property has exactly one.
+type Sortable map[string]string
+
+var (
+	// ItemSortable lists the properties items can be sorted by.
+	ItemSortable = Sortable{
+		"title":      "title",
+		"price":      "price_amount",
+		"stock":      "stock",
+		"created_at": "created_at",
+		"updated_at": "updated_at",
+	}
+
+	// AuthorSortable lists the properties authors can be sorted by.
+	AuthorSortable = Sortable{
+		"name":       "name",
+		"created_at": "created_at",
+		"updated_at": "updated_at",
+	}
+
+	// PublisherSortable lists the properties publishers can be sorted by.
+	PublisherSortable = Sortable{
+		"name":       "name",
+		"created_at": "created_at",
+		"updated_at": "updated_at",
+	}
+
+	// OrderSortable lists the properties orders can be sorted by.
+	OrderSortable = Sortable{
+		"status":     "status",
+		"created_at": "created_at",
+		"updated_at": "updated_at",
+	}
+
+	// WebhookSubscriptionSortable lists the properties webhook subscriptions can be sorted by.
+	WebhookSubscriptionSortable = Sortable{
+		"url":        "url",
+		"created_at": "created_at",
+		"updated_at": "updated_at",
+	}
+
+	// WebhookDeliverySortable lists the properties webhook deliveries can be sorted by.
+	WebhookDeliverySortable = Sortable{
+		"status":     "status",
+		"attempts":   "attempts",
+		"created_at": "created_at",
+		"updated_at": "updated_at",
+	}
+)
+
+// Column returns the column of the sortable property.
+func (s Sortable) Column(property string) (string, error) {
+	if column, ok := s[property]; ok {
+		return column, nil
+	}
+	return "", NewError(ErrorValidation,
+		fmt.Sprintf("cannot sort by %q, allowed properties are: %s", property, strings.Join(s.properties(), ", ")))
+}
+
+// Validate checks that all sort orders use sortable properties and supported directions.
+func (s Sortable) Validate(sorting Sort) error {
+	for _, o := range sorting.Orders {
+		if _, err := s.Column(o.Property); err != nil {
+			return err
+		}
+		if _, err := ParseDirection(string(o.Direction)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-// StringifyOrders travers sort orders into a slice of strings in the following of "prop1 ASC, prop2 DESC".
-func StringifyOrders(s Sort) []string {
-	var orders []string
-	for _, o := range s.Orders {
-		orders = append(orders, fmt.Sprintf("%s %s", o.Property, o.Direction))
+func (s Sortable) properties() []string {
+	props := make([]string, 0, len(s))
+	for p := range s {
+		props = append(props, p)
 	}
-	return orders
+	sort.Strings(props)
+	return props
 }
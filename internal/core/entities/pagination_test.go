@@ -0,0 +1,157 @@
+package entities
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDirection(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    Direction
+		wantErr bool
+	}{
+		{name: "given upper case direction should return it", value: "DESC", want: DESC},
+		{name: "given lower case direction should return it", value: "asc", want: ASC},
+		{name: "given direction with nulls ordering should return it", value: "desc  nulls last", want: DESC_NULLS_LAST},
+		{name: "given unknown direction should return error", value: "sideways", wantErr: true},
+		{name: "given sql fragment should return error", value: "ASC; DROP TABLE items", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDirection(tt.value)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrorValidation)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSortable(t *testing.T) {
+	t.Run("column should map property to its column", func(t *testing.T) {
+		column, err := ItemSortable.Column("price")
+		assert.NoError(t, err)
+		assert.Equal(t, "price_amount", column)
+	})
+
+	t.Run("validate should accept sortable properties", func(t *testing.T) {
+		sort := NewSort(NewSortOrder(WithProperty("title"), WithDirection(ASC)), NewSortOrder(WithProperty("stock")))
+		assert.NoError(t, ItemSortable.Validate(sort))
+	})
+
+	t.Run("validate should reject unknown property and list allowed ones", func(t *testing.T) {
+		err := OrderSortable.Validate(NewSort(NewSortOrder(WithProperty("account_id"))))
+		assert.ErrorIs(t, err, ErrorValidation)
+		assert.EqualError(t, err, `cannot sort by "account_id", allowed properties are: created_at, status, updated_at`)
+	})
+
+	t.Run("validate should reject names other than snake_case ones", func(t *testing.T) {
+		assert.ErrorIs(t, OrderSortable.Validate(NewSort(NewSortOrder(WithProperty("createdAt")))), ErrorValidation)
+		assert.ErrorIs(t, ItemSortable.Validate(NewSort(NewSortOrder(WithProperty("name")))), ErrorValidation)
+	})
+
+	t.Run("validate should reject unsupported direction", func(t *testing.T) {
+		err := ItemSortable.Validate(NewSort(NewSortOrder(WithProperty("stock"), WithDirection("DESC, 1"))))
+		assert.ErrorIs(t, err, ErrorValidation)
+	})
+}
+
+func TestCursor(t *testing.T) {
+	t.Run("parse should decode encoded cursor", func(t *testing.T) {
+		c := Cursor{Sort: "title ASC,id ASC", Keys: []string{"Dune", "200cea28-b2b0-4051-9eb6-9a99e451af01"}, Backward: true}
+		got, err := ParseCursor(c.Encode())
+		assert.NoError(t, err)
+		assert.Equal(t, c, got)
+	})
+
+	t.Run("parse should reject malformed cursor", func(t *testing.T) {
+		for _, s := range []string{"not base64!", "bm90IGpzb24", Cursor{Sort: "id ASC"}.Encode()} {
+			_, err := ParseCursor(s)
+			assert.ErrorIs(t, err, ErrorInvalidCursor)
+			assert.ErrorIs(t, err, ErrorValidation)
+		}
+	})
+}
+
+func TestCursorPage(t *testing.T) {
+	t.Run("given counted page should include total elements", func(t *testing.T) {
+		page := Page[int]{TotalElements: intPtr(3), Elements: []int{1, 2}, Cursors: &PageCursors{Next: "next", Counted: true}}
+		assert.Equal(t, CursorPage[int]{Elements: []int{1, 2}, Next: "next", TotalElements: intPtr(3)}, page.CursorPage())
+	})
+
+	t.Run("given page without count should leave total elements out", func(t *testing.T) {
+		page := Page[int]{Elements: []int{1}, Cursors: &PageCursors{Prev: "prev"}}
+		assert.Equal(t, CursorPage[int]{Elements: []int{1}, Prev: "prev"}, page.CursorPage())
+	})
+}
+
+func TestNewPage(t *testing.T) {
+	tests := []struct {
+		name    string
+		p       Pageable
+		total   int
+		hasNext bool
+		want    Page[int]
+	}{
+		{
+			name:    "given first page should count pages from total",
+			p:       Pageable{Size: 2},
+			total:   5,
+			hasNext: true,
+			want:    Page[int]{TotalPages: intPtr(3), TotalElements: intPtr(5), Size: 2, HasNext: true},
+		},
+		{
+			name:  "given last page should have previous page only",
+			p:     Pageable{Size: 2, Offset: 4},
+			total: 5,
+			want:  Page[int]{TotalPages: intPtr(3), TotalElements: intPtr(5), Number: 2, Size: 2, HasPrev: true},
+		},
+		{
+			name:  "given unlimited page should have single page",
+			p:     Pageable{},
+			total: 5,
+			want:  Page[int]{TotalPages: intPtr(1), TotalElements: intPtr(5)},
+		},
+		{
+			name:    "given skipped count should leave totals unset",
+			p:       Pageable{Size: 2, Offset: 2, SkipCount: true},
+			hasNext: true,
+			want:    Page[int]{Number: 1, Size: 2, HasNext: true, HasPrev: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NewPage[int](nil, tt.p, tt.total, tt.hasNext))
+		})
+	}
+}
+
+func TestMapPage(t *testing.T) {
+	page := Page[int]{TotalPages: intPtr(3), TotalElements: intPtr(5), Number: 1, Size: 2, HasNext: true, HasPrev: true, Elements: []int{3, 4}}
+	got := MapPage(page, func(i int) string { return string(rune('a' + i)) })
+	assert.Equal(t, Page[string]{TotalPages: intPtr(3), TotalElements: intPtr(5), Number: 1, Size: 2, HasNext: true, HasPrev: true, Elements: []string{"d", "e"}}, got)
+}
+
+func TestPageJSON(t *testing.T) {
+	t.Run("given counted page should include totals, even if zero", func(t *testing.T) {
+		b, err := json.Marshal(NewPage([]int{}, Pageable{Size: 2}, 0, false))
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"total_pages":0,"total_elements":0,"number":0,"size":2,"has_next":false,"has_prev":false,"elements":[]}`, string(b))
+	})
+
+	t.Run("given page without count should leave totals out", func(t *testing.T) {
+		b, err := json.Marshal(NewPage([]int{1}, Pageable{Size: 2, SkipCount: true}, 0, false))
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"number":0,"size":2,"has_next":false,"has_prev":false,"elements":[1]}`, string(b))
+	})
+}
+
+func intPtr(i int) *int {
+	return &i
+}
@@ -0,0 +1,235 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+var (
+	ErrorPaymentNotFound         = NewError(ErrorNotFound, "payment not found")
+	ErrorPaymentDeclined         = NewError(ErrorConflict, "payment has been declined")
+	ErrorPaymentRejected         = NewError(ErrorConflict, "payment operation has been rejected by the provider")
+	ErrorInvalidPaymentOperation = NewError(ErrorConflict, "payment operation is not allowed in the current payment status")
+	ErrorOrderPaymentExists      = NewError(ErrorConflict, "order is paid by another payment already")
+	ErrorOrderNotPayable         = NewError(ErrorConflict, "only pending orders can be paid")
+	ErrorOrderPaymentActive      = NewError(ErrorConflict, "order is paid by an active payment, void or refund the payment instead")
+	ErrorPaymentAmount           = NewError(ErrorValidation, "payment amount exceeds the available amount")
+	ErrorPaymentOperationPending = NewError(ErrorConflict, "another operation on the payment is in progress")
+)
+
+// PaymentStatus represents a stage of the payment lifecycle.
+type PaymentStatus string
+
+const (
+	// PAYMENT_AUTHORIZED payments have the amount reserved on the payment method of the customer.
+	PAYMENT_AUTHORIZED PaymentStatus = "authorized"
+	// PAYMENT_CAPTURED payments have the amount collected, it may be refunded partially.
+	PAYMENT_CAPTURED PaymentStatus = "captured"
+	// PAYMENT_VOIDED payments have been released without collecting anything.
+	PAYMENT_VOIDED PaymentStatus = "voided"
+	// PAYMENT_FAILED payments have been declined or failed at the provider.
+	PAYMENT_FAILED PaymentStatus = "failed"
+	// PAYMENT_REFUNDED payments have the whole captured amount refunded.
+	PAYMENT_REFUNDED PaymentStatus = "refunded"
+)
+
+// PaymentOperation is an operation on a payment carried out by the payment provider.
+type PaymentOperation string
+
+const (
+	PAYMENT_OPERATION_CAPTURE PaymentOperation = "capture"
+	PAYMENT_OPERATION_VOID    PaymentOperation = "void"
+	PAYMENT_OPERATION_REFUND  PaymentOperation = "refund"
+)
+
+// Payment records money moved through a payment provider for an order.
+// Captured and refunded amounts are kept in minor units of the currency of the payment.
+// An operation sent to the provider is pending until its outcome is known, see Begin.
+type Payment struct {
+	bun.BaseModel `bun:"table:payments,alias:pa"`
+
+	Entity
+
+	OrderID       uuid.UUID     `bun:"order_id,notnull"`
+	Provider      string        `bun:"provider,notnull"`
+	Reference     string        `bun:"reference,nullzero"`
+	Status        PaymentStatus `bun:"status,notnull"`
+	Amount        Money         `bun:"embed:amount_"`
+	Captured      int64         `bun:"captured_amount,notnull"`
+	Refunded      int64         `bun:"refunded_amount,notnull"`
+	FailureReason string        `bun:"failure_reason,nullzero"`
+
+	PendingOperation PaymentOperation `bun:"pending_operation,nullzero"`
+	PendingAmount    int64            `bun:"pending_amount,notnull"`
+	// PendingKey is the idempotency key the pending operation is sent to the provider with.
+	PendingKey string `bun:"pending_key,nullzero"`
+}
+
+// NewPayment creates a new payment of the amount for the order, which is authorized at the provider.
+func NewPayment(orderId uuid.UUID, provider string, amount Money) *Payment {
+	now := time.Now()
+	return &Payment{
+		Entity:   Entity{ID: uuid.New(), CreatedAt: now, UpdatedAt: now},
+		OrderID:  orderId,
+		Provider: provider,
+		Status:   PAYMENT_AUTHORIZED,
+		Amount:   amount,
+	}
+}
+
+// CapturedAmount returns the collected amount of the payment.
+func (p Payment) CapturedAmount() Money {
+	return NewMoney(p.Captured, p.Amount.Currency)
+}
+
+// RefundedAmount returns the refunded amount of the payment.
+func (p Payment) RefundedAmount() Money {
+	return NewMoney(p.Refunded, p.Amount.Currency)
+}
+
+// Refundable returns the captured amount of the payment which has not been refunded yet.
+func (p Payment) Refundable() Money {
+	return NewMoney(p.Captured-p.Refunded, p.Amount.Currency)
+}
+
+// IsActive reports whether the payment pays its order, that is it is authorized or captured.
+func (p Payment) IsActive() bool {
+	return p.Status == PAYMENT_AUTHORIZED || p.Status == PAYMENT_CAPTURED
+}
+
+// Pending returns the amount of the pending operation.
+func (p Payment) Pending() Money {
+	return NewMoney(p.PendingAmount, p.Amount.Currency)
+}
+
+// Begin records the operation of the amount as pending, if it can be applied to the payment.
+// The operation is applied by Complete, once the provider carried it out, or dropped by Abort.
+// Beginning the pending operation again resumes it, with the amount and the idempotency key it was begun with,
+// so the provider carries it out only once. Other operations are rejected until the pending one is settled.
+func (p *Payment) Begin(op PaymentOperation, amount Money) error {
+	if p.PendingOperation == op {
+		return nil
+	}
+	if p.PendingOperation != "" {
+		return fmt.Errorf("%w: %s is pending", ErrorPaymentOperationPending, p.PendingOperation)
+	}
+
+	applied := *p
+	if err := applied.apply(op, amount); err != nil {
+		return err
+	}
+	p.PendingOperation = op
+	p.PendingAmount = amount.Amount
+	p.PendingKey = uuid.NewString()
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+// Complete applies the pending operation begun with the key, once the provider carried it out.
+// It returns false if the operation has been settled already, e.g. by an event of the provider.
+func (p *Payment) Complete(key string) (bool, error) {
+	if p.PendingOperation == "" || p.PendingKey != key {
+		return false, nil
+	}
+	if err := p.apply(p.PendingOperation, p.Pending()); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Abort drops the pending operation begun with the key, once the provider rejected it.
+func (p *Payment) Abort(key string) {
+	if p.PendingOperation != "" && p.PendingKey == key {
+		p.settle()
+		p.UpdatedAt = time.Now()
+	}
+}
+
+func (p *Payment) apply(op PaymentOperation, amount Money) error {
+	switch op {
+	case PAYMENT_OPERATION_CAPTURE:
+		return p.Capture(amount)
+	case PAYMENT_OPERATION_VOID:
+		return p.Void()
+	case PAYMENT_OPERATION_REFUND:
+		return p.Refund(amount)
+	default:
+		return fmt.Errorf("%w: unknown operation %s", ErrorInvalidPaymentOperation, op)
+	}
+}
+
+// settle drops the pending operation, changes of the payment settle it as they are reported by the provider.
+func (p *Payment) settle() {
+	p.PendingOperation = ""
+	p.PendingAmount = 0
+	p.PendingKey = ""
+}
+
+// Capture collects the amount of the authorized payment, which must not exceed the authorized amount.
+func (p *Payment) Capture(amount Money) error {
+	if p.Status != PAYMENT_AUTHORIZED {
+		return fmt.Errorf("%w: can not capture %s payment", ErrorInvalidPaymentOperation, p.Status)
+	}
+	if err := p.checkAmount(amount, p.Amount.Amount); err != nil {
+		return err
+	}
+	p.Status = PAYMENT_CAPTURED
+	p.Captured = amount.Amount
+	p.settle()
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+// Void releases the authorized payment without collecting anything.
+func (p *Payment) Void() error {
+	if p.Status != PAYMENT_AUTHORIZED {
+		return fmt.Errorf("%w: can not void %s payment", ErrorInvalidPaymentOperation, p.Status)
+	}
+	p.Status = PAYMENT_VOIDED
+	p.settle()
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+// Fail marks the payment as declined or failed at the provider for the reason.
+func (p *Payment) Fail(reason string) error {
+	if p.Status != PAYMENT_AUTHORIZED {
+		return fmt.Errorf("%w: can not fail %s payment", ErrorInvalidPaymentOperation, p.Status)
+	}
+	p.Status = PAYMENT_FAILED
+	p.FailureReason = reason
+	p.settle()
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+// Refund gives back the amount of the captured payment, which must not exceed the amount not refunded yet.
+// The payment is refunded once the whole captured amount is refunded.
+func (p *Payment) Refund(amount Money) error {
+	if p.Status != PAYMENT_CAPTURED {
+		return fmt.Errorf("%w: can not refund %s payment", ErrorInvalidPaymentOperation, p.Status)
+	}
+	if err := p.checkAmount(amount, p.Captured-p.Refunded); err != nil {
+		return err
+	}
+	p.Refunded += amount.Amount
+	if p.Refunded == p.Captured {
+		p.Status = PAYMENT_REFUNDED
+	}
+	p.settle()
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+func (p Payment) checkAmount(amount Money, available int64) error {
+	if amount.Currency != p.Amount.Currency {
+		return fmt.Errorf("%w: %s and %s", ErrorCurrencyMismatch, amount.Currency, p.Amount.Currency)
+	}
+	if amount.Amount <= 0 || amount.Amount > available {
+		return fmt.Errorf("%w: %s of %s available", ErrorPaymentAmount, amount, NewMoney(available, p.Amount.Currency))
+	}
+	return nil
+}
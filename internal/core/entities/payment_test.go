@@ -0,0 +1,83 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaymentLifecycle(t *testing.T) {
+	amount := NewMoney(2000, EUR)
+
+	t.Run("should capture up to authorized amount and refund captured amount in parts", func(t *testing.T) {
+		p := NewPayment(uuid.New(), "fake", amount)
+
+		assert.ErrorIs(t, p.Capture(NewMoney(2001, EUR)), ErrorPaymentAmount)
+		assert.ErrorIs(t, p.Capture(NewMoney(1500, USD)), ErrorCurrencyMismatch)
+		assert.Nil(t, p.Capture(NewMoney(1500, EUR)))
+		assert.Equal(t, PAYMENT_CAPTURED, p.Status)
+
+		assert.Nil(t, p.Refund(NewMoney(1000, EUR)))
+		assert.Equal(t, PAYMENT_CAPTURED, p.Status)
+		assert.Equal(t, NewMoney(500, EUR), p.Refundable())
+		assert.ErrorIs(t, p.Refund(NewMoney(501, EUR)), ErrorPaymentAmount)
+		assert.Nil(t, p.Refund(NewMoney(500, EUR)))
+		assert.Equal(t, PAYMENT_REFUNDED, p.Status)
+		assert.False(t, p.IsActive())
+	})
+
+	t.Run("should only void or fail authorized payments", func(t *testing.T) {
+		p := NewPayment(uuid.New(), "fake", amount)
+		assert.True(t, p.IsActive())
+		assert.Nil(t, p.Void())
+		assert.ErrorIs(t, p.Capture(amount), ErrorInvalidPaymentOperation)
+		assert.ErrorIs(t, p.Fail("declined"), ErrorInvalidPaymentOperation)
+
+		p = NewPayment(uuid.New(), "fake", amount)
+		assert.Nil(t, p.Fail("declined"))
+		assert.Equal(t, "declined", p.FailureReason)
+		assert.ErrorIs(t, p.Refund(amount), ErrorInvalidPaymentOperation)
+	})
+
+	t.Run("should apply pending operation once it is completed with its key", func(t *testing.T) {
+		p := NewPayment(uuid.New(), "fake", amount)
+
+		assert.ErrorIs(t, p.Begin(PAYMENT_OPERATION_REFUND, amount), ErrorInvalidPaymentOperation)
+		assert.Nil(t, p.Begin(PAYMENT_OPERATION_CAPTURE, NewMoney(1500, EUR)))
+		key := p.PendingKey
+		assert.Equal(t, PAYMENT_AUTHORIZED, p.Status)
+		assert.ErrorIs(t, p.Begin(PAYMENT_OPERATION_VOID, amount), ErrorPaymentOperationPending)
+
+		// resumed with its amount and key
+		assert.Nil(t, p.Begin(PAYMENT_OPERATION_CAPTURE, amount))
+		assert.Equal(t, key, p.PendingKey)
+		assert.Equal(t, NewMoney(1500, EUR), p.Pending())
+
+		completed, err := p.Complete("other")
+		assert.Nil(t, err)
+		assert.False(t, completed)
+		completed, err = p.Complete(key)
+		assert.Nil(t, err)
+		assert.True(t, completed)
+		assert.Equal(t, PAYMENT_CAPTURED, p.Status)
+		assert.Equal(t, NewMoney(1500, EUR), p.CapturedAmount())
+		assert.Empty(t, p.PendingOperation)
+	})
+
+	t.Run("should drop pending operation aborted or settled by the provider", func(t *testing.T) {
+		p := NewPayment(uuid.New(), "fake", amount)
+		assert.Nil(t, p.Begin(PAYMENT_OPERATION_VOID, amount))
+		p.Abort(p.PendingKey)
+		assert.Empty(t, p.PendingOperation)
+		assert.Equal(t, PAYMENT_AUTHORIZED, p.Status)
+
+		assert.Nil(t, p.Begin(PAYMENT_OPERATION_CAPTURE, amount))
+		key := p.PendingKey
+		assert.Nil(t, p.Capture(amount))
+		completed, err := p.Complete(key)
+		assert.Nil(t, err)
+		assert.False(t, completed)
+		assert.Equal(t, amount, p.CapturedAmount())
+	})
+}
@@ -0,0 +1,43 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+var (
+	ErrorPublisherNotUnique = NewError(ErrorConflict, "publisher name is not unique")
+	ErrorPublisherNotFound  = NewError(ErrorNotFound, "publisher not found")
+	ErrorPublisherHasItems  = NewError(ErrorConflict, "publisher is referenced by items")
+)
+
+// Publisher published books of the store.
+type Publisher struct {
+	bun.BaseModel `bun:"table:publishers,alias:p"`
+
+	Entity
+	Name string `bun:"name,notnull"`
+}
+
+type PublisherBuilder struct {
+	name string
+}
+
+func NewPublisherBuilder() *PublisherBuilder {
+	return &PublisherBuilder{}
+}
+
+// Name sets the name on the Builder.
+func (b *PublisherBuilder) Name(name string) *PublisherBuilder {
+	b.name = name
+	return b
+}
+
+// Build creates a new Publisher entity.
+func (b *PublisherBuilder) Build() *Publisher {
+	return &Publisher{
+		Entity: Entity{ID: uuid.New(), CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		Name:   b.name,
+	}
+}
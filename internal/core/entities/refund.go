@@ -0,0 +1,157 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+var (
+	ErrorOrderNotRefundable    = NewError(ErrorConflict, "order can not be refunded in its current status")
+	ErrorOrderRefunded         = NewError(ErrorConflict, "order has been refunded already")
+	ErrorRefundItemNotFound    = NewError(ErrorValidation, "refunded item is not part of the order")
+	ErrorRefundExceedsQuantity = NewError(ErrorValidation, "refunded quantity exceeds the ordered quantity")
+)
+
+// refundableStatuses are the statuses of orders which have been paid for and not refunded yet.
+var refundableStatuses = []OrderStatus{ORDER_PAID, ORDER_SHIPPED, ORDER_DELIVERED}
+
+// Refund documents money given back for order items, who refunded them and why.
+// Refunds are valued at the prices the items were ordered for.
+type Refund struct {
+	bun.BaseModel `bun:"table:refunds,alias:r"`
+
+	Entity
+
+	OrderID uuid.UUID     `bun:"order_id,notnull"`
+	ActorID uuid.NullUUID `bun:"actor_id,type:uuid"`
+	Reason  string        `bun:"reason,nullzero"`
+	Amount  Money         `bun:"embed:amount_"`
+
+	Items []*RefundItem `bun:"rel:has-many,join:id=refund_id"`
+}
+
+// RefundItem records the refunded quantity of a single order item.
+type RefundItem struct {
+	bun.BaseModel `bun:"table:refund_items,alias:ri"`
+
+	Entity
+
+	RefundID    uuid.UUID `bun:"refund_id,notnull"`
+	OrderItemID uuid.UUID `bun:"order_item_id,notnull"`
+	Quantity    int       `bun:"quantity,notnull"`
+	Amount      Money     `bun:"embed:amount_"`
+}
+
+// RefundLine requests refunding a quantity of an order item.
+type RefundLine struct {
+	OrderItemID uuid.UUID
+	Quantity    int
+}
+
+// Refundable returns the quantities of the order items which have not been refunded by any of the refunds.
+func (o Order) Refundable(refunds []Refund) map[uuid.UUID]int {
+	quantities := make(map[uuid.UUID]int, len(o.OrderItems))
+	for _, oi := range o.OrderItems {
+		if oi != nil {
+			quantities[oi.ID] += oi.Quantity
+		}
+	}
+	for _, r := range refunds {
+		for _, ri := range r.Items {
+			if ri != nil {
+				quantities[ri.OrderItemID] -= ri.Quantity
+			}
+		}
+	}
+	return quantities
+}
+
+// Refund creates a refund of the lines, given the refunds made for the order before.
+// Without lines everything not refunded yet is refunded. Refunded quantities are capped at the ordered quantities.
+// If nothing is left to refund afterward, the order is moved into refunded status and the history record of the
+// transition is returned as well, provided the order lifecycle allows it.
+func (o *Order) Refund(refunds []Refund, lines []RefundLine, actorId uuid.UUID, reason string) (*Refund, *OrderHistory, error) {
+	if !o.isRefundable() {
+		return nil, nil, fmt.Errorf("%w: %s", ErrorOrderNotRefundable, o.Status)
+	}
+
+	refundable := o.Refundable(refunds)
+	if len(lines) == 0 {
+		for _, oi := range o.OrderItems {
+			if oi != nil && refundable[oi.ID] > 0 {
+				lines = append(lines, RefundLine{OrderItemID: oi.ID, Quantity: refundable[oi.ID]})
+			}
+		}
+		if len(lines) == 0 {
+			return nil, nil, ErrorOrderRefunded
+		}
+	}
+
+	now := time.Now()
+	refund := &Refund{
+		Entity:  Entity{ID: uuid.New(), CreatedAt: now, UpdatedAt: now},
+		OrderID: o.ID,
+		ActorID: uuid.NullUUID{UUID: actorId, Valid: actorId != uuid.Nil},
+		Reason:  reason,
+		Amount:  Zero(o.Currency()),
+	}
+	for _, line := range lines {
+		oi := o.orderItem(line.OrderItemID)
+		if oi == nil {
+			return nil, nil, fmt.Errorf("%w: %s", ErrorRefundItemNotFound, line.OrderItemID)
+		}
+		if line.Quantity < 1 || line.Quantity > refundable[oi.ID] {
+			return nil, nil, fmt.Errorf("%w: %d of %s, %d left to refund",
+				ErrorRefundExceedsQuantity, line.Quantity, oi.ID, refundable[oi.ID])
+		}
+		refundable[oi.ID] -= line.Quantity
+
+		amount := oi.UnitPrice.Mul(int64(line.Quantity))
+		var err error
+		if refund.Amount, err = refund.Amount.Add(amount); err != nil {
+			return nil, nil, err
+		}
+		refund.Items = append(refund.Items, &RefundItem{
+			Entity:      Entity{ID: uuid.New(), CreatedAt: now, UpdatedAt: now},
+			RefundID:    refund.ID,
+			OrderItemID: oi.ID,
+			Quantity:    line.Quantity,
+			Amount:      amount,
+		})
+	}
+
+	for _, left := range refundable {
+		if left > 0 {
+			return refund, nil, nil
+		}
+	}
+	if !o.Status.CanTransitionTo(ORDER_REFUNDED) {
+		return refund, nil, nil
+	}
+	change, err := o.TransitionTo(ORDER_REFUNDED, actorId, reason)
+	if err != nil {
+		return nil, nil, err
+	}
+	return refund, change, nil
+}
+
+func (o Order) isRefundable() bool {
+	for _, s := range refundableStatuses {
+		if o.Status == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (o Order) orderItem(id uuid.UUID) *OrderItem {
+	for _, oi := range o.OrderItems {
+		if oi != nil && oi.ID == id {
+			return oi
+		}
+	}
+	return nil
+}
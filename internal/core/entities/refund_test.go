@@ -0,0 +1,101 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderRefund(t *testing.T) {
+	actorId := uuid.New()
+	newOrder := func(status OrderStatus) *Order {
+		order := NewOrderBuilder().AccountID(uuid.New()).Build()
+		order.Status = status
+		dune := NewOrderItemBuilder().OrderID(order.ID).ItemID(uuid.New()).Quantity(2).Build()
+		dune.UnitPrice = NewMoney(1250, EUR)
+		messiah := NewOrderItemBuilder().OrderID(order.ID).ItemID(uuid.New()).Quantity(1).Build()
+		messiah.UnitPrice = NewMoney(900, EUR)
+		order.OrderItems = []*OrderItem{dune, messiah}
+		return order
+	}
+
+	t.Run("given no lines should refund everything and move order into refunded status", func(t *testing.T) {
+		order := newOrder(ORDER_PAID)
+
+		refund, change, err := order.Refund(nil, nil, actorId, "damaged")
+		assert.Nil(t, err)
+		assert.Len(t, refund.Items, 2)
+		assert.Equal(t, NewMoney(3400, EUR), refund.Amount)
+		assert.Equal(t, "damaged", refund.Reason)
+		assert.NotNil(t, change)
+		assert.Equal(t, ORDER_REFUNDED, order.Status)
+	})
+
+	t.Run("given lines should refund them and keep order status while items are left", func(t *testing.T) {
+		order := newOrder(ORDER_DELIVERED)
+		dune := order.OrderItems[0]
+
+		refund, change, err := order.Refund(nil, []RefundLine{{OrderItemID: dune.ID, Quantity: 1}}, actorId, "")
+		assert.Nil(t, err)
+		assert.Equal(t, NewMoney(1250, EUR), refund.Amount)
+		assert.Nil(t, change)
+		assert.Equal(t, ORDER_DELIVERED, order.Status)
+		assert.Equal(t, map[uuid.UUID]int{dune.ID: 1, order.OrderItems[1].ID: 1}, order.Refundable([]Refund{*refund}))
+	})
+
+	t.Run("should cap refunded quantities at ordered quantities minus previous refunds", func(t *testing.T) {
+		order := newOrder(ORDER_DELIVERED)
+		dune := order.OrderItems[0]
+		previous, _, err := order.Refund(nil, []RefundLine{{OrderItemID: dune.ID, Quantity: 1}}, actorId, "")
+		assert.Nil(t, err)
+
+		_, _, err = order.Refund([]Refund{*previous}, []RefundLine{{OrderItemID: dune.ID, Quantity: 2}}, actorId, "")
+		assert.ErrorIs(t, err, ErrorRefundExceedsQuantity)
+
+		_, _, err = order.Refund([]Refund{*previous}, []RefundLine{
+			{OrderItemID: dune.ID, Quantity: 1},
+			{OrderItemID: dune.ID, Quantity: 1},
+		}, actorId, "")
+		assert.ErrorIs(t, err, ErrorRefundExceedsQuantity)
+	})
+
+	t.Run("given item of another order should return error", func(t *testing.T) {
+		order := newOrder(ORDER_PAID)
+
+		_, _, err := order.Refund(nil, []RefundLine{{OrderItemID: uuid.New(), Quantity: 1}}, actorId, "")
+		assert.ErrorIs(t, err, ErrorRefundItemNotFound)
+	})
+
+	t.Run("given fully refunded order should return error", func(t *testing.T) {
+		order := newOrder(ORDER_SHIPPED)
+		refund, change, err := order.Refund(nil, nil, actorId, "")
+		assert.Nil(t, err)
+		// shipped orders are refunded, but only move into refunded status once delivered
+		assert.Nil(t, change)
+
+		_, _, err = order.Refund([]Refund{*refund}, nil, actorId, "")
+		assert.ErrorIs(t, err, ErrorOrderRefunded)
+	})
+
+	t.Run("given unpaid or cancelled order should return error", func(t *testing.T) {
+		for _, status := range []OrderStatus{ORDER_PENDING, ORDER_CANCELLED, ORDER_REFUNDED} {
+			_, _, err := newOrder(status).Refund(nil, nil, actorId, "")
+			assert.ErrorIs(t, err, ErrorOrderNotRefundable)
+		}
+	})
+}
+
+func TestOrderCancellation(t *testing.T) {
+	order := NewOrderBuilder().AccountID(uuid.New()).Build()
+
+	assert.True(t, order.Status.IsCancellable())
+	change, err := order.TransitionTo(ORDER_CANCELLED, uuid.New(), "changed my mind")
+	assert.Nil(t, err)
+	assert.Equal(t, "changed my mind", change.Note)
+	assert.Equal(t, "changed my mind", order.CancellationReason)
+
+	for _, status := range []OrderStatus{ORDER_SHIPPED, ORDER_DELIVERED, ORDER_CANCELLED} {
+		assert.False(t, status.IsCancellable())
+	}
+}
@@ -0,0 +1,131 @@
+package entities
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+	"slices"
+	"time"
+)
+
+var (
+	ErrorWebhookNotFound  = NewError(ErrorNotFound, "webhook subscription not found")
+	ErrorDeliveryNotFound = NewError(ErrorNotFound, "webhook delivery not found")
+)
+
+const (
+	// WebhookMaxAttempts is the number of failed attempts after which a delivery is given up on.
+	WebhookMaxAttempts = 8
+	// WebhookRetryDelay is the delay after the first failed attempt, it doubles with every further failed attempt.
+	WebhookRetryDelay = 30 * time.Second
+	// WebhookMaxRetryDelay caps the delay between attempts.
+	WebhookMaxRetryDelay = time.Hour
+)
+
+// WebhookSubscription subscribes the URL of a partner to events of the listed types.
+// Deliveries to the URL are signed with the secret, so the partner can verify they were sent by the store.
+type WebhookSubscription struct {
+	bun.BaseModel `bun:"table:webhook_subscriptions,alias:ws"`
+
+	Entity
+	URL        string      `bun:"url,notnull"`
+	EventTypes []EventType `bun:"event_types,array,notnull"`
+	Secret     string      `bun:"secret,notnull"`
+	Disabled   bool        `bun:"disabled,notnull"`
+}
+
+// Subscribes reports whether events of the type are delivered to the subscription.
+func (s WebhookSubscription) Subscribes(eventType EventType) bool {
+	return !s.Disabled && slices.Contains(s.EventTypes, eventType)
+}
+
+type DeliveryStatus string
+
+const (
+	DELIVERY_PENDING   DeliveryStatus = "pending"
+	DELIVERY_DELIVERED DeliveryStatus = "delivered"
+	// DELIVERY_DEAD deliveries failed WebhookMaxAttempts times, they are only attempted again if redelivered.
+	DELIVERY_DEAD DeliveryStatus = "dead"
+)
+
+// WebhookDelivery is a single event sent to a webhook subscription, attempted until it is delivered or dead.
+// It records the outcome of the last attempt.
+type WebhookDelivery struct {
+	bun.BaseModel `bun:"table:webhook_deliveries,alias:wd"`
+
+	Entity
+	SubscriptionID uuid.UUID            `bun:"subscription_id,type:uuid,notnull"`
+	Subscription   *WebhookSubscription `bun:"rel:belongs-to,join:subscription_id=id"`
+	EventID        uuid.UUID            `bun:"event_id,type:uuid,notnull"`
+	EventType      EventType            `bun:"event_type,notnull"`
+	Payload        json.RawMessage      `bun:"payload,type:jsonb,notnull"`
+	Status         DeliveryStatus       `bun:"status,notnull"`
+	Attempts       int                  `bun:"attempts,notnull"`
+	NextAttemptAt  time.Time            `bun:"next_attempt_at,nullzero"`
+	LastStatusCode int                  `bun:"last_status_code,nullzero"`
+	LastError      string               `bun:"last_error,nullzero"`
+	DeliveredAt    time.Time            `bun:"delivered_at,nullzero"`
+}
+
+// NewWebhookDelivery creates the pending delivery of the event to the subscription, due immediately.
+func NewWebhookDelivery(subscription WebhookSubscription, event Event) (*WebhookDelivery, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return &WebhookDelivery{
+		Entity:         Entity{ID: uuid.New(), CreatedAt: now, UpdatedAt: now},
+		SubscriptionID: subscription.ID,
+		EventID:        event.ID,
+		EventType:      event.Type,
+		Payload:        payload,
+		Status:         DELIVERY_PENDING,
+		NextAttemptAt:  now,
+	}, nil
+}
+
+// Succeeded records the attempt the receiver answered with the 2xx status code.
+func (d *WebhookDelivery) Succeeded(statusCode int, now time.Time) {
+	d.Attempts++
+	d.Status = DELIVERY_DELIVERED
+	d.LastStatusCode = statusCode
+	d.LastError = ""
+	d.NextAttemptAt = time.Time{}
+	d.DeliveredAt = now
+	d.UpdatedAt = now
+}
+
+// Failed records the failed attempt and schedules the next one with exponential backoff.
+// The status code is zero if the receiver did not answer at all. After WebhookMaxAttempts
+// failed attempts the delivery is dead.
+func (d *WebhookDelivery) Failed(statusCode int, reason string, now time.Time) {
+	d.Attempts++
+	d.LastStatusCode = statusCode
+	d.LastError = reason
+	d.UpdatedAt = now
+	if d.Attempts >= WebhookMaxAttempts {
+		d.Status = DELIVERY_DEAD
+		d.NextAttemptAt = time.Time{}
+		return
+	}
+	d.Status = DELIVERY_PENDING
+	d.NextAttemptAt = now.Add(WebhookRetryAfter(d.Attempts))
+}
+
+// Redeliver makes the delivery due again, no matter if it was delivered or dead.
+// A dead delivery gets a single attempt.
+func (d *WebhookDelivery) Redeliver(now time.Time) {
+	d.Status = DELIVERY_PENDING
+	d.NextAttemptAt = now
+	d.UpdatedAt = now
+}
+
+// WebhookRetryAfter returns the delay before the next attempt after the number of failed attempts.
+func WebhookRetryAfter(attempts int) time.Duration {
+	delay := WebhookRetryDelay
+	for i := 1; i < attempts && delay < WebhookMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, WebhookMaxRetryDelay)
+}
@@ -0,0 +1,60 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookDelivery(t *testing.T) {
+	subscription := WebhookSubscription{Entity: Entity{ID: uuid.New()}, EventTypes: []EventType{EVENT_ORDER_PLACED}}
+	event, err := NewEvent(EVENT_ORDER_PLACED, uuid.New(), map[string]string{})
+	assert.Nil(t, err)
+	now := time.Now()
+
+	t.Run("should retry failed delivery with exponential backoff until it is dead", func(t *testing.T) {
+		delivery, err := NewWebhookDelivery(subscription, *event)
+		assert.Nil(t, err)
+		assert.Equal(t, DELIVERY_PENDING, delivery.Status)
+
+		delivery.Failed(500, "internal server error", now)
+		assert.Equal(t, DELIVERY_PENDING, delivery.Status)
+		assert.Equal(t, now.Add(30*time.Second), delivery.NextAttemptAt)
+
+		delivery.Failed(0, "connection refused", now)
+		assert.Equal(t, now.Add(time.Minute), delivery.NextAttemptAt)
+		assert.Equal(t, 0, delivery.LastStatusCode)
+
+		for delivery.Attempts < WebhookMaxAttempts-1 {
+			delivery.Failed(502, "bad gateway", now)
+			assert.Equal(t, DELIVERY_PENDING, delivery.Status)
+		}
+		delivery.Failed(502, "bad gateway", now)
+		assert.Equal(t, DELIVERY_DEAD, delivery.Status)
+		assert.True(t, delivery.NextAttemptAt.IsZero())
+
+		delivery.Redeliver(now)
+		assert.Equal(t, DELIVERY_PENDING, delivery.Status)
+		delivery.Succeeded(204, now)
+		assert.Equal(t, DELIVERY_DELIVERED, delivery.Status)
+		assert.Equal(t, now, delivery.DeliveredAt)
+		assert.Empty(t, delivery.LastError)
+		assert.Equal(t, WebhookMaxAttempts+1, delivery.Attempts)
+	})
+
+	t.Run("should cap retry delay", func(t *testing.T) {
+		assert.Equal(t, 30*time.Second, WebhookRetryAfter(1))
+		assert.Equal(t, 4*time.Minute, WebhookRetryAfter(4))
+		assert.Equal(t, WebhookMaxRetryDelay, WebhookRetryAfter(100))
+	})
+
+	t.Run("should subscribe to listed event types unless disabled", func(t *testing.T) {
+		assert.True(t, subscription.Subscribes(EVENT_ORDER_PLACED))
+		assert.False(t, subscription.Subscribes(EVENT_ORDER_STATUS_CHANGED))
+		disabled := subscription
+		disabled.Disabled = true
+		assert.False(t, disabled.Subscribes(EVENT_ORDER_PLACED))
+	})
+}
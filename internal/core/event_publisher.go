@@ -0,0 +1,14 @@
+package core
+
+import (
+	"context"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+)
+
+// EventPublisher is a secondary port for delivering domain events to consumers outside the application.
+// Events are published at least once, so the same event may be published again after a failure.
+type EventPublisher interface {
+	// Publish delivers the event. An error means the event was not delivered and publishing is retried later.
+	Publish(ctx context.Context, event entities.Event) error
+}
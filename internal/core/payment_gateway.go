@@ -0,0 +1,28 @@
+package core
+
+import (
+	"context"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+)
+
+// PaymentGateway is a secondary port for moving money through a payment provider.
+// Payments are identified at the provider by the reference returned when they are authorized.
+// Payments declined by the provider are reported with entities.ErrorPaymentDeclined, operations the provider
+// rejects with entities.ErrorPaymentRejected and failures to reach it with an entities.ErrorUnavailable error.
+// Operations are sent with an idempotency key, an operation sent again with the same key is carried out only once
+// and answered with the outcome of the first one, so operations with an unknown outcome are safe to retry.
+type PaymentGateway interface {
+	// Provider returns the name of the payment provider.
+	Provider() string
+	// Authorize reserves the amount on the payment method the token stands for and returns the reference of the payment.
+	// Authorizing the same payment again returns the same reference, so it is safe to retry.
+	Authorize(ctx context.Context, paymentId uuid.UUID, amount entities.Money, token string) (string, error)
+	// Capture collects the amount of the authorized payment.
+	Capture(ctx context.Context, reference string, amount entities.Money, key string) error
+	// Void releases the authorized payment without collecting anything.
+	Void(ctx context.Context, reference string, key string) error
+	// Refund gives back the amount of the captured payment.
+	Refund(ctx context.Context, reference string, amount entities.Money, key string) error
+}
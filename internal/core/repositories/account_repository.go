@@ -11,4 +11,7 @@ type AccountRepository[ID any] interface {
 	GetById(ctx context.Context, id ID) (entities.Account, error)
 	GetByEmail(ctx context.Context, email string) (entities.Account, error)
 	Create(ctx context.Context, account *entities.Account) error
+	Update(ctx context.Context, account *entities.Account) error
+	DeleteById(ctx context.Context, id ID, version int64) error
+	ForceDeleteById(ctx context.Context, id ID, version int64) error
 }
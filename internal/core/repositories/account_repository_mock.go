@@ -69,17 +69,17 @@ func (_c *AccountRepositoryMock_Create_Call[ID]) RunAndReturn(run func(context.C
 	return _c
 }
 
-// DeleteById provides a mock function with given fields: ctx, id
-func (_m *AccountRepositoryMock[ID]) DeleteById(ctx context.Context, id ID) error {
-	ret := _m.Called(ctx, id)
+// DeleteById provides a mock function with given fields: ctx, id, version
+func (_m *AccountRepositoryMock[ID]) DeleteById(ctx context.Context, id ID, version int64) error {
+	ret := _m.Called(ctx, id, version)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteById")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, ID) error); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, ID, int64) error); ok {
+		r0 = rf(ctx, id, version)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -95,13 +95,14 @@ type AccountRepositoryMock_DeleteById_Call[ID interface{}] struct {
 // DeleteById is a helper method to define mock.On call
 //   - ctx context.Context
 //   - id ID
-func (_e *AccountRepositoryMock_Expecter[ID]) DeleteById(ctx interface{}, id interface{}) *AccountRepositoryMock_DeleteById_Call[ID] {
-	return &AccountRepositoryMock_DeleteById_Call[ID]{Call: _e.mock.On("DeleteById", ctx, id)}
+//   - version int64
+func (_e *AccountRepositoryMock_Expecter[ID]) DeleteById(ctx interface{}, id interface{}, version interface{}) *AccountRepositoryMock_DeleteById_Call[ID] {
+	return &AccountRepositoryMock_DeleteById_Call[ID]{Call: _e.mock.On("DeleteById", ctx, id, version)}
 }
 
-func (_c *AccountRepositoryMock_DeleteById_Call[ID]) Run(run func(ctx context.Context, id ID)) *AccountRepositoryMock_DeleteById_Call[ID] {
+func (_c *AccountRepositoryMock_DeleteById_Call[ID]) Run(run func(ctx context.Context, id ID, version int64)) *AccountRepositoryMock_DeleteById_Call[ID] {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(ID))
+		run(args[0].(context.Context), args[1].(ID), args[2].(int64))
 	})
 	return _c
 }
@@ -111,7 +112,55 @@ func (_c *AccountRepositoryMock_DeleteById_Call[ID]) Return(_a0 error) *AccountR
 	return _c
 }
 
-func (_c *AccountRepositoryMock_DeleteById_Call[ID]) RunAndReturn(run func(context.Context, ID) error) *AccountRepositoryMock_DeleteById_Call[ID] {
+func (_c *AccountRepositoryMock_DeleteById_Call[ID]) RunAndReturn(run func(context.Context, ID, int64) error) *AccountRepositoryMock_DeleteById_Call[ID] {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ForceDeleteById provides a mock function with given fields: ctx, id, version
+func (_m *AccountRepositoryMock[ID]) ForceDeleteById(ctx context.Context, id ID, version int64) error {
+	ret := _m.Called(ctx, id, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ForceDeleteById")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, ID, int64) error); ok {
+		r0 = rf(ctx, id, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AccountRepositoryMock_ForceDeleteById_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ForceDeleteById'
+type AccountRepositoryMock_ForceDeleteById_Call[ID interface{}] struct {
+	*mock.Call
+}
+
+// ForceDeleteById is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id ID
+//   - version int64
+func (_e *AccountRepositoryMock_Expecter[ID]) ForceDeleteById(ctx interface{}, id interface{}, version interface{}) *AccountRepositoryMock_ForceDeleteById_Call[ID] {
+	return &AccountRepositoryMock_ForceDeleteById_Call[ID]{Call: _e.mock.On("ForceDeleteById", ctx, id, version)}
+}
+
+func (_c *AccountRepositoryMock_ForceDeleteById_Call[ID]) Run(run func(ctx context.Context, id ID, version int64)) *AccountRepositoryMock_ForceDeleteById_Call[ID] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(ID), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *AccountRepositoryMock_ForceDeleteById_Call[ID]) Return(_a0 error) *AccountRepositoryMock_ForceDeleteById_Call[ID] {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *AccountRepositoryMock_ForceDeleteById_Call[ID]) RunAndReturn(run func(context.Context, ID, int64) error) *AccountRepositoryMock_ForceDeleteById_Call[ID] {
 	_c.Call.Return(run)
 	return _c
 }
@@ -230,63 +279,6 @@ func (_c *AccountRepositoryMock_GetById_Call[ID]) RunAndReturn(run func(context.
 	return _c
 }
 
-// GetPage provides a mock function with given fields: ctx, p
-func (_m *AccountRepositoryMock[ID]) GetPage(ctx context.Context, p entities.Pageable) (entities.Page[entities.Account], error) {
-	ret := _m.Called(ctx, p)
-
-	if len(ret) == 0 {
-		panic("no return value specified for GetPage")
-	}
-
-	var r0 entities.Page[entities.Account]
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, entities.Pageable) (entities.Page[entities.Account], error)); ok {
-		return rf(ctx, p)
-	}
-	if rf, ok := ret.Get(0).(func(context.Context, entities.Pageable) entities.Page[entities.Account]); ok {
-		r0 = rf(ctx, p)
-	} else {
-		r0 = ret.Get(0).(entities.Page[entities.Account])
-	}
-
-	if rf, ok := ret.Get(1).(func(context.Context, entities.Pageable) error); ok {
-		r1 = rf(ctx, p)
-	} else {
-		r1 = ret.Error(1)
-	}
-
-	return r0, r1
-}
-
-// AccountRepositoryMock_GetPage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPage'
-type AccountRepositoryMock_GetPage_Call[ID interface{}] struct {
-	*mock.Call
-}
-
-// GetPage is a helper method to define mock.On call
-//   - ctx context.Context
-//   - p entities.Pageable
-func (_e *AccountRepositoryMock_Expecter[ID]) GetPage(ctx interface{}, p interface{}) *AccountRepositoryMock_GetPage_Call[ID] {
-	return &AccountRepositoryMock_GetPage_Call[ID]{Call: _e.mock.On("GetPage", ctx, p)}
-}
-
-func (_c *AccountRepositoryMock_GetPage_Call[ID]) Run(run func(ctx context.Context, p entities.Pageable)) *AccountRepositoryMock_GetPage_Call[ID] {
-	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(entities.Pageable))
-	})
-	return _c
-}
-
-func (_c *AccountRepositoryMock_GetPage_Call[ID]) Return(_a0 entities.Page[entities.Account], _a1 error) *AccountRepositoryMock_GetPage_Call[ID] {
-	_c.Call.Return(_a0, _a1)
-	return _c
-}
-
-func (_c *AccountRepositoryMock_GetPage_Call[ID]) RunAndReturn(run func(context.Context, entities.Pageable) (entities.Page[entities.Account], error)) *AccountRepositoryMock_GetPage_Call[ID] {
-	_c.Call.Return(run)
-	return _c
-}
-
 // Update provides a mock function with given fields: ctx, account
 func (_m *AccountRepositoryMock[ID]) Update(ctx context.Context, account *entities.Account) error {
 	ret := _m.Called(ctx, account)
@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"context"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+)
+
+// AuthorRepository is a secondary port for author operations.
+type AuthorRepository[ID any] interface {
+	GetById(ctx context.Context, id ID) (entities.Author, error)
+	GetPage(ctx context.Context, p entities.Pageable) (entities.Page[entities.Author], error)
+	Create(ctx context.Context, author *entities.Author) error
+	Update(ctx context.Context, author *entities.Author) error
+	DeleteById(ctx context.Context, id ID, version int64) error
+}
@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"context"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+)
+
+// CartRepository is a secondary port for cart operations.
+type CartRepository[ID any] interface {
+	GetById(ctx context.Context, id ID) (entities.Cart, error)
+	// GetByAccount returns the cart of the account, an empty cart is created if the account has none yet.
+	GetByAccount(ctx context.Context, accountId ID) (entities.Cart, error)
+	Create(ctx context.Context, cart *entities.Cart) error
+	AddItem(ctx context.Context, cartId ID, itemId ID, quantity int) error
+	SetItemQuantity(ctx context.Context, cartId ID, itemId ID, quantity int) error
+	RemoveItem(ctx context.Context, cartId ID, itemId ID) error
+	Clear(ctx context.Context, cartId ID) error
+	// Merge moves items of the cart fromId into the cart toId and deletes the emptied cart.
+	Merge(ctx context.Context, fromId ID, toId ID) error
+	// Checkout calls place with the locked cart and clears the cart if place succeeds, both in one transaction.
+	// The context passed to place carries the transaction, so repositories called by place join it.
+	Checkout(ctx context.Context, cartId ID, place func(ctx context.Context, cart entities.Cart) error) error
+}
@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"context"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+)
+
+// CategoryRepository is a secondary port for category operations.
+type CategoryRepository[ID any] interface {
+	GetById(ctx context.Context, id ID) (entities.Category, error)
+	GetTopLevel(ctx context.Context) ([]entities.Category, error)
+	GetSubtree(ctx context.Context, id ID) (entities.Category, error)
+	Create(ctx context.Context, category *entities.Category) error
+	Update(ctx context.Context, category *entities.Category) error
+	DeleteById(ctx context.Context, id ID, version int64) error
+	GetByItem(ctx context.Context, itemId ID) ([]entities.Category, error)
+	AssignItem(ctx context.Context, itemId ID, categoryIds []ID) error
+}
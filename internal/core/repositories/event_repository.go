@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+)
+
+// EventRepository is a secondary port for the outbox of domain events.
+// Events are stored by the repositories persisting the changes they describe, within the same transaction.
+type EventRepository interface {
+	// Claim returns at most limit unpublished events which are due, the oldest first, and records the attempt
+	// on them, see entities.Event.Claim. The claim is committed before it returns, so the events are published
+	// without holding a transaction and concurrent relays skip them.
+	Claim(ctx context.Context, limit int) ([]entities.Event, error)
+	// Update persists the outcome of the attempt recorded on the claimed event.
+	Update(ctx context.Context, event *entities.Event) error
+}
@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"context"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+	"time"
+)
+
+// IdempotencyRepository is a secondary port for storing idempotency keys and the responses of their requests.
+type IdempotencyRepository interface {
+	// Reserve stores the key if it is not used yet or has expired and returns true.
+	// Otherwise, it returns the stored key and false.
+	Reserve(ctx context.Context, key *entities.IdempotencyKey) (entities.IdempotencyKey, bool, error)
+	// Complete stores the response of the request made with the key.
+	Complete(ctx context.Context, key *entities.IdempotencyKey) error
+	// Release deletes the key of the account, so the request can be made with the key again.
+	Release(ctx context.Context, accountId uuid.UUID, key string) error
+	// DeleteExpired deletes the keys which expired by now and returns how many were deleted.
+	DeleteExpired(ctx context.Context, now time.Time) (int, error)
+}
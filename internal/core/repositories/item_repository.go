@@ -9,4 +9,15 @@ import (
 type ItemRepository[ID any] interface {
 	GetById(ctx context.Context, id ID) (entities.Item, error)
 	GetPage(ctx context.Context, p entities.Pageable) (entities.Page[entities.Item], error)
+	GetPageByCategory(ctx context.Context, categoryId ID, p entities.Pageable) (entities.Page[entities.Item], error)
+	GetPageByAuthor(ctx context.Context, authorId ID, p entities.Pageable) (entities.Page[entities.Item], error)
+	GetByISBN(ctx context.Context, isbn string) (entities.Item, error)
+	// Search and SearchSimilar rank their results, which can only be paged by offset,
+	// so sorting and keyset mode are rejected with entities.ErrorSearchPaging.
+	Search(ctx context.Context, query string, p entities.Pageable) (entities.Page[entities.ItemMatch], error)
+	SearchSimilar(ctx context.Context, query string, p entities.Pageable) (entities.Page[entities.ItemMatch], error)
+	Suggest(ctx context.Context, query string, limit int) ([]string, error)
+	Create(ctx context.Context, item *entities.Item) error
+	Update(ctx context.Context, item *entities.Item) error
+	DeleteById(ctx context.Context, id ID, version int64) error
 }
@@ -22,6 +22,158 @@ func (_m *ItemRepositoryMock[ID]) EXPECT() *ItemRepositoryMock_Expecter[ID] {
 	return &ItemRepositoryMock_Expecter[ID]{mock: &_m.Mock}
 }
 
+// Create provides a mock function with given fields: ctx, item
+func (_m *ItemRepositoryMock[ID]) Create(ctx context.Context, item *entities.Item) error {
+	ret := _m.Called(ctx, item)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Item) error); ok {
+		r0 = rf(ctx, item)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ItemRepositoryMock_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type ItemRepositoryMock_Create_Call[ID interface{}] struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - item *entities.Item
+func (_e *ItemRepositoryMock_Expecter[ID]) Create(ctx interface{}, item interface{}) *ItemRepositoryMock_Create_Call[ID] {
+	return &ItemRepositoryMock_Create_Call[ID]{Call: _e.mock.On("Create", ctx, item)}
+}
+
+func (_c *ItemRepositoryMock_Create_Call[ID]) Run(run func(ctx context.Context, item *entities.Item)) *ItemRepositoryMock_Create_Call[ID] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entities.Item))
+	})
+	return _c
+}
+
+func (_c *ItemRepositoryMock_Create_Call[ID]) Return(_a0 error) *ItemRepositoryMock_Create_Call[ID] {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ItemRepositoryMock_Create_Call[ID]) RunAndReturn(run func(context.Context, *entities.Item) error) *ItemRepositoryMock_Create_Call[ID] {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteById provides a mock function with given fields: ctx, id, version
+func (_m *ItemRepositoryMock[ID]) DeleteById(ctx context.Context, id ID, version int64) error {
+	ret := _m.Called(ctx, id, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteById")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, ID, int64) error); ok {
+		r0 = rf(ctx, id, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ItemRepositoryMock_DeleteById_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteById'
+type ItemRepositoryMock_DeleteById_Call[ID interface{}] struct {
+	*mock.Call
+}
+
+// DeleteById is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id ID
+//   - version int64
+func (_e *ItemRepositoryMock_Expecter[ID]) DeleteById(ctx interface{}, id interface{}, version interface{}) *ItemRepositoryMock_DeleteById_Call[ID] {
+	return &ItemRepositoryMock_DeleteById_Call[ID]{Call: _e.mock.On("DeleteById", ctx, id, version)}
+}
+
+func (_c *ItemRepositoryMock_DeleteById_Call[ID]) Run(run func(ctx context.Context, id ID, version int64)) *ItemRepositoryMock_DeleteById_Call[ID] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(ID), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *ItemRepositoryMock_DeleteById_Call[ID]) Return(_a0 error) *ItemRepositoryMock_DeleteById_Call[ID] {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ItemRepositoryMock_DeleteById_Call[ID]) RunAndReturn(run func(context.Context, ID, int64) error) *ItemRepositoryMock_DeleteById_Call[ID] {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByISBN provides a mock function with given fields: ctx, isbn
+func (_m *ItemRepositoryMock[ID]) GetByISBN(ctx context.Context, isbn string) (entities.Item, error) {
+	ret := _m.Called(ctx, isbn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByISBN")
+	}
+
+	var r0 entities.Item
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (entities.Item, error)); ok {
+		return rf(ctx, isbn)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) entities.Item); ok {
+		r0 = rf(ctx, isbn)
+	} else {
+		r0 = ret.Get(0).(entities.Item)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, isbn)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ItemRepositoryMock_GetByISBN_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByISBN'
+type ItemRepositoryMock_GetByISBN_Call[ID interface{}] struct {
+	*mock.Call
+}
+
+// GetByISBN is a helper method to define mock.On call
+//   - ctx context.Context
+//   - isbn string
+func (_e *ItemRepositoryMock_Expecter[ID]) GetByISBN(ctx interface{}, isbn interface{}) *ItemRepositoryMock_GetByISBN_Call[ID] {
+	return &ItemRepositoryMock_GetByISBN_Call[ID]{Call: _e.mock.On("GetByISBN", ctx, isbn)}
+}
+
+func (_c *ItemRepositoryMock_GetByISBN_Call[ID]) Run(run func(ctx context.Context, isbn string)) *ItemRepositoryMock_GetByISBN_Call[ID] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *ItemRepositoryMock_GetByISBN_Call[ID]) Return(_a0 entities.Item, _a1 error) *ItemRepositoryMock_GetByISBN_Call[ID] {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ItemRepositoryMock_GetByISBN_Call[ID]) RunAndReturn(run func(context.Context, string) (entities.Item, error)) *ItemRepositoryMock_GetByISBN_Call[ID] {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetById provides a mock function with given fields: ctx, id
 func (_m *ItemRepositoryMock[ID]) GetById(ctx context.Context, id ID) (entities.Item, error) {
 	ret := _m.Called(ctx, id)
@@ -136,6 +288,345 @@ func (_c *ItemRepositoryMock_GetPage_Call[ID]) RunAndReturn(run func(context.Con
 	return _c
 }
 
+// GetPageByAuthor provides a mock function with given fields: ctx, authorId, p
+func (_m *ItemRepositoryMock[ID]) GetPageByAuthor(ctx context.Context, authorId ID, p entities.Pageable) (entities.Page[entities.Item], error) {
+	ret := _m.Called(ctx, authorId, p)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPageByAuthor")
+	}
+
+	var r0 entities.Page[entities.Item]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, ID, entities.Pageable) (entities.Page[entities.Item], error)); ok {
+		return rf(ctx, authorId, p)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ID, entities.Pageable) entities.Page[entities.Item]); ok {
+		r0 = rf(ctx, authorId, p)
+	} else {
+		r0 = ret.Get(0).(entities.Page[entities.Item])
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ID, entities.Pageable) error); ok {
+		r1 = rf(ctx, authorId, p)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ItemRepositoryMock_GetPageByAuthor_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPageByAuthor'
+type ItemRepositoryMock_GetPageByAuthor_Call[ID interface{}] struct {
+	*mock.Call
+}
+
+// GetPageByAuthor is a helper method to define mock.On call
+//   - ctx context.Context
+//   - authorId ID
+//   - p entities.Pageable
+func (_e *ItemRepositoryMock_Expecter[ID]) GetPageByAuthor(ctx interface{}, authorId interface{}, p interface{}) *ItemRepositoryMock_GetPageByAuthor_Call[ID] {
+	return &ItemRepositoryMock_GetPageByAuthor_Call[ID]{Call: _e.mock.On("GetPageByAuthor", ctx, authorId, p)}
+}
+
+func (_c *ItemRepositoryMock_GetPageByAuthor_Call[ID]) Run(run func(ctx context.Context, authorId ID, p entities.Pageable)) *ItemRepositoryMock_GetPageByAuthor_Call[ID] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(ID), args[2].(entities.Pageable))
+	})
+	return _c
+}
+
+func (_c *ItemRepositoryMock_GetPageByAuthor_Call[ID]) Return(_a0 entities.Page[entities.Item], _a1 error) *ItemRepositoryMock_GetPageByAuthor_Call[ID] {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ItemRepositoryMock_GetPageByAuthor_Call[ID]) RunAndReturn(run func(context.Context, ID, entities.Pageable) (entities.Page[entities.Item], error)) *ItemRepositoryMock_GetPageByAuthor_Call[ID] {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPageByCategory provides a mock function with given fields: ctx, categoryId, p
+func (_m *ItemRepositoryMock[ID]) GetPageByCategory(ctx context.Context, categoryId ID, p entities.Pageable) (entities.Page[entities.Item], error) {
+	ret := _m.Called(ctx, categoryId, p)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPageByCategory")
+	}
+
+	var r0 entities.Page[entities.Item]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, ID, entities.Pageable) (entities.Page[entities.Item], error)); ok {
+		return rf(ctx, categoryId, p)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ID, entities.Pageable) entities.Page[entities.Item]); ok {
+		r0 = rf(ctx, categoryId, p)
+	} else {
+		r0 = ret.Get(0).(entities.Page[entities.Item])
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ID, entities.Pageable) error); ok {
+		r1 = rf(ctx, categoryId, p)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ItemRepositoryMock_GetPageByCategory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPageByCategory'
+type ItemRepositoryMock_GetPageByCategory_Call[ID interface{}] struct {
+	*mock.Call
+}
+
+// GetPageByCategory is a helper method to define mock.On call
+//   - ctx context.Context
+//   - categoryId ID
+//   - p entities.Pageable
+func (_e *ItemRepositoryMock_Expecter[ID]) GetPageByCategory(ctx interface{}, categoryId interface{}, p interface{}) *ItemRepositoryMock_GetPageByCategory_Call[ID] {
+	return &ItemRepositoryMock_GetPageByCategory_Call[ID]{Call: _e.mock.On("GetPageByCategory", ctx, categoryId, p)}
+}
+
+func (_c *ItemRepositoryMock_GetPageByCategory_Call[ID]) Run(run func(ctx context.Context, categoryId ID, p entities.Pageable)) *ItemRepositoryMock_GetPageByCategory_Call[ID] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(ID), args[2].(entities.Pageable))
+	})
+	return _c
+}
+
+func (_c *ItemRepositoryMock_GetPageByCategory_Call[ID]) Return(_a0 entities.Page[entities.Item], _a1 error) *ItemRepositoryMock_GetPageByCategory_Call[ID] {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ItemRepositoryMock_GetPageByCategory_Call[ID]) RunAndReturn(run func(context.Context, ID, entities.Pageable) (entities.Page[entities.Item], error)) *ItemRepositoryMock_GetPageByCategory_Call[ID] {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Search provides a mock function with given fields: ctx, query, p
+func (_m *ItemRepositoryMock[ID]) Search(ctx context.Context, query string, p entities.Pageable) (entities.Page[entities.ItemMatch], error) {
+	ret := _m.Called(ctx, query, p)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Search")
+	}
+
+	var r0 entities.Page[entities.ItemMatch]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, entities.Pageable) (entities.Page[entities.ItemMatch], error)); ok {
+		return rf(ctx, query, p)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, entities.Pageable) entities.Page[entities.ItemMatch]); ok {
+		r0 = rf(ctx, query, p)
+	} else {
+		r0 = ret.Get(0).(entities.Page[entities.ItemMatch])
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, entities.Pageable) error); ok {
+		r1 = rf(ctx, query, p)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ItemRepositoryMock_Search_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Search'
+type ItemRepositoryMock_Search_Call[ID interface{}] struct {
+	*mock.Call
+}
+
+// Search is a helper method to define mock.On call
+//   - ctx context.Context
+//   - query string
+//   - p entities.Pageable
+func (_e *ItemRepositoryMock_Expecter[ID]) Search(ctx interface{}, query interface{}, p interface{}) *ItemRepositoryMock_Search_Call[ID] {
+	return &ItemRepositoryMock_Search_Call[ID]{Call: _e.mock.On("Search", ctx, query, p)}
+}
+
+func (_c *ItemRepositoryMock_Search_Call[ID]) Run(run func(ctx context.Context, query string, p entities.Pageable)) *ItemRepositoryMock_Search_Call[ID] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(entities.Pageable))
+	})
+	return _c
+}
+
+func (_c *ItemRepositoryMock_Search_Call[ID]) Return(_a0 entities.Page[entities.ItemMatch], _a1 error) *ItemRepositoryMock_Search_Call[ID] {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ItemRepositoryMock_Search_Call[ID]) RunAndReturn(run func(context.Context, string, entities.Pageable) (entities.Page[entities.ItemMatch], error)) *ItemRepositoryMock_Search_Call[ID] {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SearchSimilar provides a mock function with given fields: ctx, query, p
+func (_m *ItemRepositoryMock[ID]) SearchSimilar(ctx context.Context, query string, p entities.Pageable) (entities.Page[entities.ItemMatch], error) {
+	ret := _m.Called(ctx, query, p)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchSimilar")
+	}
+
+	var r0 entities.Page[entities.ItemMatch]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, entities.Pageable) (entities.Page[entities.ItemMatch], error)); ok {
+		return rf(ctx, query, p)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, entities.Pageable) entities.Page[entities.ItemMatch]); ok {
+		r0 = rf(ctx, query, p)
+	} else {
+		r0 = ret.Get(0).(entities.Page[entities.ItemMatch])
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, entities.Pageable) error); ok {
+		r1 = rf(ctx, query, p)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ItemRepositoryMock_SearchSimilar_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SearchSimilar'
+type ItemRepositoryMock_SearchSimilar_Call[ID interface{}] struct {
+	*mock.Call
+}
+
+// SearchSimilar is a helper method to define mock.On call
+//   - ctx context.Context
+//   - query string
+//   - p entities.Pageable
+func (_e *ItemRepositoryMock_Expecter[ID]) SearchSimilar(ctx interface{}, query interface{}, p interface{}) *ItemRepositoryMock_SearchSimilar_Call[ID] {
+	return &ItemRepositoryMock_SearchSimilar_Call[ID]{Call: _e.mock.On("SearchSimilar", ctx, query, p)}
+}
+
+func (_c *ItemRepositoryMock_SearchSimilar_Call[ID]) Run(run func(ctx context.Context, query string, p entities.Pageable)) *ItemRepositoryMock_SearchSimilar_Call[ID] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(entities.Pageable))
+	})
+	return _c
+}
+
+func (_c *ItemRepositoryMock_SearchSimilar_Call[ID]) Return(_a0 entities.Page[entities.ItemMatch], _a1 error) *ItemRepositoryMock_SearchSimilar_Call[ID] {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ItemRepositoryMock_SearchSimilar_Call[ID]) RunAndReturn(run func(context.Context, string, entities.Pageable) (entities.Page[entities.ItemMatch], error)) *ItemRepositoryMock_SearchSimilar_Call[ID] {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Suggest provides a mock function with given fields: ctx, query, limit
+func (_m *ItemRepositoryMock[ID]) Suggest(ctx context.Context, query string, limit int) ([]string, error) {
+	ret := _m.Called(ctx, query, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Suggest")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]string, error)); ok {
+		return rf(ctx, query, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []string); ok {
+		r0 = rf(ctx, query, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, query, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ItemRepositoryMock_Suggest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Suggest'
+type ItemRepositoryMock_Suggest_Call[ID interface{}] struct {
+	*mock.Call
+}
+
+// Suggest is a helper method to define mock.On call
+//   - ctx context.Context
+//   - query string
+//   - limit int
+func (_e *ItemRepositoryMock_Expecter[ID]) Suggest(ctx interface{}, query interface{}, limit interface{}) *ItemRepositoryMock_Suggest_Call[ID] {
+	return &ItemRepositoryMock_Suggest_Call[ID]{Call: _e.mock.On("Suggest", ctx, query, limit)}
+}
+
+func (_c *ItemRepositoryMock_Suggest_Call[ID]) Run(run func(ctx context.Context, query string, limit int)) *ItemRepositoryMock_Suggest_Call[ID] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *ItemRepositoryMock_Suggest_Call[ID]) Return(_a0 []string, _a1 error) *ItemRepositoryMock_Suggest_Call[ID] {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ItemRepositoryMock_Suggest_Call[ID]) RunAndReturn(run func(context.Context, string, int) ([]string, error)) *ItemRepositoryMock_Suggest_Call[ID] {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Update provides a mock function with given fields: ctx, item
+func (_m *ItemRepositoryMock[ID]) Update(ctx context.Context, item *entities.Item) error {
+	ret := _m.Called(ctx, item)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Item) error); ok {
+		r0 = rf(ctx, item)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ItemRepositoryMock_Update_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Update'
+type ItemRepositoryMock_Update_Call[ID interface{}] struct {
+	*mock.Call
+}
+
+// Update is a helper method to define mock.On call
+//   - ctx context.Context
+//   - item *entities.Item
+func (_e *ItemRepositoryMock_Expecter[ID]) Update(ctx interface{}, item interface{}) *ItemRepositoryMock_Update_Call[ID] {
+	return &ItemRepositoryMock_Update_Call[ID]{Call: _e.mock.On("Update", ctx, item)}
+}
+
+func (_c *ItemRepositoryMock_Update_Call[ID]) Run(run func(ctx context.Context, item *entities.Item)) *ItemRepositoryMock_Update_Call[ID] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entities.Item))
+	})
+	return _c
+}
+
+func (_c *ItemRepositoryMock_Update_Call[ID]) Return(_a0 error) *ItemRepositoryMock_Update_Call[ID] {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ItemRepositoryMock_Update_Call[ID]) RunAndReturn(run func(context.Context, *entities.Item) error) *ItemRepositoryMock_Update_Call[ID] {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewItemRepositoryMock creates a new instance of ItemRepositoryMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewItemRepositoryMock[ID interface{}](t interface {
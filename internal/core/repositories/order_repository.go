@@ -10,4 +10,12 @@ type OrderRepository[ID any] interface {
 	GetById(ctx context.Context, id ID) (entities.Order, error)
 	Search(ctx context.Context, accountId ID, pageRequest entities.Pageable) (entities.Page[entities.Order], error)
 	Create(ctx context.Context, order *entities.Order) error
+	UpdateStatus(ctx context.Context, order *entities.Order, change *entities.OrderHistory) error
+	GetHistory(ctx context.Context, orderId ID) ([]entities.OrderHistory, error)
+	// GetByIdForUpdate returns the order with its order items and locks it until the transaction carried by ctx ends,
+	// see core.TxManager, so changes of the order based on what it was read as can not interleave.
+	GetByIdForUpdate(ctx context.Context, id ID) (entities.Order, error)
+	// CreateRefund persists the refund of the order together with the status change, if it is not nil.
+	CreateRefund(ctx context.Context, order *entities.Order, refund *entities.Refund, change *entities.OrderHistory) error
+	GetRefunds(ctx context.Context, orderId ID) ([]entities.Refund, error)
 }
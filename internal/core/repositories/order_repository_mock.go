@@ -1,4 +1,4 @@
-// Code generated by mockery v2.42.2. DO NOT EDIT.
+// Code generated by mockery v2.42.2 DO NOT EDIT.
 
 package repositories
 
@@ -69,6 +69,55 @@ func (_c *OrderRepositoryMock_Create_Call[ID]) RunAndReturn(run func(context.Con
 	return _c
 }
 
+// CreateRefund provides a mock function with given fields: ctx, order, refund, change
+func (_m *OrderRepositoryMock[ID]) CreateRefund(ctx context.Context, order *entities.Order, refund *entities.Refund, change *entities.OrderHistory) error {
+	ret := _m.Called(ctx, order, refund, change)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRefund")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Order, *entities.Refund, *entities.OrderHistory) error); ok {
+		r0 = rf(ctx, order, refund, change)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// OrderRepositoryMock_CreateRefund_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateRefund'
+type OrderRepositoryMock_CreateRefund_Call[ID interface{}] struct {
+	*mock.Call
+}
+
+// CreateRefund is a helper method to define mock.On call
+//   - ctx context.Context
+//   - order *entities.Order
+//   - refund *entities.Refund
+//   - change *entities.OrderHistory
+func (_e *OrderRepositoryMock_Expecter[ID]) CreateRefund(ctx interface{}, order interface{}, refund interface{}, change interface{}) *OrderRepositoryMock_CreateRefund_Call[ID] {
+	return &OrderRepositoryMock_CreateRefund_Call[ID]{Call: _e.mock.On("CreateRefund", ctx, order, refund, change)}
+}
+
+func (_c *OrderRepositoryMock_CreateRefund_Call[ID]) Run(run func(ctx context.Context, order *entities.Order, refund *entities.Refund, change *entities.OrderHistory)) *OrderRepositoryMock_CreateRefund_Call[ID] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entities.Order), args[2].(*entities.Refund), args[3].(*entities.OrderHistory))
+	})
+	return _c
+}
+
+func (_c *OrderRepositoryMock_CreateRefund_Call[ID]) Return(_a0 error) *OrderRepositoryMock_CreateRefund_Call[ID] {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *OrderRepositoryMock_CreateRefund_Call[ID]) RunAndReturn(run func(context.Context, *entities.Order, *entities.Refund, *entities.OrderHistory) error) *OrderRepositoryMock_CreateRefund_Call[ID] {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetById provides a mock function with given fields: ctx, id
 func (_m *OrderRepositoryMock[ID]) GetById(ctx context.Context, id ID) (entities.Order, error) {
 	ret := _m.Called(ctx, id)
@@ -126,6 +175,181 @@ func (_c *OrderRepositoryMock_GetById_Call[ID]) RunAndReturn(run func(context.Co
 	return _c
 }
 
+// GetByIdForUpdate provides a mock function with given fields: ctx, id
+func (_m *OrderRepositoryMock[ID]) GetByIdForUpdate(ctx context.Context, id ID) (entities.Order, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByIdForUpdate")
+	}
+
+	var r0 entities.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, ID) (entities.Order, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ID) entities.Order); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(entities.Order)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderRepositoryMock_GetByIdForUpdate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByIdForUpdate'
+type OrderRepositoryMock_GetByIdForUpdate_Call[ID interface{}] struct {
+	*mock.Call
+}
+
+// GetByIdForUpdate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id ID
+func (_e *OrderRepositoryMock_Expecter[ID]) GetByIdForUpdate(ctx interface{}, id interface{}) *OrderRepositoryMock_GetByIdForUpdate_Call[ID] {
+	return &OrderRepositoryMock_GetByIdForUpdate_Call[ID]{Call: _e.mock.On("GetByIdForUpdate", ctx, id)}
+}
+
+func (_c *OrderRepositoryMock_GetByIdForUpdate_Call[ID]) Run(run func(ctx context.Context, id ID)) *OrderRepositoryMock_GetByIdForUpdate_Call[ID] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(ID))
+	})
+	return _c
+}
+
+func (_c *OrderRepositoryMock_GetByIdForUpdate_Call[ID]) Return(_a0 entities.Order, _a1 error) *OrderRepositoryMock_GetByIdForUpdate_Call[ID] {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderRepositoryMock_GetByIdForUpdate_Call[ID]) RunAndReturn(run func(context.Context, ID) (entities.Order, error)) *OrderRepositoryMock_GetByIdForUpdate_Call[ID] {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetHistory provides a mock function with given fields: ctx, orderId
+func (_m *OrderRepositoryMock[ID]) GetHistory(ctx context.Context, orderId ID) ([]entities.OrderHistory, error) {
+	ret := _m.Called(ctx, orderId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetHistory")
+	}
+
+	var r0 []entities.OrderHistory
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, ID) ([]entities.OrderHistory, error)); ok {
+		return rf(ctx, orderId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ID) []entities.OrderHistory); ok {
+		r0 = rf(ctx, orderId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.OrderHistory)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ID) error); ok {
+		r1 = rf(ctx, orderId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderRepositoryMock_GetHistory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetHistory'
+type OrderRepositoryMock_GetHistory_Call[ID interface{}] struct {
+	*mock.Call
+}
+
+// GetHistory is a helper method to define mock.On call
+//   - ctx context.Context
+//   - orderId ID
+func (_e *OrderRepositoryMock_Expecter[ID]) GetHistory(ctx interface{}, orderId interface{}) *OrderRepositoryMock_GetHistory_Call[ID] {
+	return &OrderRepositoryMock_GetHistory_Call[ID]{Call: _e.mock.On("GetHistory", ctx, orderId)}
+}
+
+func (_c *OrderRepositoryMock_GetHistory_Call[ID]) Run(run func(ctx context.Context, orderId ID)) *OrderRepositoryMock_GetHistory_Call[ID] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(ID))
+	})
+	return _c
+}
+
+func (_c *OrderRepositoryMock_GetHistory_Call[ID]) Return(_a0 []entities.OrderHistory, _a1 error) *OrderRepositoryMock_GetHistory_Call[ID] {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderRepositoryMock_GetHistory_Call[ID]) RunAndReturn(run func(context.Context, ID) ([]entities.OrderHistory, error)) *OrderRepositoryMock_GetHistory_Call[ID] {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRefunds provides a mock function with given fields: ctx, orderId
+func (_m *OrderRepositoryMock[ID]) GetRefunds(ctx context.Context, orderId ID) ([]entities.Refund, error) {
+	ret := _m.Called(ctx, orderId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRefunds")
+	}
+
+	var r0 []entities.Refund
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, ID) ([]entities.Refund, error)); ok {
+		return rf(ctx, orderId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ID) []entities.Refund); ok {
+		r0 = rf(ctx, orderId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Refund)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ID) error); ok {
+		r1 = rf(ctx, orderId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderRepositoryMock_GetRefunds_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRefunds'
+type OrderRepositoryMock_GetRefunds_Call[ID interface{}] struct {
+	*mock.Call
+}
+
+// GetRefunds is a helper method to define mock.On call
+//   - ctx context.Context
+//   - orderId ID
+func (_e *OrderRepositoryMock_Expecter[ID]) GetRefunds(ctx interface{}, orderId interface{}) *OrderRepositoryMock_GetRefunds_Call[ID] {
+	return &OrderRepositoryMock_GetRefunds_Call[ID]{Call: _e.mock.On("GetRefunds", ctx, orderId)}
+}
+
+func (_c *OrderRepositoryMock_GetRefunds_Call[ID]) Run(run func(ctx context.Context, orderId ID)) *OrderRepositoryMock_GetRefunds_Call[ID] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(ID))
+	})
+	return _c
+}
+
+func (_c *OrderRepositoryMock_GetRefunds_Call[ID]) Return(_a0 []entities.Refund, _a1 error) *OrderRepositoryMock_GetRefunds_Call[ID] {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrderRepositoryMock_GetRefunds_Call[ID]) RunAndReturn(run func(context.Context, ID) ([]entities.Refund, error)) *OrderRepositoryMock_GetRefunds_Call[ID] {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Search provides a mock function with given fields: ctx, accountId, pageRequest
 func (_m *OrderRepositoryMock[ID]) Search(ctx context.Context, accountId ID, pageRequest entities.Pageable) (entities.Page[entities.Order], error) {
 	ret := _m.Called(ctx, accountId, pageRequest)
@@ -184,6 +408,54 @@ func (_c *OrderRepositoryMock_Search_Call[ID]) RunAndReturn(run func(context.Con
 	return _c
 }
 
+// UpdateStatus provides a mock function with given fields: ctx, order, change
+func (_m *OrderRepositoryMock[ID]) UpdateStatus(ctx context.Context, order *entities.Order, change *entities.OrderHistory) error {
+	ret := _m.Called(ctx, order, change)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Order, *entities.OrderHistory) error); ok {
+		r0 = rf(ctx, order, change)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// OrderRepositoryMock_UpdateStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateStatus'
+type OrderRepositoryMock_UpdateStatus_Call[ID interface{}] struct {
+	*mock.Call
+}
+
+// UpdateStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - order *entities.Order
+//   - change *entities.OrderHistory
+func (_e *OrderRepositoryMock_Expecter[ID]) UpdateStatus(ctx interface{}, order interface{}, change interface{}) *OrderRepositoryMock_UpdateStatus_Call[ID] {
+	return &OrderRepositoryMock_UpdateStatus_Call[ID]{Call: _e.mock.On("UpdateStatus", ctx, order, change)}
+}
+
+func (_c *OrderRepositoryMock_UpdateStatus_Call[ID]) Run(run func(ctx context.Context, order *entities.Order, change *entities.OrderHistory)) *OrderRepositoryMock_UpdateStatus_Call[ID] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entities.Order), args[2].(*entities.OrderHistory))
+	})
+	return _c
+}
+
+func (_c *OrderRepositoryMock_UpdateStatus_Call[ID]) Return(_a0 error) *OrderRepositoryMock_UpdateStatus_Call[ID] {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *OrderRepositoryMock_UpdateStatus_Call[ID]) RunAndReturn(run func(context.Context, *entities.Order, *entities.OrderHistory) error) *OrderRepositoryMock_UpdateStatus_Call[ID] {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewOrderRepositoryMock creates a new instance of OrderRepositoryMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewOrderRepositoryMock[ID interface{}](t interface {
@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+)
+
+// PaymentRepository is an interface for interacting with the payment repository.
+type PaymentRepository[ID any] interface {
+	GetById(ctx context.Context, id ID) (entities.Payment, error)
+	GetByReference(ctx context.Context, provider string, reference string) (entities.Payment, error)
+	GetByOrder(ctx context.Context, orderId ID) ([]entities.Payment, error)
+	Create(ctx context.Context, payment *entities.Payment) error
+	// Update locks the payment and passes it with its order to the update func.
+	// The payment and, if it is not nil, the order status change the update returns are persisted before the lock is released.
+	Update(ctx context.Context, id ID, update func(ctx context.Context, payment *entities.Payment, order entities.Order) (*entities.OrderHistory, error)) (entities.Payment, error)
+}
@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"context"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+)
+
+// PublisherRepository is a secondary port for publisher operations.
+type PublisherRepository[ID any] interface {
+	GetById(ctx context.Context, id ID) (entities.Publisher, error)
+	GetPage(ctx context.Context, p entities.Pageable) (entities.Page[entities.Publisher], error)
+	Create(ctx context.Context, publisher *entities.Publisher) error
+	Update(ctx context.Context, publisher *entities.Publisher) error
+	DeleteById(ctx context.Context, id ID, version int64) error
+}
@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"context"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+)
+
+// WebhookSubscriptionRepository is a secondary port for webhook subscription operations.
+type WebhookSubscriptionRepository[ID any] interface {
+	GetById(ctx context.Context, id ID) (entities.WebhookSubscription, error)
+	GetPage(ctx context.Context, p entities.Pageable) (entities.Page[entities.WebhookSubscription], error)
+	// GetSubscribed returns enabled subscriptions to events of the type.
+	GetSubscribed(ctx context.Context, eventType entities.EventType) ([]entities.WebhookSubscription, error)
+	Create(ctx context.Context, subscription *entities.WebhookSubscription) error
+	Update(ctx context.Context, subscription *entities.WebhookSubscription) error
+	DeleteById(ctx context.Context, id ID, version int64) error
+}
+
+// WebhookDeliveryRepository is a secondary port for the log of webhook deliveries.
+type WebhookDeliveryRepository[ID any] interface {
+	// GetPage returns a page of deliveries to the subscription, only deliveries in the status if it is not empty.
+	GetPage(ctx context.Context, subscriptionId ID, status entities.DeliveryStatus, p entities.Pageable) (entities.Page[entities.WebhookDelivery], error)
+	// Create stores the deliveries, skipping deliveries of an event to a subscription which exist already.
+	Create(ctx context.Context, deliveries []*entities.WebhookDelivery) error
+	// Deliver hands at most limit pending deliveries which are due to the deliver func one after another
+	// and persists the outcome the func recorded on them. It returns the number of attempted deliveries.
+	Deliver(
+		ctx context.Context,
+		limit int,
+		deliver func(ctx context.Context, subscription entities.WebhookSubscription, delivery *entities.WebhookDelivery) error,
+	) (int, error)
+	// Update locks the delivery and persists the outcome the update func recorded on it.
+	Update(
+		ctx context.Context,
+		id ID,
+		update func(ctx context.Context, subscription entities.WebhookSubscription, delivery *entities.WebhookDelivery) error,
+	) (entities.WebhookDelivery, error)
+}
@@ -2,55 +2,186 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/fmiskovic/new-amz/internal/core"
 	"github.com/fmiskovic/new-amz/internal/core/dtos"
 	"github.com/fmiskovic/new-amz/internal/core/entities"
 	"github.com/fmiskovic/new-amz/internal/core/repositories"
+	"github.com/fmiskovic/new-amz/internal/utils"
 	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
 	"strings"
 )
 
 var (
-	ErrorEmailRequired  = errors.New("email is required")
-	ErrorEmailNotUnique = errors.New("email is not unique")
+	ErrorEmailRequired    = entities.NewError(entities.ErrorValidation, "email is required")
+	ErrorEmailNotUnique   = entities.ErrorEmailNotUnique
+	ErrorPasswordRequired = entities.NewError(entities.ErrorValidation, "password is required")
+	ErrorInvalidPatch     = entities.NewError(entities.ErrorValidation, "invalid patch document")
 )
 
 // AccountService represents business logic related to entities.Account.
 type AccountService struct {
-	repo repositories.AccountRepository[uuid.UUID]
+	UnitOfWork
+	repo       repositories.AccountRepository[uuid.UUID]
+	placeOrder core.ServiceFunc[dtos.CreateOrderCommand, dtos.CreateOrderAnswer]
 }
 
 // NewAccountService instantiates new Account Service.
-func NewAccountService(repo repositories.AccountRepository[uuid.UUID]) AccountService {
-	return AccountService{repo}
+// First orders of new accounts are placed with placeOrder, usually OrderService.Create,
+// in the unit of work creating the account.
+func NewAccountService(
+	repo repositories.AccountRepository[uuid.UUID],
+	uow UnitOfWork,
+	placeOrder core.ServiceFunc[dtos.CreateOrderCommand, dtos.CreateOrderAnswer],
+) AccountService {
+	return AccountService{UnitOfWork: uow, repo: repo, placeOrder: placeOrder}
 }
 
 // Create creates new account.
+// If the command lists items of the first order, the order is placed on behalf of the new account as well,
+// both or none of it happens, e.g. the account is not created if the items are out of stock.
 func (s AccountService) Create(ctx context.Context, cmd dtos.CreateAccountCommand) (dtos.CreateAccountAnswer, error) {
 	if len(strings.TrimSpace(cmd.Email)) == 0 {
 		return dtos.CreateAccountAnswer{}, ErrorEmailRequired
 	}
+	if len(cmd.Password) == 0 {
+		return dtos.CreateAccountAnswer{}, ErrorPasswordRequired
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(cmd.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return dtos.CreateAccountAnswer{}, newError("failed to hash password", err)
+	}
+
 	a := entities.NewAccountBuilder().
 		Email(cmd.Email).
+		PasswordHash(string(hash)).
 		FullName(cmd.FullName).
 		DateOfBirth(cmd.DateOfBirth).
 		Location(cmd.Location).
 		Gender(cmd.Gender.Numberfy()).
 		Build()
 
-	if err := s.repo.Create(ctx, a); err != nil {
-		return dtos.CreateAccountAnswer{}, newError("failed to create account", err)
+	if len(cmd.Order) == 0 {
+		if err := s.repo.Create(ctx, a); err != nil {
+			return dtos.CreateAccountAnswer{}, newError("failed to create account", err)
+		}
+		return dtos.CreateAccountAnswer{AccountDto: dtos.ToAccountDto(*a)}, nil
 	}
 
-	return dtos.CreateAccountAnswer{AccountDto: dtos.ToAccountDto(*a)}, nil
+	var order dtos.CreateOrderAnswer
+	err = s.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, a); err != nil {
+			return newError("failed to create account", err)
+		}
+
+		// the new account places its first order itself
+		ctx = core.WithPrincipal(ctx, core.Principal{AccountID: a.ID, Role: a.Role})
+		var err error
+		order, err = s.placeOrder(ctx, dtos.CreateOrderCommand{AccountID: a.ID.String(), Items: cmd.Order})
+		if err != nil {
+			return newError("failed to place first order", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return dtos.CreateAccountAnswer{}, err
+	}
+
+	return dtos.CreateAccountAnswer{AccountDto: dtos.ToAccountDto(*a), Order: &order.OrderDto}, nil
 }
 
 // GetById returns existing account.
+// Only the account itself is allowed to read it.
 func (s AccountService) GetById(ctx context.Context, id uuid.UUID) (dtos.AccountDto, error) {
+	if err := authorize(ctx, id); err != nil {
+		return dtos.AccountDto{}, err
+	}
 	a, err := s.repo.GetById(ctx, id)
 	if err != nil {
 		return dtos.AccountDto{}, newError(fmt.Sprintf("failed to get account by id: %s", id.String()), err)
 	}
 	return dtos.ToAccountDto(a), nil
 }
+
+// Update replaces profile of existing account.
+// Only the account itself is allowed to change it.
+func (s AccountService) Update(ctx context.Context, cmd dtos.UpdateAccountCommand) (dtos.AccountDto, error) {
+	if err := authorize(ctx, cmd.ID); err != nil {
+		return dtos.AccountDto{}, err
+	}
+	if len(strings.TrimSpace(cmd.Email)) == 0 {
+		return dtos.AccountDto{}, ErrorEmailRequired
+	}
+
+	a := &entities.Account{
+		Entity:      entities.Entity{ID: cmd.ID, Version: cmd.Version},
+		Email:       cmd.Email,
+		FullName:    cmd.FullName,
+		DateOfBirth: cmd.DateOfBirth,
+		Location:    cmd.Location,
+		Gender:      cmd.Gender.Numberfy(),
+	}
+
+	if err := s.repo.Update(ctx, a); err != nil {
+		return dtos.AccountDto{}, newError(fmt.Sprintf("failed to update account: %s", cmd.ID.String()), err)
+	}
+	return dtos.ToAccountDto(*a), nil
+}
+
+// Patch changes profile of existing account by applying JSON Merge Patch to it.
+// Only the account itself is allowed to change it.
+func (s AccountService) Patch(ctx context.Context, cmd dtos.PatchAccountCommand) (dtos.AccountDto, error) {
+	if err := authorize(ctx, cmd.ID); err != nil {
+		return dtos.AccountDto{}, err
+	}
+
+	a, err := s.repo.GetById(ctx, cmd.ID)
+	if err != nil {
+		return dtos.AccountDto{}, newError(fmt.Sprintf("failed to get account by id: %s", cmd.ID.String()), err)
+	}
+
+	original, err := json.Marshal(dtos.ToUpdateAccountCommand(a))
+	if err != nil {
+		return dtos.AccountDto{}, newError("failed to encode account", err)
+	}
+	patched, err := utils.MergePatch(original, cmd.Patch)
+	if err != nil {
+		return dtos.AccountDto{}, errors.Join(ErrorInvalidPatch, err)
+	}
+
+	var update dtos.UpdateAccountCommand
+	if err := json.Unmarshal(patched, &update); err != nil {
+		return dtos.AccountDto{}, errors.Join(ErrorInvalidPatch, err)
+	}
+	update.ID = cmd.ID
+	update.Version = cmd.Version
+
+	return s.Update(ctx, update)
+}
+
+// Delete closes existing account.
+// Accounts are soft deleted and can be closed by the account itself,
+// permanent deletion of an account together with its orders is reserved for administrators.
+func (s AccountService) Delete(ctx context.Context, cmd dtos.DeleteAccountCommand) (struct{}, error) {
+	if cmd.Force {
+		if _, err := authorizeAdmin(ctx); err != nil {
+			return struct{}{}, err
+		}
+		if err := s.repo.ForceDeleteById(ctx, cmd.ID, cmd.Version); err != nil {
+			return struct{}{}, newError(fmt.Sprintf("failed to delete account: %s", cmd.ID.String()), err)
+		}
+		return struct{}{}, nil
+	}
+
+	if err := authorize(ctx, cmd.ID); err != nil {
+		return struct{}{}, err
+	}
+	if err := s.repo.DeleteById(ctx, cmd.ID, cmd.Version); err != nil {
+		return struct{}{}, newError(fmt.Sprintf("failed to delete account: %s", cmd.ID.String()), err)
+	}
+	return struct{}{}, nil
+}
@@ -2,12 +2,16 @@ package services
 
 import (
 	"context"
+	"github.com/fmiskovic/new-amz/internal/core"
 	"github.com/fmiskovic/new-amz/internal/core/dtos"
 	"github.com/fmiskovic/new-amz/internal/core/entities"
 	"github.com/fmiskovic/new-amz/internal/core/repositories"
+	"github.com/fmiskovic/new-amz/internal/repositories/memory"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
 	"testing"
 	"time"
 )
@@ -18,10 +22,11 @@ func TestCreateAccount(t *testing.T) {
 
 	t.Run("create new account with valid data should return account dto", func(t *testing.T) {
 		repoMock := repositories.NewAccountRepositoryMock[uuid.UUID](t)
-		svc := NewAccountService(repoMock)
+		svc := NewAccountService(repoMock, newUnitOfWork(t), nil)
 
 		cmd := dtos.CreateAccountCommand{
 			Email:       "fake@mail.com",
+			Password:    "secret-password",
 			FullName:    "Fake Name",
 			Location:    "Fake Location",
 			DateOfBirth: time.Date(1990, time.January, 1, 0, 0, 0, 0, time.UTC),
@@ -39,10 +44,11 @@ func TestCreateAccount(t *testing.T) {
 
 	t.Run("create new account with empty email should return error", func(t *testing.T) {
 		repoMock := repositories.NewAccountRepositoryMock[uuid.UUID](t)
-		svc := NewAccountService(repoMock)
+		svc := NewAccountService(repoMock, newUnitOfWork(t), nil)
 
 		cmd := dtos.CreateAccountCommand{
 			Email:       " ",
+			Password:    "secret-password",
 			FullName:    "Fake Name",
 			Location:    "Fake Location",
 			DateOfBirth: time.Date(1990, time.January, 1, 0, 0, 0, 0, time.UTC),
@@ -54,12 +60,44 @@ func TestCreateAccount(t *testing.T) {
 		repoMock.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
 	})
 
+	t.Run("create new account with empty password should return error", func(t *testing.T) {
+		repoMock := repositories.NewAccountRepositoryMock[uuid.UUID](t)
+		svc := NewAccountService(repoMock, newUnitOfWork(t), nil)
+
+		cmd := dtos.CreateAccountCommand{
+			Email:    "fake@mail.com",
+			FullName: "Fake Name",
+		}
+
+		_, err := svc.Create(ctx, cmd)
+		assert.ErrorIs(t, err, ErrorPasswordRequired)
+		repoMock.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("create new account should store password hash", func(t *testing.T) {
+		repoMock := repositories.NewAccountRepositoryMock[uuid.UUID](t)
+		svc := NewAccountService(repoMock, newUnitOfWork(t), nil)
+
+		cmd := dtos.CreateAccountCommand{
+			Email:    "fake@mail.com",
+			Password: "secret-password",
+		}
+
+		repoMock.On("Create", mock.Anything, mock.MatchedBy(func(a *entities.Account) bool {
+			return bcrypt.CompareHashAndPassword([]byte(a.PasswordHash), []byte(cmd.Password)) == nil
+		})).Return(nil).Once()
+
+		_, err := svc.Create(ctx, cmd)
+		assert.NoError(t, err)
+	})
+
 	t.Run("create new account with existing email should return error", func(t *testing.T) {
 		repoMock := repositories.NewAccountRepositoryMock[uuid.UUID](t)
-		svc := NewAccountService(repoMock)
+		svc := NewAccountService(repoMock, newUnitOfWork(t), nil)
 
 		cmd := dtos.CreateAccountCommand{
 			Email:       "fake@mail.com",
+			Password:    "secret-password",
 			FullName:    "Fake Name",
 			Location:    "Fake Location",
 			DateOfBirth: time.Date(1990, time.January, 1, 0, 0, 0, 0, time.UTC),
@@ -72,6 +110,94 @@ func TestCreateAccount(t *testing.T) {
 		assert.NotNil(t, err)
 		repoMock.AssertCalled(t, "Create", mock.Anything, mock.Anything)
 	})
+
+	t.Run("create new account with first order should place order on behalf of the account", func(t *testing.T) {
+		repoMock := repositories.NewAccountRepositoryMock[uuid.UUID](t)
+		txManager := core.NewTxManagerMock(t)
+		txManager.On("WithinTx", mock.Anything, mock.Anything).
+			Return(func(ctx context.Context, fn func(ctx context.Context) error) error { return fn(ctx) }).
+			Once()
+		var placedBy core.Principal
+		placeOrder := func(ctx context.Context, cmd dtos.CreateOrderCommand) (dtos.CreateOrderAnswer, error) {
+			placedBy, _ = core.PrincipalFrom(ctx)
+			return dtos.CreateOrderAnswer{OrderDto: dtos.OrderDto{AccountID: cmd.AccountID}}, nil
+		}
+		svc := NewAccountService(repoMock, NewUnitOfWork(txManager), placeOrder)
+
+		repoMock.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+
+		got, err := svc.Create(ctx, dtos.CreateAccountCommand{
+			Email:    "fake@mail.com",
+			Password: "secret-password",
+			Order:    []dtos.OrderItemDto{{ItemID: uuid.NewString(), Quantity: 1}},
+		})
+		assert.NoError(t, err)
+		assert.NotNil(t, got.Order)
+		assert.Equal(t, got.ID, got.Order.AccountID)
+		assert.Equal(t, got.ID, placedBy.AccountID.String())
+	})
+
+	t.Run("create new account with first order which failed should return error", func(t *testing.T) {
+		repoMock := repositories.NewAccountRepositoryMock[uuid.UUID](t)
+		placeOrder := func(context.Context, dtos.CreateOrderCommand) (dtos.CreateOrderAnswer, error) {
+			return dtos.CreateOrderAnswer{}, entities.ErrorInsufficientStock
+		}
+		svc := NewAccountService(repoMock, newUnitOfWork(t), placeOrder)
+
+		repoMock.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+
+		_, err := svc.Create(ctx, dtos.CreateAccountCommand{
+			Email:    "fake@mail.com",
+			Password: "secret-password",
+			Order:    []dtos.OrderItemDto{{ItemID: uuid.NewString(), Quantity: 1}},
+		})
+		assert.ErrorIs(t, err, entities.ErrorInsufficientStock)
+	})
+}
+
+// TestCreateAccountWithFirstOrder runs the services on in-memory repositories,
+// so the unit of work creating the account and placing its first order is rolled back for real.
+func TestCreateAccountWithFirstOrder(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T, stock int) (AccountService, memory.AccountRepository, *entities.Item) {
+		store := memory.NewStore()
+		uow := NewUnitOfWork(memory.NewTxManager(store))
+		item := entities.NewItemBuilder().Title("Dune").Price(entities.NewMoney(750, entities.EUR)).Stock(stock).Build()
+		require.NoError(t, memory.NewItemRepository(store).Create(ctx, item))
+		accounts := memory.NewAccountRepository(store)
+		orderService := NewOrderService(memory.NewOrderRepository(store), uow)
+		return NewAccountService(accounts, uow, orderService.Create), accounts, item
+	}
+	newCommand := func(item *entities.Item, quantity int) dtos.CreateAccountCommand {
+		return dtos.CreateAccountCommand{
+			Email:    "first.order@mail.com",
+			Password: "secret-password",
+			Order:    []dtos.OrderItemDto{{ItemID: item.ID.String(), Quantity: quantity}},
+		}
+	}
+
+	t.Run("should create account together with its first order", func(t *testing.T) {
+		svc, accounts, item := setup(t, 1)
+
+		got, err := svc.Create(ctx, newCommand(item, 1))
+
+		require.NoError(t, err)
+		require.NotNil(t, got.Order)
+		assert.Equal(t, got.ID, got.Order.AccountID)
+		_, err = accounts.GetByEmail(ctx, "first.order@mail.com")
+		assert.Nil(t, err)
+	})
+
+	t.Run("should roll back account when its first order fails", func(t *testing.T) {
+		svc, accounts, item := setup(t, 1)
+
+		_, err := svc.Create(ctx, newCommand(item, 2))
+
+		assert.ErrorIs(t, err, entities.ErrorInsufficientStock)
+		_, err = accounts.GetByEmail(ctx, "first.order@mail.com")
+		assert.ErrorIs(t, err, entities.ErrorEntityNotFound)
+	})
 }
 
 func TestGetAccountById(t *testing.T) {
@@ -80,7 +206,7 @@ func TestGetAccountById(t *testing.T) {
 
 	t.Run("get account by id should return account dto", func(t *testing.T) {
 		repoMock := repositories.NewAccountRepositoryMock[uuid.UUID](t)
-		svc := NewAccountService(repoMock)
+		svc := NewAccountService(repoMock, newUnitOfWork(t), nil)
 
 		a := entities.NewAccountBuilder().
 			Email("fake@mail.com").
@@ -92,7 +218,8 @@ func TestGetAccountById(t *testing.T) {
 
 		repoMock.On("GetById", mock.Anything, mock.Anything).Return(*a, nil).Once()
 
-		got, err := svc.GetById(ctx, uuid.New())
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: a.ID})
+		got, err := svc.GetById(ctx, a.ID)
 		assert.NoError(t, err)
 		assert.Equal(t, a.Email, got.Email)
 		assert.Equal(t, dtos.GenderDto(a.Gender.Stringify()), got.Gender)
@@ -101,12 +228,180 @@ func TestGetAccountById(t *testing.T) {
 
 	t.Run("get account by non existing id should return error", func(t *testing.T) {
 		repoMock := repositories.NewAccountRepositoryMock[uuid.UUID](t)
-		svc := NewAccountService(repoMock)
+		svc := NewAccountService(repoMock, newUnitOfWork(t), nil)
 
 		repoMock.On("GetById", mock.Anything, mock.Anything).Return(entities.Account{}, entities.ErrorEntityNotFound).Once()
 
-		_, err := svc.GetById(ctx, uuid.New())
+		id := uuid.New()
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: id})
+		_, err := svc.GetById(ctx, id)
 		assert.NotNil(t, err)
 		repoMock.AssertCalled(t, "GetById", mock.Anything, mock.Anything)
 	})
+
+	t.Run("get account by id of another account should return forbidden error", func(t *testing.T) {
+		repoMock := repositories.NewAccountRepositoryMock[uuid.UUID](t)
+		svc := NewAccountService(repoMock, newUnitOfWork(t), nil)
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: uuid.New()})
+		_, err := svc.GetById(ctx, uuid.New())
+		assert.ErrorIs(t, err, ErrorForbidden)
+		repoMock.AssertNotCalled(t, "GetById", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUpdateAccount(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	t.Run("update account should return updated account dto", func(t *testing.T) {
+		repoMock := repositories.NewAccountRepositoryMock[uuid.UUID](t)
+		svc := NewAccountService(repoMock, newUnitOfWork(t), nil)
+
+		cmd := dtos.UpdateAccountCommand{
+			ID:       uuid.New(),
+			Email:    "fake@mail.com",
+			FullName: "New Name",
+			Location: "New Location",
+			Gender:   "Female",
+		}
+
+		repoMock.On("Update", mock.Anything, mock.MatchedBy(func(a *entities.Account) bool {
+			return a.ID == cmd.ID && a.FullName == cmd.FullName && a.Gender == entities.FEMALE
+		})).Return(nil).Once()
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: cmd.ID})
+		got, err := svc.Update(ctx, cmd)
+		assert.NoError(t, err)
+		assert.Equal(t, cmd.ID.String(), got.ID)
+		assert.Equal(t, cmd.Location, got.Location)
+	})
+
+	t.Run("update account with taken email should return conflict error", func(t *testing.T) {
+		repoMock := repositories.NewAccountRepositoryMock[uuid.UUID](t)
+		svc := NewAccountService(repoMock, newUnitOfWork(t), nil)
+
+		cmd := dtos.UpdateAccountCommand{ID: uuid.New(), Email: "taken@mail.com"}
+		repoMock.On("Update", mock.Anything, mock.Anything).Return(entities.ErrorEmailNotUnique).Once()
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: cmd.ID})
+		_, err := svc.Update(ctx, cmd)
+		assert.ErrorIs(t, err, ErrorEmailNotUnique)
+	})
+
+	t.Run("update another account should return forbidden error", func(t *testing.T) {
+		repoMock := repositories.NewAccountRepositoryMock[uuid.UUID](t)
+		svc := NewAccountService(repoMock, newUnitOfWork(t), nil)
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: uuid.New()})
+		_, err := svc.Update(ctx, dtos.UpdateAccountCommand{ID: uuid.New(), Email: "fake@mail.com"})
+		assert.ErrorIs(t, err, ErrorForbidden)
+		repoMock.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
+func TestPatchAccount(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	existing := entities.NewAccountBuilder().
+		Email("fake@mail.com").
+		FullName("Fake Name").
+		Location("Fake Location").
+		Gender(entities.MALE).
+		Build()
+
+	t.Run("patch account should change only patched fields", func(t *testing.T) {
+		repoMock := repositories.NewAccountRepositoryMock[uuid.UUID](t)
+		svc := NewAccountService(repoMock, newUnitOfWork(t), nil)
+
+		repoMock.On("GetById", mock.Anything, existing.ID).Return(*existing, nil).Once()
+		repoMock.On("Update", mock.Anything, mock.MatchedBy(func(a *entities.Account) bool {
+			return a.Email == existing.Email && a.FullName == existing.FullName && a.Location == "Vienna/AUT" && a.Version == 2
+		})).Return(nil).Once()
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: existing.ID})
+		got, err := svc.Patch(ctx, dtos.PatchAccountCommand{ID: existing.ID, Version: 2, Patch: []byte(`{"location":"Vienna/AUT"}`)})
+		assert.NoError(t, err)
+		assert.Equal(t, "Vienna/AUT", got.Location)
+		assert.Equal(t, existing.FullName, got.FullName)
+	})
+
+	t.Run("patch account with null should clear the field", func(t *testing.T) {
+		repoMock := repositories.NewAccountRepositoryMock[uuid.UUID](t)
+		svc := NewAccountService(repoMock, newUnitOfWork(t), nil)
+
+		repoMock.On("GetById", mock.Anything, existing.ID).Return(*existing, nil).Once()
+		repoMock.On("Update", mock.Anything, mock.MatchedBy(func(a *entities.Account) bool {
+			return a.Location == ""
+		})).Return(nil).Once()
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: existing.ID})
+		_, err := svc.Patch(ctx, dtos.PatchAccountCommand{ID: existing.ID, Patch: []byte(`{"location":null}`)})
+		assert.NoError(t, err)
+	})
+
+	t.Run("patch account removing email should return error", func(t *testing.T) {
+		repoMock := repositories.NewAccountRepositoryMock[uuid.UUID](t)
+		svc := NewAccountService(repoMock, newUnitOfWork(t), nil)
+
+		repoMock.On("GetById", mock.Anything, existing.ID).Return(*existing, nil).Once()
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: existing.ID})
+		_, err := svc.Patch(ctx, dtos.PatchAccountCommand{ID: existing.ID, Patch: []byte(`{"email":null}`)})
+		assert.ErrorIs(t, err, ErrorEmailRequired)
+		repoMock.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("patch account with mistyped field should return error", func(t *testing.T) {
+		repoMock := repositories.NewAccountRepositoryMock[uuid.UUID](t)
+		svc := NewAccountService(repoMock, newUnitOfWork(t), nil)
+
+		repoMock.On("GetById", mock.Anything, existing.ID).Return(*existing, nil).Once()
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: existing.ID})
+		_, err := svc.Patch(ctx, dtos.PatchAccountCommand{ID: existing.ID, Patch: []byte(`{"full_name":42}`)})
+		assert.ErrorIs(t, err, ErrorInvalidPatch)
+	})
+}
+
+func TestDeleteAccount(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	t.Run("delete account should soft delete it", func(t *testing.T) {
+		repoMock := repositories.NewAccountRepositoryMock[uuid.UUID](t)
+		svc := NewAccountService(repoMock, newUnitOfWork(t), nil)
+
+		id := uuid.New()
+		repoMock.On("DeleteById", mock.Anything, id, int64(3)).Return(nil).Once()
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: id})
+		_, err := svc.Delete(ctx, dtos.DeleteAccountCommand{ID: id, Version: 3})
+		assert.NoError(t, err)
+		repoMock.AssertNotCalled(t, "ForceDeleteById", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("force delete by account itself should return forbidden error", func(t *testing.T) {
+		repoMock := repositories.NewAccountRepositoryMock[uuid.UUID](t)
+		svc := NewAccountService(repoMock, newUnitOfWork(t), nil)
+
+		id := uuid.New()
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: id})
+		_, err := svc.Delete(ctx, dtos.DeleteAccountCommand{ID: id, Force: true})
+		assert.ErrorIs(t, err, ErrorForbidden)
+		repoMock.AssertNotCalled(t, "ForceDeleteById", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("force delete by admin should permanently delete account", func(t *testing.T) {
+		repoMock := repositories.NewAccountRepositoryMock[uuid.UUID](t)
+		svc := NewAccountService(repoMock, newUnitOfWork(t), nil)
+
+		id := uuid.New()
+		repoMock.On("ForceDeleteById", mock.Anything, id, int64(0)).Return(nil).Once()
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: uuid.New(), Role: entities.ADMIN})
+		_, err := svc.Delete(ctx, dtos.DeleteAccountCommand{ID: id, Force: true})
+		assert.NoError(t, err)
+	})
 }
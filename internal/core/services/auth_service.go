@@ -0,0 +1,48 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fmiskovic/new-amz/internal/core"
+	"github.com/fmiskovic/new-amz/internal/core/dtos"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/core/repositories"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const tokenType = "Bearer"
+
+// AuthService represents business logic related to account authentication.
+type AuthService struct {
+	repo   repositories.AccountRepository[uuid.UUID]
+	issuer core.TokenIssuer
+}
+
+// NewAuthService instantiates new AuthService.
+func NewAuthService(repo repositories.AccountRepository[uuid.UUID], issuer core.TokenIssuer) AuthService {
+	return AuthService{repo: repo, issuer: issuer}
+}
+
+// Login verifies account credentials and issues a new access token.
+func (s AuthService) Login(ctx context.Context, cmd dtos.LoginCommand) (dtos.TokenDto, error) {
+	a, err := s.repo.GetByEmail(ctx, cmd.Email)
+	if err != nil {
+		if errors.Is(err, entities.ErrorNotFound) {
+			return dtos.TokenDto{}, ErrorInvalidCredentials
+		}
+		return dtos.TokenDto{}, newError("failed to get account by email", err)
+	}
+
+	if a.PasswordHash == "" || bcrypt.CompareHashAndPassword([]byte(a.PasswordHash), []byte(cmd.Password)) != nil {
+		return dtos.TokenDto{}, ErrorInvalidCredentials
+	}
+
+	token, expiresAt, err := s.issuer.Issue(a)
+	if err != nil {
+		return dtos.TokenDto{}, newError("failed to issue access token", err)
+	}
+
+	return dtos.TokenDto{AccessToken: token, TokenType: tokenType, ExpiresAt: expiresAt}, nil
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/fmiskovic/new-amz/internal/core"
+	"github.com/fmiskovic/new-amz/internal/core/dtos"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/core/repositories"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+	"testing"
+	"time"
+)
+
+func TestLogin(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret-password"), bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	a := entities.NewAccountBuilder().
+		Email("fake@mail.com").
+		PasswordHash(string(hash)).
+		Build()
+
+	t.Run("login with valid credentials should return access token", func(t *testing.T) {
+		repoMock := repositories.NewAccountRepositoryMock[uuid.UUID](t)
+		issuerMock := core.NewTokenIssuerMock(t)
+		svc := NewAuthService(repoMock, issuerMock)
+
+		expiresAt := time.Now().Add(time.Hour)
+		repoMock.On("GetByEmail", mock.Anything, a.Email).Return(*a, nil).Once()
+		issuerMock.On("Issue", *a).Return("signed-token", expiresAt, nil).Once()
+
+		got, err := svc.Login(ctx, dtos.LoginCommand{Email: a.Email, Password: "secret-password"})
+		assert.NoError(t, err)
+		assert.Equal(t, "signed-token", got.AccessToken)
+		assert.Equal(t, "Bearer", got.TokenType)
+		assert.Equal(t, expiresAt, got.ExpiresAt)
+	})
+
+	t.Run("login with wrong password should return invalid credentials error", func(t *testing.T) {
+		repoMock := repositories.NewAccountRepositoryMock[uuid.UUID](t)
+		issuerMock := core.NewTokenIssuerMock(t)
+		svc := NewAuthService(repoMock, issuerMock)
+
+		repoMock.On("GetByEmail", mock.Anything, a.Email).Return(*a, nil).Once()
+
+		_, err := svc.Login(ctx, dtos.LoginCommand{Email: a.Email, Password: "wrong-password"})
+		assert.ErrorIs(t, err, ErrorInvalidCredentials)
+		issuerMock.AssertNotCalled(t, "Issue", mock.Anything)
+	})
+
+	t.Run("login with unknown email should return invalid credentials error", func(t *testing.T) {
+		repoMock := repositories.NewAccountRepositoryMock[uuid.UUID](t)
+		issuerMock := core.NewTokenIssuerMock(t)
+		svc := NewAuthService(repoMock, issuerMock)
+
+		repoMock.On("GetByEmail", mock.Anything, mock.Anything).Return(entities.Account{}, entities.ErrorEntityNotFound).Once()
+
+		_, err := svc.Login(ctx, dtos.LoginCommand{Email: "unknown@mail.com", Password: "secret-password"})
+		assert.ErrorIs(t, err, ErrorInvalidCredentials)
+	})
+
+	t.Run("login when token can not be issued should return error", func(t *testing.T) {
+		repoMock := repositories.NewAccountRepositoryMock[uuid.UUID](t)
+		issuerMock := core.NewTokenIssuerMock(t)
+		svc := NewAuthService(repoMock, issuerMock)
+
+		repoMock.On("GetByEmail", mock.Anything, a.Email).Return(*a, nil).Once()
+		issuerMock.On("Issue", *a).Return("", time.Time{}, errors.New("unexpected error")).Once()
+
+		_, err := svc.Login(ctx, dtos.LoginCommand{Email: a.Email, Password: "secret-password"})
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/fmiskovic/new-amz/internal/core/dtos"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/core/repositories"
+	"github.com/google/uuid"
+)
+
+// AuthorService represents business logic related to entities.Author.
+type AuthorService struct {
+	repo repositories.AuthorRepository[uuid.UUID]
+}
+
+// NewAuthorService instantiates new AuthorService.
+func NewAuthorService(repo repositories.AuthorRepository[uuid.UUID]) AuthorService {
+	return AuthorService{repo}
+}
+
+// GetById returns existing author by id.
+func (s AuthorService) GetById(ctx context.Context, id uuid.UUID) (dtos.AuthorDto, error) {
+	author, err := s.repo.GetById(ctx, id)
+	if err != nil {
+		return dtos.AuthorDto{}, newError(fmt.Sprintf("failed to get author by id: %s", id.String()), err)
+	}
+	return dtos.ToAuthorDto(author), nil
+}
+
+// GetPage returns page of authors.
+func (s AuthorService) GetPage(ctx context.Context, p entities.Pageable) (entities.Page[dtos.AuthorDto], error) {
+	page, err := s.repo.GetPage(ctx, p)
+	if err != nil {
+		return entities.Page[dtos.AuthorDto]{}, newError("failed to get page of authors", err)
+	}
+	return dtos.ToPageAuthorDto(page), nil
+}
+
+// Create creates new author.
+func (s AuthorService) Create(ctx context.Context, dto dtos.AuthorDto) (dtos.AuthorDto, error) {
+	if _, err := authorizeAdmin(ctx); err != nil {
+		return dtos.AuthorDto{}, err
+	}
+
+	dto.ID = ""
+	author, err := dtos.ToAuthorEntity(dto)
+	if err != nil {
+		return dtos.AuthorDto{}, newError("invalid author", err)
+	}
+
+	if err := s.repo.Create(ctx, author); err != nil {
+		return dtos.AuthorDto{}, newError("failed to create author", err)
+	}
+	return dtos.ToAuthorDto(*author), nil
+}
+
+// Update replaces existing author.
+func (s AuthorService) Update(ctx context.Context, dto dtos.AuthorDto) (dtos.AuthorDto, error) {
+	if _, err := authorizeAdmin(ctx); err != nil {
+		return dtos.AuthorDto{}, err
+	}
+
+	author, err := dtos.ToAuthorEntity(dto)
+	if err != nil {
+		return dtos.AuthorDto{}, newError("invalid author", err)
+	}
+
+	if err := s.repo.Update(ctx, author); err != nil {
+		return dtos.AuthorDto{}, newError(fmt.Sprintf("failed to update author: %s", author.ID.String()), err)
+	}
+	return dtos.ToAuthorDto(*author), nil
+}
+
+// DeleteById deletes existing author who is not credited on any item.
+func (s AuthorService) DeleteById(ctx context.Context, cmd dtos.DeleteCommand) (struct{}, error) {
+	if _, err := authorizeAdmin(ctx); err != nil {
+		return struct{}{}, err
+	}
+
+	if err := s.repo.DeleteById(ctx, cmd.ID, cmd.Version); err != nil {
+		return struct{}{}, newError(fmt.Sprintf("failed to delete author: %s", cmd.ID.String()), err)
+	}
+	return struct{}{}, nil
+}
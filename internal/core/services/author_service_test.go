@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"github.com/fmiskovic/new-amz/internal/core"
+	"github.com/fmiskovic/new-amz/internal/core/dtos"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/core/repositories"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestGetAuthorById(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	t.Run("should return author dto", func(t *testing.T) {
+		repoMock := repositories.NewAuthorRepositoryMock[uuid.UUID](t)
+		svc := NewAuthorService(repoMock)
+
+		author := entities.NewAuthorBuilder().Name("Frank Herbert").Biography("American science fiction author").Build()
+		repoMock.On("GetById", mock.Anything, author.ID).Return(*author, nil).Once()
+
+		got, err := svc.GetById(ctx, author.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, author.ID.String(), got.ID)
+		assert.Equal(t, "Frank Herbert", got.Name)
+		assert.Equal(t, "American science fiction author", got.Biography)
+	})
+
+	t.Run("given unknown author should return not found error", func(t *testing.T) {
+		repoMock := repositories.NewAuthorRepositoryMock[uuid.UUID](t)
+		svc := NewAuthorService(repoMock)
+
+		repoMock.On("GetById", mock.Anything, mock.Anything).Return(entities.Author{}, entities.ErrorEntityNotFound).Once()
+
+		_, err := svc.GetById(ctx, uuid.New())
+		assert.ErrorIs(t, err, entities.ErrorNotFound)
+	})
+}
+
+func TestCreateAuthor(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	dto := dtos.AuthorDto{Name: " Ursula K. Le Guin "}
+
+	t.Run("admin should create author", func(t *testing.T) {
+		repoMock := repositories.NewAuthorRepositoryMock[uuid.UUID](t)
+		svc := NewAuthorService(repoMock)
+
+		repoMock.On("Create", mock.Anything, mock.MatchedBy(func(a *entities.Author) bool {
+			return a.Name == "Ursula K. Le Guin"
+		})).Return(nil).Once()
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: uuid.New(), Role: entities.ADMIN})
+		got, err := svc.Create(ctx, dto)
+		assert.Nil(t, err)
+		assert.NotEmpty(t, got.ID)
+		assert.Equal(t, "Ursula K. Le Guin", got.Name)
+	})
+
+	t.Run("customer should not be allowed to create author", func(t *testing.T) {
+		repoMock := repositories.NewAuthorRepositoryMock[uuid.UUID](t)
+		svc := NewAuthorService(repoMock)
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: uuid.New(), Role: entities.CUSTOMER})
+		_, err := svc.Create(ctx, dto)
+		assert.ErrorIs(t, err, ErrorForbidden)
+		repoMock.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestDeleteAuthor(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	t.Run("admin should delete author", func(t *testing.T) {
+		repoMock := repositories.NewAuthorRepositoryMock[uuid.UUID](t)
+		svc := NewAuthorService(repoMock)
+
+		id := uuid.New()
+		repoMock.On("DeleteById", mock.Anything, id, int64(3)).Return(nil).Once()
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: uuid.New(), Role: entities.ADMIN})
+		_, err := svc.DeleteById(ctx, dtos.DeleteCommand{ID: id, Version: 3})
+		assert.Nil(t, err)
+	})
+
+	t.Run("delete of author credited on items should return conflict error", func(t *testing.T) {
+		repoMock := repositories.NewAuthorRepositoryMock[uuid.UUID](t)
+		svc := NewAuthorService(repoMock)
+
+		repoMock.On("DeleteById", mock.Anything, mock.Anything, mock.Anything).Return(entities.ErrorAuthorHasItems).Once()
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: uuid.New(), Role: entities.ADMIN})
+		_, err := svc.DeleteById(ctx, dtos.DeleteCommand{ID: uuid.New()})
+		assert.ErrorIs(t, err, entities.ErrorAuthorHasItems)
+		assert.ErrorIs(t, err, entities.ErrorConflict)
+	})
+}
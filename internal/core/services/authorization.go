@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+
+	"github.com/fmiskovic/new-amz/internal/core"
+	"github.com/google/uuid"
+)
+
+// authorize checks that the principal calling the service owns the resource belonging to ownerId.
+// Administrators are allowed to access resources of any account.
+func authorize(ctx context.Context, ownerId uuid.UUID) error {
+	p, ok := core.PrincipalFrom(ctx)
+	if !ok || (p.AccountID != ownerId && !p.IsAdmin()) {
+		return ErrorForbidden
+	}
+	return nil
+}
+
+// authorizeAdmin checks that the principal calling the service is an administrator.
+// Managing the catalogue, its taxonomy and webhook subscriptions is reserved for administrators,
+// and so is driving orders and payments through their lifecycle.
+func authorizeAdmin(ctx context.Context) (core.Principal, error) {
+	p, ok := core.PrincipalFrom(ctx)
+	if !ok || !p.IsAdmin() {
+		return core.Principal{}, ErrorForbidden
+	}
+	return p, nil
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/fmiskovic/new-amz/internal/core"
+	"github.com/fmiskovic/new-amz/internal/core/dtos"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/core/repositories"
+	"github.com/google/uuid"
+)
+
+// CartService represents business logic related to entities.Cart.
+type CartService struct {
+	repo       repositories.CartRepository[uuid.UUID]
+	placeOrder core.ServiceFunc[dtos.CreateOrderCommand, dtos.CreateOrderAnswer]
+}
+
+// NewCartService instantiates new CartService.
+// Carts are checked out by placing an order with placeOrder, usually OrderService.Create.
+func NewCartService(
+	repo repositories.CartRepository[uuid.UUID],
+	placeOrder core.ServiceFunc[dtos.CreateOrderCommand, dtos.CreateOrderAnswer],
+) CartService {
+	return CartService{repo: repo, placeOrder: placeOrder}
+}
+
+// Get returns the cart with its items valued at their current prices.
+func (s CartService) Get(ctx context.Context, query dtos.CartQuery) (dtos.CartDto, error) {
+	cart, err := s.resolve(ctx, query.CartID)
+	if err != nil {
+		return dtos.CartDto{}, err
+	}
+	return dtos.ToCartDto(cart), nil
+}
+
+// CreateGuest creates new empty guest cart. Its id is the only way to access it, so it is handed out to the guest only.
+func (s CartService) CreateGuest(ctx context.Context, _ struct{}) (dtos.CartDto, error) {
+	cart := entities.NewCartBuilder().Build()
+	if err := s.repo.Create(ctx, cart); err != nil {
+		return dtos.CartDto{}, newError("failed to create guest cart", err)
+	}
+	return dtos.ToCartDto(*cart), nil
+}
+
+// AddItem adds units of the item to the cart, on top of the units already in the cart.
+// The item has to have enough units in stock for the resulting quantity.
+func (s CartService) AddItem(ctx context.Context, cmd dtos.CartItemCommand) (dtos.CartDto, error) {
+	return s.putItem(ctx, cmd, s.repo.AddItem)
+}
+
+// SetItemQuantity sets quantity of the item in the cart, the item is added if it is not in the cart yet.
+// The item has to have enough units in stock for the quantity.
+func (s CartService) SetItemQuantity(ctx context.Context, cmd dtos.CartItemCommand) (dtos.CartDto, error) {
+	return s.putItem(ctx, cmd, s.repo.SetItemQuantity)
+}
+
+func (s CartService) putItem(
+	ctx context.Context,
+	cmd dtos.CartItemCommand,
+	put func(ctx context.Context, cartId uuid.UUID, itemId uuid.UUID, quantity int) error,
+) (dtos.CartDto, error) {
+	itemId, err := uuid.Parse(cmd.ItemID)
+	if err != nil {
+		return dtos.CartDto{}, newError("invalid item id", err)
+	}
+
+	cart, err := s.resolve(ctx, cmd.CartID)
+	if err != nil {
+		return dtos.CartDto{}, err
+	}
+
+	if err := put(ctx, cart.ID, itemId, cmd.Quantity); err != nil {
+		return dtos.CartDto{}, newError(fmt.Sprintf("failed to put item %s into cart: %s", itemId.String(), cart.ID.String()), err)
+	}
+	return s.reload(ctx, cart.ID)
+}
+
+// RemoveItem removes the item from the cart.
+func (s CartService) RemoveItem(ctx context.Context, cmd dtos.RemoveCartItemCommand) (dtos.CartDto, error) {
+	cart, err := s.resolve(ctx, cmd.CartID)
+	if err != nil {
+		return dtos.CartDto{}, err
+	}
+
+	if err := s.repo.RemoveItem(ctx, cart.ID, cmd.ItemID); err != nil {
+		return dtos.CartDto{}, newError(fmt.Sprintf("failed to remove item %s from cart: %s", cmd.ItemID.String(), cart.ID.String()), err)
+	}
+	return s.reload(ctx, cart.ID)
+}
+
+// Clear removes all items from the cart.
+func (s CartService) Clear(ctx context.Context, query dtos.CartQuery) (struct{}, error) {
+	cart, err := s.resolve(ctx, query.CartID)
+	if err != nil {
+		return struct{}{}, err
+	}
+
+	if err := s.repo.Clear(ctx, cart.ID); err != nil {
+		return struct{}{}, newError(fmt.Sprintf("failed to clear cart: %s", cart.ID.String()), err)
+	}
+	return struct{}{}, nil
+}
+
+// Merge moves items of the guest cart into the cart of the calling account and deletes the guest cart.
+// Guests call it right after logging in, so they keep what they collected before.
+func (s CartService) Merge(ctx context.Context, cmd dtos.MergeCartCommand) (dtos.CartDto, error) {
+	guestId, err := uuid.Parse(cmd.CartID)
+	if err != nil {
+		return dtos.CartDto{}, newError("invalid cart id", err)
+	}
+
+	guest, err := s.repo.GetById(ctx, guestId)
+	if err != nil {
+		return dtos.CartDto{}, newError(fmt.Sprintf("failed to get guest cart: %s", guestId.String()), err)
+	}
+	if !guest.IsGuest() {
+		return dtos.CartDto{}, entities.ErrorCartNotGuest
+	}
+
+	cart, err := s.resolve(ctx, uuid.Nil)
+	if err != nil {
+		return dtos.CartDto{}, err
+	}
+
+	if err := s.repo.Merge(ctx, guest.ID, cart.ID); err != nil {
+		return dtos.CartDto{}, newError(fmt.Sprintf("failed to merge guest cart %s into cart: %s", guest.ID.String(), cart.ID.String()), err)
+	}
+	return s.reload(ctx, cart.ID)
+}
+
+// Checkout places an order for the items of the cart and empties the cart, both or none of it happens.
+// Prices and stock are checked when the order is placed, so the order is rejected if any item ran out of stock.
+// Guest carts have to be merged into an account cart before they are checked out.
+func (s CartService) Checkout(ctx context.Context, query dtos.CartQuery) (dtos.CreateOrderAnswer, error) {
+	cart, err := s.resolve(ctx, query.CartID)
+	if err != nil {
+		return dtos.CreateOrderAnswer{}, err
+	}
+	if cart.IsGuest() {
+		return dtos.CreateOrderAnswer{}, entities.ErrorGuestCheckout
+	}
+
+	var answer dtos.CreateOrderAnswer
+	err = s.repo.Checkout(ctx, cart.ID, func(ctx context.Context, locked entities.Cart) error {
+		// the cart may have changed since it was resolved, only the locked cart is ordered
+		if len(locked.Items) == 0 {
+			return entities.ErrorCartEmpty
+		}
+
+		var err error
+		answer, err = s.placeOrder(ctx, dtos.CreateOrderCommand{
+			AccountID: locked.AccountID.String(),
+			Items:     dtos.ToOrderItemDtos(locked),
+		})
+		return err
+	})
+	if err != nil {
+		return dtos.CreateOrderAnswer{}, newError(fmt.Sprintf("failed to check out cart: %s", cart.ID.String()), err)
+	}
+	return answer, nil
+}
+
+// resolve returns the guest cart by id or, if id is zero, the cart of the calling account.
+// Account carts are only accessible to their accounts and administrators.
+func (s CartService) resolve(ctx context.Context, id uuid.UUID) (entities.Cart, error) {
+	if id == uuid.Nil {
+		p, ok := core.PrincipalFrom(ctx)
+		if !ok {
+			return entities.Cart{}, ErrorForbidden
+		}
+		cart, err := s.repo.GetByAccount(ctx, p.AccountID)
+		if err != nil {
+			return entities.Cart{}, newError(fmt.Sprintf("failed to get cart of account: %s", p.AccountID.String()), err)
+		}
+		return cart, nil
+	}
+
+	cart, err := s.repo.GetById(ctx, id)
+	if err != nil {
+		return entities.Cart{}, newError(fmt.Sprintf("failed to get cart: %s", id.String()), err)
+	}
+	if !cart.IsGuest() {
+		if err := authorize(ctx, *cart.AccountID); err != nil {
+			return entities.Cart{}, err
+		}
+	}
+	return cart, nil
+}
+
+func (s CartService) reload(ctx context.Context, id uuid.UUID) (dtos.CartDto, error) {
+	cart, err := s.repo.GetById(ctx, id)
+	if err != nil {
+		return dtos.CartDto{}, newError(fmt.Sprintf("failed to get cart: %s", id.String()), err)
+	}
+	return dtos.ToCartDto(cart), nil
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"github.com/fmiskovic/new-amz/internal/core"
+	"github.com/fmiskovic/new-amz/internal/core/dtos"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/core/repositories"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestGetCart(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	accountId := uuid.New()
+	customerCtx := core.WithPrincipal(ctx, core.Principal{AccountID: accountId, Role: entities.CUSTOMER})
+
+	t.Run("should return cart of calling account valued at current prices", func(t *testing.T) {
+		repoMock := repositories.NewCartRepositoryMock[uuid.UUID](t)
+		svc := NewCartService(repoMock, nil)
+
+		cart := entities.NewCartBuilder().AccountID(accountId).Build()
+		item := entities.NewItemBuilder().Title("Dune").Price(entities.NewMoney(1250, entities.EUR)).Stock(1).Build()
+		cart.Items = []*entities.CartItem{{CartID: cart.ID, ItemID: item.ID, Item: item, Quantity: 2}}
+
+		repoMock.On("GetByAccount", mock.Anything, accountId).Return(*cart, nil).Once()
+
+		got, err := svc.Get(customerCtx, dtos.CartQuery{})
+		assert.Nil(t, err)
+		assert.Equal(t, accountId.String(), got.AccountID)
+		assert.Len(t, got.Items, 1)
+		assert.Equal(t, "Dune", got.Items[0].Title)
+		assert.Equal(t, entities.NewMoney(2500, entities.EUR), got.Items[0].LineTotal)
+		assert.False(t, got.Items[0].InStock)
+		assert.Equal(t, entities.NewMoney(2500, entities.EUR), *got.Subtotal)
+	})
+
+	t.Run("should return guest cart to anyone", func(t *testing.T) {
+		repoMock := repositories.NewCartRepositoryMock[uuid.UUID](t)
+		svc := NewCartService(repoMock, nil)
+
+		cart := entities.NewCartBuilder().Build()
+		repoMock.On("GetById", mock.Anything, cart.ID).Return(*cart, nil).Once()
+
+		got, err := svc.Get(ctx, dtos.CartQuery{CartID: cart.ID})
+		assert.Nil(t, err)
+		assert.Equal(t, cart.ID.String(), got.ID)
+		assert.Empty(t, got.AccountID)
+	})
+
+	t.Run("should forbid access to cart of another account", func(t *testing.T) {
+		repoMock := repositories.NewCartRepositoryMock[uuid.UUID](t)
+		svc := NewCartService(repoMock, nil)
+
+		cart := entities.NewCartBuilder().AccountID(uuid.New()).Build()
+		repoMock.On("GetById", mock.Anything, cart.ID).Return(*cart, nil).Once()
+
+		_, err := svc.Get(customerCtx, dtos.CartQuery{CartID: cart.ID})
+		assert.ErrorIs(t, err, ErrorForbidden)
+	})
+}
+
+func TestAddCartItem(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	cart := entities.NewCartBuilder().Build()
+	itemId := uuid.New()
+
+	t.Run("should add item to cart and return updated cart", func(t *testing.T) {
+		repoMock := repositories.NewCartRepositoryMock[uuid.UUID](t)
+		svc := NewCartService(repoMock, nil)
+
+		updated := *cart
+		updated.Items = []*entities.CartItem{{CartID: cart.ID, ItemID: itemId, Quantity: 2}}
+
+		repoMock.On("GetById", mock.Anything, cart.ID).Return(*cart, nil).Once()
+		repoMock.On("AddItem", mock.Anything, cart.ID, itemId, 2).Return(nil).Once()
+		repoMock.On("GetById", mock.Anything, cart.ID).Return(updated, nil).Once()
+
+		got, err := svc.AddItem(ctx, dtos.CartItemCommand{CartID: cart.ID, ItemID: itemId.String(), Quantity: 2})
+		assert.Nil(t, err)
+		assert.Len(t, got.Items, 1)
+		assert.Equal(t, 2, got.Items[0].Quantity)
+	})
+
+	t.Run("should return conflict error if item is out of stock", func(t *testing.T) {
+		repoMock := repositories.NewCartRepositoryMock[uuid.UUID](t)
+		svc := NewCartService(repoMock, nil)
+
+		repoMock.On("GetById", mock.Anything, cart.ID).Return(*cart, nil).Once()
+		repoMock.On("AddItem", mock.Anything, cart.ID, itemId, 5).
+			Return(entities.InsufficientStockError{ItemID: itemId, Requested: 5, Available: 1}).Once()
+
+		_, err := svc.AddItem(ctx, dtos.CartItemCommand{CartID: cart.ID, ItemID: itemId.String(), Quantity: 5})
+		assert.ErrorIs(t, err, entities.ErrorConflict)
+	})
+}
+
+func TestMergeCart(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	accountId := uuid.New()
+	customerCtx := core.WithPrincipal(ctx, core.Principal{AccountID: accountId, Role: entities.CUSTOMER})
+	cart := entities.NewCartBuilder().AccountID(accountId).Build()
+
+	t.Run("should merge guest cart into cart of calling account", func(t *testing.T) {
+		repoMock := repositories.NewCartRepositoryMock[uuid.UUID](t)
+		svc := NewCartService(repoMock, nil)
+
+		guest := entities.NewCartBuilder().Build()
+		repoMock.On("GetById", mock.Anything, guest.ID).Return(*guest, nil).Once()
+		repoMock.On("GetByAccount", mock.Anything, accountId).Return(*cart, nil).Once()
+		repoMock.On("Merge", mock.Anything, guest.ID, cart.ID).Return(nil).Once()
+		repoMock.On("GetById", mock.Anything, cart.ID).Return(*cart, nil).Once()
+
+		got, err := svc.Merge(customerCtx, dtos.MergeCartCommand{CartID: guest.ID.String()})
+		assert.Nil(t, err)
+		assert.Equal(t, cart.ID.String(), got.ID)
+	})
+
+	t.Run("should reject merging cart of an account", func(t *testing.T) {
+		repoMock := repositories.NewCartRepositoryMock[uuid.UUID](t)
+		svc := NewCartService(repoMock, nil)
+
+		other := entities.NewCartBuilder().AccountID(uuid.New()).Build()
+		repoMock.On("GetById", mock.Anything, other.ID).Return(*other, nil).Once()
+
+		_, err := svc.Merge(customerCtx, dtos.MergeCartCommand{CartID: other.ID.String()})
+		assert.ErrorIs(t, err, entities.ErrorCartNotGuest)
+	})
+}
+
+func TestCheckoutCart(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	accountId := uuid.New()
+	customerCtx := core.WithPrincipal(ctx, core.Principal{AccountID: accountId, Role: entities.CUSTOMER})
+	cart := entities.NewCartBuilder().AccountID(accountId).Build()
+	itemId := uuid.New()
+
+	checkout := func(locked entities.Cart) func(context.Context, uuid.UUID, func(context.Context, entities.Cart) error) error {
+		return func(ctx context.Context, _ uuid.UUID, place func(context.Context, entities.Cart) error) error {
+			return place(ctx, locked)
+		}
+	}
+
+	t.Run("should place order for items of the locked cart", func(t *testing.T) {
+		repoMock := repositories.NewCartRepositoryMock[uuid.UUID](t)
+		var placed dtos.CreateOrderCommand
+		svc := NewCartService(repoMock, func(ctx context.Context, cmd dtos.CreateOrderCommand) (dtos.CreateOrderAnswer, error) {
+			placed = cmd
+			return dtos.CreateOrderAnswer{OrderDto: dtos.OrderDto{ID: uuid.NewString(), AccountID: cmd.AccountID}}, nil
+		})
+
+		locked := *cart
+		locked.Items = []*entities.CartItem{{CartID: cart.ID, ItemID: itemId, Quantity: 3}}
+
+		repoMock.On("GetByAccount", mock.Anything, accountId).Return(*cart, nil).Once()
+		repoMock.On("Checkout", mock.Anything, cart.ID, mock.Anything).Return(checkout(locked)).Once()
+
+		got, err := svc.Checkout(customerCtx, dtos.CartQuery{})
+		assert.Nil(t, err)
+		assert.NotEmpty(t, got.ID)
+		assert.Equal(t, accountId.String(), placed.AccountID)
+		assert.Equal(t, []dtos.OrderItemDto{{ItemID: itemId.String(), Quantity: 3}}, placed.Items)
+	})
+
+	t.Run("should reject checkout of empty cart", func(t *testing.T) {
+		repoMock := repositories.NewCartRepositoryMock[uuid.UUID](t)
+		svc := NewCartService(repoMock, func(ctx context.Context, cmd dtos.CreateOrderCommand) (dtos.CreateOrderAnswer, error) {
+			t.Fatal("order must not be placed")
+			return dtos.CreateOrderAnswer{}, nil
+		})
+
+		repoMock.On("GetByAccount", mock.Anything, accountId).Return(*cart, nil).Once()
+		repoMock.On("Checkout", mock.Anything, cart.ID, mock.Anything).Return(checkout(*cart)).Once()
+
+		_, err := svc.Checkout(customerCtx, dtos.CartQuery{})
+		assert.ErrorIs(t, err, entities.ErrorCartEmpty)
+	})
+
+	t.Run("should reject checkout of guest cart", func(t *testing.T) {
+		repoMock := repositories.NewCartRepositoryMock[uuid.UUID](t)
+		svc := NewCartService(repoMock, nil)
+
+		guest := entities.NewCartBuilder().Build()
+		repoMock.On("GetById", mock.Anything, guest.ID).Return(*guest, nil).Once()
+
+		_, err := svc.Checkout(ctx, dtos.CartQuery{CartID: guest.ID})
+		assert.ErrorIs(t, err, entities.ErrorGuestCheckout)
+	})
+}
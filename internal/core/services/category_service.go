@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/fmiskovic/new-amz/internal/core/dtos"
+	"github.com/fmiskovic/new-amz/internal/core/repositories"
+	"github.com/google/uuid"
+)
+
+// CategoryService represents business logic related to entities.Category.
+type CategoryService struct {
+	repo repositories.CategoryRepository[uuid.UUID]
+}
+
+// NewCategoryService instantiates new CategoryService.
+func NewCategoryService(repo repositories.CategoryRepository[uuid.UUID]) CategoryService {
+	return CategoryService{repo}
+}
+
+// GetTopLevel returns categories without a parent, the entry points for browsing the taxonomy.
+func (s CategoryService) GetTopLevel(ctx context.Context, _ struct{}) ([]dtos.CategoryDto, error) {
+	categories, err := s.repo.GetTopLevel(ctx)
+	if err != nil {
+		return nil, newError("failed to get top level categories", err)
+	}
+	return dtos.ToCategoryDtos(categories), nil
+}
+
+// GetSubtree returns existing category by id with all of its subcategories nested under it.
+func (s CategoryService) GetSubtree(ctx context.Context, id uuid.UUID) (dtos.CategoryDto, error) {
+	category, err := s.repo.GetSubtree(ctx, id)
+	if err != nil {
+		return dtos.CategoryDto{}, newError(fmt.Sprintf("failed to get category subtree: %s", id.String()), err)
+	}
+	return dtos.ToCategoryDto(category), nil
+}
+
+// Create creates new category, nested under its parent if it has one.
+func (s CategoryService) Create(ctx context.Context, dto dtos.CategoryDto) (dtos.CategoryDto, error) {
+	if _, err := authorizeAdmin(ctx); err != nil {
+		return dtos.CategoryDto{}, err
+	}
+
+	dto.ID = ""
+	category, err := dtos.ToCategoryEntity(dto)
+	if err != nil {
+		return dtos.CategoryDto{}, newError("invalid category", err)
+	}
+
+	if err := s.repo.Create(ctx, category); err != nil {
+		return dtos.CategoryDto{}, newError("failed to create category", err)
+	}
+	return dtos.ToCategoryDto(*category), nil
+}
+
+// Update replaces existing category, which moves it under another parent if the parent changed.
+func (s CategoryService) Update(ctx context.Context, dto dtos.CategoryDto) (dtos.CategoryDto, error) {
+	if _, err := authorizeAdmin(ctx); err != nil {
+		return dtos.CategoryDto{}, err
+	}
+
+	category, err := dtos.ToCategoryEntity(dto)
+	if err != nil {
+		return dtos.CategoryDto{}, newError("invalid category", err)
+	}
+
+	if err := s.repo.Update(ctx, category); err != nil {
+		return dtos.CategoryDto{}, newError(fmt.Sprintf("failed to update category: %s", category.ID.String()), err)
+	}
+	return dtos.ToCategoryDto(*category), nil
+}
+
+// DeleteById deletes existing category without subcategories, its items are unassigned from it.
+func (s CategoryService) DeleteById(ctx context.Context, cmd dtos.DeleteCommand) (struct{}, error) {
+	if _, err := authorizeAdmin(ctx); err != nil {
+		return struct{}{}, err
+	}
+
+	if err := s.repo.DeleteById(ctx, cmd.ID, cmd.Version); err != nil {
+		return struct{}{}, newError(fmt.Sprintf("failed to delete category: %s", cmd.ID.String()), err)
+	}
+	return struct{}{}, nil
+}
+
+// GetByItem returns categories the item is assigned to.
+func (s CategoryService) GetByItem(ctx context.Context, itemId uuid.UUID) ([]dtos.CategoryDto, error) {
+	categories, err := s.repo.GetByItem(ctx, itemId)
+	if err != nil {
+		return nil, newError(fmt.Sprintf("failed to get categories of item: %s", itemId.String()), err)
+	}
+	return dtos.ToCategoryDtos(categories), nil
+}
+
+// AssignItem replaces categories the item is assigned to and returns the assigned categories.
+func (s CategoryService) AssignItem(ctx context.Context, cmd dtos.AssignItemCategoriesCommand) ([]dtos.CategoryDto, error) {
+	if _, err := authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	categoryIds := make([]uuid.UUID, len(cmd.CategoryIDs))
+	for i, id := range cmd.CategoryIDs {
+		categoryId, err := uuid.Parse(id)
+		if err != nil {
+			return nil, newError("invalid category id", err)
+		}
+		categoryIds[i] = categoryId
+	}
+
+	if err := s.repo.AssignItem(ctx, cmd.ItemID, categoryIds); err != nil {
+		return nil, newError(fmt.Sprintf("failed to assign categories to item: %s", cmd.ItemID.String()), err)
+	}
+	return s.GetByItem(ctx, cmd.ItemID)
+}
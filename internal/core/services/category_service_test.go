@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"github.com/fmiskovic/new-amz/internal/core"
+	"github.com/fmiskovic/new-amz/internal/core/dtos"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/core/repositories"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestGetCategorySubtree(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	t.Run("should return category with nested subcategories", func(t *testing.T) {
+		repoMock := repositories.NewCategoryRepositoryMock[uuid.UUID](t)
+		svc := NewCategoryService(repoMock)
+
+		fiction := entities.NewCategoryBuilder().Name("Fiction").Build()
+		fantasy := entities.NewCategoryBuilder().Name("Fantasy").Parent(fiction.ID).Build()
+		fiction.Children = []*entities.Category{fantasy}
+
+		repoMock.On("GetSubtree", mock.Anything, fiction.ID).Return(*fiction, nil).Once()
+
+		got, err := svc.GetSubtree(ctx, fiction.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, "Fiction", got.Name)
+		assert.Empty(t, got.ParentID)
+		assert.Len(t, got.Children, 1)
+		assert.Equal(t, "Fantasy", got.Children[0].Name)
+		assert.Equal(t, fiction.ID.String(), got.Children[0].ParentID)
+	})
+
+	t.Run("given unknown category should return not found error", func(t *testing.T) {
+		repoMock := repositories.NewCategoryRepositoryMock[uuid.UUID](t)
+		svc := NewCategoryService(repoMock)
+
+		repoMock.On("GetSubtree", mock.Anything, mock.Anything).Return(entities.Category{}, entities.ErrorEntityNotFound).Once()
+
+		_, err := svc.GetSubtree(ctx, uuid.New())
+		assert.ErrorIs(t, err, entities.ErrorNotFound)
+	})
+}
+
+func TestCreateCategory(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	parentId := uuid.New()
+	dto := dtos.CategoryDto{Name: " Fantasy ", ParentID: parentId.String()}
+
+	t.Run("admin should create subcategory", func(t *testing.T) {
+		repoMock := repositories.NewCategoryRepositoryMock[uuid.UUID](t)
+		svc := NewCategoryService(repoMock)
+
+		repoMock.On("Create", mock.Anything, mock.MatchedBy(func(c *entities.Category) bool {
+			return c.Name == "Fantasy" && c.ParentID != nil && *c.ParentID == parentId
+		})).Return(nil).Once()
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: uuid.New(), Role: entities.ADMIN})
+		got, err := svc.Create(ctx, dto)
+		assert.Nil(t, err)
+		assert.NotEmpty(t, got.ID)
+		assert.Equal(t, parentId.String(), got.ParentID)
+	})
+
+	t.Run("customer should not be allowed to create category", func(t *testing.T) {
+		repoMock := repositories.NewCategoryRepositoryMock[uuid.UUID](t)
+		svc := NewCategoryService(repoMock)
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: uuid.New(), Role: entities.CUSTOMER})
+		_, err := svc.Create(ctx, dto)
+		assert.ErrorIs(t, err, ErrorForbidden)
+		repoMock.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUpdateCategory(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	t.Run("moving category under its subcategory should return error", func(t *testing.T) {
+		repoMock := repositories.NewCategoryRepositoryMock[uuid.UUID](t)
+		svc := NewCategoryService(repoMock)
+
+		repoMock.On("Update", mock.Anything, mock.Anything).Return(entities.ErrorCategoryCycle).Once()
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: uuid.New(), Role: entities.ADMIN})
+		_, err := svc.Update(ctx, dtos.CategoryDto{ID: uuid.NewString(), Name: "Fiction", ParentID: uuid.NewString()})
+		assert.ErrorIs(t, err, entities.ErrorCategoryCycle)
+		assert.ErrorIs(t, err, entities.ErrorValidation)
+	})
+}
+
+func TestAssignItemCategories(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	t.Run("admin should replace categories of the item", func(t *testing.T) {
+		repoMock := repositories.NewCategoryRepositoryMock[uuid.UUID](t)
+		svc := NewCategoryService(repoMock)
+
+		itemId := uuid.New()
+		fantasy := entities.NewCategoryBuilder().Name("Fantasy").Build()
+
+		repoMock.On("AssignItem", mock.Anything, itemId, []uuid.UUID{fantasy.ID}).Return(nil).Once()
+		repoMock.On("GetByItem", mock.Anything, itemId).Return([]entities.Category{*fantasy}, nil).Once()
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: uuid.New(), Role: entities.ADMIN})
+		got, err := svc.AssignItem(ctx, dtos.AssignItemCategoriesCommand{ItemID: itemId, CategoryIDs: []string{fantasy.ID.String()}})
+		assert.Nil(t, err)
+		assert.Len(t, got, 1)
+		assert.Equal(t, "Fantasy", got[0].Name)
+	})
+
+	t.Run("customer should not be allowed to assign categories", func(t *testing.T) {
+		repoMock := repositories.NewCategoryRepositoryMock[uuid.UUID](t)
+		svc := NewCategoryService(repoMock)
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: uuid.New()})
+		_, err := svc.AssignItem(ctx, dtos.AssignItemCategoriesCommand{ItemID: uuid.New(), CategoryIDs: []string{}})
+		assert.ErrorIs(t, err, ErrorForbidden)
+		repoMock.AssertNotCalled(t, "AssignItem", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -1,6 +1,16 @@
 package services
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+)
+
+var (
+	ErrorInvalidCredentials = errors.New("invalid credentials")
+	ErrorForbidden          = entities.NewError(entities.ErrorForbidden, "access to the resource is forbidden")
+	ErrorInvalidEvent       = entities.NewError(entities.ErrorValidation, "invalid payment event")
+)
 
 type ServiceError struct {
 	message string
@@ -14,3 +24,8 @@ func newError(message string, err error) ServiceError {
 func (e ServiceError) Error() string {
 	return fmt.Sprintf("messge: %s, error: %v", e.message, e.err)
 }
+
+// Unwrap returns the cause of the error, so the kind of the cause can be checked with errors.Is.
+func (e ServiceError) Unwrap() error {
+	return e.err
+}
@@ -28,11 +28,106 @@ func (s ItemService) GetById(ctx context.Context, id uuid.UUID) (dtos.ItemDto, e
 	return dtos.ToItemDto(item), nil
 }
 
-// GetPage returns page of items.
-func (s ItemService) GetPage(ctx context.Context, p entities.Pageable) (entities.Page[dtos.ItemDto], error) {
-	page, err := s.repo.GetPage(ctx, p)
+// GetByISBN returns existing item by its ISBN-13.
+func (s ItemService) GetByISBN(ctx context.Context, q dtos.ItemIsbnQuery) (dtos.ItemDto, error) {
+	item, err := s.repo.GetByISBN(ctx, q.ISBN)
+	if err != nil {
+		return dtos.ItemDto{}, newError(fmt.Sprintf("failed to get item by isbn: %s", q.ISBN), err)
+	}
+	return dtos.ToItemDto(item), nil
+}
+
+// GetPage returns page of items, optionally only items in a category including its subcategories.
+func (s ItemService) GetPage(ctx context.Context, filter dtos.ItemFilter) (entities.Page[dtos.ItemDto], error) {
+	var page entities.Page[entities.Item]
+	var err error
+	if filter.CategoryID != nil {
+		page, err = s.repo.GetPageByCategory(ctx, *filter.CategoryID, filter.PageRequest)
+	} else {
+		page, err = s.repo.GetPage(ctx, filter.PageRequest)
+	}
 	if err != nil {
 		return entities.Page[dtos.ItemDto]{}, newError("failed to get page of items", err)
 	}
 	return dtos.ToPageItemDto(page), nil
 }
+
+// GetPageByAuthor returns page of items the author is credited on.
+func (s ItemService) GetPageByAuthor(ctx context.Context, q dtos.AuthorItemsQuery) (entities.Page[dtos.ItemDto], error) {
+	page, err := s.repo.GetPageByAuthor(ctx, q.AuthorID, q.PageRequest)
+	if err != nil {
+		return entities.Page[dtos.ItemDto]{}, newError(fmt.Sprintf("failed to get page of items by author: %s", q.AuthorID.String()), err)
+	}
+	return dtos.ToPageItemDto(page), nil
+}
+
+// Search returns page of items matching the query, most relevant first.
+// Items are searched by full-text search, or by name similarity in fuzzy mode.
+func (s ItemService) Search(ctx context.Context, q dtos.ItemSearchQuery) (entities.Page[dtos.ItemMatchDto], error) {
+	search := s.repo.Search
+	if q.Mode == dtos.SearchModeFuzzy {
+		search = s.repo.SearchSimilar
+	}
+
+	page, err := search(ctx, q.Query, q.PageRequest)
+	if err != nil {
+		return entities.Page[dtos.ItemMatchDto]{}, newError("failed to search items", err)
+	}
+	return dtos.ToPageItemMatchDto(page), nil
+}
+
+// Suggest returns item names resembling the query, to offer corrections of mistyped names.
+func (s ItemService) Suggest(ctx context.Context, q dtos.ItemSuggestQuery) (dtos.ItemSuggestionsDto, error) {
+	titles, err := s.repo.Suggest(ctx, q.Query, q.Limit)
+	if err != nil {
+		return dtos.ItemSuggestionsDto{}, newError("failed to suggest items", err)
+	}
+	return dtos.ItemSuggestionsDto{Query: q.Query, Suggestions: titles}, nil
+}
+
+// Create creates new item in the catalogue.
+func (s ItemService) Create(ctx context.Context, dto dtos.ItemDto) (dtos.ItemDto, error) {
+	if _, err := authorizeAdmin(ctx); err != nil {
+		return dtos.ItemDto{}, err
+	}
+
+	dto.ID = ""
+	item, err := dtos.ToItemEntity(dto)
+	if err != nil {
+		return dtos.ItemDto{}, newError("invalid item", err)
+	}
+
+	if err := s.repo.Create(ctx, item); err != nil {
+		return dtos.ItemDto{}, newError("failed to create item", err)
+	}
+	return dtos.ToItemDto(*item), nil
+}
+
+// Update replaces existing item of the catalogue.
+func (s ItemService) Update(ctx context.Context, dto dtos.ItemDto) (dtos.ItemDto, error) {
+	if _, err := authorizeAdmin(ctx); err != nil {
+		return dtos.ItemDto{}, err
+	}
+
+	item, err := dtos.ToItemEntity(dto)
+	if err != nil {
+		return dtos.ItemDto{}, newError("invalid item", err)
+	}
+
+	if err := s.repo.Update(ctx, item); err != nil {
+		return dtos.ItemDto{}, newError(fmt.Sprintf("failed to update item: %s", item.ID.String()), err)
+	}
+	return dtos.ToItemDto(*item), nil
+}
+
+// DeleteById deletes existing item from the catalogue.
+func (s ItemService) DeleteById(ctx context.Context, cmd dtos.DeleteCommand) (struct{}, error) {
+	if _, err := authorizeAdmin(ctx); err != nil {
+		return struct{}{}, err
+	}
+
+	if err := s.repo.DeleteById(ctx, cmd.ID, cmd.Version); err != nil {
+		return struct{}{}, newError(fmt.Sprintf("failed to delete item: %s", cmd.ID.String()), err)
+	}
+	return struct{}{}, nil
+}
@@ -2,6 +2,8 @@ package services
 
 import (
 	"context"
+	"github.com/fmiskovic/new-amz/internal/core"
+	"github.com/fmiskovic/new-amz/internal/core/dtos"
 	"github.com/fmiskovic/new-amz/internal/core/entities"
 	"github.com/fmiskovic/new-amz/internal/core/repositories"
 	"github.com/google/uuid"
@@ -22,7 +24,7 @@ func TestGetItemById(t *testing.T) {
 		item := entities.NewItemBuilder().
 			Title("A cool book").
 			Description("A cool book written by a cool author").
-			Price(100).
+			Price(entities.NewMoney(10000, entities.EUR)).
 			Build()
 
 		repoMock.On("GetById", mock.Anything, mock.Anything).Return(*item, nil).Once()
@@ -46,14 +48,11 @@ func TestGetItemsPage(t *testing.T) {
 		item := entities.NewItemBuilder().
 			Title("A cool book").
 			Description("A cool book written by a cool author").
-			Price(100).
+			Price(entities.NewMoney(10000, entities.EUR)).
 			Build()
 
-		page := entities.Page[entities.Item]{
-			TotalPages:    1,
-			TotalElements: 1,
-			Elements:      []entities.Item{*item},
-		}
+		page := entities.Page[entities.Item]{Elements: []entities.Item{*item}}
+		page.SetTotal(1)
 
 		repoMock.On("GetPage", mock.Anything, mock.Anything).Return(page, nil).Once()
 
@@ -62,11 +61,320 @@ func TestGetItemsPage(t *testing.T) {
 			Offset: 0,
 		}
 
-		got, err := svc.GetPage(ctx, pagable)
+		got, err := svc.GetPage(ctx, dtos.ItemFilter{PageRequest: pagable})
 		assert.Nil(t, err)
-		assert.Equal(t, 1, got.TotalElements)
+		assert.Equal(t, 1, *got.TotalElements)
 		assert.Equal(t, 1, len(got.Elements))
 		assert.Equal(t, item.Title, got.Elements[0].Title)
 		repoMock.AssertCalled(t, "GetPage", mock.Anything, mock.Anything)
 	})
+
+	t.Run("given category should return page of items in the category", func(t *testing.T) {
+		repoMock := repositories.NewItemRepositoryMock[uuid.UUID](t)
+		svc := NewItemService(repoMock)
+
+		item := entities.NewItemBuilder().Title("Dune").Build()
+		page := entities.Page[entities.Item]{Elements: []entities.Item{*item}}
+		page.SetTotal(1)
+		categoryId := uuid.New()
+
+		repoMock.On("GetPageByCategory", mock.Anything, categoryId, mock.Anything).Return(page, nil).Once()
+
+		got, err := svc.GetPage(ctx, dtos.ItemFilter{CategoryID: &categoryId, PageRequest: entities.Pageable{Size: 10}})
+		assert.Nil(t, err)
+		assert.Equal(t, "Dune", got.Elements[0].Title)
+		repoMock.AssertNotCalled(t, "GetPage", mock.Anything, mock.Anything)
+	})
+}
+
+func TestGetItemByISBN(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	t.Run("should return item with its publisher and authors", func(t *testing.T) {
+		repoMock := repositories.NewItemRepositoryMock[uuid.UUID](t)
+		svc := NewItemService(repoMock)
+
+		publisher := entities.NewPublisherBuilder().Name("Bloomsbury").Build()
+		author := entities.NewAuthorBuilder().Name("J. K. Rowling").Build()
+		item := entities.NewItemBuilder().Title("Philosopher's Stone").ISBN("9780747532699").Publisher(publisher.ID).Build()
+		item.Publisher = publisher
+		item.Authors = []*entities.Author{author}
+
+		repoMock.On("GetByISBN", mock.Anything, "9780747532699").Return(*item, nil).Once()
+
+		got, err := svc.GetByISBN(ctx, dtos.ItemIsbnQuery{ISBN: "9780747532699"})
+		assert.Nil(t, err)
+		assert.Equal(t, "9780747532699", got.ISBN)
+		assert.Equal(t, publisher.ID.String(), got.PublisherID)
+		assert.Equal(t, "Bloomsbury", got.Publisher.Name)
+		assert.Equal(t, []string{author.ID.String()}, got.AuthorIDs)
+		assert.Equal(t, "J. K. Rowling", got.Authors[0].Name)
+	})
+
+	t.Run("given unknown isbn should return not found error", func(t *testing.T) {
+		repoMock := repositories.NewItemRepositoryMock[uuid.UUID](t)
+		svc := NewItemService(repoMock)
+
+		repoMock.On("GetByISBN", mock.Anything, mock.Anything).Return(entities.Item{}, entities.ErrorEntityNotFound).Once()
+
+		_, err := svc.GetByISBN(ctx, dtos.ItemIsbnQuery{ISBN: "9780306406157"})
+		assert.ErrorIs(t, err, entities.ErrorNotFound)
+	})
+}
+
+func TestGetItemsPageByAuthor(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	t.Run("should return page of items the author is credited on", func(t *testing.T) {
+		repoMock := repositories.NewItemRepositoryMock[uuid.UUID](t)
+		svc := NewItemService(repoMock)
+
+		authorId := uuid.New()
+		pageRequest := entities.Pageable{Size: 10}
+		item := entities.NewItemBuilder().Title("Dune").Authors(authorId).Build()
+		repoMock.On("GetPageByAuthor", mock.Anything, authorId, pageRequest).
+			Return(entities.NewPage([]entities.Item{*item}, pageRequest, 1, false), nil).Once()
+
+		got, err := svc.GetPageByAuthor(ctx, dtos.AuthorItemsQuery{AuthorID: authorId, PageRequest: pageRequest})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, *got.TotalElements)
+		assert.Equal(t, "Dune", got.Elements[0].Title)
+	})
+
+	t.Run("given unknown author should return not found error", func(t *testing.T) {
+		repoMock := repositories.NewItemRepositoryMock[uuid.UUID](t)
+		svc := NewItemService(repoMock)
+
+		repoMock.On("GetPageByAuthor", mock.Anything, mock.Anything, mock.Anything).
+			Return(entities.Page[entities.Item]{}, entities.ErrorEntityNotFound).Once()
+
+		_, err := svc.GetPageByAuthor(ctx, dtos.AuthorItemsQuery{AuthorID: uuid.New()})
+		assert.ErrorIs(t, err, entities.ErrorNotFound)
+	})
+}
+
+func TestSearchItems(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	t.Run("should return page of ranked and highlighted items", func(t *testing.T) {
+		repoMock := repositories.NewItemRepositoryMock[uuid.UUID](t)
+		svc := NewItemService(repoMock)
+
+		item := entities.NewItemBuilder().Title("Dune").Description("A desert planet").Build()
+		page := entities.Page[entities.ItemMatch]{
+			Size: 10,
+			Elements: []entities.ItemMatch{{
+				Item:                 *item,
+				Rank:                 0.6,
+				TitleHighlight:       "<mark>Dune</mark>",
+				DescriptionHighlight: "A desert planet",
+			}},
+		}
+		page.SetTotal(1)
+		pageRequest := entities.Pageable{Size: 10}
+
+		repoMock.On("Search", mock.Anything, "dune", pageRequest).Return(page, nil).Once()
+
+		got, err := svc.Search(ctx, dtos.ItemSearchQuery{Query: "dune", PageRequest: pageRequest})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, *got.TotalElements)
+		assert.Equal(t, 10, got.Size)
+		assert.Equal(t, item.Title, got.Elements[0].Title)
+		assert.Equal(t, 0.6, got.Elements[0].Rank)
+		assert.Equal(t, "<mark>Dune</mark>", got.Elements[0].Highlight.Title)
+	})
+
+	t.Run("given fuzzy mode should search items by similar names", func(t *testing.T) {
+		repoMock := repositories.NewItemRepositoryMock[uuid.UUID](t)
+		svc := NewItemService(repoMock)
+
+		item := entities.NewItemBuilder().Title("Dune").Build()
+		page := entities.Page[entities.ItemMatch]{Elements: []entities.ItemMatch{{Item: *item, Rank: 0.75}}}
+		page.SetTotal(1)
+
+		repoMock.On("SearchSimilar", mock.Anything, "dnue", mock.Anything).Return(page, nil).Once()
+
+		got, err := svc.Search(ctx, dtos.ItemSearchQuery{Query: "dnue", Mode: dtos.SearchModeFuzzy})
+		assert.Nil(t, err)
+		assert.Equal(t, "Dune", got.Elements[0].Title)
+		assert.Equal(t, 0.75, got.Elements[0].Rank)
+		assert.Nil(t, got.Elements[0].Highlight)
+	})
+
+	t.Run("given invalid paging should return validation error", func(t *testing.T) {
+		repoMock := repositories.NewItemRepositoryMock[uuid.UUID](t)
+		svc := NewItemService(repoMock)
+
+		repoMock.On("Search", mock.Anything, "dune", mock.Anything).
+			Return(entities.Page[entities.ItemMatch]{}, entities.ErrorSearchPaging).Once()
+
+		_, err := svc.Search(ctx, dtos.ItemSearchQuery{Query: "dune", PageRequest: entities.Pageable{Keyset: true}})
+		assert.ErrorIs(t, err, entities.ErrorValidation)
+	})
+}
+
+func TestSuggestItems(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	t.Run("should return names resembling the query", func(t *testing.T) {
+		repoMock := repositories.NewItemRepositoryMock[uuid.UUID](t)
+		svc := NewItemService(repoMock)
+
+		repoMock.On("Suggest", mock.Anything, "dnue", 5).Return([]string{"Dune", "Dune Messiah"}, nil).Once()
+
+		got, err := svc.Suggest(ctx, dtos.ItemSuggestQuery{Query: "dnue", Limit: 5})
+		assert.Nil(t, err)
+		assert.Equal(t, dtos.ItemSuggestionsDto{Query: "dnue", Suggestions: []string{"Dune", "Dune Messiah"}}, got)
+	})
+}
+
+func TestCreateItem(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	dto := dtos.ItemDto{
+		Title: "A cool book",
+		Price: entities.NewMoney(1999, entities.EUR),
+		Stock: 10,
+	}
+
+	t.Run("admin should create item", func(t *testing.T) {
+		repoMock := repositories.NewItemRepositoryMock[uuid.UUID](t)
+		svc := NewItemService(repoMock)
+
+		repoMock.On("Create", mock.Anything, mock.MatchedBy(func(i *entities.Item) bool {
+			return i.Title == dto.Title && i.Price == dto.Price && i.Stock == dto.Stock
+		})).Return(nil).Once()
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: uuid.New(), Role: entities.ADMIN})
+		got, err := svc.Create(ctx, dto)
+		assert.Nil(t, err)
+		assert.NotEmpty(t, got.ID)
+		assert.Equal(t, dto.Title, got.Title)
+	})
+
+	t.Run("admin should create book with its publisher and authors", func(t *testing.T) {
+		repoMock := repositories.NewItemRepositoryMock[uuid.UUID](t)
+		svc := NewItemService(repoMock)
+
+		publisherId, firstAuthorId, secondAuthorId := uuid.New(), uuid.New(), uuid.New()
+		book := dto
+		book.ISBN = "9780306406157"
+		book.PublisherID = publisherId.String()
+		book.PublicationDate = "1997-06-26"
+		book.PageCount = 223
+		book.Language = "en"
+		book.AuthorIDs = []string{firstAuthorId.String(), secondAuthorId.String()}
+
+		repoMock.On("Create", mock.Anything, mock.MatchedBy(func(i *entities.Item) bool {
+			return i.ISBN == book.ISBN && *i.PublisherID == publisherId &&
+				i.PublicationDate.Equal(time.Date(1997, 6, 26, 0, 0, 0, 0, time.UTC)) &&
+				len(i.Authors) == 2 && i.Authors[0].ID == firstAuthorId && i.Authors[1].ID == secondAuthorId
+		})).Return(nil).Once()
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: uuid.New(), Role: entities.ADMIN})
+		got, err := svc.Create(ctx, book)
+		assert.Nil(t, err)
+		assert.Equal(t, book.ISBN, got.ISBN)
+		assert.Equal(t, book.PublicationDate, got.PublicationDate)
+		assert.Equal(t, book.AuthorIDs, got.AuthorIDs)
+	})
+
+	t.Run("given unknown publisher should return not found error", func(t *testing.T) {
+		repoMock := repositories.NewItemRepositoryMock[uuid.UUID](t)
+		svc := NewItemService(repoMock)
+
+		book := dto
+		book.PublisherID = uuid.New().String()
+		repoMock.On("Create", mock.Anything, mock.Anything).Return(entities.ErrorPublisherNotFound).Once()
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: uuid.New(), Role: entities.ADMIN})
+		_, err := svc.Create(ctx, book)
+		assert.ErrorIs(t, err, entities.ErrorPublisherNotFound)
+		assert.ErrorIs(t, err, entities.ErrorNotFound)
+	})
+
+	t.Run("customer should not be allowed to create item", func(t *testing.T) {
+		repoMock := repositories.NewItemRepositoryMock[uuid.UUID](t)
+		svc := NewItemService(repoMock)
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: uuid.New(), Role: entities.CUSTOMER})
+		_, err := svc.Create(ctx, dto)
+		assert.ErrorIs(t, err, ErrorForbidden)
+		repoMock.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUpdateItem(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	t.Run("admin should update item", func(t *testing.T) {
+		repoMock := repositories.NewItemRepositoryMock[uuid.UUID](t)
+		svc := NewItemService(repoMock)
+
+		id := uuid.New()
+		dto := dtos.ItemDto{ID: id.String(), Title: "Renamed book", Price: entities.NewMoney(999, entities.EUR)}
+
+		repoMock.On("Update", mock.Anything, mock.MatchedBy(func(i *entities.Item) bool {
+			return i.ID == id && i.Title == dto.Title
+		})).Return(nil).Once()
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: uuid.New(), Role: entities.ADMIN})
+		got, err := svc.Update(ctx, dto)
+		assert.Nil(t, err)
+		assert.Equal(t, dto.ID, got.ID)
+	})
+
+	t.Run("update of invalid item id should return error", func(t *testing.T) {
+		repoMock := repositories.NewItemRepositoryMock[uuid.UUID](t)
+		svc := NewItemService(repoMock)
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: uuid.New(), Role: entities.ADMIN})
+		_, err := svc.Update(ctx, dtos.ItemDto{ID: "invalid-uuid"})
+		assert.NotNil(t, err)
+		repoMock.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
+func TestDeleteItem(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	t.Run("admin should delete item", func(t *testing.T) {
+		repoMock := repositories.NewItemRepositoryMock[uuid.UUID](t)
+		svc := NewItemService(repoMock)
+
+		id := uuid.New()
+		repoMock.On("DeleteById", mock.Anything, id, int64(3)).Return(nil).Once()
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: uuid.New(), Role: entities.ADMIN})
+		_, err := svc.DeleteById(ctx, dtos.DeleteCommand{ID: id, Version: 3})
+		assert.Nil(t, err)
+	})
+
+	t.Run("delete of item which is part of an order should return error", func(t *testing.T) {
+		repoMock := repositories.NewItemRepositoryMock[uuid.UUID](t)
+		svc := NewItemService(repoMock)
+
+		repoMock.On("DeleteById", mock.Anything, mock.Anything, mock.Anything).Return(entities.ErrorItemInUse).Once()
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: uuid.New(), Role: entities.ADMIN})
+		_, err := svc.DeleteById(ctx, dtos.DeleteCommand{ID: uuid.New()})
+		assert.ErrorIs(t, err, entities.ErrorItemInUse)
+	})
+
+	t.Run("customer should not be allowed to delete item", func(t *testing.T) {
+		repoMock := repositories.NewItemRepositoryMock[uuid.UUID](t)
+		svc := NewItemService(repoMock)
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: uuid.New()})
+		_, err := svc.DeleteById(ctx, dtos.DeleteCommand{ID: uuid.New()})
+		assert.ErrorIs(t, err, ErrorForbidden)
+		repoMock.AssertNotCalled(t, "DeleteById", mock.Anything, mock.Anything, mock.Anything)
+	})
 }
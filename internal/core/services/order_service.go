@@ -2,6 +2,8 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"github.com/fmiskovic/new-amz/internal/core"
 	"github.com/fmiskovic/new-amz/internal/core/dtos"
 	"github.com/fmiskovic/new-amz/internal/core/entities"
 	"github.com/fmiskovic/new-amz/internal/core/repositories"
@@ -10,12 +12,13 @@ import (
 
 // OrderService represents business logic related to entities.Order.
 type OrderService struct {
+	UnitOfWork
 	repo repositories.OrderRepository[uuid.UUID]
 }
 
 // NewOrderService instantiates new OrderService.
-func NewOrderService(repo repositories.OrderRepository[uuid.UUID]) OrderService {
-	return OrderService{repo: repo}
+func NewOrderService(repo repositories.OrderRepository[uuid.UUID], uow UnitOfWork) OrderService {
+	return OrderService{UnitOfWork: uow, repo: repo}
 }
 
 // GetById returns existing order.
@@ -24,12 +27,19 @@ func (s OrderService) GetById(ctx context.Context, id uuid.UUID) (dtos.OrderDto,
 	if err != nil {
 		return dtos.OrderDto{}, err
 	}
+	if err := authorize(ctx, order.AccountID); err != nil {
+		return dtos.OrderDto{}, err
+	}
 
 	return dtos.ToOrderDto(order), nil
 }
 
 // Search returns page of orders created by specified account.
 func (s OrderService) Search(ctx context.Context, filter dtos.OrderFilter) (entities.Page[dtos.OrderDto], error) {
+	if err := authorize(ctx, filter.AccountID); err != nil {
+		return entities.Page[dtos.OrderDto]{}, err
+	}
+
 	orders, err := s.repo.Search(ctx, filter.AccountID, filter.PageRequest)
 	if err != nil {
 		return entities.Page[dtos.OrderDto]{}, err
@@ -39,11 +49,21 @@ func (s OrderService) Search(ctx context.Context, filter dtos.OrderFilter) (enti
 }
 
 // Create creates new order.
+// If account id is omitted the order is placed for the calling account.
 func (s OrderService) Create(ctx context.Context, cmd dtos.CreateOrderCommand) (dtos.CreateOrderAnswer, error) {
+	if cmd.AccountID == "" {
+		if p, ok := core.PrincipalFrom(ctx); ok {
+			cmd.AccountID = p.AccountID.String()
+		}
+	}
+
 	accountId, err := uuid.Parse(cmd.AccountID)
 	if err != nil {
 		return dtos.CreateOrderAnswer{}, newError("invalid account id", err)
 	}
+	if err := authorize(ctx, accountId); err != nil {
+		return dtos.CreateOrderAnswer{}, err
+	}
 
 	orderItems, err := dtos.ToOrderItemEntities(cmd.Items)
 	if err != nil {
@@ -61,3 +81,152 @@ func (s OrderService) Create(ctx context.Context, cmd dtos.CreateOrderCommand) (
 
 	return dtos.CreateOrderAnswer{OrderDto: dtos.ToOrderDto(*order)}, nil
 }
+
+// Transition moves the order into the requested status, if the order lifecycle allows it.
+// Orders paid by an active payment are not cancelled or refunded this way, see Cancel and Refund.
+// The order is read and moved in one unit of work.
+func (s OrderService) Transition(ctx context.Context, cmd dtos.TransitionOrderCommand) (dtos.OrderTransitionDto, error) {
+	p, err := authorizeAdmin(ctx)
+	if err != nil {
+		return dtos.OrderTransitionDto{}, err
+	}
+
+	var change *entities.OrderHistory
+	err = s.WithinTx(ctx, func(ctx context.Context) error {
+		order, err := s.repo.GetById(ctx, cmd.OrderID)
+		if err != nil {
+			return newError(fmt.Sprintf("failed to get order by id: %s", cmd.OrderID.String()), err)
+		}
+
+		change, err = order.TransitionTo(entities.OrderStatus(cmd.Status), p.AccountID, cmd.Note)
+		if err != nil {
+			return err
+		}
+
+		if err := s.repo.UpdateStatus(ctx, &order, change); err != nil {
+			return newError("failed to update order status", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return dtos.OrderTransitionDto{}, err
+	}
+
+	return dtos.ToOrderTransitionDto(*change), nil
+}
+
+// GetTransitions returns status history of the order.
+func (s OrderService) GetTransitions(ctx context.Context, id uuid.UUID) ([]dtos.OrderTransitionDto, error) {
+	order, err := s.repo.GetById(ctx, id)
+	if err != nil {
+		return nil, newError(fmt.Sprintf("failed to get order by id: %s", id.String()), err)
+	}
+	if err := authorize(ctx, order.AccountID); err != nil {
+		return nil, err
+	}
+
+	history, err := s.repo.GetHistory(ctx, id)
+	if err != nil {
+		return nil, newError("failed to get order history", err)
+	}
+
+	return dtos.ToOrderTransitionDtos(history), nil
+}
+
+// Cancel cancels the order on behalf of its account or an administrator and records why.
+// Orders can only be cancelled before they are shipped, their reserved stock is given back.
+// Orders paid by an authorized or captured payment are not cancelled until the payment is voided,
+// entities.ErrorOrderPaymentActive is returned for them. The order is read and cancelled in one unit of work.
+func (s OrderService) Cancel(ctx context.Context, cmd dtos.CancelOrderCommand) (dtos.OrderDto, error) {
+	var order entities.Order
+	err := s.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = s.repo.GetById(ctx, cmd.OrderID)
+		if err != nil {
+			return newError(fmt.Sprintf("failed to get order by id: %s", cmd.OrderID.String()), err)
+		}
+		if err := authorize(ctx, order.AccountID); err != nil {
+			return err
+		}
+		if !order.Status.IsCancellable() {
+			return fmt.Errorf("%w: order is %s", entities.ErrorOrderNotCancellable, order.Status)
+		}
+
+		p, _ := core.PrincipalFrom(ctx)
+		change, err := order.TransitionTo(entities.ORDER_CANCELLED, p.AccountID, cmd.Reason)
+		if err != nil {
+			return err
+		}
+
+		if err := s.repo.UpdateStatus(ctx, &order, change); err != nil {
+			return newError("failed to cancel order", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return dtos.OrderDto{}, err
+	}
+
+	return dtos.ToOrderDto(order), nil
+}
+
+// Refund refunds the order in full or the listed order items, up to their ordered quantities.
+// Refunds are recorded for orders paid outside of the payment provider, orders paid by a captured payment
+// are refunded by refunding the payment, see PaymentService.Refund.
+func (s OrderService) Refund(ctx context.Context, cmd dtos.RefundOrderCommand) (dtos.RefundDto, error) {
+	p, err := authorizeAdmin(ctx)
+	if err != nil {
+		return dtos.RefundDto{}, err
+	}
+
+	lines, err := dtos.ToRefundLines(cmd.Items)
+	if err != nil {
+		return dtos.RefundDto{}, newError("invalid refund items", err)
+	}
+
+	var refund *entities.Refund
+	err = s.WithinTx(ctx, func(ctx context.Context) error {
+		// the order stays locked until the refund is stored, so concurrent refunds can not refund more than was ordered
+		order, err := s.repo.GetByIdForUpdate(ctx, cmd.OrderID)
+		if err != nil {
+			return newError(fmt.Sprintf("failed to get order by id: %s", cmd.OrderID.String()), err)
+		}
+		refunds, err := s.repo.GetRefunds(ctx, cmd.OrderID)
+		if err != nil {
+			return newError("failed to get order refunds", err)
+		}
+
+		r, change, err := order.Refund(refunds, lines, p.AccountID, cmd.Reason)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.CreateRefund(ctx, &order, r, change); err != nil {
+			return newError("failed to refund order", err)
+		}
+		refund = r
+		return nil
+	})
+	if err != nil {
+		return dtos.RefundDto{}, err
+	}
+
+	return dtos.ToRefundDto(*refund), nil
+}
+
+// GetRefunds returns refunds of the order.
+func (s OrderService) GetRefunds(ctx context.Context, id uuid.UUID) ([]dtos.RefundDto, error) {
+	order, err := s.repo.GetById(ctx, id)
+	if err != nil {
+		return nil, newError(fmt.Sprintf("failed to get order by id: %s", id.String()), err)
+	}
+	if err := authorize(ctx, order.AccountID); err != nil {
+		return nil, err
+	}
+
+	refunds, err := s.repo.GetRefunds(ctx, id)
+	if err != nil {
+		return nil, newError("failed to get order refunds", err)
+	}
+
+	return dtos.ToRefundDtos(refunds), nil
+}
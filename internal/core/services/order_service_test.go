@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"github.com/fmiskovic/new-amz/internal/core"
 	"github.com/fmiskovic/new-amz/internal/core/dtos"
 	"github.com/fmiskovic/new-amz/internal/core/entities"
 	"github.com/fmiskovic/new-amz/internal/core/repositories"
@@ -19,7 +20,7 @@ func TestCreateOrder(t *testing.T) {
 
 	t.Run("create order with valid data should return no error", func(t *testing.T) {
 		repoMock := repositories.NewOrderRepositoryMock[uuid.UUID](t)
-		svc := NewOrderService(repoMock)
+		svc := NewOrderService(repoMock, newUnitOfWork(t))
 
 		orderIdMock := uuid.NewString()
 
@@ -35,8 +36,11 @@ func TestCreateOrder(t *testing.T) {
 			Quantity: 1,
 		}
 
+		accountId := uuid.New()
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: accountId})
+
 		cmd := dtos.CreateOrderCommand{
-			AccountID: uuid.NewString(),
+			AccountID: accountId.String(),
 			Items:     items,
 		}
 
@@ -50,7 +54,7 @@ func TestCreateOrder(t *testing.T) {
 
 	t.Run("create order with invalid account id should return error", func(t *testing.T) {
 		repoMock := repositories.NewOrderRepositoryMock[uuid.UUID](t)
-		svc := NewOrderService(repoMock)
+		svc := NewOrderService(repoMock, newUnitOfWork(t))
 
 		cmd := dtos.CreateOrderCommand{
 			AccountID: "invalid-uuid",
@@ -63,7 +67,7 @@ func TestCreateOrder(t *testing.T) {
 
 	t.Run("create order with invalid item id should return error", func(t *testing.T) {
 		repoMock := repositories.NewOrderRepositoryMock[uuid.UUID](t)
-		svc := NewOrderService(repoMock)
+		svc := NewOrderService(repoMock, newUnitOfWork(t))
 
 		orderIdMock := uuid.NewString()
 
@@ -74,8 +78,11 @@ func TestCreateOrder(t *testing.T) {
 			Quantity: 2,
 		}
 
+		accountId := uuid.New()
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: accountId})
+
 		cmd := dtos.CreateOrderCommand{
-			AccountID: uuid.NewString(),
+			AccountID: accountId.String(),
 			Items:     items,
 		}
 
@@ -86,7 +93,7 @@ func TestCreateOrder(t *testing.T) {
 
 	t.Run("create order fails should return error", func(t *testing.T) {
 		repoMock := repositories.NewOrderRepositoryMock[uuid.UUID](t)
-		svc := NewOrderService(repoMock)
+		svc := NewOrderService(repoMock, newUnitOfWork(t))
 
 		orderIdMock := uuid.NewString()
 
@@ -102,8 +109,11 @@ func TestCreateOrder(t *testing.T) {
 			Quantity: 1,
 		}
 
+		accountId := uuid.New()
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: accountId})
+
 		cmd := dtos.CreateOrderCommand{
-			AccountID: uuid.NewString(),
+			AccountID: accountId.String(),
 			Items:     items,
 		}
 
@@ -121,7 +131,7 @@ func TestSearchAccountOrders(t *testing.T) {
 
 	t.Run("search orders with valid account id should return page of orders", func(t *testing.T) {
 		repoMock := repositories.NewOrderRepositoryMock[uuid.UUID](t)
-		svc := NewOrderService(repoMock)
+		svc := NewOrderService(repoMock, newUnitOfWork(t))
 
 		mockItems := make([]*entities.OrderItem, 1)
 		mockItems[0] = &entities.OrderItem{
@@ -135,14 +145,12 @@ func TestSearchAccountOrders(t *testing.T) {
 			OrderItems(mockItems).
 			Build()
 
-		mockPage := entities.Page[entities.Order]{
-			TotalPages:    1,
-			TotalElements: 1,
-			Elements:      []entities.Order{*mockOrder},
-		}
+		mockPage := entities.Page[entities.Order]{Elements: []entities.Order{*mockOrder}}
+		mockPage.SetTotal(1)
 		repoMock.On("Search", mock.Anything, mock.Anything, mock.Anything).Return(mockPage, nil).Once()
 
 		accountId := uuid.New()
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: accountId})
 		pageRequest := entities.Pageable{Size: 10, Offset: 0}
 
 		filter := dtos.OrderFilter{
@@ -152,25 +160,37 @@ func TestSearchAccountOrders(t *testing.T) {
 
 		page, err := svc.Search(ctx, filter)
 		assert.Nil(t, err)
-		assert.Equal(t, 1, page.TotalElements)
+		assert.Equal(t, 1, *page.TotalElements)
 		assert.Equal(t, mockOrder.AccountID.String(), page.Elements[0].AccountID)
 		repoMock.AssertCalled(t, "Search", mock.Anything, accountId, pageRequest)
 	})
 
-	t.Run("search orders returns unexpected error", func(t *testing.T) {
+	t.Run("search orders of another account should return forbidden error", func(t *testing.T) {
 		repoMock := repositories.NewOrderRepositoryMock[uuid.UUID](t)
-		svc := NewOrderService(repoMock)
+		svc := NewOrderService(repoMock, newUnitOfWork(t))
 
-		mockPage := entities.Page[entities.Order]{
-			TotalPages:    1,
-			TotalElements: 1,
-			Elements:      []entities.Order{},
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: uuid.New()})
+		filter := dtos.OrderFilter{
+			AccountID:   uuid.New(),
+			PageRequest: entities.Pageable{Size: 10, Offset: 0},
 		}
 
+		_, err := svc.Search(ctx, filter)
+		assert.ErrorIs(t, err, ErrorForbidden)
+		repoMock.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("search orders returns unexpected error", func(t *testing.T) {
+		repoMock := repositories.NewOrderRepositoryMock[uuid.UUID](t)
+		svc := NewOrderService(repoMock, newUnitOfWork(t))
+
+		mockPage := entities.Page[entities.Order]{Elements: []entities.Order{}}
+
 		repoMock.On("Search", mock.Anything, mock.Anything, mock.Anything).
 			Return(mockPage, errors.New("unexpected error")).Once()
 
 		accountId := uuid.New()
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: accountId})
 		pageRequest := entities.Pageable{Size: 10, Offset: 0}
 
 		filter := dtos.OrderFilter{
@@ -190,7 +210,7 @@ func TestGetOrderById(t *testing.T) {
 
 	t.Run("get order by id should return order", func(t *testing.T) {
 		repoMock := repositories.NewOrderRepositoryMock[uuid.UUID](t)
-		svc := NewOrderService(repoMock)
+		svc := NewOrderService(repoMock, newUnitOfWork(t))
 
 		mockItems := make([]*entities.OrderItem, 1)
 		mockItems[0] = &entities.OrderItem{
@@ -206,15 +226,79 @@ func TestGetOrderById(t *testing.T) {
 
 		repoMock.On("GetById", mock.Anything, mock.Anything).Return(*mockOrder, nil).Once()
 
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: mockOrder.AccountID})
 		got, err := svc.GetById(ctx, mockOrder.ID)
 		assert.Nil(t, err)
 		assert.Equal(t, mockOrder.AccountID.String(), got.AccountID)
 		repoMock.AssertCalled(t, "GetById", mock.Anything, mock.Anything)
 	})
 
+	t.Run("get order by id should return order totals", func(t *testing.T) {
+		repoMock := repositories.NewOrderRepositoryMock[uuid.UUID](t)
+		svc := NewOrderService(repoMock, newUnitOfWork(t))
+
+		mockItems := []*entities.OrderItem{
+			{ItemID: uuid.New(), Title: "Book 1", Quantity: 3, UnitPrice: entities.NewMoney(10, entities.EUR)},
+			{ItemID: uuid.New(), Title: "Book 2", Quantity: 1, UnitPrice: entities.NewMoney(1999, entities.EUR)},
+		}
+
+		mockOrder := entities.NewOrderBuilder().
+			AccountID(uuid.New()).
+			OrderItems(mockItems).
+			Build()
+
+		repoMock.On("GetById", mock.Anything, mock.Anything).Return(*mockOrder, nil).Once()
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: mockOrder.AccountID})
+		got, err := svc.GetById(ctx, mockOrder.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, "0.10 EUR", got.Items[0].UnitPrice.String())
+		assert.Equal(t, "0.30 EUR", got.Items[0].LineTotal.String())
+		assert.Equal(t, "20.29 EUR", got.Subtotal.String())
+		assert.Equal(t, "20.29 EUR", got.Total.String())
+	})
+
+	t.Run("get order by id with items in different currencies should omit totals", func(t *testing.T) {
+		repoMock := repositories.NewOrderRepositoryMock[uuid.UUID](t)
+		svc := NewOrderService(repoMock, newUnitOfWork(t))
+
+		mockItems := []*entities.OrderItem{
+			{ItemID: uuid.New(), Title: "Book 1", Quantity: 1, UnitPrice: entities.NewMoney(1000, entities.EUR)},
+			{ItemID: uuid.New(), Title: "Book 2", Quantity: 1, UnitPrice: entities.NewMoney(1000, entities.USD)},
+		}
+
+		mockOrder := entities.NewOrderBuilder().
+			AccountID(uuid.New()).
+			OrderItems(mockItems).
+			Build()
+
+		repoMock.On("GetById", mock.Anything, mock.Anything).Return(*mockOrder, nil).Once()
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: mockOrder.AccountID})
+		got, err := svc.GetById(ctx, mockOrder.ID)
+		assert.Nil(t, err)
+		assert.Nil(t, got.Subtotal)
+		assert.Nil(t, got.Total)
+	})
+
+	t.Run("get order by id of another account should return forbidden error", func(t *testing.T) {
+		repoMock := repositories.NewOrderRepositoryMock[uuid.UUID](t)
+		svc := NewOrderService(repoMock, newUnitOfWork(t))
+
+		mockOrder := entities.NewOrderBuilder().
+			AccountID(uuid.New()).
+			Build()
+
+		repoMock.On("GetById", mock.Anything, mock.Anything).Return(*mockOrder, nil).Once()
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: uuid.New()})
+		_, err := svc.GetById(ctx, mockOrder.ID)
+		assert.ErrorIs(t, err, ErrorForbidden)
+	})
+
 	t.Run("get order by id returns unexpected error", func(t *testing.T) {
 		repoMock := repositories.NewOrderRepositoryMock[uuid.UUID](t)
-		svc := NewOrderService(repoMock)
+		svc := NewOrderService(repoMock, newUnitOfWork(t))
 
 		repoMock.On("GetById", mock.Anything, mock.Anything).
 			Return(entities.Order{}, errors.New("unexpected error")).Once()
@@ -224,3 +308,225 @@ func TestGetOrderById(t *testing.T) {
 		repoMock.AssertCalled(t, "GetById", mock.Anything, mock.Anything)
 	})
 }
+
+func TestTransitionOrder(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	adminId := uuid.New()
+	adminCtx := core.WithPrincipal(ctx, core.Principal{AccountID: adminId, Role: entities.ADMIN})
+
+	t.Run("admin should move pending order to paid", func(t *testing.T) {
+		repoMock := repositories.NewOrderRepositoryMock[uuid.UUID](t)
+		svc := NewOrderService(repoMock, newUnitOfWork(t))
+
+		mockOrder := entities.NewOrderBuilder().AccountID(uuid.New()).Build()
+		repoMock.On("GetById", mock.Anything, mockOrder.ID).Return(*mockOrder, nil).Once()
+		repoMock.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(o *entities.Order) bool {
+			return o.Status == entities.ORDER_PAID
+		}), mock.Anything).Return(nil).Once()
+
+		got, err := svc.Transition(adminCtx, dtos.TransitionOrderCommand{OrderID: mockOrder.ID, Status: "paid"})
+		assert.NoError(t, err)
+		assert.Equal(t, "pending", got.FromStatus)
+		assert.Equal(t, "paid", got.ToStatus)
+		assert.Equal(t, adminId.String(), got.ActorID)
+	})
+
+	t.Run("customer should not be allowed to transition order", func(t *testing.T) {
+		repoMock := repositories.NewOrderRepositoryMock[uuid.UUID](t)
+		svc := NewOrderService(repoMock, newUnitOfWork(t))
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: uuid.New(), Role: entities.CUSTOMER})
+		_, err := svc.Transition(ctx, dtos.TransitionOrderCommand{OrderID: uuid.New(), Status: "paid"})
+		assert.ErrorIs(t, err, ErrorForbidden)
+		repoMock.AssertNotCalled(t, "GetById", mock.Anything, mock.Anything)
+	})
+
+	t.Run("transition not allowed by order lifecycle should return error", func(t *testing.T) {
+		repoMock := repositories.NewOrderRepositoryMock[uuid.UUID](t)
+		svc := NewOrderService(repoMock, newUnitOfWork(t))
+
+		mockOrder := entities.NewOrderBuilder().AccountID(uuid.New()).Build()
+		repoMock.On("GetById", mock.Anything, mockOrder.ID).Return(*mockOrder, nil).Once()
+
+		_, err := svc.Transition(adminCtx, dtos.TransitionOrderCommand{OrderID: mockOrder.ID, Status: "delivered"})
+		assert.ErrorIs(t, err, entities.ErrorInvalidOrderTransition)
+		repoMock.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("transition of terminal order should return error", func(t *testing.T) {
+		repoMock := repositories.NewOrderRepositoryMock[uuid.UUID](t)
+		svc := NewOrderService(repoMock, newUnitOfWork(t))
+
+		mockOrder := entities.NewOrderBuilder().AccountID(uuid.New()).Build()
+		mockOrder.Status = entities.ORDER_CANCELLED
+		repoMock.On("GetById", mock.Anything, mockOrder.ID).Return(*mockOrder, nil).Once()
+
+		_, err := svc.Transition(adminCtx, dtos.TransitionOrderCommand{OrderID: mockOrder.ID, Status: "paid"})
+		assert.ErrorIs(t, err, entities.ErrorInvalidOrderTransition)
+	})
+
+	t.Run("order changed concurrently should return error", func(t *testing.T) {
+		repoMock := repositories.NewOrderRepositoryMock[uuid.UUID](t)
+		svc := NewOrderService(repoMock, newUnitOfWork(t))
+
+		mockOrder := entities.NewOrderBuilder().AccountID(uuid.New()).Build()
+		repoMock.On("GetById", mock.Anything, mockOrder.ID).Return(*mockOrder, nil).Once()
+		repoMock.On("UpdateStatus", mock.Anything, mock.Anything, mock.Anything).
+			Return(entities.ErrorOrderStatusChanged).Once()
+
+		_, err := svc.Transition(adminCtx, dtos.TransitionOrderCommand{OrderID: mockOrder.ID, Status: "paid"})
+		assert.ErrorIs(t, err, entities.ErrorOrderStatusChanged)
+	})
+}
+
+func TestGetOrderTransitions(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	t.Run("owner should get order history", func(t *testing.T) {
+		repoMock := repositories.NewOrderRepositoryMock[uuid.UUID](t)
+		svc := NewOrderService(repoMock, newUnitOfWork(t))
+
+		mockOrder := entities.NewOrderBuilder().AccountID(uuid.New()).Build()
+		change, err := mockOrder.TransitionTo(entities.ORDER_PAID, uuid.New(), "")
+		assert.NoError(t, err)
+
+		repoMock.On("GetById", mock.Anything, mockOrder.ID).Return(*mockOrder, nil).Once()
+		repoMock.On("GetHistory", mock.Anything, mockOrder.ID).Return([]entities.OrderHistory{*change}, nil).Once()
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: mockOrder.AccountID})
+		got, err := svc.GetTransitions(ctx, mockOrder.ID)
+		assert.NoError(t, err)
+		assert.Len(t, got, 1)
+		assert.Equal(t, "paid", got[0].ToStatus)
+	})
+
+	t.Run("another account should not get order history", func(t *testing.T) {
+		repoMock := repositories.NewOrderRepositoryMock[uuid.UUID](t)
+		svc := NewOrderService(repoMock, newUnitOfWork(t))
+
+		mockOrder := entities.NewOrderBuilder().AccountID(uuid.New()).Build()
+		repoMock.On("GetById", mock.Anything, mockOrder.ID).Return(*mockOrder, nil).Once()
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: uuid.New()})
+		_, err := svc.GetTransitions(ctx, mockOrder.ID)
+		assert.ErrorIs(t, err, ErrorForbidden)
+		repoMock.AssertNotCalled(t, "GetHistory", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCancelOrder(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	accountId := uuid.New()
+	customerCtx := core.WithPrincipal(ctx, core.Principal{AccountID: accountId, Role: entities.CUSTOMER})
+
+	t.Run("customer should cancel own paid order with reason", func(t *testing.T) {
+		repoMock := repositories.NewOrderRepositoryMock[uuid.UUID](t)
+		svc := NewOrderService(repoMock, newUnitOfWork(t))
+
+		mockOrder := entities.NewOrderBuilder().AccountID(accountId).Build()
+		mockOrder.Status = entities.ORDER_PAID
+		repoMock.On("GetById", mock.Anything, mockOrder.ID).Return(*mockOrder, nil).Once()
+		repoMock.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(o *entities.Order) bool {
+			return o.Status == entities.ORDER_CANCELLED && o.CancellationReason == "found it cheaper"
+		}), mock.MatchedBy(func(h *entities.OrderHistory) bool {
+			return h.FromStatus == entities.ORDER_PAID && h.ActorID.UUID == accountId && h.Note == "found it cheaper"
+		})).Return(nil).Once()
+
+		got, err := svc.Cancel(customerCtx, dtos.CancelOrderCommand{OrderID: mockOrder.ID, Reason: "found it cheaper"})
+		assert.NoError(t, err)
+		assert.Equal(t, "cancelled", got.Status)
+		assert.Equal(t, "found it cheaper", got.CancellationReason)
+	})
+
+	t.Run("shipped order should not be cancelled", func(t *testing.T) {
+		repoMock := repositories.NewOrderRepositoryMock[uuid.UUID](t)
+		svc := NewOrderService(repoMock, newUnitOfWork(t))
+
+		mockOrder := entities.NewOrderBuilder().AccountID(accountId).Build()
+		mockOrder.Status = entities.ORDER_SHIPPED
+		repoMock.On("GetById", mock.Anything, mockOrder.ID).Return(*mockOrder, nil).Once()
+
+		_, err := svc.Cancel(customerCtx, dtos.CancelOrderCommand{OrderID: mockOrder.ID, Reason: "too late"})
+		assert.ErrorIs(t, err, entities.ErrorOrderNotCancellable)
+		repoMock.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("customer should not cancel order of another account", func(t *testing.T) {
+		repoMock := repositories.NewOrderRepositoryMock[uuid.UUID](t)
+		svc := NewOrderService(repoMock, newUnitOfWork(t))
+
+		mockOrder := entities.NewOrderBuilder().AccountID(uuid.New()).Build()
+		repoMock.On("GetById", mock.Anything, mockOrder.ID).Return(*mockOrder, nil).Once()
+
+		_, err := svc.Cancel(customerCtx, dtos.CancelOrderCommand{OrderID: mockOrder.ID, Reason: "not mine"})
+		assert.ErrorIs(t, err, ErrorForbidden)
+	})
+}
+
+func TestRefundOrder(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	adminId := uuid.New()
+	adminCtx := core.WithPrincipal(ctx, core.Principal{AccountID: adminId, Role: entities.ADMIN})
+
+	newOrder := func() *entities.Order {
+		order := entities.NewOrderBuilder().AccountID(uuid.New()).Build()
+		order.Status = entities.ORDER_DELIVERED
+		oi := entities.NewOrderItemBuilder().OrderID(order.ID).ItemID(uuid.New()).Quantity(3).Build()
+		oi.UnitPrice = entities.NewMoney(1000, entities.EUR)
+		order.OrderItems = []*entities.OrderItem{oi}
+		return order
+	}
+	t.Run("admin should refund part of order line", func(t *testing.T) {
+		repoMock := repositories.NewOrderRepositoryMock[uuid.UUID](t)
+		svc := NewOrderService(repoMock, newUnitOfWork(t))
+
+		order := newOrder()
+		repoMock.On("GetByIdForUpdate", mock.Anything, order.ID).Return(*order, nil).Once()
+		repoMock.On("GetRefunds", mock.Anything, order.ID).Return(nil, nil).Once()
+		repoMock.On("CreateRefund", mock.Anything, mock.Anything, mock.Anything, (*entities.OrderHistory)(nil)).Return(nil).Once()
+
+		got, err := svc.Refund(adminCtx, dtos.RefundOrderCommand{
+			OrderID: order.ID,
+			Reason:  "damaged",
+			Items:   []dtos.RefundItemCommand{{OrderItemID: order.OrderItems[0].ID.String(), Quantity: 2}},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, entities.NewMoney(2000, entities.EUR), got.Amount)
+		assert.Equal(t, adminId.String(), got.ActorID)
+		assert.Len(t, got.Items, 1)
+		assert.Equal(t, entities.ORDER_DELIVERED, order.Status)
+	})
+
+	t.Run("refund exceeding ordered quantity should return error", func(t *testing.T) {
+		repoMock := repositories.NewOrderRepositoryMock[uuid.UUID](t)
+		svc := NewOrderService(repoMock, newUnitOfWork(t))
+
+		order := newOrder()
+		oi := order.OrderItems[0]
+		previous := entities.Refund{Items: []*entities.RefundItem{{OrderItemID: oi.ID, Quantity: 2}}}
+		repoMock.On("GetByIdForUpdate", mock.Anything, order.ID).Return(*order, nil).Once()
+		repoMock.On("GetRefunds", mock.Anything, order.ID).Return([]entities.Refund{previous}, nil).Once()
+
+		_, err := svc.Refund(adminCtx, dtos.RefundOrderCommand{
+			OrderID: order.ID,
+			Items:   []dtos.RefundItemCommand{{OrderItemID: oi.ID.String(), Quantity: 2}},
+		})
+		assert.ErrorIs(t, err, entities.ErrorRefundExceedsQuantity)
+	})
+
+	t.Run("customer should not be allowed to refund order", func(t *testing.T) {
+		repoMock := repositories.NewOrderRepositoryMock[uuid.UUID](t)
+		svc := NewOrderService(repoMock, newUnitOfWork(t))
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: uuid.New(), Role: entities.CUSTOMER})
+		_, err := svc.Refund(ctx, dtos.RefundOrderCommand{OrderID: uuid.New()})
+		assert.ErrorIs(t, err, ErrorForbidden)
+	})
+}
@@ -0,0 +1,314 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/fmiskovic/new-amz/internal/core"
+	"github.com/fmiskovic/new-amz/internal/core/dtos"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/core/repositories"
+	"github.com/google/uuid"
+)
+
+// PaymentService represents business logic related to entities.Payment.
+type PaymentService struct {
+	repo    repositories.PaymentRepository[uuid.UUID]
+	orders  repositories.OrderRepository[uuid.UUID]
+	gateway core.PaymentGateway
+}
+
+// NewPaymentService instantiates new PaymentService.
+func NewPaymentService(
+	repo repositories.PaymentRepository[uuid.UUID],
+	orders repositories.OrderRepository[uuid.UUID],
+	gateway core.PaymentGateway,
+) PaymentService {
+	return PaymentService{repo: repo, orders: orders, gateway: gateway}
+}
+
+// Authorize pays the pending order with the payment method the token stands for, by authorizing its total
+// at the payment provider. Declined payments are recorded as failed and entities.ErrorPaymentDeclined is returned.
+func (s PaymentService) Authorize(ctx context.Context, cmd dtos.AuthorizePaymentCommand) (dtos.PaymentDto, error) {
+	order, err := s.orders.GetById(ctx, cmd.OrderID)
+	if err != nil {
+		return dtos.PaymentDto{}, newError(fmt.Sprintf("failed to get order by id: %s", cmd.OrderID.String()), err)
+	}
+	if err := authorize(ctx, order.AccountID); err != nil {
+		return dtos.PaymentDto{}, err
+	}
+	if order.Status != entities.ORDER_PENDING {
+		return dtos.PaymentDto{}, fmt.Errorf("%w: order is %s", entities.ErrorOrderNotPayable, order.Status)
+	}
+
+	payments, err := s.repo.GetByOrder(ctx, order.ID)
+	if err != nil {
+		return dtos.PaymentDto{}, newError("failed to get order payments", err)
+	}
+	for _, p := range payments {
+		if p.IsActive() {
+			return dtos.PaymentDto{}, entities.ErrorOrderPaymentExists
+		}
+	}
+
+	total, err := order.Total()
+	if err != nil {
+		return dtos.PaymentDto{}, newError("failed to get order total", err)
+	}
+
+	payment := entities.NewPayment(order.ID, s.gateway.Provider(), total)
+	reference, err := s.gateway.Authorize(ctx, payment.ID, total, cmd.Token)
+	if errors.Is(err, entities.ErrorPaymentDeclined) {
+		_ = payment.Fail(err.Error())
+		if createErr := s.repo.Create(ctx, payment); createErr != nil {
+			return dtos.PaymentDto{}, newError("failed to create payment", createErr)
+		}
+		return dtos.PaymentDto{}, err
+	}
+	if err != nil {
+		return dtos.PaymentDto{}, newError("failed to authorize payment", err)
+	}
+	payment.Reference = reference
+
+	if err := s.repo.Create(ctx, payment); err != nil {
+		// the order has been paid concurrently, the authorization is released again
+		if errors.Is(err, entities.ErrorOrderPaymentExists) {
+			_ = s.gateway.Void(context.WithoutCancel(ctx), reference, uuid.NewString())
+		}
+		return dtos.PaymentDto{}, newError("failed to create payment", err)
+	}
+
+	return dtos.ToPaymentDto(*payment), nil
+}
+
+// GetByOrder returns payments of the order.
+func (s PaymentService) GetByOrder(ctx context.Context, orderId uuid.UUID) ([]dtos.PaymentDto, error) {
+	order, err := s.orders.GetById(ctx, orderId)
+	if err != nil {
+		return nil, newError(fmt.Sprintf("failed to get order by id: %s", orderId.String()), err)
+	}
+	if err := authorize(ctx, order.AccountID); err != nil {
+		return nil, err
+	}
+
+	payments, err := s.repo.GetByOrder(ctx, orderId)
+	if err != nil {
+		return nil, newError("failed to get order payments", err)
+	}
+
+	return dtos.ToPaymentDtos(payments), nil
+}
+
+// Capture collects the authorized payment, the whole authorized amount if no amount is given,
+// and moves its order into paid status.
+func (s PaymentService) Capture(ctx context.Context, cmd dtos.PaymentAmountCommand) (dtos.PaymentDto, error) {
+	p, err := authorizeAdmin(ctx)
+	if err != nil {
+		return dtos.PaymentDto{}, err
+	}
+
+	payment, err := s.operate(ctx, cmd.PaymentID,
+		func(payment *entities.Payment, order entities.Order) error {
+			if order.Status != entities.ORDER_PENDING {
+				return fmt.Errorf("%w: order is %s", entities.ErrorOrderNotPayable, order.Status)
+			}
+			amount := payment.Amount
+			if cmd.Amount != nil {
+				amount = *cmd.Amount
+			}
+			return payment.Begin(entities.PAYMENT_OPERATION_CAPTURE, amount)
+		},
+		func(_ *entities.Payment, order entities.Order) (*entities.OrderHistory, error) {
+			if order.Status != entities.ORDER_PENDING {
+				return nil, nil
+			}
+			return order.TransitionTo(entities.ORDER_PAID, p.AccountID, "payment captured")
+		},
+	)
+	if err != nil {
+		return dtos.PaymentDto{}, newError("failed to capture payment", err)
+	}
+
+	return dtos.ToPaymentDto(payment), nil
+}
+
+// Void releases the authorized payment without collecting anything.
+func (s PaymentService) Void(ctx context.Context, id uuid.UUID) (dtos.PaymentDto, error) {
+	if _, err := authorizeAdmin(ctx); err != nil {
+		return dtos.PaymentDto{}, err
+	}
+
+	payment, err := s.operate(ctx, id,
+		func(payment *entities.Payment, _ entities.Order) error {
+			return payment.Begin(entities.PAYMENT_OPERATION_VOID, payment.Amount)
+		},
+		func(*entities.Payment, entities.Order) (*entities.OrderHistory, error) {
+			return nil, nil
+		},
+	)
+	if err != nil {
+		return dtos.PaymentDto{}, newError("failed to void payment", err)
+	}
+
+	return dtos.ToPaymentDto(payment), nil
+}
+
+// Refund gives back the amount of the captured payment, everything not refunded yet if no amount is given.
+// Once the payment is refunded in full, its order moves into refunded status if the order lifecycle allows it.
+func (s PaymentService) Refund(ctx context.Context, cmd dtos.PaymentAmountCommand) (dtos.PaymentDto, error) {
+	p, err := authorizeAdmin(ctx)
+	if err != nil {
+		return dtos.PaymentDto{}, err
+	}
+
+	payment, err := s.operate(ctx, cmd.PaymentID,
+		func(payment *entities.Payment, _ entities.Order) error {
+			amount := payment.Refundable()
+			if cmd.Amount != nil {
+				amount = *cmd.Amount
+			}
+			return payment.Begin(entities.PAYMENT_OPERATION_REFUND, amount)
+		},
+		func(payment *entities.Payment, order entities.Order) (*entities.OrderHistory, error) {
+			return refundOrder(payment, &order, p.AccountID)
+		},
+	)
+	if err != nil {
+		return dtos.PaymentDto{}, newError("failed to refund payment", err)
+	}
+
+	return dtos.ToPaymentDto(payment), nil
+}
+
+// operate carries out an operation on the payment at the provider without keeping the payment locked meanwhile.
+// The begin func records the operation as pending, then it is sent to the provider and its outcome is recorded:
+// the operation is completed, together with the order status change the complete func returns, or aborted
+// if the provider rejects it. Operations with an unknown outcome, as the provider could not be reached or the outcome
+// could not be recorded, stay pending and are resumed by repeating the request. They are sent with the same idempotency
+// key then, so the provider carries them out only once.
+func (s PaymentService) operate(
+	ctx context.Context,
+	id uuid.UUID,
+	begin func(payment *entities.Payment, order entities.Order) error,
+	complete func(payment *entities.Payment, order entities.Order) (*entities.OrderHistory, error),
+) (entities.Payment, error) {
+	payment, err := s.repo.Update(ctx, id, func(_ context.Context, payment *entities.Payment, order entities.Order) (*entities.OrderHistory, error) {
+		return nil, begin(payment, order)
+	})
+	if err != nil {
+		return entities.Payment{}, err
+	}
+
+	// money may be moved once the operation is sent, so its outcome is recorded even if the caller is gone already
+	ctx = context.WithoutCancel(ctx)
+	key := payment.PendingKey
+	if err := s.send(ctx, payment); err != nil {
+		if !rejected(err) {
+			return entities.Payment{}, err
+		}
+		_, abortErr := s.repo.Update(ctx, id, func(_ context.Context, payment *entities.Payment, _ entities.Order) (*entities.OrderHistory, error) {
+			payment.Abort(key)
+			return nil, nil
+		})
+		return entities.Payment{}, errors.Join(err, abortErr)
+	}
+
+	return s.repo.Update(ctx, id, func(_ context.Context, payment *entities.Payment, order entities.Order) (*entities.OrderHistory, error) {
+		completed, err := payment.Complete(key)
+		if err != nil || !completed {
+			// operations settled by events of the provider changed the order already
+			return nil, err
+		}
+		return complete(payment, order)
+	})
+}
+
+// send sends the pending operation of the payment to the provider.
+func (s PaymentService) send(ctx context.Context, payment entities.Payment) error {
+	switch payment.PendingOperation {
+	case entities.PAYMENT_OPERATION_CAPTURE:
+		return s.gateway.Capture(ctx, payment.Reference, payment.Pending(), payment.PendingKey)
+	case entities.PAYMENT_OPERATION_VOID:
+		return s.gateway.Void(ctx, payment.Reference, payment.PendingKey)
+	case entities.PAYMENT_OPERATION_REFUND:
+		return s.gateway.Refund(ctx, payment.Reference, payment.Pending(), payment.PendingKey)
+	default:
+		return fmt.Errorf("%w: no operation is pending", entities.ErrorInvalidPaymentOperation)
+	}
+}
+
+// rejected reports whether the provider turned the operation down, so it is known not to be carried out.
+// Other errors, like failures to reach the provider, leave the outcome of the operation unknown.
+func rejected(err error) bool {
+	var domainErr entities.DomainError
+	return errors.As(err, &domainErr) && !errors.Is(err, entities.ErrorUnavailable)
+}
+
+// HandleEvent applies the payment event sent by the payment provider to the payment and its order.
+// Providers deliver events at least once, so events applied already are ignored.
+func (s PaymentService) HandleEvent(ctx context.Context, cmd dtos.PaymentEventCommand) (dtos.PaymentDto, error) {
+	payment, err := s.repo.GetByReference(ctx, s.gateway.Provider(), cmd.Reference)
+	if err != nil {
+		return dtos.PaymentDto{}, newError(fmt.Sprintf("failed to get payment by reference: %s", cmd.Reference), err)
+	}
+
+	payment, err = s.repo.Update(ctx, payment.ID, func(_ context.Context, payment *entities.Payment, order entities.Order) (*entities.OrderHistory, error) {
+		return applyEvent(cmd, payment, &order)
+	})
+	if err != nil {
+		return dtos.PaymentDto{}, newError(fmt.Sprintf("failed to apply payment event: %s", cmd.ID), err)
+	}
+
+	return dtos.ToPaymentDto(payment), nil
+}
+
+// applyEvent changes the payment as told by the event and returns the resulting status change of its order, if any.
+func applyEvent(event dtos.PaymentEventCommand, payment *entities.Payment, order *entities.Order) (*entities.OrderHistory, error) {
+	switch event.Type {
+	case dtos.PaymentEventCaptured:
+		if payment.Status == entities.PAYMENT_AUTHORIZED {
+			amount := payment.Amount
+			if event.Amount != nil {
+				amount = *event.Amount
+			}
+			if err := payment.Capture(amount); err != nil {
+				return nil, err
+			}
+		}
+		if payment.Status == entities.PAYMENT_CAPTURED && order.Status == entities.ORDER_PENDING {
+			return order.TransitionTo(entities.ORDER_PAID, uuid.Nil, "payment captured by "+payment.Provider)
+		}
+		return nil, nil
+	case dtos.PaymentEventVoided:
+		if payment.Status == entities.PAYMENT_VOIDED {
+			return nil, nil
+		}
+		return nil, payment.Void()
+	case dtos.PaymentEventFailed:
+		if payment.Status == entities.PAYMENT_FAILED {
+			return nil, nil
+		}
+		return nil, payment.Fail(event.Reason)
+	case dtos.PaymentEventRefunded:
+		if event.Amount == nil {
+			return nil, fmt.Errorf("%w: refunded amount is missing", ErrorInvalidEvent)
+		}
+		if event.Amount.Amount > payment.Refunded {
+			refunded := entities.NewMoney(event.Amount.Amount-payment.Refunded, event.Amount.Currency)
+			if err := payment.Refund(refunded); err != nil {
+				return nil, err
+			}
+		}
+		return refundOrder(payment, order, uuid.Nil)
+	default:
+		return nil, fmt.Errorf("%w: unknown type %s", ErrorInvalidEvent, event.Type)
+	}
+}
+
+// refundOrder moves the order of the payment refunded in full into refunded status, if the order lifecycle allows it.
+func refundOrder(payment *entities.Payment, order *entities.Order, actorId uuid.UUID) (*entities.OrderHistory, error) {
+	if payment.Status != entities.PAYMENT_REFUNDED || !order.Status.CanTransitionTo(entities.ORDER_REFUNDED) {
+		return nil, nil
+	}
+	return order.TransitionTo(entities.ORDER_REFUNDED, actorId, "payment refunded")
+}
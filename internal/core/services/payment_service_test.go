@@ -0,0 +1,318 @@
+package services
+
+import (
+	"context"
+	"github.com/fmiskovic/new-amz/internal/core"
+	"github.com/fmiskovic/new-amz/internal/core/dtos"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/core/repositories"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+type updatePaymentFunc = func(context.Context, *entities.Payment, entities.Order) (*entities.OrderHistory, error)
+
+// updatePayment runs the update func of the service against the payment and order, like the repository does,
+// and records the order status change it returns.
+func updatePayment(payment entities.Payment, order entities.Order, change **entities.OrderHistory) func(context.Context, uuid.UUID, updatePaymentFunc) (entities.Payment, error) {
+	return func(ctx context.Context, _ uuid.UUID, update updatePaymentFunc) (entities.Payment, error) {
+		var err error
+		if *change, err = update(ctx, &payment, order); err != nil {
+			return entities.Payment{}, err
+		}
+		return payment, nil
+	}
+}
+
+func newPendingOrder(accountId uuid.UUID) *entities.Order {
+	order := entities.NewOrderBuilder().AccountID(accountId).Build()
+	oi := entities.NewOrderItemBuilder().OrderID(order.ID).ItemID(uuid.New()).Quantity(2).Build()
+	oi.UnitPrice = entities.NewMoney(1000, entities.EUR)
+	order.OrderItems = []*entities.OrderItem{oi}
+	return order
+}
+
+func TestAuthorizePayment(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	accountId := uuid.New()
+	customerCtx := core.WithPrincipal(ctx, core.Principal{AccountID: accountId, Role: entities.CUSTOMER})
+
+	t.Run("should authorize order total and record payment", func(t *testing.T) {
+		repoMock := repositories.NewPaymentRepositoryMock[uuid.UUID](t)
+		ordersMock := repositories.NewOrderRepositoryMock[uuid.UUID](t)
+		gatewayMock := core.NewPaymentGatewayMock(t)
+		svc := NewPaymentService(repoMock, ordersMock, gatewayMock)
+
+		order := newPendingOrder(accountId)
+		ordersMock.On("GetById", mock.Anything, order.ID).Return(*order, nil).Once()
+		repoMock.On("GetByOrder", mock.Anything, order.ID).Return([]entities.Payment{}, nil).Once()
+		gatewayMock.On("Provider").Return("fake")
+		gatewayMock.On("Authorize", mock.Anything, mock.Anything, entities.NewMoney(2000, entities.EUR), "tok_visa").
+			Return("fake_ref", nil).Once()
+		repoMock.On("Create", mock.Anything, mock.MatchedBy(func(p *entities.Payment) bool {
+			return p.Status == entities.PAYMENT_AUTHORIZED && p.Reference == "fake_ref" && p.Provider == "fake"
+		})).Return(nil).Once()
+
+		got, err := svc.Authorize(customerCtx, dtos.AuthorizePaymentCommand{OrderID: order.ID, Token: "tok_visa"})
+		assert.Nil(t, err)
+		assert.Equal(t, "authorized", got.Status)
+		assert.Equal(t, "20.00", got.Amount.Decimal())
+	})
+
+	t.Run("should record declined payment as failed", func(t *testing.T) {
+		repoMock := repositories.NewPaymentRepositoryMock[uuid.UUID](t)
+		ordersMock := repositories.NewOrderRepositoryMock[uuid.UUID](t)
+		gatewayMock := core.NewPaymentGatewayMock(t)
+		svc := NewPaymentService(repoMock, ordersMock, gatewayMock)
+
+		order := newPendingOrder(accountId)
+		ordersMock.On("GetById", mock.Anything, order.ID).Return(*order, nil).Once()
+		repoMock.On("GetByOrder", mock.Anything, order.ID).Return([]entities.Payment{}, nil).Once()
+		gatewayMock.On("Provider").Return("fake")
+		gatewayMock.On("Authorize", mock.Anything, mock.Anything, mock.Anything, "tok_declined").
+			Return("", entities.ErrorPaymentDeclined).Once()
+		repoMock.On("Create", mock.Anything, mock.MatchedBy(func(p *entities.Payment) bool {
+			return p.Status == entities.PAYMENT_FAILED && p.Reference == ""
+		})).Return(nil).Once()
+
+		_, err := svc.Authorize(customerCtx, dtos.AuthorizePaymentCommand{OrderID: order.ID, Token: "tok_declined"})
+		assert.ErrorIs(t, err, entities.ErrorPaymentDeclined)
+	})
+
+	t.Run("should reject paying order with active payment", func(t *testing.T) {
+		repoMock := repositories.NewPaymentRepositoryMock[uuid.UUID](t)
+		ordersMock := repositories.NewOrderRepositoryMock[uuid.UUID](t)
+		svc := NewPaymentService(repoMock, ordersMock, core.NewPaymentGatewayMock(t))
+
+		order := newPendingOrder(accountId)
+		active := entities.NewPayment(order.ID, "fake", entities.NewMoney(2000, entities.EUR))
+		ordersMock.On("GetById", mock.Anything, order.ID).Return(*order, nil).Once()
+		repoMock.On("GetByOrder", mock.Anything, order.ID).Return([]entities.Payment{*active}, nil).Once()
+
+		_, err := svc.Authorize(customerCtx, dtos.AuthorizePaymentCommand{OrderID: order.ID, Token: "tok_visa"})
+		assert.ErrorIs(t, err, entities.ErrorOrderPaymentExists)
+	})
+
+	t.Run("should reject paying order which is not pending", func(t *testing.T) {
+		ordersMock := repositories.NewOrderRepositoryMock[uuid.UUID](t)
+		svc := NewPaymentService(repositories.NewPaymentRepositoryMock[uuid.UUID](t), ordersMock, core.NewPaymentGatewayMock(t))
+
+		order := newPendingOrder(accountId)
+		order.Status = entities.ORDER_CANCELLED
+		ordersMock.On("GetById", mock.Anything, order.ID).Return(*order, nil).Once()
+
+		_, err := svc.Authorize(customerCtx, dtos.AuthorizePaymentCommand{OrderID: order.ID, Token: "tok_visa"})
+		assert.ErrorIs(t, err, entities.ErrorOrderNotPayable)
+	})
+}
+
+func TestCapturePayment(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	adminCtx := core.WithPrincipal(ctx, core.Principal{AccountID: uuid.New(), Role: entities.ADMIN})
+
+	t.Run("should capture payment and move order into paid status", func(t *testing.T) {
+		repoMock := repositories.NewPaymentRepositoryMock[uuid.UUID](t)
+		gatewayMock := core.NewPaymentGatewayMock(t)
+		svc := NewPaymentService(repoMock, repositories.NewOrderRepositoryMock[uuid.UUID](t), gatewayMock)
+
+		order := newPendingOrder(uuid.New())
+		payment := entities.NewPayment(order.ID, "fake", entities.NewMoney(2000, entities.EUR))
+		payment.Reference = "fake_ref"
+		var change *entities.OrderHistory
+		update := updatePayment(*payment, *order, &change)
+		updating := false
+		repoMock.On("Update", mock.Anything, payment.ID, mock.Anything).
+			Return(func(ctx context.Context, id uuid.UUID, fn updatePaymentFunc) (entities.Payment, error) {
+				updating = true
+				defer func() { updating = false }()
+				return update(ctx, id, fn)
+			}).
+			Twice()
+		gatewayMock.On("Capture", mock.Anything, "fake_ref", payment.Amount, mock.AnythingOfType("string")).
+			Run(func(mock.Arguments) {
+				assert.False(t, updating, "provider must not be called while the payment is locked")
+			}).
+			Return(nil).Once()
+
+		got, err := svc.Capture(adminCtx, dtos.PaymentAmountCommand{PaymentID: payment.ID})
+		assert.Nil(t, err)
+		assert.Equal(t, "captured", got.Status)
+		assert.Equal(t, "20.00", got.Captured.Decimal())
+		assert.NotNil(t, change)
+		assert.Equal(t, entities.ORDER_PAID, change.ToStatus)
+	})
+
+	t.Run("should abort capture rejected by the provider", func(t *testing.T) {
+		repoMock := repositories.NewPaymentRepositoryMock[uuid.UUID](t)
+		gatewayMock := core.NewPaymentGatewayMock(t)
+		svc := NewPaymentService(repoMock, repositories.NewOrderRepositoryMock[uuid.UUID](t), gatewayMock)
+
+		order := newPendingOrder(uuid.New())
+		payment := entities.NewPayment(order.ID, "fake", entities.NewMoney(2000, entities.EUR))
+		var change *entities.OrderHistory
+		var recorded entities.Payment
+		update := updatePayment(*payment, *order, &change)
+		repoMock.On("Update", mock.Anything, payment.ID, mock.Anything).
+			Return(func(ctx context.Context, id uuid.UUID, fn updatePaymentFunc) (entities.Payment, error) {
+				var err error
+				recorded, err = update(ctx, id, fn)
+				return recorded, err
+			}).
+			Twice()
+		gatewayMock.On("Capture", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(entities.ErrorPaymentRejected).Once()
+
+		_, err := svc.Capture(adminCtx, dtos.PaymentAmountCommand{PaymentID: payment.ID})
+		assert.ErrorIs(t, err, entities.ErrorPaymentRejected)
+		assert.Equal(t, entities.PAYMENT_AUTHORIZED, recorded.Status)
+		assert.Empty(t, recorded.PendingOperation)
+		assert.Nil(t, change)
+	})
+
+	t.Run("should keep capture pending while provider is unavailable and resume it with the same key", func(t *testing.T) {
+		repoMock := repositories.NewPaymentRepositoryMock[uuid.UUID](t)
+		gatewayMock := core.NewPaymentGatewayMock(t)
+		svc := NewPaymentService(repoMock, repositories.NewOrderRepositoryMock[uuid.UUID](t), gatewayMock)
+
+		order := newPendingOrder(uuid.New())
+		payment := entities.NewPayment(order.ID, "fake", entities.NewMoney(2000, entities.EUR))
+		var change *entities.OrderHistory
+		repoMock.On("Update", mock.Anything, payment.ID, mock.Anything).Return(updatePayment(*payment, *order, &change))
+		var keys []string
+		unavailable := entities.NewError(entities.ErrorUnavailable, "payment provider is unavailable")
+		gatewayMock.On("Capture", mock.Anything, mock.Anything, payment.Amount, mock.Anything).
+			Run(func(args mock.Arguments) { keys = append(keys, args.String(3)) }).
+			Return(unavailable).Once()
+
+		_, err := svc.Capture(adminCtx, dtos.PaymentAmountCommand{PaymentID: payment.ID})
+		assert.ErrorIs(t, err, entities.ErrorUnavailable)
+
+		// when retried with another amount, the pending capture is resumed
+		gatewayMock.On("Capture", mock.Anything, mock.Anything, payment.Amount, mock.Anything).
+			Run(func(args mock.Arguments) { keys = append(keys, args.String(3)) }).
+			Return(nil).Once()
+		amount := entities.NewMoney(1000, entities.EUR)
+		got, err := svc.Capture(adminCtx, dtos.PaymentAmountCommand{PaymentID: payment.ID, Amount: &amount})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "20.00", got.Captured.Decimal())
+		assert.Len(t, keys, 2)
+		assert.NotEmpty(t, keys[0])
+		assert.Equal(t, keys[0], keys[1])
+		assert.NotNil(t, change)
+	})
+
+	t.Run("should reject void while capture is pending", func(t *testing.T) {
+		repoMock := repositories.NewPaymentRepositoryMock[uuid.UUID](t)
+		svc := NewPaymentService(repoMock, repositories.NewOrderRepositoryMock[uuid.UUID](t), core.NewPaymentGatewayMock(t))
+
+		order := newPendingOrder(uuid.New())
+		payment := entities.NewPayment(order.ID, "fake", entities.NewMoney(2000, entities.EUR))
+		assert.Nil(t, payment.Begin(entities.PAYMENT_OPERATION_CAPTURE, payment.Amount))
+		var change *entities.OrderHistory
+		repoMock.On("Update", mock.Anything, payment.ID, mock.Anything).Return(updatePayment(*payment, *order, &change)).Once()
+
+		_, err := svc.Void(adminCtx, payment.ID)
+		assert.ErrorIs(t, err, entities.ErrorPaymentOperationPending)
+	})
+
+	t.Run("should not capture more than authorized", func(t *testing.T) {
+		repoMock := repositories.NewPaymentRepositoryMock[uuid.UUID](t)
+		svc := NewPaymentService(repoMock, repositories.NewOrderRepositoryMock[uuid.UUID](t), core.NewPaymentGatewayMock(t))
+
+		order := newPendingOrder(uuid.New())
+		payment := entities.NewPayment(order.ID, "fake", entities.NewMoney(2000, entities.EUR))
+		var change *entities.OrderHistory
+		repoMock.On("Update", mock.Anything, payment.ID, mock.Anything).Return(updatePayment(*payment, *order, &change)).Once()
+
+		amount := entities.NewMoney(2001, entities.EUR)
+		_, err := svc.Capture(adminCtx, dtos.PaymentAmountCommand{PaymentID: payment.ID, Amount: &amount})
+		assert.ErrorIs(t, err, entities.ErrorPaymentAmount)
+	})
+
+	t.Run("customer should not be allowed to capture payment", func(t *testing.T) {
+		svc := NewPaymentService(repositories.NewPaymentRepositoryMock[uuid.UUID](t), repositories.NewOrderRepositoryMock[uuid.UUID](t), core.NewPaymentGatewayMock(t))
+
+		customerCtx := core.WithPrincipal(ctx, core.Principal{AccountID: uuid.New(), Role: entities.CUSTOMER})
+		_, err := svc.Capture(customerCtx, dtos.PaymentAmountCommand{PaymentID: uuid.New()})
+		assert.ErrorIs(t, err, ErrorForbidden)
+	})
+}
+
+func TestHandlePaymentEvent(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	newService := func(t *testing.T, payment entities.Payment, order entities.Order, change **entities.OrderHistory) PaymentService {
+		repoMock := repositories.NewPaymentRepositoryMock[uuid.UUID](t)
+		gatewayMock := core.NewPaymentGatewayMock(t)
+		gatewayMock.On("Provider").Return("fake")
+		repoMock.On("GetByReference", mock.Anything, "fake", "fake_ref").Return(payment, nil).Once()
+		repoMock.On("Update", mock.Anything, payment.ID, mock.Anything).Return(updatePayment(payment, order, change)).Once()
+		return NewPaymentService(repoMock, repositories.NewOrderRepositoryMock[uuid.UUID](t), gatewayMock)
+	}
+
+	t.Run("captured event should move pending order into paid status", func(t *testing.T) {
+		order := newPendingOrder(uuid.New())
+		payment := entities.NewPayment(order.ID, "fake", entities.NewMoney(2000, entities.EUR))
+		var change *entities.OrderHistory
+		svc := newService(t, *payment, *order, &change)
+
+		got, err := svc.HandleEvent(ctx, dtos.PaymentEventCommand{ID: "evt_1", Type: dtos.PaymentEventCaptured, Reference: "fake_ref"})
+		assert.Nil(t, err)
+		assert.Equal(t, "captured", got.Status)
+		assert.NotNil(t, change)
+		assert.Equal(t, entities.ORDER_PAID, change.ToStatus)
+		assert.False(t, change.ActorID.Valid)
+	})
+
+	t.Run("redelivered captured event should change nothing", func(t *testing.T) {
+		order := newPendingOrder(uuid.New())
+		order.Status = entities.ORDER_PAID
+		payment := entities.NewPayment(order.ID, "fake", entities.NewMoney(2000, entities.EUR))
+		assert.Nil(t, payment.Capture(payment.Amount))
+		var change *entities.OrderHistory
+		svc := newService(t, *payment, *order, &change)
+
+		got, err := svc.HandleEvent(ctx, dtos.PaymentEventCommand{ID: "evt_1", Type: dtos.PaymentEventCaptured, Reference: "fake_ref"})
+		assert.Nil(t, err)
+		assert.Equal(t, "20.00", got.Captured.Decimal())
+		assert.Nil(t, change)
+	})
+
+	t.Run("refunded events should apply refunded amount in total once", func(t *testing.T) {
+		order := newPendingOrder(uuid.New())
+		order.Status = entities.ORDER_DELIVERED
+		payment := entities.NewPayment(order.ID, "fake", entities.NewMoney(2000, entities.EUR))
+		assert.Nil(t, payment.Capture(payment.Amount))
+		assert.Nil(t, payment.Refund(entities.NewMoney(500, entities.EUR)))
+		var change *entities.OrderHistory
+		svc := newService(t, *payment, *order, &change)
+
+		total := entities.NewMoney(2000, entities.EUR)
+		got, err := svc.HandleEvent(ctx, dtos.PaymentEventCommand{ID: "evt_2", Type: dtos.PaymentEventRefunded, Reference: "fake_ref", Amount: &total})
+		assert.Nil(t, err)
+		assert.Equal(t, "refunded", got.Status)
+		assert.Equal(t, "20.00", got.Refunded.Decimal())
+		assert.NotNil(t, change)
+		assert.Equal(t, entities.ORDER_REFUNDED, change.ToStatus)
+	})
+
+	t.Run("voided event of captured payment should return error", func(t *testing.T) {
+		order := newPendingOrder(uuid.New())
+		payment := entities.NewPayment(order.ID, "fake", entities.NewMoney(2000, entities.EUR))
+		assert.Nil(t, payment.Capture(payment.Amount))
+		var change *entities.OrderHistory
+		svc := newService(t, *payment, *order, &change)
+
+		_, err := svc.HandleEvent(ctx, dtos.PaymentEventCommand{ID: "evt_3", Type: dtos.PaymentEventVoided, Reference: "fake_ref"})
+		assert.ErrorIs(t, err, entities.ErrorInvalidPaymentOperation)
+	})
+}
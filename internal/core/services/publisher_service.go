@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/fmiskovic/new-amz/internal/core/dtos"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/core/repositories"
+	"github.com/google/uuid"
+)
+
+// PublisherService represents business logic related to entities.Publisher.
+type PublisherService struct {
+	repo repositories.PublisherRepository[uuid.UUID]
+}
+
+// NewPublisherService instantiates new PublisherService.
+func NewPublisherService(repo repositories.PublisherRepository[uuid.UUID]) PublisherService {
+	return PublisherService{repo}
+}
+
+// GetById returns existing publisher by id.
+func (s PublisherService) GetById(ctx context.Context, id uuid.UUID) (dtos.PublisherDto, error) {
+	publisher, err := s.repo.GetById(ctx, id)
+	if err != nil {
+		return dtos.PublisherDto{}, newError(fmt.Sprintf("failed to get publisher by id: %s", id.String()), err)
+	}
+	return dtos.ToPublisherDto(publisher), nil
+}
+
+// GetPage returns page of publishers.
+func (s PublisherService) GetPage(ctx context.Context, p entities.Pageable) (entities.Page[dtos.PublisherDto], error) {
+	page, err := s.repo.GetPage(ctx, p)
+	if err != nil {
+		return entities.Page[dtos.PublisherDto]{}, newError("failed to get page of publishers", err)
+	}
+	return dtos.ToPagePublisherDto(page), nil
+}
+
+// Create creates new publisher.
+func (s PublisherService) Create(ctx context.Context, dto dtos.PublisherDto) (dtos.PublisherDto, error) {
+	if _, err := authorizeAdmin(ctx); err != nil {
+		return dtos.PublisherDto{}, err
+	}
+
+	dto.ID = ""
+	publisher, err := dtos.ToPublisherEntity(dto)
+	if err != nil {
+		return dtos.PublisherDto{}, newError("invalid publisher", err)
+	}
+
+	if err := s.repo.Create(ctx, publisher); err != nil {
+		return dtos.PublisherDto{}, newError("failed to create publisher", err)
+	}
+	return dtos.ToPublisherDto(*publisher), nil
+}
+
+// Update replaces existing publisher.
+func (s PublisherService) Update(ctx context.Context, dto dtos.PublisherDto) (dtos.PublisherDto, error) {
+	if _, err := authorizeAdmin(ctx); err != nil {
+		return dtos.PublisherDto{}, err
+	}
+
+	publisher, err := dtos.ToPublisherEntity(dto)
+	if err != nil {
+		return dtos.PublisherDto{}, newError("invalid publisher", err)
+	}
+
+	if err := s.repo.Update(ctx, publisher); err != nil {
+		return dtos.PublisherDto{}, newError(fmt.Sprintf("failed to update publisher: %s", publisher.ID.String()), err)
+	}
+	return dtos.ToPublisherDto(*publisher), nil
+}
+
+// DeleteById deletes existing publisher which has not published any item.
+func (s PublisherService) DeleteById(ctx context.Context, cmd dtos.DeleteCommand) (struct{}, error) {
+	if _, err := authorizeAdmin(ctx); err != nil {
+		return struct{}{}, err
+	}
+
+	if err := s.repo.DeleteById(ctx, cmd.ID, cmd.Version); err != nil {
+		return struct{}{}, newError(fmt.Sprintf("failed to delete publisher: %s", cmd.ID.String()), err)
+	}
+	return struct{}{}, nil
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"github.com/fmiskovic/new-amz/internal/core"
+	"github.com/fmiskovic/new-amz/internal/core/dtos"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/core/repositories"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestGetPublishersPage(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	t.Run("should return page of publisher dtos", func(t *testing.T) {
+		repoMock := repositories.NewPublisherRepositoryMock[uuid.UUID](t)
+		svc := NewPublisherService(repoMock)
+
+		pageRequest := entities.Pageable{Size: 10}
+		publisher := entities.NewPublisherBuilder().Name("Penguin").Build()
+		repoMock.On("GetPage", mock.Anything, pageRequest).
+			Return(entities.NewPage([]entities.Publisher{*publisher}, pageRequest, 1, false), nil).Once()
+
+		got, err := svc.GetPage(ctx, pageRequest)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, *got.TotalElements)
+		assert.Equal(t, "Penguin", got.Elements[0].Name)
+	})
+}
+
+func TestCreatePublisher(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	dto := dtos.PublisherDto{Name: "Penguin"}
+
+	t.Run("admin should create publisher", func(t *testing.T) {
+		repoMock := repositories.NewPublisherRepositoryMock[uuid.UUID](t)
+		svc := NewPublisherService(repoMock)
+
+		repoMock.On("Create", mock.Anything, mock.MatchedBy(func(p *entities.Publisher) bool {
+			return p.Name == "Penguin"
+		})).Return(nil).Once()
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: uuid.New(), Role: entities.ADMIN})
+		got, err := svc.Create(ctx, dto)
+		assert.Nil(t, err)
+		assert.NotEmpty(t, got.ID)
+	})
+
+	t.Run("given taken name should return conflict error", func(t *testing.T) {
+		repoMock := repositories.NewPublisherRepositoryMock[uuid.UUID](t)
+		svc := NewPublisherService(repoMock)
+
+		repoMock.On("Create", mock.Anything, mock.Anything).Return(entities.ErrorPublisherNotUnique).Once()
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: uuid.New(), Role: entities.ADMIN})
+		_, err := svc.Create(ctx, dto)
+		assert.ErrorIs(t, err, entities.ErrorConflict)
+	})
+
+	t.Run("customer should not be allowed to create publisher", func(t *testing.T) {
+		repoMock := repositories.NewPublisherRepositoryMock[uuid.UUID](t)
+		svc := NewPublisherService(repoMock)
+
+		ctx := core.WithPrincipal(ctx, core.Principal{AccountID: uuid.New(), Role: entities.CUSTOMER})
+		_, err := svc.Create(ctx, dto)
+		assert.ErrorIs(t, err, ErrorForbidden)
+		repoMock.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
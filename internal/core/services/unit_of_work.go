@@ -0,0 +1,35 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fmiskovic/new-amz/internal/core"
+)
+
+// ErrorNoTxManager is returned by units of work of services constructed without a transaction manager,
+// rather than running the unit without a transaction.
+var ErrorNoTxManager = errors.New("unit of work has no transaction manager")
+
+// UnitOfWork gives services which embed it the WithinTx helper,
+// for combining operations of several repositories atomically.
+type UnitOfWork struct {
+	txManager core.TxManager
+}
+
+// NewUnitOfWork instantiates new UnitOfWork running its units in transactions of the txManager.
+func NewUnitOfWork(txManager core.TxManager) UnitOfWork {
+	return UnitOfWork{txManager: txManager}
+}
+
+// WithinTx runs fn in a transaction, repositories called with the context passed to fn join it.
+// Their changes are committed if fn returns nil and rolled back otherwise. The error of fn is returned as is,
+// errors of the transaction itself are translated by the transaction manager.
+// Units nested in another unit join the outer one and are committed together with it.
+// Without a transaction manager fn is not run at all and ErrorNoTxManager is returned.
+func (u UnitOfWork) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if u.txManager == nil {
+		return ErrorNoTxManager
+	}
+	return u.txManager.WithinTx(ctx, fn)
+}
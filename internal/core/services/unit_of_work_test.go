@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/fmiskovic/new-amz/internal/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type txKey struct{}
+
+func TestWithinTx(t *testing.T) {
+	t.Run("should run unit in transaction of the transaction manager", func(t *testing.T) {
+		txManager := core.NewTxManagerMock(t)
+		txManager.On("WithinTx", mock.Anything, mock.Anything).
+			Return(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(context.WithValue(ctx, txKey{}, "tx"))
+			}).
+			Once()
+
+		var joined bool
+		err := NewUnitOfWork(txManager).WithinTx(context.Background(), func(ctx context.Context) error {
+			joined = ctx.Value(txKey{}) == "tx"
+			return nil
+		})
+
+		assert.Nil(t, err)
+		assert.True(t, joined)
+	})
+
+	t.Run("should return error of the unit as is", func(t *testing.T) {
+		txManager := core.NewTxManagerMock(t)
+		txManager.On("WithinTx", mock.Anything, mock.Anything).
+			Return(func(ctx context.Context, fn func(ctx context.Context) error) error { return fn(ctx) }).
+			Once()
+		failure := errors.New("out of stock")
+
+		err := NewUnitOfWork(txManager).WithinTx(context.Background(), func(context.Context) error { return failure })
+
+		assert.Same(t, failure, err)
+	})
+
+	t.Run("should not run unit without transaction manager", func(t *testing.T) {
+		var called bool
+		err := UnitOfWork{}.WithinTx(context.Background(), func(context.Context) error {
+			called = true
+			return nil
+		})
+
+		assert.ErrorIs(t, err, ErrorNoTxManager)
+		assert.False(t, called)
+	})
+}
+
+// newUnitOfWork returns a UnitOfWork whose transaction manager runs units as they are, for services under test.
+func newUnitOfWork(t *testing.T) UnitOfWork {
+	txManager := core.NewTxManagerMock(t)
+	txManager.On("WithinTx", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, fn func(ctx context.Context) error) error { return fn(ctx) }).
+		Maybe()
+	return NewUnitOfWork(txManager)
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/fmiskovic/new-amz/internal/core"
+	"github.com/fmiskovic/new-amz/internal/core/dtos"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/core/repositories"
+	"github.com/google/uuid"
+)
+
+// WebhookService represents business logic related to entities.WebhookSubscription and entities.WebhookDelivery.
+// It publishes domain events by delivering them to the subscribed webhooks, see Publish.
+type WebhookService struct {
+	repo       repositories.WebhookSubscriptionRepository[uuid.UUID]
+	deliveries repositories.WebhookDeliveryRepository[uuid.UUID]
+	sender     core.WebhookSender
+}
+
+// NewWebhookService instantiates new WebhookService.
+func NewWebhookService(
+	repo repositories.WebhookSubscriptionRepository[uuid.UUID],
+	deliveries repositories.WebhookDeliveryRepository[uuid.UUID],
+	sender core.WebhookSender,
+) WebhookService {
+	return WebhookService{repo: repo, deliveries: deliveries, sender: sender}
+}
+
+// GetById returns existing webhook subscription by id.
+func (s WebhookService) GetById(ctx context.Context, id uuid.UUID) (dtos.WebhookSubscriptionDto, error) {
+	if _, err := authorizeAdmin(ctx); err != nil {
+		return dtos.WebhookSubscriptionDto{}, err
+	}
+
+	subscription, err := s.repo.GetById(ctx, id)
+	if err != nil {
+		return dtos.WebhookSubscriptionDto{}, newError(fmt.Sprintf("failed to get webhook subscription by id: %s", id.String()), err)
+	}
+	return dtos.ToWebhookSubscriptionDto(subscription), nil
+}
+
+// GetPage returns page of webhook subscriptions.
+func (s WebhookService) GetPage(ctx context.Context, p entities.Pageable) (entities.Page[dtos.WebhookSubscriptionDto], error) {
+	if _, err := authorizeAdmin(ctx); err != nil {
+		return entities.Page[dtos.WebhookSubscriptionDto]{}, err
+	}
+
+	page, err := s.repo.GetPage(ctx, p)
+	if err != nil {
+		return entities.Page[dtos.WebhookSubscriptionDto]{}, newError("failed to get page of webhook subscriptions", err)
+	}
+	return dtos.ToPageWebhookSubscriptionDto(page), nil
+}
+
+// Create creates new webhook subscription. If no secret is given, a random one is generated.
+// The secret is returned by the creation only.
+func (s WebhookService) Create(ctx context.Context, dto dtos.WebhookSubscriptionDto) (dtos.WebhookSubscriptionDto, error) {
+	if _, err := authorizeAdmin(ctx); err != nil {
+		return dtos.WebhookSubscriptionDto{}, err
+	}
+
+	dto.ID = ""
+	subscription, err := dtos.ToWebhookSubscriptionEntity(dto)
+	if err != nil {
+		return dtos.WebhookSubscriptionDto{}, newError("invalid webhook subscription", err)
+	}
+	if subscription.Secret == "" {
+		if subscription.Secret, err = newWebhookSecret(); err != nil {
+			return dtos.WebhookSubscriptionDto{}, newError("failed to generate webhook secret", err)
+		}
+	}
+
+	if err := s.repo.Create(ctx, subscription); err != nil {
+		return dtos.WebhookSubscriptionDto{}, newError("failed to create webhook subscription", err)
+	}
+
+	created := dtos.ToWebhookSubscriptionDto(*subscription)
+	created.Secret = subscription.Secret
+	return created, nil
+}
+
+// Update replaces existing webhook subscription. The secret is kept if no new one is given.
+func (s WebhookService) Update(ctx context.Context, dto dtos.WebhookSubscriptionDto) (dtos.WebhookSubscriptionDto, error) {
+	if _, err := authorizeAdmin(ctx); err != nil {
+		return dtos.WebhookSubscriptionDto{}, err
+	}
+
+	subscription, err := dtos.ToWebhookSubscriptionEntity(dto)
+	if err != nil {
+		return dtos.WebhookSubscriptionDto{}, newError("invalid webhook subscription", err)
+	}
+	if subscription.Secret == "" {
+		existing, err := s.repo.GetById(ctx, subscription.ID)
+		if err != nil {
+			return dtos.WebhookSubscriptionDto{}, newError(fmt.Sprintf("failed to get webhook subscription by id: %s", subscription.ID.String()), err)
+		}
+		subscription.Secret = existing.Secret
+	}
+
+	if err := s.repo.Update(ctx, subscription); err != nil {
+		return dtos.WebhookSubscriptionDto{}, newError(fmt.Sprintf("failed to update webhook subscription: %s", subscription.ID.String()), err)
+	}
+	return dtos.ToWebhookSubscriptionDto(*subscription), nil
+}
+
+// DeleteById deletes existing webhook subscription together with its deliveries.
+func (s WebhookService) DeleteById(ctx context.Context, cmd dtos.DeleteCommand) (struct{}, error) {
+	if _, err := authorizeAdmin(ctx); err != nil {
+		return struct{}{}, err
+	}
+
+	if err := s.repo.DeleteById(ctx, cmd.ID, cmd.Version); err != nil {
+		return struct{}{}, newError(fmt.Sprintf("failed to delete webhook subscription: %s", cmd.ID.String()), err)
+	}
+	return struct{}{}, nil
+}
+
+// GetDeliveries returns page of deliveries to the webhook subscription.
+func (s WebhookService) GetDeliveries(ctx context.Context, q dtos.WebhookDeliveriesQuery) (entities.Page[dtos.WebhookDeliveryDto], error) {
+	if _, err := authorizeAdmin(ctx); err != nil {
+		return entities.Page[dtos.WebhookDeliveryDto]{}, err
+	}
+
+	page, err := s.deliveries.GetPage(ctx, q.SubscriptionID, entities.DeliveryStatus(q.Status), q.PageRequest)
+	if err != nil {
+		return entities.Page[dtos.WebhookDeliveryDto]{}, newError(fmt.Sprintf("failed to get webhook deliveries: %s", q.SubscriptionID.String()), err)
+	}
+	return dtos.ToPageWebhookDeliveryDto(page), nil
+}
+
+// Redeliver attempts the delivery to the webhook subscription once more right away, no matter if it was delivered
+// or is dead, and returns the outcome. Failed redeliveries are retried like any other delivery.
+func (s WebhookService) Redeliver(ctx context.Context, cmd dtos.RedeliverWebhookCommand) (dtos.WebhookDeliveryDto, error) {
+	if _, err := authorizeAdmin(ctx); err != nil {
+		return dtos.WebhookDeliveryDto{}, err
+	}
+
+	delivery, err := s.deliveries.Update(ctx, cmd.DeliveryID, func(ctx context.Context, subscription entities.WebhookSubscription, delivery *entities.WebhookDelivery) error {
+		if delivery.SubscriptionID != cmd.SubscriptionID {
+			return entities.ErrorDeliveryNotFound
+		}
+		delivery.Redeliver(time.Now())
+		s.send(ctx, subscription, delivery)
+		return nil
+	})
+	if err != nil {
+		return dtos.WebhookDeliveryDto{}, newError(fmt.Sprintf("failed to redeliver webhook delivery: %s", cmd.DeliveryID.String()), err)
+	}
+	return dtos.ToWebhookDeliveryDto(delivery), nil
+}
+
+// Publish schedules the delivery of the event to every webhook subscribed to events of its type.
+// Together with the other event publishers it is called by the event relay, see core.EventPublisher.
+func (s WebhookService) Publish(ctx context.Context, event entities.Event) error {
+	subscriptions, err := s.repo.GetSubscribed(ctx, event.Type)
+	if err != nil {
+		return newError("failed to get webhook subscriptions", err)
+	}
+
+	deliveries := make([]*entities.WebhookDelivery, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		delivery, err := entities.NewWebhookDelivery(subscription, event)
+		if err != nil {
+			return newError("invalid webhook delivery", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := s.deliveries.Create(ctx, deliveries); err != nil {
+		return newError("failed to create webhook deliveries", err)
+	}
+	return nil
+}
+
+// Deliver attempts at most limit deliveries which are due and returns the number of attempted deliveries.
+func (s WebhookService) Deliver(ctx context.Context, limit int) (int, error) {
+	attempted, err := s.deliveries.Deliver(ctx, limit, func(ctx context.Context, subscription entities.WebhookSubscription, delivery *entities.WebhookDelivery) error {
+		s.send(ctx, subscription, delivery)
+		return nil
+	})
+	if err != nil {
+		return attempted, newError("failed to deliver webhooks", err)
+	}
+	return attempted, nil
+}
+
+// send makes a single attempt of the delivery and records its outcome. Only 2xx responses count as delivered.
+func (s WebhookService) send(ctx context.Context, subscription entities.WebhookSubscription, delivery *entities.WebhookDelivery) {
+	statusCode, err := s.sender.Send(ctx, subscription, *delivery)
+	switch {
+	case err != nil:
+		delivery.Failed(0, err.Error(), time.Now())
+	case statusCode < 200 || statusCode > 299:
+		delivery.Failed(statusCode, fmt.Sprintf("webhook responded with status %d", statusCode), time.Now())
+	default:
+		delivery.Succeeded(statusCode, time.Now())
+	}
+}
+
+// newWebhookSecret returns a random secret for signing webhook deliveries.
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
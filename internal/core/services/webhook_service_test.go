@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/fmiskovic/new-amz/internal/core"
+	"github.com/fmiskovic/new-amz/internal/core/dtos"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/core/repositories"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type attemptDeliveryFunc = func(context.Context, entities.WebhookSubscription, *entities.WebhookDelivery) error
+
+func TestCreateWebhook(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	adminCtx := core.WithPrincipal(ctx, core.Principal{AccountID: uuid.New(), Role: entities.ADMIN})
+	dto := dtos.WebhookSubscriptionDto{URL: "https://partner.com/hooks", EventTypes: []string{"order.placed"}}
+
+	t.Run("should generate secret and return it once", func(t *testing.T) {
+		repoMock := repositories.NewWebhookSubscriptionRepositoryMock[uuid.UUID](t)
+		svc := NewWebhookService(repoMock, repositories.NewWebhookDeliveryRepositoryMock[uuid.UUID](t), core.NewWebhookSenderMock(t))
+		repoMock.On("Create", mock.Anything, mock.MatchedBy(func(s *entities.WebhookSubscription) bool {
+			return strings.HasPrefix(s.Secret, "whsec_") && s.Subscribes(entities.EVENT_ORDER_PLACED)
+		})).Return(nil).Once()
+
+		created, err := svc.Create(adminCtx, dto)
+
+		assert.Nil(t, err)
+		assert.True(t, strings.HasPrefix(created.Secret, "whsec_"))
+		assert.Equal(t, []string{"order.placed"}, created.EventTypes)
+	})
+
+	t.Run("should return forbidden error when caller is not admin", func(t *testing.T) {
+		svc := NewWebhookService(
+			repositories.NewWebhookSubscriptionRepositoryMock[uuid.UUID](t),
+			repositories.NewWebhookDeliveryRepositoryMock[uuid.UUID](t),
+			core.NewWebhookSenderMock(t),
+		)
+		customerCtx := core.WithPrincipal(ctx, core.Principal{AccountID: uuid.New(), Role: entities.CUSTOMER})
+
+		_, err := svc.Create(customerCtx, dto)
+
+		assert.ErrorIs(t, err, ErrorForbidden)
+	})
+}
+
+func TestPublishWebhooks(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	t.Run("should schedule delivery of the event to every subscribed webhook", func(t *testing.T) {
+		repoMock := repositories.NewWebhookSubscriptionRepositoryMock[uuid.UUID](t)
+		deliveriesMock := repositories.NewWebhookDeliveryRepositoryMock[uuid.UUID](t)
+		svc := NewWebhookService(repoMock, deliveriesMock, core.NewWebhookSenderMock(t))
+		event, err := entities.NewEvent(entities.EVENT_ORDER_PLACED, uuid.New(), map[string]string{})
+		assert.Nil(t, err)
+		subscriptions := []entities.WebhookSubscription{{Entity: entities.Entity{ID: uuid.New()}}, {Entity: entities.Entity{ID: uuid.New()}}}
+		repoMock.On("GetSubscribed", mock.Anything, entities.EVENT_ORDER_PLACED).Return(subscriptions, nil).Once()
+		deliveriesMock.On("Create", mock.Anything, mock.MatchedBy(func(d []*entities.WebhookDelivery) bool {
+			return len(d) == 2 &&
+				d[0].SubscriptionID == subscriptions[0].ID && d[1].SubscriptionID == subscriptions[1].ID &&
+				d[0].EventID == event.ID && d[0].Status == entities.DELIVERY_PENDING
+		})).Return(nil).Once()
+
+		err = svc.Publish(ctx, *event)
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestDeliverWebhooks(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	subscription := entities.WebhookSubscription{Entity: entities.Entity{ID: uuid.New()}, URL: "https://partner.com/hooks"}
+	newDelivery := func() *entities.WebhookDelivery {
+		event, err := entities.NewEvent(entities.EVENT_ORDER_PLACED, uuid.New(), map[string]string{})
+		assert.Nil(t, err)
+		delivery, err := entities.NewWebhookDelivery(subscription, *event)
+		assert.Nil(t, err)
+		return delivery
+	}
+	// deliver runs the deliver func of the service against the deliveries, like the repository does
+	deliver := func(deliveries ...*entities.WebhookDelivery) func(context.Context, int, attemptDeliveryFunc) (int, error) {
+		return func(ctx context.Context, _ int, attempt attemptDeliveryFunc) (int, error) {
+			for _, d := range deliveries {
+				if err := attempt(ctx, subscription, d); err != nil {
+					return 0, err
+				}
+			}
+			return len(deliveries), nil
+		}
+	}
+
+	t.Run("should record outcome of every attempt", func(t *testing.T) {
+		deliveriesMock := repositories.NewWebhookDeliveryRepositoryMock[uuid.UUID](t)
+		senderMock := core.NewWebhookSenderMock(t)
+		svc := NewWebhookService(repositories.NewWebhookSubscriptionRepositoryMock[uuid.UUID](t), deliveriesMock, senderMock)
+		delivered, rejected, unreachable := newDelivery(), newDelivery(), newDelivery()
+		deliveriesMock.On("Deliver", mock.Anything, 10, mock.Anything).Return(deliver(delivered, rejected, unreachable), nil).Once()
+		senderMock.On("Send", mock.Anything, subscription, *delivered).Return(http.StatusNoContent, nil).Once()
+		senderMock.On("Send", mock.Anything, subscription, *rejected).Return(http.StatusInternalServerError, nil).Once()
+		senderMock.On("Send", mock.Anything, subscription, *unreachable).Return(0, errors.New("connection refused")).Once()
+
+		attempted, err := svc.Deliver(ctx, 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 3, attempted)
+		assert.Equal(t, entities.DELIVERY_DELIVERED, delivered.Status)
+		assert.Equal(t, http.StatusNoContent, delivered.LastStatusCode)
+		assert.Equal(t, entities.DELIVERY_PENDING, rejected.Status)
+		assert.Equal(t, http.StatusInternalServerError, rejected.LastStatusCode)
+		assert.True(t, rejected.NextAttemptAt.After(time.Now()))
+		assert.Equal(t, "connection refused", unreachable.LastError)
+		assert.Equal(t, 1, unreachable.Attempts)
+	})
+}
+
+func TestRedeliverWebhook(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	adminCtx := core.WithPrincipal(ctx, core.Principal{AccountID: uuid.New(), Role: entities.ADMIN})
+	subscription := entities.WebhookSubscription{Entity: entities.Entity{ID: uuid.New()}}
+	event, err := entities.NewEvent(entities.EVENT_ORDER_PLACED, uuid.New(), map[string]string{})
+	assert.Nil(t, err)
+
+	// update runs the update func of the service against the delivery, like the repository does
+	update := func(delivery *entities.WebhookDelivery) func(context.Context, uuid.UUID, attemptDeliveryFunc) (entities.WebhookDelivery, error) {
+		return func(ctx context.Context, _ uuid.UUID, attempt attemptDeliveryFunc) (entities.WebhookDelivery, error) {
+			if err := attempt(ctx, subscription, delivery); err != nil {
+				return entities.WebhookDelivery{}, err
+			}
+			return *delivery, nil
+		}
+	}
+
+	t.Run("should attempt dead delivery once more", func(t *testing.T) {
+		deliveriesMock := repositories.NewWebhookDeliveryRepositoryMock[uuid.UUID](t)
+		senderMock := core.NewWebhookSenderMock(t)
+		svc := NewWebhookService(repositories.NewWebhookSubscriptionRepositoryMock[uuid.UUID](t), deliveriesMock, senderMock)
+		delivery, err := entities.NewWebhookDelivery(subscription, *event)
+		assert.Nil(t, err)
+		for i := 0; i < entities.WebhookMaxAttempts; i++ {
+			delivery.Failed(http.StatusBadGateway, "bad gateway", time.Now())
+		}
+		assert.Equal(t, entities.DELIVERY_DEAD, delivery.Status)
+		deliveriesMock.On("Update", mock.Anything, delivery.ID, mock.Anything).Return(update(delivery), nil).Once()
+		senderMock.On("Send", mock.Anything, subscription, mock.Anything).Return(http.StatusOK, nil).Once()
+
+		got, err := svc.Redeliver(adminCtx, dtos.RedeliverWebhookCommand{SubscriptionID: subscription.ID, DeliveryID: delivery.ID})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "delivered", got.Status)
+		assert.Equal(t, entities.WebhookMaxAttempts+1, got.Attempts)
+	})
+
+	t.Run("should return not found error when delivery belongs to another subscription", func(t *testing.T) {
+		deliveriesMock := repositories.NewWebhookDeliveryRepositoryMock[uuid.UUID](t)
+		svc := NewWebhookService(repositories.NewWebhookSubscriptionRepositoryMock[uuid.UUID](t), deliveriesMock, core.NewWebhookSenderMock(t))
+		delivery, err := entities.NewWebhookDelivery(subscription, *event)
+		assert.Nil(t, err)
+		deliveriesMock.On("Update", mock.Anything, delivery.ID, mock.Anything).Return(update(delivery), nil).Once()
+
+		_, err = svc.Redeliver(adminCtx, dtos.RedeliverWebhookCommand{SubscriptionID: uuid.New(), DeliveryID: delivery.ID})
+
+		assert.ErrorIs(t, err, entities.ErrorDeliveryNotFound)
+	})
+}
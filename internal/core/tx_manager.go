@@ -0,0 +1,13 @@
+package core
+
+import "context"
+
+// TxManager is a secondary port for running operations of several repositories as one unit of work.
+// The transaction is passed through the context, repositories called with it join the transaction.
+type TxManager interface {
+	// WithinTx runs fn in a transaction which is committed if fn returns nil and rolled back otherwise.
+	// Called with a context which carries a transaction already, fn joins that transaction, so its changes
+	// are rolled back if fn fails and committed only together with the outer transaction. The error of fn is
+	// returned as is.
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,14 @@
+package core
+
+import (
+	"context"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+)
+
+// WebhookSender is a secondary port for sending webhook deliveries to the URLs of their subscriptions.
+type WebhookSender interface {
+	// Send posts the payload of the delivery to the URL of the subscription, signed with the secret of the subscription,
+	// and returns the status code of the response. An error is returned if no response was received.
+	Send(ctx context.Context, subscription entities.WebhookSubscription, delivery entities.WebhookDelivery) (int, error)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 
+	"github.com/fmiskovic/new-amz/internal/core/entities"
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/dialect/pgdialect"
 	"github.com/uptrace/bun/driver/pgdriver"
@@ -58,6 +59,8 @@ func (svc Service) Connect() (*sql.DB, error) {
 // It can be useful for hooking bun.DB with bun.QueryHook.
 func (svc Service) WrapWithBun(db *sql.DB) *bun.DB {
 	bunDb := bun.NewDB(db, pgdialect.New())
+	// join models of many-to-many relations have to be known before the models relating through them
+	bunDb.RegisterModel((*entities.ItemAuthor)(nil))
 	// if utils.IsDev() {
 	// 	bunDb.AddQueryHook(bundebug.NewQueryHook(bundebug.WithVerbose(true)))
 	// }
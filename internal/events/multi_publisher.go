@@ -0,0 +1,29 @@
+package events
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fmiskovic/new-amz/internal/core"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+)
+
+// MultiPublisher publishes events to several publishers. If any of them fails, the event is published
+// to all of them again later, so the publishers must cope with duplicates like with any other redelivery.
+type MultiPublisher []core.EventPublisher
+
+// NewMultiPublisher instantiates new MultiPublisher.
+func NewMultiPublisher(publishers ...core.EventPublisher) MultiPublisher {
+	return publishers
+}
+
+// Publish publishes the event to every publisher and returns the errors of the failed ones.
+func (p MultiPublisher) Publish(ctx context.Context, event entities.Event) error {
+	var errs []error
+	for _, publisher := range p {
+		if err := publisher.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
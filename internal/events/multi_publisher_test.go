@@ -0,0 +1,29 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/fmiskovic/new-amz/internal/core"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMultiPublisher(t *testing.T) {
+	event, err := entities.NewEvent(entities.EVENT_ACCOUNT_CREATED, uuid.New(), map[string]string{})
+	assert.Nil(t, err)
+
+	t.Run("should publish to every publisher even if one fails", func(t *testing.T) {
+		failing := core.NewEventPublisherMock(t)
+		working := core.NewEventPublisherMock(t)
+		failing.On("Publish", mock.Anything, *event).Return(errors.New("disk full")).Once()
+		working.On("Publish", mock.Anything, *event).Return(nil).Once()
+
+		err := NewMultiPublisher(failing, working).Publish(context.Background(), *event)
+
+		assert.ErrorContains(t, err, "disk full")
+	})
+}
@@ -0,0 +1,107 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/fmiskovic/new-amz/internal/core"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/core/repositories"
+)
+
+// Relay publishes domain events stored in the outbox. Events are marked as published only after
+// the publisher delivered them, so every event is published at least once.
+type Relay struct {
+	repo      repositories.EventRepository
+	publisher core.EventPublisher
+	interval  time.Duration
+	batchSize int
+	retries   atomic.Int64
+}
+
+// NewRelay instantiates new Relay checking the outbox for unpublished events every interval
+// and publishing at most batchSize events at a time.
+func NewRelay(repo repositories.EventRepository, publisher core.EventPublisher, interval time.Duration, batchSize int) *Relay {
+	return &Relay{repo: repo, publisher: publisher, interval: interval, batchSize: batchSize}
+}
+
+// Run relays events until ctx is done.
+func (r *Relay) Run(ctx context.Context) {
+	slog.Info("Starting event relay", "interval", r.interval.String())
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.RelayAll(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("failed to relay events", "error", err.Error())
+		}
+		select {
+		case <-ctx.Done():
+			slog.Info("Stopped event relay.")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayAll publishes due events batch by batch until none are left. It returns the number of published events.
+// A failure to publish is not an error of the relay, the event is retried with backoff and parked after
+// entities.EventMaxAttempts attempts, it is logged and counted as a retry instead. Events failing to publish
+// do not hold up the later ones.
+func (r *Relay) RelayAll(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		events, err := r.repo.Claim(ctx, r.batchSize)
+		if err != nil {
+			return total, err
+		}
+		for i := range events {
+			if r.publish(ctx, &events[i]) {
+				total++
+			}
+			if err := r.repo.Update(ctx, &events[i]); err != nil {
+				return total, err
+			}
+		}
+		if len(events) < r.batchSize {
+			return total, nil
+		}
+	}
+}
+
+// Retries returns the number of events which failed to publish and were left for a retry since the relay started.
+func (r *Relay) Retries() int64 {
+	return r.retries.Load()
+}
+
+// publish publishes the claimed event and records the outcome on it. It reports whether the event was published.
+func (r *Relay) publish(ctx context.Context, event *entities.Event) bool {
+	err := r.publisher.Publish(ctx, *event)
+	if err == nil {
+		event.Published(time.Now())
+		return true
+	}
+
+	event.Failed(err.Error(), time.Now())
+	if event.Parked() {
+		slog.Error("failed to publish event, it is parked and no longer relayed",
+			"event_id", event.ID.String(),
+			"type", string(event.Type),
+			"attempts", event.Attempts,
+			"error", err.Error(),
+		)
+		return false
+	}
+	retries := r.retries.Add(1)
+	slog.Warn("failed to publish event, it is retried later",
+		"event_id", event.ID.String(),
+		"type", string(event.Type),
+		"attempts", event.Attempts,
+		"retries", retries,
+		"next_attempt_at", event.NextAttemptAt.Format(time.RFC3339),
+		"error", err.Error(),
+	)
+	return false
+}
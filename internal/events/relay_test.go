@@ -0,0 +1,177 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/fmiskovic/new-amz/internal/core"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/core/repositories"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRelay(t *testing.T) {
+	ctx := context.Background()
+
+	newEvents := func(n int) []entities.Event {
+		events := make([]entities.Event, n)
+		for i := range events {
+			event, err := entities.NewEvent(entities.EVENT_ORDER_PLACED, uuid.New(), map[string]int{"n": i})
+			assert.Nil(t, err)
+			events[i] = *event
+		}
+		return events
+	}
+
+	t.Run("should publish events batch by batch until outbox is drained", func(t *testing.T) {
+		repoMock := repositories.NewEventRepositoryMock(t)
+		publisherMock := core.NewEventPublisherMock(t)
+		events := newEvents(5)
+		repoMock.On("Claim", mock.Anything, 2).Return(events[:2], nil).Once()
+		repoMock.On("Claim", mock.Anything, 2).Return(events[2:4], nil).Once()
+		repoMock.On("Claim", mock.Anything, 2).Return(events[4:], nil).Once()
+		for _, event := range events {
+			publisherMock.On("Publish", mock.Anything, event).Return(nil).Once()
+		}
+		repoMock.On("Update", mock.Anything, mock.MatchedBy(func(e *entities.Event) bool {
+			return !e.PublishedAt.IsZero() && e.LastError == ""
+		})).Return(nil).Times(5)
+
+		published, err := NewRelay(repoMock, publisherMock, time.Second, 2).RelayAll(ctx)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 5, published)
+	})
+
+	t.Run("should keep publishing later events when publishing one fails", func(t *testing.T) {
+		repoMock := repositories.NewEventRepositoryMock(t)
+		publisherMock := core.NewEventPublisherMock(t)
+		events := newEvents(3)
+		repoMock.On("Claim", mock.Anything, 10).Return(events, nil).Once()
+		publisherMock.On("Publish", mock.Anything, events[0]).Return(nil).Once()
+		publisherMock.On("Publish", mock.Anything, events[1]).Return(errors.New("connection refused")).Once()
+		publisherMock.On("Publish", mock.Anything, events[2]).Return(nil).Once()
+		var failed *entities.Event
+		repoMock.On("Update", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			if e := args.Get(1).(*entities.Event); e.ID == events[1].ID {
+				failed = e
+			}
+		}).Return(nil).Times(3)
+
+		published, err := NewRelay(repoMock, publisherMock, time.Second, 10).RelayAll(ctx)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, published)
+		assert.NotNil(t, failed)
+		assert.True(t, failed.PublishedAt.IsZero())
+		assert.Equal(t, "connection refused", failed.LastError)
+		assert.False(t, failed.NextAttemptAt.IsZero())
+		assert.False(t, failed.Parked())
+	})
+
+	t.Run("should log and count event failed to publish", func(t *testing.T) {
+		var logs bytes.Buffer
+		defaultLogger := slog.Default()
+		slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+		defer slog.SetDefault(defaultLogger)
+
+		repoMock := repositories.NewEventRepositoryMock(t)
+		publisherMock := core.NewEventPublisherMock(t)
+		events := newEvents(1)
+		events[0].Attempts = 3
+		// every claim hands over a fresh copy of the event like the repository does
+		repoMock.On("Claim", mock.Anything, 10).Return(slices.Clone(events), nil).Once()
+		repoMock.On("Claim", mock.Anything, 10).Return(slices.Clone(events), nil).Once()
+		repoMock.On("Update", mock.Anything, mock.Anything).Return(nil).Twice()
+		publisherMock.On("Publish", mock.Anything, events[0]).Return(errors.New("connection refused")).Twice()
+		relay := NewRelay(repoMock, publisherMock, time.Second, 10)
+
+		for i := 0; i < 2; i++ {
+			published, err := relay.RelayAll(ctx)
+			assert.Nil(t, err)
+			assert.Equal(t, 0, published)
+		}
+
+		assert.Equal(t, int64(2), relay.Retries())
+		assert.Contains(t, logs.String(), "event_id="+events[0].ID.String())
+		assert.Contains(t, logs.String(), "attempts=3")
+		assert.Contains(t, logs.String(), "retries=2")
+		assert.Contains(t, logs.String(), "connection refused")
+	})
+
+	t.Run("should park event failed to publish too many times", func(t *testing.T) {
+		var logs bytes.Buffer
+		defaultLogger := slog.Default()
+		slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+		defer slog.SetDefault(defaultLogger)
+
+		repoMock := repositories.NewEventRepositoryMock(t)
+		publisherMock := core.NewEventPublisherMock(t)
+		events := newEvents(1)
+		events[0].Attempts = entities.EventMaxAttempts
+		repoMock.On("Claim", mock.Anything, 10).Return(events, nil).Once()
+		publisherMock.On("Publish", mock.Anything, events[0]).Return(errors.New("connection refused")).Once()
+		repoMock.On("Update", mock.Anything, mock.MatchedBy(func(e *entities.Event) bool {
+			return e.Parked() && e.NextAttemptAt.IsZero()
+		})).Return(nil).Once()
+		relay := NewRelay(repoMock, publisherMock, time.Second, 10)
+
+		published, err := relay.RelayAll(ctx)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 0, published)
+		assert.Equal(t, int64(0), relay.Retries())
+		assert.Contains(t, logs.String(), "parked")
+	})
+
+	t.Run("should return error of the outbox", func(t *testing.T) {
+		repoMock := repositories.NewEventRepositoryMock(t)
+		publisherMock := core.NewEventPublisherMock(t)
+		repoMock.On("Claim", mock.Anything, 10).Return(nil, errors.New("connection lost")).Once()
+
+		_, err := NewRelay(repoMock, publisherMock, time.Second, 10).RelayAll(ctx)
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("should return error of recording the outcome", func(t *testing.T) {
+		repoMock := repositories.NewEventRepositoryMock(t)
+		publisherMock := core.NewEventPublisherMock(t)
+		events := newEvents(2)
+		repoMock.On("Claim", mock.Anything, 10).Return(events, nil).Once()
+		publisherMock.On("Publish", mock.Anything, events[0]).Return(nil).Once()
+		repoMock.On("Update", mock.Anything, mock.Anything).Return(errors.New("connection lost")).Once()
+
+		published, err := NewRelay(repoMock, publisherMock, time.Second, 10).RelayAll(ctx)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, 1, published)
+	})
+
+	t.Run("should stop running when context is done", func(t *testing.T) {
+		repoMock := repositories.NewEventRepositoryMock(t)
+		publisherMock := core.NewEventPublisherMock(t)
+		repoMock.On("Claim", mock.Anything, 10).Return(nil, nil)
+		ctx, cancel := context.WithCancel(ctx)
+
+		done := make(chan struct{})
+		go func() {
+			NewRelay(repoMock, publisherMock, time.Millisecond, 10).Run(ctx)
+			close(done)
+		}()
+		cancel()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("relay did not stop")
+		}
+	})
+}
@@ -0,0 +1,44 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+)
+
+// StreamPublisher publishes events as JSON lines written to a stream, like stdout or a file.
+type StreamPublisher struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewStreamPublisher instantiates new StreamPublisher writing to w.
+func NewStreamPublisher(w io.Writer) *StreamPublisher {
+	return &StreamPublisher{w: w}
+}
+
+// NewFilePublisher instantiates new StreamPublisher appending to the file, which is created if it does not exist.
+func NewFilePublisher(path string) (*StreamPublisher, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return NewStreamPublisher(f), nil
+}
+
+// Publish writes the event as a single line of JSON.
+func (p *StreamPublisher) Publish(_ context.Context, event entities.Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err = p.w.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,61 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamPublisher(t *testing.T) {
+	ctx := context.Background()
+	account := entities.NewAccountBuilder().Email("john@smith.com").FullName("John Smith").Build()
+	event, err := entities.NewAccountCreated(*account)
+	assert.Nil(t, err)
+
+	t.Run("should write events as JSON lines", func(t *testing.T) {
+		var buf bytes.Buffer
+		p := NewStreamPublisher(&buf)
+
+		assert.Nil(t, p.Publish(ctx, *event))
+		assert.Nil(t, p.Publish(ctx, *event))
+
+		scanner := bufio.NewScanner(&buf)
+		lines := 0
+		for scanner.Scan() {
+			var got struct {
+				ID          uuid.UUID               `json:"id"`
+				Type        entities.EventType      `json:"type"`
+				AggregateID uuid.UUID               `json:"aggregate_id"`
+				Payload     entities.AccountCreated `json:"payload"`
+			}
+			assert.Nil(t, json.Unmarshal(scanner.Bytes(), &got))
+			assert.Equal(t, event.ID, got.ID)
+			assert.Equal(t, entities.EVENT_ACCOUNT_CREATED, got.Type)
+			assert.Equal(t, account.ID, got.AggregateID)
+			assert.Equal(t, "john@smith.com", got.Payload.Email)
+			lines++
+		}
+		assert.Equal(t, 2, lines)
+	})
+
+	t.Run("should append events to file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "events.jsonl")
+		for i := 0; i < 2; i++ {
+			p, err := NewFilePublisher(path)
+			assert.Nil(t, err)
+			assert.Nil(t, p.Publish(ctx, *event))
+		}
+
+		data, err := os.ReadFile(path)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, bytes.Count(data, []byte("\n")))
+	})
+}
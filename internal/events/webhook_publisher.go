@@ -0,0 +1,58 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+)
+
+const (
+	// HeaderEventID carries the ID of the published event, consumers use it to skip events delivered already.
+	HeaderEventID = "X-Event-ID"
+	// HeaderEventType carries the type of the published event.
+	HeaderEventType = "X-Event-Type"
+)
+
+// WebhookPublisher publishes events by posting them as JSON to a URL.
+// Any response other than 2xx is a failed delivery.
+type WebhookPublisher struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookPublisher instantiates new WebhookPublisher posting events to the url.
+func NewWebhookPublisher(url string, timeout time.Duration) *WebhookPublisher {
+	return &WebhookPublisher{url: url, client: &http.Client{Timeout: timeout}}
+}
+
+// Publish posts the event to the URL.
+func (p *WebhookPublisher) Publish(ctx context.Context, event entities.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEventID, event.ID.String())
+	req.Header.Set(HeaderEventType, string(event.Type))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookPublisher(t *testing.T) {
+	ctx := context.Background()
+	event, err := entities.NewEvent(entities.EVENT_ORDER_PLACED, uuid.New(), map[string]string{"status": "pending"})
+	assert.Nil(t, err)
+
+	t.Run("should post event to the url", func(t *testing.T) {
+		var got entities.Event
+		var header http.Header
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header = r.Header
+			assert.Nil(t, json.NewDecoder(r.Body).Decode(&got))
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer srv.Close()
+
+		err := NewWebhookPublisher(srv.URL, time.Second).Publish(ctx, *event)
+
+		assert.Nil(t, err)
+		assert.Equal(t, event.ID, got.ID)
+		assert.Equal(t, event.AggregateID, got.AggregateID)
+		assert.JSONEq(t, `{"status":"pending"}`, string(got.Payload))
+		assert.Equal(t, event.ID.String(), header.Get(HeaderEventID))
+		assert.Equal(t, "order.placed", header.Get(HeaderEventType))
+	})
+
+	t.Run("should fail when webhook does not respond with 2xx", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer srv.Close()
+
+		err := NewWebhookPublisher(srv.URL, time.Second).Publish(ctx, *event)
+
+		assert.ErrorContains(t, err, "503")
+	})
+}
@@ -1,7 +1,13 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/core/services"
 )
 
 type HandlerError struct {
@@ -21,3 +27,44 @@ func NewErr(msg string, err error, code int) HandlerError {
 func (h HandlerError) Error() string {
 	return fmt.Sprintf("error code: %d, message: %s, error: %v", h.code, h.message, h.err)
 }
+
+// Unwrap returns the cause of the error.
+func (h HandlerError) Unwrap() error {
+	return h.err
+}
+
+// problem describes the error to clients. The cause is described only if it is a domain error.
+func (h HandlerError) problem() Problem {
+	detail := h.message
+	if cause := errorDetail(h.err); cause != "" {
+		detail += ": " + cause
+	}
+	return newProblem(h.code, detail)
+}
+
+// retryAfterSeconds is suggested to clients as delay before retrying a request failed by a transient error.
+const retryAfterSeconds = "5"
+
+// serviceErrorCode resolves the HTTP status code of an error returned by a service function
+// from the kind of the error, see entities.DomainError.
+func serviceErrorCode(err error) int {
+	switch {
+	case errors.Is(err, services.ErrorInvalidCredentials):
+		return http.StatusUnauthorized
+	case errors.Is(err, entities.ErrorValidation):
+		return http.StatusBadRequest
+	case errors.Is(err, entities.ErrorForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, entities.ErrorNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, entities.ErrorConflict):
+		return http.StatusConflict
+	case errors.Is(err, entities.ErrorPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, entities.ErrorUnavailable),
+		errors.Is(err, context.DeadlineExceeded):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
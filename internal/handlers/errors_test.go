@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/core/services"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServiceErrorCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "invalid credentials should be unauthorized", err: services.ErrorInvalidCredentials, want: http.StatusUnauthorized},
+		{name: "validation error should be bad request", err: services.ErrorEmailRequired, want: http.StatusBadRequest},
+		{name: "forbidden error should be forbidden", err: services.ErrorForbidden, want: http.StatusForbidden},
+		{name: "wrapped not found error should be not found", err: fmt.Errorf("failed to get account: %w", entities.ErrorEntityNotFound), want: http.StatusNotFound},
+		{name: "conflict error should be conflict", err: entities.ErrorEmailNotUnique, want: http.StatusConflict},
+		{name: "typed conflict error should be conflict", err: entities.InsufficientStockError{ItemID: uuid.New()}, want: http.StatusConflict},
+		{name: "version mismatch should be precondition failed", err: fmt.Errorf("failed to update author: %w", entities.ErrorVersionMismatch), want: http.StatusPreconditionFailed},
+		{name: "unavailable error should be service unavailable", err: entities.NewError(entities.ErrorUnavailable, "database is unavailable"), want: http.StatusServiceUnavailable},
+		{name: "deadline exceeded should be service unavailable", err: context.DeadlineExceeded, want: http.StatusServiceUnavailable},
+		{name: "unknown error should be internal server error", err: errors.New("boom"), want: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, serviceErrorCode(tt.err))
+		})
+	}
+}
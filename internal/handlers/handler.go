@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/fmiskovic/new-amz/internal/core"
+	"github.com/fmiskovic/new-amz/internal/validators"
 	"github.com/labstack/echo/v4"
 )
 
@@ -48,23 +49,30 @@ func (h Handler[In, Out]) Handle(c echo.Context) error {
 		c.Logger().Error(err)
 		var herr HandlerError
 		if errors.As(err, &herr) {
-			return echo.NewHTTPError(herr.code, herr.Error())
+			return problemError(herr.problem(), err)
 		}
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		return problemError(newProblem(http.StatusBadRequest, errorDetail(err)), err)
 	}
 
 	// Validate request
 	err = c.Validate(in)
 	if err != nil {
 		c.Logger().Error(err)
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		p := newProblem(http.StatusBadRequest, "request validation failed")
+		p.InvalidParams = validators.InvalidParams(err)
+		return problemError(p, err)
 	}
 
 	// Call out to service function
 	out, err := h.serviceFunc(c.Request().Context(), in)
 	if err != nil {
 		c.Logger().Error(err)
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		code := serviceErrorCode(err)
+		if code == http.StatusServiceUnavailable {
+			// transient failure, the client may retry the request
+			c.Response().Header().Set(echo.HeaderRetryAfter, retryAfterSeconds)
+		}
+		return problemError(newProblem(code, errorDetail(err)), err)
 	}
 
 	// Map and return response
@@ -1,12 +1,21 @@
 package mappers
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/fmiskovic/new-amz/internal/core/dtos"
 	"github.com/fmiskovic/new-amz/internal/handlers"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"io"
+	"strconv"
+	"strings"
 )
 
+// mimeMergePatchJSON is the media type of JSON Merge Patch documents, see RFC 7386.
+const mimeMergePatchJSON = "application/merge-patch+json"
+
 type CreateAccountRequestMapper struct{}
 
 func NewCreateAccountRequestMapper() CreateAccountRequestMapper {
@@ -28,7 +37,7 @@ func NewCreateAccountResponseMapper() CreateAccountResponseMapper {
 }
 
 func (m CreateAccountResponseMapper) Map(c echo.Context, out dtos.CreateAccountAnswer) error {
-	return c.JSON(201, out)
+	return writeEntity(c, 201, out, out.Version)
 }
 
 type GetAccountByIdRequestMapper struct{}
@@ -52,5 +61,113 @@ func NewGetAccountByIdResponseMapper() GetAccountByIdResponseMapper {
 }
 
 func (m GetAccountByIdResponseMapper) Map(c echo.Context, out dtos.AccountDto) error {
-	return c.JSON(200, out)
+	return writeEntity(c, 200, out, out.Version)
+}
+
+type UpdateAccountRequestMapper struct{}
+
+func NewUpdateAccountRequestMapper() UpdateAccountRequestMapper {
+	return UpdateAccountRequestMapper{}
+}
+
+func (m UpdateAccountRequestMapper) Map(c echo.Context) (dtos.UpdateAccountCommand, error) {
+	var cmd dtos.UpdateAccountCommand
+	if err := c.Bind(&cmd); err != nil {
+		return cmd, handlers.NewErr("failed to bind update account request", err, 400)
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return cmd, handlers.NewErr("failed to parse account id", err, 400)
+	}
+	cmd.ID = id
+
+	version, err := ifMatch(c)
+	if err != nil {
+		return cmd, err
+	}
+	cmd.Version = version
+
+	return cmd, nil
+}
+
+type PatchAccountRequestMapper struct{}
+
+func NewPatchAccountRequestMapper() PatchAccountRequestMapper {
+	return PatchAccountRequestMapper{}
+}
+
+// Map reads JSON Merge Patch document of the account.
+// Both application/merge-patch+json and application/json content types are accepted.
+func (m PatchAccountRequestMapper) Map(c echo.Context) (dtos.PatchAccountCommand, error) {
+	var cmd dtos.PatchAccountCommand
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return cmd, handlers.NewErr("failed to parse account id", err, 400)
+	}
+	cmd.ID = id
+
+	version, err := ifMatch(c)
+	if err != nil {
+		return cmd, err
+	}
+	cmd.Version = version
+
+	contentType := c.Request().Header.Get(echo.HeaderContentType)
+	if !strings.HasPrefix(contentType, mimeMergePatchJSON) && !strings.HasPrefix(contentType, echo.MIMEApplicationJSON) {
+		return cmd, handlers.NewErr("unsupported patch content type", fmt.Errorf("content type %q", contentType), 415)
+	}
+
+	patch, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return cmd, handlers.NewErr("failed to read patch account request", err, 400)
+	}
+	if !json.Valid(patch) {
+		return cmd, handlers.NewErr("failed to parse patch account request", errors.New("invalid json"), 400)
+	}
+	cmd.Patch = patch
+
+	return cmd, nil
+}
+
+type DeleteAccountRequestMapper struct{}
+
+func NewDeleteAccountRequestMapper() DeleteAccountRequestMapper {
+	return DeleteAccountRequestMapper{}
+}
+
+func (m DeleteAccountRequestMapper) Map(c echo.Context) (dtos.DeleteAccountCommand, error) {
+	var cmd dtos.DeleteAccountCommand
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return cmd, handlers.NewErr("failed to parse account id", err, 400)
+	}
+	cmd.ID = id
+
+	version, err := ifMatch(c)
+	if err != nil {
+		return cmd, err
+	}
+	cmd.Version = version
+
+	if force := c.QueryParam("force"); force != "" {
+		cmd.Force, err = strconv.ParseBool(force)
+		if err != nil {
+			return cmd, handlers.NewErr("failed to parse force parameter", err, 400)
+		}
+	}
+
+	return cmd, nil
+}
+
+type DeleteAccountResponseMapper struct{}
+
+func NewDeleteAccountResponseMapper() DeleteAccountResponseMapper {
+	return DeleteAccountResponseMapper{}
+}
+
+func (m DeleteAccountResponseMapper) Map(c echo.Context, _ struct{}) error {
+	return c.NoContent(204)
 }
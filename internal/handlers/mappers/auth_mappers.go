@@ -0,0 +1,31 @@
+package mappers
+
+import (
+	"github.com/fmiskovic/new-amz/internal/core/dtos"
+	"github.com/fmiskovic/new-amz/internal/handlers"
+	"github.com/labstack/echo/v4"
+)
+
+type AuthLoginRequestMapper struct{}
+
+func NewAuthLoginRequestMapper() AuthLoginRequestMapper {
+	return AuthLoginRequestMapper{}
+}
+
+func (m AuthLoginRequestMapper) Map(c echo.Context) (dtos.LoginCommand, error) {
+	var cmd dtos.LoginCommand
+	if err := c.Bind(&cmd); err != nil {
+		return cmd, handlers.NewErr("failed to bind login request", err, 400)
+	}
+	return cmd, nil
+}
+
+type AuthLoginResponseMapper struct{}
+
+func NewAuthLoginResponseMapper() AuthLoginResponseMapper {
+	return AuthLoginResponseMapper{}
+}
+
+func (m AuthLoginResponseMapper) Map(c echo.Context, out dtos.TokenDto) error {
+	return c.JSON(200, out)
+}
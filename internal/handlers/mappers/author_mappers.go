@@ -0,0 +1,137 @@
+package mappers
+
+import (
+	"github.com/fmiskovic/new-amz/internal/core/dtos"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/handlers"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type AuthorGetByIdRequestMapper struct{}
+
+func NewAuthorGetByIdRequestMapper() AuthorGetByIdRequestMapper {
+	return AuthorGetByIdRequestMapper{}
+}
+
+func (m AuthorGetByIdRequestMapper) Map(c echo.Context) (uuid.UUID, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return id, handlers.NewErr("failed to parse author id", err, 400)
+	}
+	return id, nil
+}
+
+type AuthorGetByIdResponseMapper struct{}
+
+func NewAuthorGetByIdResponseMapper() AuthorGetByIdResponseMapper {
+	return AuthorGetByIdResponseMapper{}
+}
+
+func (m AuthorGetByIdResponseMapper) Map(c echo.Context, out dtos.AuthorDto) error {
+	return writeEntity(c, 200, out, out.Version)
+}
+
+type AuthorGetPageRequestMapper struct{}
+
+func NewAuthorGetPageRequestMapper() AuthorGetPageRequestMapper {
+	return AuthorGetPageRequestMapper{}
+}
+
+func (m AuthorGetPageRequestMapper) Map(c echo.Context) (entities.Pageable, error) {
+	pageRequest, err := pageRequestMapper(c, entities.AuthorSortable)
+	if err != nil {
+		return pageRequest, handlers.NewErr("invalid page request", err, 400)
+	}
+	return pageRequest, nil
+}
+
+type AuthorGetPageResponseMapper struct{}
+
+func NewAuthorGetPageResponseMapper() AuthorGetPageResponseMapper {
+	return AuthorGetPageResponseMapper{}
+}
+
+func (m AuthorGetPageResponseMapper) Map(c echo.Context, out entities.Page[dtos.AuthorDto]) error {
+	return writePage(c, out)
+}
+
+type AuthorCreateRequestMapper struct{}
+
+func NewAuthorCreateRequestMapper() AuthorCreateRequestMapper {
+	return AuthorCreateRequestMapper{}
+}
+
+func (m AuthorCreateRequestMapper) Map(c echo.Context) (dtos.AuthorDto, error) {
+	var dto dtos.AuthorDto
+	if err := c.Bind(&dto); err != nil {
+		return dto, handlers.NewErr("failed to bind create author request", err, 400)
+	}
+	return dto, nil
+}
+
+type AuthorCreateResponseMapper struct{}
+
+func NewAuthorCreateResponseMapper() AuthorCreateResponseMapper {
+	return AuthorCreateResponseMapper{}
+}
+
+func (m AuthorCreateResponseMapper) Map(c echo.Context, out dtos.AuthorDto) error {
+	return writeEntity(c, 201, out, out.Version)
+}
+
+type AuthorUpdateRequestMapper struct{}
+
+func NewAuthorUpdateRequestMapper() AuthorUpdateRequestMapper {
+	return AuthorUpdateRequestMapper{}
+}
+
+func (m AuthorUpdateRequestMapper) Map(c echo.Context) (dtos.AuthorDto, error) {
+	var dto dtos.AuthorDto
+	if err := c.Bind(&dto); err != nil {
+		return dto, handlers.NewErr("failed to bind update author request", err, 400)
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return dto, handlers.NewErr("failed to parse author id", err, 400)
+	}
+	dto.ID = id.String()
+
+	version, err := ifMatch(c)
+	if err != nil {
+		return dto, err
+	}
+	dto.Version = version
+
+	return dto, nil
+}
+
+type AuthorDeleteRequestMapper struct{}
+
+func NewAuthorDeleteRequestMapper() AuthorDeleteRequestMapper {
+	return AuthorDeleteRequestMapper{}
+}
+
+func (m AuthorDeleteRequestMapper) Map(c echo.Context) (dtos.DeleteCommand, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return dtos.DeleteCommand{}, handlers.NewErr("failed to parse author id", err, 400)
+	}
+
+	version, err := ifMatch(c)
+	if err != nil {
+		return dtos.DeleteCommand{}, err
+	}
+	return dtos.DeleteCommand{ID: id, Version: version}, nil
+}
+
+type AuthorDeleteResponseMapper struct{}
+
+func NewAuthorDeleteResponseMapper() AuthorDeleteResponseMapper {
+	return AuthorDeleteResponseMapper{}
+}
+
+func (m AuthorDeleteResponseMapper) Map(c echo.Context, _ struct{}) error {
+	return c.NoContent(204)
+}
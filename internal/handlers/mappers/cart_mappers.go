@@ -0,0 +1,158 @@
+package mappers
+
+import (
+	"github.com/fmiskovic/new-amz/internal/core/dtos"
+	"github.com/fmiskovic/new-amz/internal/handlers"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// cartId parses the id of the guest cart addressed by the request.
+// Requests without the id address the cart of the calling account, they get the zero id.
+func cartId(c echo.Context) (uuid.UUID, error) {
+	if c.Param("id") == "" {
+		return uuid.Nil, nil
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return id, handlers.NewErr("failed to parse cart id", err, 400)
+	}
+	return id, nil
+}
+
+type CartGetRequestMapper struct{}
+
+func NewCartGetRequestMapper() CartGetRequestMapper {
+	return CartGetRequestMapper{}
+}
+
+func (m CartGetRequestMapper) Map(c echo.Context) (dtos.CartQuery, error) {
+	id, err := cartId(c)
+	return dtos.CartQuery{CartID: id}, err
+}
+
+type CartGetResponseMapper struct{}
+
+func NewCartGetResponseMapper() CartGetResponseMapper {
+	return CartGetResponseMapper{}
+}
+
+func (m CartGetResponseMapper) Map(c echo.Context, out dtos.CartDto) error {
+	return c.JSON(200, out)
+}
+
+type CartCreateGuestRequestMapper struct{}
+
+func NewCartCreateGuestRequestMapper() CartCreateGuestRequestMapper {
+	return CartCreateGuestRequestMapper{}
+}
+
+func (m CartCreateGuestRequestMapper) Map(_ echo.Context) (struct{}, error) {
+	return struct{}{}, nil
+}
+
+type CartCreateGuestResponseMapper struct{}
+
+func NewCartCreateGuestResponseMapper() CartCreateGuestResponseMapper {
+	return CartCreateGuestResponseMapper{}
+}
+
+func (m CartCreateGuestResponseMapper) Map(c echo.Context, out dtos.CartDto) error {
+	return c.JSON(201, out)
+}
+
+type CartAddItemRequestMapper struct{}
+
+func NewCartAddItemRequestMapper() CartAddItemRequestMapper {
+	return CartAddItemRequestMapper{}
+}
+
+func (m CartAddItemRequestMapper) Map(c echo.Context) (dtos.CartItemCommand, error) {
+	var cmd dtos.CartItemCommand
+	if err := c.Bind(&cmd); err != nil {
+		return cmd, handlers.NewErr("failed to bind cart item request", err, 400)
+	}
+
+	id, err := cartId(c)
+	if err != nil {
+		return cmd, err
+	}
+	cmd.CartID = id
+
+	return cmd, nil
+}
+
+type CartSetItemRequestMapper struct{}
+
+func NewCartSetItemRequestMapper() CartSetItemRequestMapper {
+	return CartSetItemRequestMapper{}
+}
+
+func (m CartSetItemRequestMapper) Map(c echo.Context) (dtos.CartItemCommand, error) {
+	var cmd dtos.CartItemCommand
+	if err := c.Bind(&cmd); err != nil {
+		return cmd, handlers.NewErr("failed to bind cart item request", err, 400)
+	}
+
+	id, err := cartId(c)
+	if err != nil {
+		return cmd, err
+	}
+	cmd.CartID = id
+
+	itemId, err := uuid.Parse(c.Param("itemId"))
+	if err != nil {
+		return cmd, handlers.NewErr("failed to parse item id", err, 400)
+	}
+	cmd.ItemID = itemId.String()
+
+	return cmd, nil
+}
+
+type CartRemoveItemRequestMapper struct{}
+
+func NewCartRemoveItemRequestMapper() CartRemoveItemRequestMapper {
+	return CartRemoveItemRequestMapper{}
+}
+
+func (m CartRemoveItemRequestMapper) Map(c echo.Context) (dtos.RemoveCartItemCommand, error) {
+	var cmd dtos.RemoveCartItemCommand
+
+	id, err := cartId(c)
+	if err != nil {
+		return cmd, err
+	}
+	cmd.CartID = id
+
+	itemId, err := uuid.Parse(c.Param("itemId"))
+	if err != nil {
+		return cmd, handlers.NewErr("failed to parse item id", err, 400)
+	}
+	cmd.ItemID = itemId
+
+	return cmd, nil
+}
+
+type CartClearResponseMapper struct{}
+
+func NewCartClearResponseMapper() CartClearResponseMapper {
+	return CartClearResponseMapper{}
+}
+
+func (m CartClearResponseMapper) Map(c echo.Context, _ struct{}) error {
+	return c.NoContent(204)
+}
+
+type CartMergeRequestMapper struct{}
+
+func NewCartMergeRequestMapper() CartMergeRequestMapper {
+	return CartMergeRequestMapper{}
+}
+
+func (m CartMergeRequestMapper) Map(c echo.Context) (dtos.MergeCartCommand, error) {
+	var cmd dtos.MergeCartCommand
+	if err := c.Bind(&cmd); err != nil {
+		return cmd, handlers.NewErr("failed to bind merge cart request", err, 400)
+	}
+	return cmd, nil
+}
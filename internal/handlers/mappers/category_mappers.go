@@ -0,0 +1,153 @@
+package mappers
+
+import (
+	"github.com/fmiskovic/new-amz/internal/core/dtos"
+	"github.com/fmiskovic/new-amz/internal/handlers"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type CategoryGetTopLevelRequestMapper struct{}
+
+func NewCategoryGetTopLevelRequestMapper() CategoryGetTopLevelRequestMapper {
+	return CategoryGetTopLevelRequestMapper{}
+}
+
+func (m CategoryGetTopLevelRequestMapper) Map(_ echo.Context) (struct{}, error) {
+	return struct{}{}, nil
+}
+
+type CategoryListResponseMapper struct{}
+
+func NewCategoryListResponseMapper() CategoryListResponseMapper {
+	return CategoryListResponseMapper{}
+}
+
+func (m CategoryListResponseMapper) Map(c echo.Context, out []dtos.CategoryDto) error {
+	return c.JSON(200, out)
+}
+
+type CategoryGetByIdRequestMapper struct{}
+
+func NewCategoryGetByIdRequestMapper() CategoryGetByIdRequestMapper {
+	return CategoryGetByIdRequestMapper{}
+}
+
+func (m CategoryGetByIdRequestMapper) Map(c echo.Context) (uuid.UUID, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return id, handlers.NewErr("failed to parse category id", err, 400)
+	}
+	return id, nil
+}
+
+type CategoryGetByIdResponseMapper struct{}
+
+func NewCategoryGetByIdResponseMapper() CategoryGetByIdResponseMapper {
+	return CategoryGetByIdResponseMapper{}
+}
+
+func (m CategoryGetByIdResponseMapper) Map(c echo.Context, out dtos.CategoryDto) error {
+	return writeEntity(c, 200, out, out.Version)
+}
+
+type CategoryCreateRequestMapper struct{}
+
+func NewCategoryCreateRequestMapper() CategoryCreateRequestMapper {
+	return CategoryCreateRequestMapper{}
+}
+
+func (m CategoryCreateRequestMapper) Map(c echo.Context) (dtos.CategoryDto, error) {
+	var dto dtos.CategoryDto
+	if err := c.Bind(&dto); err != nil {
+		return dto, handlers.NewErr("failed to bind create category request", err, 400)
+	}
+	return dto, nil
+}
+
+type CategoryCreateResponseMapper struct{}
+
+func NewCategoryCreateResponseMapper() CategoryCreateResponseMapper {
+	return CategoryCreateResponseMapper{}
+}
+
+func (m CategoryCreateResponseMapper) Map(c echo.Context, out dtos.CategoryDto) error {
+	return writeEntity(c, 201, out, out.Version)
+}
+
+type CategoryUpdateRequestMapper struct{}
+
+func NewCategoryUpdateRequestMapper() CategoryUpdateRequestMapper {
+	return CategoryUpdateRequestMapper{}
+}
+
+func (m CategoryUpdateRequestMapper) Map(c echo.Context) (dtos.CategoryDto, error) {
+	var dto dtos.CategoryDto
+	if err := c.Bind(&dto); err != nil {
+		return dto, handlers.NewErr("failed to bind update category request", err, 400)
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return dto, handlers.NewErr("failed to parse category id", err, 400)
+	}
+	dto.ID = id.String()
+
+	version, err := ifMatch(c)
+	if err != nil {
+		return dto, err
+	}
+	dto.Version = version
+
+	return dto, nil
+}
+
+type CategoryDeleteRequestMapper struct{}
+
+func NewCategoryDeleteRequestMapper() CategoryDeleteRequestMapper {
+	return CategoryDeleteRequestMapper{}
+}
+
+func (m CategoryDeleteRequestMapper) Map(c echo.Context) (dtos.DeleteCommand, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return dtos.DeleteCommand{}, handlers.NewErr("failed to parse category id", err, 400)
+	}
+
+	version, err := ifMatch(c)
+	if err != nil {
+		return dtos.DeleteCommand{}, err
+	}
+	return dtos.DeleteCommand{ID: id, Version: version}, nil
+}
+
+type CategoryDeleteResponseMapper struct{}
+
+func NewCategoryDeleteResponseMapper() CategoryDeleteResponseMapper {
+	return CategoryDeleteResponseMapper{}
+}
+
+func (m CategoryDeleteResponseMapper) Map(c echo.Context, _ struct{}) error {
+	return c.NoContent(204)
+}
+
+type ItemAssignCategoriesRequestMapper struct{}
+
+func NewItemAssignCategoriesRequestMapper() ItemAssignCategoriesRequestMapper {
+	return ItemAssignCategoriesRequestMapper{}
+}
+
+func (m ItemAssignCategoriesRequestMapper) Map(c echo.Context) (dtos.AssignItemCategoriesCommand, error) {
+	var cmd dtos.AssignItemCategoriesCommand
+	if err := c.Bind(&cmd); err != nil {
+		return cmd, handlers.NewErr("failed to bind assign item categories request", err, 400)
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return cmd, handlers.NewErr("failed to parse item id", err, 400)
+	}
+	cmd.ItemID = id
+
+	return cmd, nil
+}
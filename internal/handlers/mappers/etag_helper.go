@@ -0,0 +1,59 @@
+package mappers
+
+import (
+	"fmt"
+	"github.com/fmiskovic/new-amz/internal/handlers"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Headers of conditional requests, see RFC 9110.
+const (
+	headerETag        = "ETag"
+	headerIfMatch     = "If-Match"
+	headerIfNoneMatch = "If-None-Match"
+)
+
+// writeEntity responds with the entity in the version and its ETag, the quoted version, e.g. "3".
+// GET requests whose If-None-Match header matches the ETag are answered with 304 Not Modified instead.
+func writeEntity(c echo.Context, code int, entity any, version int64) error {
+	etag := fmt.Sprintf(`"%d"`, version)
+	c.Response().Header().Set(headerETag, etag)
+	if c.Request().Method == http.MethodGet && noneMatch(c.Request().Header.Get(headerIfNoneMatch), etag) {
+		return c.NoContent(http.StatusNotModified)
+	}
+	return c.JSON(code, entity)
+}
+
+// noneMatch reports whether any of the ETags listed in the If-None-Match header is the etag, comparing weakly.
+func noneMatch(header string, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// ifMatch returns the version of the entity the request changing it expects from the If-Match header.
+// The header is required and has to be the ETag of the entity, so changes based on a stale read are not applied
+// unnoticed: 428 Precondition Required is returned if it is missing or "*", which would match any version,
+// and 412 Precondition Failed if it is not an ETag of the entity.
+func ifMatch(c echo.Context) (int64, error) {
+	header := strings.TrimSpace(c.Request().Header.Get(headerIfMatch))
+	if header == "" || header == "*" {
+		return 0, handlers.NewErr("If-Match header with the ETag of the resource is required", fmt.Errorf("If-Match header %q", header), http.StatusPreconditionRequired)
+	}
+
+	// weak ETags never match, a list of ETags can not match a single version
+	tag, ok := strings.CutPrefix(header, `"`)
+	tag, closed := strings.CutSuffix(tag, `"`)
+	version, err := strconv.ParseInt(tag, 10, 64)
+	if !ok || !closed || err != nil || version <= 0 {
+		return 0, handlers.NewErr("If-Match header does not match", fmt.Errorf("invalid ETag %s", header), http.StatusPreconditionFailed)
+	}
+	return version, nil
+}
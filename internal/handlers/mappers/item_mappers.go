@@ -4,8 +4,11 @@ import (
 	"github.com/fmiskovic/new-amz/internal/core/dtos"
 	"github.com/fmiskovic/new-amz/internal/core/entities"
 	"github.com/fmiskovic/new-amz/internal/handlers"
+	"github.com/fmiskovic/new-amz/internal/validators"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"strconv"
+	"strings"
 )
 
 type ItemGetByIdRequestMapper struct{}
@@ -29,7 +32,17 @@ func NewItemGetByIdResponseMapper() ItemGetByIdResponseMapper {
 }
 
 func (m ItemGetByIdResponseMapper) Map(c echo.Context, out dtos.ItemDto) error {
-	return c.JSON(200, out)
+	return writeEntity(c, 200, out, out.Version)
+}
+
+type ItemGetByISBNRequestMapper struct{}
+
+func NewItemGetByISBNRequestMapper() ItemGetByISBNRequestMapper {
+	return ItemGetByISBNRequestMapper{}
+}
+
+func (m ItemGetByISBNRequestMapper) Map(c echo.Context) (dtos.ItemIsbnQuery, error) {
+	return dtos.ItemIsbnQuery{ISBN: normalizeISBN(c.Param("isbn"))}, nil
 }
 
 type ItemGetPageRequestMapper struct{}
@@ -38,8 +51,49 @@ func NewItemGetPageRequestMapper() ItemGetPageRequestMapper {
 	return ItemGetPageRequestMapper{}
 }
 
-func (m ItemGetPageRequestMapper) Map(c echo.Context) (entities.Pageable, error) {
-	return pageRequestMapper(c), nil
+func (m ItemGetPageRequestMapper) Map(c echo.Context) (dtos.ItemFilter, error) {
+	var filter dtos.ItemFilter
+
+	if category := c.QueryParam("category"); category != "" {
+		categoryId, err := uuid.Parse(category)
+		if err != nil {
+			return filter, handlers.NewErr("failed to parse category id", err, 400)
+		}
+		filter.CategoryID = &categoryId
+	}
+
+	pageRequest, err := pageRequestMapper(c, entities.ItemSortable)
+	if err != nil {
+		return filter, handlers.NewErr("invalid page request", err, 400)
+	}
+	filter.PageRequest = pageRequest
+
+	return filter, nil
+}
+
+type AuthorItemsRequestMapper struct{}
+
+func NewAuthorItemsRequestMapper() AuthorItemsRequestMapper {
+	return AuthorItemsRequestMapper{}
+}
+
+func (m AuthorItemsRequestMapper) Map(c echo.Context) (dtos.AuthorItemsQuery, error) {
+	var q dtos.AuthorItemsQuery
+
+	authorId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return q, handlers.NewErr("failed to parse author id", err, 400)
+	}
+
+	pageRequest, err := pageRequestMapper(c, entities.ItemSortable)
+	if err != nil {
+		return q, handlers.NewErr("invalid page request", err, 400)
+	}
+
+	q.AuthorID = authorId
+	q.PageRequest = pageRequest
+
+	return q, nil
 }
 
 type ItemGetPageResponseMapper struct{}
@@ -49,5 +103,162 @@ func NewItemGetPageResponseMapper() ItemGetPageResponseMapper {
 }
 
 func (m ItemGetPageResponseMapper) Map(c echo.Context, out entities.Page[dtos.ItemDto]) error {
+	return writePage(c, out)
+}
+
+type ItemSearchRequestMapper struct{}
+
+func NewItemSearchRequestMapper() ItemSearchRequestMapper {
+	return ItemSearchRequestMapper{}
+}
+
+func (m ItemSearchRequestMapper) Map(c echo.Context) (dtos.ItemSearchQuery, error) {
+	q := dtos.ItemSearchQuery{
+		Query: strings.TrimSpace(c.QueryParam("q")),
+		Mode:  c.QueryParam("mode"),
+	}
+
+	// search results are ranked by relevance, so they cannot be sorted otherwise
+	_, keyset := c.QueryParams()["cursor"]
+	if c.QueryParam("sort") != "" || keyset {
+		return q, handlers.NewErr("invalid page request", entities.ErrorSearchPaging, 400)
+	}
+	pageRequest, err := pageRequestMapper(c, nil)
+	if err != nil {
+		return q, handlers.NewErr("invalid page request", err, 400)
+	}
+	q.PageRequest = pageRequest
+	return q, nil
+}
+
+type ItemSearchResponseMapper struct{}
+
+func NewItemSearchResponseMapper() ItemSearchResponseMapper {
+	return ItemSearchResponseMapper{}
+}
+
+func (m ItemSearchResponseMapper) Map(c echo.Context, out entities.Page[dtos.ItemMatchDto]) error {
+	return writePage(c, out)
+}
+
+// defaultSuggestLimit is the number of suggestions returned if the request does not limit them.
+const defaultSuggestLimit = 5
+
+type ItemSuggestRequestMapper struct{}
+
+func NewItemSuggestRequestMapper() ItemSuggestRequestMapper {
+	return ItemSuggestRequestMapper{}
+}
+
+func (m ItemSuggestRequestMapper) Map(c echo.Context) (dtos.ItemSuggestQuery, error) {
+	q := dtos.ItemSuggestQuery{Query: strings.TrimSpace(c.QueryParam("q")), Limit: defaultSuggestLimit}
+	if limit := c.QueryParam("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil {
+			return q, handlers.NewErr("failed to parse limit", err, 400)
+		}
+		q.Limit = l
+	}
+	return q, nil
+}
+
+type ItemSuggestResponseMapper struct{}
+
+func NewItemSuggestResponseMapper() ItemSuggestResponseMapper {
+	return ItemSuggestResponseMapper{}
+}
+
+func (m ItemSuggestResponseMapper) Map(c echo.Context, out dtos.ItemSuggestionsDto) error {
 	return c.JSON(200, out)
 }
+
+type ItemCreateRequestMapper struct{}
+
+func NewItemCreateRequestMapper() ItemCreateRequestMapper {
+	return ItemCreateRequestMapper{}
+}
+
+func (m ItemCreateRequestMapper) Map(c echo.Context) (dtos.ItemDto, error) {
+	var dto dtos.ItemDto
+	if err := c.Bind(&dto); err != nil {
+		return dto, handlers.NewErr("failed to bind create item request", err, 400)
+	}
+	dto.ISBN = normalizeISBN(dto.ISBN)
+	return dto, nil
+}
+
+type ItemCreateResponseMapper struct{}
+
+func NewItemCreateResponseMapper() ItemCreateResponseMapper {
+	return ItemCreateResponseMapper{}
+}
+
+func (m ItemCreateResponseMapper) Map(c echo.Context, out dtos.ItemDto) error {
+	return writeEntity(c, 201, out, out.Version)
+}
+
+type ItemUpdateRequestMapper struct{}
+
+func NewItemUpdateRequestMapper() ItemUpdateRequestMapper {
+	return ItemUpdateRequestMapper{}
+}
+
+func (m ItemUpdateRequestMapper) Map(c echo.Context) (dtos.ItemDto, error) {
+	var dto dtos.ItemDto
+	if err := c.Bind(&dto); err != nil {
+		return dto, handlers.NewErr("failed to bind update item request", err, 400)
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return dto, handlers.NewErr("failed to parse item id", err, 400)
+	}
+	dto.ID = id.String()
+
+	version, err := ifMatch(c)
+	if err != nil {
+		return dto, err
+	}
+	dto.Version = version
+	dto.ISBN = normalizeISBN(dto.ISBN)
+
+	return dto, nil
+}
+
+type ItemDeleteRequestMapper struct{}
+
+func NewItemDeleteRequestMapper() ItemDeleteRequestMapper {
+	return ItemDeleteRequestMapper{}
+}
+
+func (m ItemDeleteRequestMapper) Map(c echo.Context) (dtos.DeleteCommand, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return dtos.DeleteCommand{}, handlers.NewErr("failed to parse item id", err, 400)
+	}
+
+	version, err := ifMatch(c)
+	if err != nil {
+		return dtos.DeleteCommand{}, err
+	}
+	return dtos.DeleteCommand{ID: id, Version: version}, nil
+}
+
+type ItemDeleteResponseMapper struct{}
+
+func NewItemDeleteResponseMapper() ItemDeleteResponseMapper {
+	return ItemDeleteResponseMapper{}
+}
+
+func (m ItemDeleteResponseMapper) Map(c echo.Context, _ struct{}) error {
+	return c.NoContent(204)
+}
+
+// normalizeISBN converts valid ISBNs into ISBN-13 without hyphens, the form they are stored in.
+// Invalid ISBNs are kept, so they are reported by the validation of the request.
+func normalizeISBN(isbn string) string {
+	if normalized, err := validators.ParseISBN(isbn); err == nil {
+		return normalized
+	}
+	return isbn
+}
@@ -46,8 +46,13 @@ func (m OrderSearchRequestMapper) Map(c echo.Context) (dtos.OrderFilter, error)
 		return filter, handlers.NewErr("failed to parse account id", err, 400)
 	}
 
+	pageRequest, err := pageRequestMapper(c, entities.OrderSortable)
+	if err != nil {
+		return filter, handlers.NewErr("invalid page request", err, 400)
+	}
+
 	filter.AccountID = accountId
-	filter.PageRequest = pageRequestMapper(c)
+	filter.PageRequest = pageRequest
 
 	return filter, nil
 }
@@ -59,7 +64,7 @@ func NewOrderSearchResponseMapper() OrderSearchResponseMapper {
 }
 
 func (m OrderSearchResponseMapper) Map(c echo.Context, out entities.Page[dtos.OrderDto]) error {
-	return c.JSON(200, out)
+	return writePage(c, out)
 }
 
 type OrderCreateRequestMapper struct{}
@@ -85,3 +90,106 @@ func NewOrderCreateResponseMapper() OrderCreateResponseMapper {
 func (m OrderCreateResponseMapper) Map(c echo.Context, out dtos.CreateOrderAnswer) error {
 	return c.JSON(201, out)
 }
+
+type OrderTransitionRequestMapper struct{}
+
+func NewOrderTransitionRequestMapper() OrderTransitionRequestMapper {
+	return OrderTransitionRequestMapper{}
+}
+
+func (m OrderTransitionRequestMapper) Map(c echo.Context) (dtos.TransitionOrderCommand, error) {
+	var cmd dtos.TransitionOrderCommand
+	if err := c.Bind(&cmd); err != nil {
+		return cmd, handlers.NewErr("failed to bind order transition request", err, 400)
+	}
+
+	orderId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return cmd, handlers.NewErr("failed to parse order id", err, 400)
+	}
+	cmd.OrderID = orderId
+
+	return cmd, nil
+}
+
+type OrderTransitionResponseMapper struct{}
+
+func NewOrderTransitionResponseMapper() OrderTransitionResponseMapper {
+	return OrderTransitionResponseMapper{}
+}
+
+func (m OrderTransitionResponseMapper) Map(c echo.Context, out dtos.OrderTransitionDto) error {
+	return c.JSON(201, out)
+}
+
+type OrderGetTransitionsResponseMapper struct{}
+
+func NewOrderGetTransitionsResponseMapper() OrderGetTransitionsResponseMapper {
+	return OrderGetTransitionsResponseMapper{}
+}
+
+func (m OrderGetTransitionsResponseMapper) Map(c echo.Context, out []dtos.OrderTransitionDto) error {
+	return c.JSON(200, out)
+}
+
+type OrderCancelRequestMapper struct{}
+
+func NewOrderCancelRequestMapper() OrderCancelRequestMapper {
+	return OrderCancelRequestMapper{}
+}
+
+func (m OrderCancelRequestMapper) Map(c echo.Context) (dtos.CancelOrderCommand, error) {
+	var cmd dtos.CancelOrderCommand
+	if err := c.Bind(&cmd); err != nil {
+		return cmd, handlers.NewErr("failed to bind cancel order request", err, 400)
+	}
+
+	orderId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return cmd, handlers.NewErr("failed to parse order id", err, 400)
+	}
+	cmd.OrderID = orderId
+
+	return cmd, nil
+}
+
+type OrderRefundRequestMapper struct{}
+
+func NewOrderRefundRequestMapper() OrderRefundRequestMapper {
+	return OrderRefundRequestMapper{}
+}
+
+func (m OrderRefundRequestMapper) Map(c echo.Context) (dtos.RefundOrderCommand, error) {
+	var cmd dtos.RefundOrderCommand
+	if err := c.Bind(&cmd); err != nil {
+		return cmd, handlers.NewErr("failed to bind refund order request", err, 400)
+	}
+
+	orderId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return cmd, handlers.NewErr("failed to parse order id", err, 400)
+	}
+	cmd.OrderID = orderId
+
+	return cmd, nil
+}
+
+type OrderRefundResponseMapper struct{}
+
+func NewOrderRefundResponseMapper() OrderRefundResponseMapper {
+	return OrderRefundResponseMapper{}
+}
+
+func (m OrderRefundResponseMapper) Map(c echo.Context, out dtos.RefundDto) error {
+	return c.JSON(201, out)
+}
+
+type OrderGetRefundsResponseMapper struct{}
+
+func NewOrderGetRefundsResponseMapper() OrderGetRefundsResponseMapper {
+	return OrderGetRefundsResponseMapper{}
+}
+
+func (m OrderGetRefundsResponseMapper) Map(c echo.Context, out []dtos.RefundDto) error {
+	return c.JSON(200, out)
+}
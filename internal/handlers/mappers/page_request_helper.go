@@ -1,34 +1,139 @@
 package mappers
 
 import (
+	"fmt"
 	"github.com/fmiskovic/new-amz/internal/core/entities"
 	"github.com/labstack/echo/v4"
+	"net/http"
 	"strconv"
 	"strings"
 )
 
-func pageRequestMapper(c echo.Context) entities.Pageable {
-	size, err := strconv.Atoi(c.QueryParam("size"))
+// pageRequestMapper maps the size, offset, sort, count and cursor query parameters to the page request.
+// Size defaults to entities.DefaultPageSize and can not exceed entities.MaxPageSize, offset defaults to zero.
+func pageRequestMapper(c echo.Context, sortable entities.Sortable) (entities.Pageable, error) {
+	size, err := intParam(c, "size", entities.DefaultPageSize)
 	if err != nil {
-		size = 10
+		return entities.Pageable{}, err
 	}
-	offset, err := strconv.Atoi(c.QueryParam("offset"))
+	if size < 1 || size > entities.MaxPageSize {
+		return entities.Pageable{}, fmt.Errorf("%w: size must be between 1 and %d", entities.ErrorInvalidPageRequest, entities.MaxPageSize)
+	}
+	offset, err := intParam(c, "offset", 0)
 	if err != nil {
-		offset = 0
+		return entities.Pageable{}, err
+	}
+	if offset < 0 {
+		return entities.Pageable{}, fmt.Errorf("%w: offset must not be negative", entities.ErrorInvalidPageRequest)
+	}
+
+	sort, err := resolveSort(c, sortable)
+	if err != nil {
+		return entities.Pageable{}, err
+	}
+
+	skipCount := false
+	if count := c.QueryParam("count"); count != "" {
+		withCount, err := strconv.ParseBool(count)
+		if err != nil {
+			return entities.Pageable{}, entities.NewError(entities.ErrorValidation, fmt.Sprintf("invalid count parameter %q", count))
+		}
+		skipCount = !withCount
 	}
 
+	// presence of the cursor parameter, even empty one for the first page, switches to keyset pagination
+	_, keyset := c.QueryParams()["cursor"]
+
 	pageReq := entities.Pageable{
-		Size:   size,
-		Offset: offset,
-		Sort:   resolveSort(c),
+		Size:      size,
+		Offset:    offset,
+		Sort:      sort,
+		Keyset:    keyset,
+		Cursor:    c.QueryParam("cursor"),
+		SkipCount: skipCount,
+	}
+	return pageReq, nil
+}
+
+// intParam returns the integer query parameter, or the default value if the parameter is missing.
+func intParam(c echo.Context, name string, defaultValue int) (int, error) {
+	param := c.QueryParam(name)
+	if param == "" {
+		return defaultValue, nil
+	}
+	value, err := strconv.Atoi(param)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid %s parameter %q", entities.ErrorInvalidPageRequest, name, param)
+	}
+	return value, nil
+}
+
+// headerLink is the header of RFC 8288 web links.
+const headerLink = "Link"
+
+// writePage responds with the page, using the cursor page envelope for pages requested by cursor.
+// Links to the adjacent pages are sent in the Link header.
+func writePage[T any](c echo.Context, page entities.Page[T]) error {
+	if links := pageLinks(c, page); len(links) > 0 {
+		c.Response().Header().Set(headerLink, strings.Join(links, ", "))
+	}
+	if page.Cursors != nil {
+		return c.JSON(http.StatusOK, page.CursorPage())
 	}
-	return pageReq
+	return c.JSON(http.StatusOK, page)
 }
 
-func resolveSort(c echo.Context) entities.Sort {
+// pageLinks creates the first, prev, next and last links of the page from the request URL.
+// Pages requested by cursor have no last link, since it cannot be addressed.
+func pageLinks[T any](c echo.Context, page entities.Page[T]) []string {
+	link := func(rel string, param string, value string) string {
+		u := *c.Request().URL
+		u.Scheme = c.Scheme()
+		u.Host = c.Request().Host
+		q := u.Query()
+		q.Set(param, value)
+		u.RawQuery = q.Encode()
+		return fmt.Sprintf(`<%s>; rel="%s"`, u.String(), rel)
+	}
+
+	var links []string
+	if page.Cursors != nil {
+		links = append(links, link("first", "cursor", ""))
+		if page.Cursors.Prev != "" {
+			links = append(links, link("prev", "cursor", page.Cursors.Prev))
+		}
+		if page.Cursors.Next != "" {
+			links = append(links, link("next", "cursor", page.Cursors.Next))
+		}
+		return links
+	}
+
+	if page.Size <= 0 {
+		return nil
+	}
+	offset, err := strconv.Atoi(c.QueryParam("offset"))
+	if err != nil {
+		offset = 0
+	}
+	links = append(links, link("first", "offset", "0"))
+	if page.HasPrev {
+		links = append(links, link("prev", "offset", strconv.Itoa(max(offset-page.Size, 0))))
+	}
+	if page.HasNext {
+		links = append(links, link("next", "offset", strconv.Itoa(offset+page.Size)))
+	}
+	if page.TotalPages != nil && *page.TotalPages > 0 {
+		links = append(links, link("last", "offset", strconv.Itoa((*page.TotalPages-1)*page.Size)))
+	}
+	return links
+}
+
+// resolveSort parses the sort parameter in the form of "prop1 ASC, prop2 DESC" and checks it against
+// the properties the resource can be sorted by. Direction is optional and defaults to ASC.
+func resolveSort(c echo.Context, sortable entities.Sortable) (entities.Sort, error) {
 	sortParam := c.QueryParam("sort")
 	if sortParam == "" {
-		return entities.Sort{}
+		return entities.Sort{}, nil
 	}
 
 	// split the sort parameter into individual sort orderParams
@@ -37,13 +142,21 @@ func resolveSort(c echo.Context) entities.Sort {
 	var sortOrders []*entities.SortOrder
 
 	for i := range orderParams {
-		o := strings.Split(strings.TrimSpace(orderParams[i]), " ")
-		sortOrder := entities.NewSortOrder(entities.WithProperty(o[0]), entities.WithDirection(entities.ASC))
-		if len(o) == 2 {
-			sortOrder.Direction = entities.Direction(o[1])
+		property, direction, _ := strings.Cut(strings.TrimSpace(orderParams[i]), " ")
+		sortOrder := entities.NewSortOrder(entities.WithProperty(property), entities.WithDirection(entities.ASC))
+		if strings.TrimSpace(direction) != "" {
+			d, err := entities.ParseDirection(direction)
+			if err != nil {
+				return entities.Sort{}, err
+			}
+			sortOrder.Direction = d
 		}
 		sortOrders = append(sortOrders, sortOrder)
 	}
 
-	return entities.NewSort(sortOrders...)
+	sort := entities.NewSort(sortOrders...)
+	if err := sortable.Validate(sort); err != nil {
+		return entities.Sort{}, err
+	}
+	return sort, nil
 }
@@ -0,0 +1,108 @@
+package mappers
+
+import (
+	"github.com/fmiskovic/new-amz/internal/core/dtos"
+	"github.com/fmiskovic/new-amz/internal/handlers"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type PaymentAuthorizeRequestMapper struct{}
+
+func NewPaymentAuthorizeRequestMapper() PaymentAuthorizeRequestMapper {
+	return PaymentAuthorizeRequestMapper{}
+}
+
+func (m PaymentAuthorizeRequestMapper) Map(c echo.Context) (dtos.AuthorizePaymentCommand, error) {
+	var cmd dtos.AuthorizePaymentCommand
+	if err := c.Bind(&cmd); err != nil {
+		return cmd, handlers.NewErr("failed to bind authorize payment request", err, 400)
+	}
+
+	orderId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return cmd, handlers.NewErr("failed to parse order id", err, 400)
+	}
+	cmd.OrderID = orderId
+
+	return cmd, nil
+}
+
+type PaymentAuthorizeResponseMapper struct{}
+
+func NewPaymentAuthorizeResponseMapper() PaymentAuthorizeResponseMapper {
+	return PaymentAuthorizeResponseMapper{}
+}
+
+func (m PaymentAuthorizeResponseMapper) Map(c echo.Context, out dtos.PaymentDto) error {
+	return c.JSON(201, out)
+}
+
+type PaymentGetByIdRequestMapper struct{}
+
+func NewPaymentGetByIdRequestMapper() PaymentGetByIdRequestMapper {
+	return PaymentGetByIdRequestMapper{}
+}
+
+func (m PaymentGetByIdRequestMapper) Map(c echo.Context) (uuid.UUID, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return id, handlers.NewErr("failed to parse payment id", err, 400)
+	}
+	return id, nil
+}
+
+type PaymentAmountRequestMapper struct{}
+
+func NewPaymentAmountRequestMapper() PaymentAmountRequestMapper {
+	return PaymentAmountRequestMapper{}
+}
+
+func (m PaymentAmountRequestMapper) Map(c echo.Context) (dtos.PaymentAmountCommand, error) {
+	var cmd dtos.PaymentAmountCommand
+	if err := c.Bind(&cmd); err != nil {
+		return cmd, handlers.NewErr("failed to bind payment amount request", err, 400)
+	}
+
+	paymentId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return cmd, handlers.NewErr("failed to parse payment id", err, 400)
+	}
+	cmd.PaymentID = paymentId
+
+	return cmd, nil
+}
+
+type PaymentResponseMapper struct{}
+
+func NewPaymentResponseMapper() PaymentResponseMapper {
+	return PaymentResponseMapper{}
+}
+
+func (m PaymentResponseMapper) Map(c echo.Context, out dtos.PaymentDto) error {
+	return c.JSON(200, out)
+}
+
+type PaymentsResponseMapper struct{}
+
+func NewPaymentsResponseMapper() PaymentsResponseMapper {
+	return PaymentsResponseMapper{}
+}
+
+func (m PaymentsResponseMapper) Map(c echo.Context, out []dtos.PaymentDto) error {
+	return c.JSON(200, out)
+}
+
+type PaymentEventRequestMapper struct{}
+
+func NewPaymentEventRequestMapper() PaymentEventRequestMapper {
+	return PaymentEventRequestMapper{}
+}
+
+func (m PaymentEventRequestMapper) Map(c echo.Context) (dtos.PaymentEventCommand, error) {
+	var cmd dtos.PaymentEventCommand
+	if err := c.Bind(&cmd); err != nil {
+		return cmd, handlers.NewErr("failed to bind payment event", err, 400)
+	}
+	return cmd, nil
+}
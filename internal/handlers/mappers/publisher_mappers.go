@@ -0,0 +1,137 @@
+package mappers
+
+import (
+	"github.com/fmiskovic/new-amz/internal/core/dtos"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/handlers"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type PublisherGetByIdRequestMapper struct{}
+
+func NewPublisherGetByIdRequestMapper() PublisherGetByIdRequestMapper {
+	return PublisherGetByIdRequestMapper{}
+}
+
+func (m PublisherGetByIdRequestMapper) Map(c echo.Context) (uuid.UUID, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return id, handlers.NewErr("failed to parse publisher id", err, 400)
+	}
+	return id, nil
+}
+
+type PublisherGetByIdResponseMapper struct{}
+
+func NewPublisherGetByIdResponseMapper() PublisherGetByIdResponseMapper {
+	return PublisherGetByIdResponseMapper{}
+}
+
+func (m PublisherGetByIdResponseMapper) Map(c echo.Context, out dtos.PublisherDto) error {
+	return writeEntity(c, 200, out, out.Version)
+}
+
+type PublisherGetPageRequestMapper struct{}
+
+func NewPublisherGetPageRequestMapper() PublisherGetPageRequestMapper {
+	return PublisherGetPageRequestMapper{}
+}
+
+func (m PublisherGetPageRequestMapper) Map(c echo.Context) (entities.Pageable, error) {
+	pageRequest, err := pageRequestMapper(c, entities.PublisherSortable)
+	if err != nil {
+		return pageRequest, handlers.NewErr("invalid page request", err, 400)
+	}
+	return pageRequest, nil
+}
+
+type PublisherGetPageResponseMapper struct{}
+
+func NewPublisherGetPageResponseMapper() PublisherGetPageResponseMapper {
+	return PublisherGetPageResponseMapper{}
+}
+
+func (m PublisherGetPageResponseMapper) Map(c echo.Context, out entities.Page[dtos.PublisherDto]) error {
+	return writePage(c, out)
+}
+
+type PublisherCreateRequestMapper struct{}
+
+func NewPublisherCreateRequestMapper() PublisherCreateRequestMapper {
+	return PublisherCreateRequestMapper{}
+}
+
+func (m PublisherCreateRequestMapper) Map(c echo.Context) (dtos.PublisherDto, error) {
+	var dto dtos.PublisherDto
+	if err := c.Bind(&dto); err != nil {
+		return dto, handlers.NewErr("failed to bind create publisher request", err, 400)
+	}
+	return dto, nil
+}
+
+type PublisherCreateResponseMapper struct{}
+
+func NewPublisherCreateResponseMapper() PublisherCreateResponseMapper {
+	return PublisherCreateResponseMapper{}
+}
+
+func (m PublisherCreateResponseMapper) Map(c echo.Context, out dtos.PublisherDto) error {
+	return writeEntity(c, 201, out, out.Version)
+}
+
+type PublisherUpdateRequestMapper struct{}
+
+func NewPublisherUpdateRequestMapper() PublisherUpdateRequestMapper {
+	return PublisherUpdateRequestMapper{}
+}
+
+func (m PublisherUpdateRequestMapper) Map(c echo.Context) (dtos.PublisherDto, error) {
+	var dto dtos.PublisherDto
+	if err := c.Bind(&dto); err != nil {
+		return dto, handlers.NewErr("failed to bind update publisher request", err, 400)
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return dto, handlers.NewErr("failed to parse publisher id", err, 400)
+	}
+	dto.ID = id.String()
+
+	version, err := ifMatch(c)
+	if err != nil {
+		return dto, err
+	}
+	dto.Version = version
+
+	return dto, nil
+}
+
+type PublisherDeleteRequestMapper struct{}
+
+func NewPublisherDeleteRequestMapper() PublisherDeleteRequestMapper {
+	return PublisherDeleteRequestMapper{}
+}
+
+func (m PublisherDeleteRequestMapper) Map(c echo.Context) (dtos.DeleteCommand, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return dtos.DeleteCommand{}, handlers.NewErr("failed to parse publisher id", err, 400)
+	}
+
+	version, err := ifMatch(c)
+	if err != nil {
+		return dtos.DeleteCommand{}, err
+	}
+	return dtos.DeleteCommand{ID: id, Version: version}, nil
+}
+
+type PublisherDeleteResponseMapper struct{}
+
+func NewPublisherDeleteResponseMapper() PublisherDeleteResponseMapper {
+	return PublisherDeleteResponseMapper{}
+}
+
+func (m PublisherDeleteResponseMapper) Map(c echo.Context, _ struct{}) error {
+	return c.NoContent(204)
+}
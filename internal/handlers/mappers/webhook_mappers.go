@@ -0,0 +1,207 @@
+package mappers
+
+import (
+	"github.com/fmiskovic/new-amz/internal/core/dtos"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/handlers"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type WebhookGetByIdRequestMapper struct{}
+
+func NewWebhookGetByIdRequestMapper() WebhookGetByIdRequestMapper {
+	return WebhookGetByIdRequestMapper{}
+}
+
+func (m WebhookGetByIdRequestMapper) Map(c echo.Context) (uuid.UUID, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return id, handlers.NewErr("failed to parse webhook subscription id", err, 400)
+	}
+	return id, nil
+}
+
+type WebhookGetByIdResponseMapper struct{}
+
+func NewWebhookGetByIdResponseMapper() WebhookGetByIdResponseMapper {
+	return WebhookGetByIdResponseMapper{}
+}
+
+func (m WebhookGetByIdResponseMapper) Map(c echo.Context, out dtos.WebhookSubscriptionDto) error {
+	return writeEntity(c, 200, out, out.Version)
+}
+
+type WebhookGetPageRequestMapper struct{}
+
+func NewWebhookGetPageRequestMapper() WebhookGetPageRequestMapper {
+	return WebhookGetPageRequestMapper{}
+}
+
+func (m WebhookGetPageRequestMapper) Map(c echo.Context) (entities.Pageable, error) {
+	pageRequest, err := pageRequestMapper(c, entities.WebhookSubscriptionSortable)
+	if err != nil {
+		return pageRequest, handlers.NewErr("invalid page request", err, 400)
+	}
+	return pageRequest, nil
+}
+
+type WebhookGetPageResponseMapper struct{}
+
+func NewWebhookGetPageResponseMapper() WebhookGetPageResponseMapper {
+	return WebhookGetPageResponseMapper{}
+}
+
+func (m WebhookGetPageResponseMapper) Map(c echo.Context, out entities.Page[dtos.WebhookSubscriptionDto]) error {
+	return writePage(c, out)
+}
+
+type WebhookCreateRequestMapper struct{}
+
+func NewWebhookCreateRequestMapper() WebhookCreateRequestMapper {
+	return WebhookCreateRequestMapper{}
+}
+
+func (m WebhookCreateRequestMapper) Map(c echo.Context) (dtos.WebhookSubscriptionDto, error) {
+	var dto dtos.WebhookSubscriptionDto
+	if err := c.Bind(&dto); err != nil {
+		return dto, handlers.NewErr("failed to bind create webhook subscription request", err, 400)
+	}
+	return dto, nil
+}
+
+type WebhookCreateResponseMapper struct{}
+
+func NewWebhookCreateResponseMapper() WebhookCreateResponseMapper {
+	return WebhookCreateResponseMapper{}
+}
+
+func (m WebhookCreateResponseMapper) Map(c echo.Context, out dtos.WebhookSubscriptionDto) error {
+	return writeEntity(c, 201, out, out.Version)
+}
+
+type WebhookUpdateRequestMapper struct{}
+
+func NewWebhookUpdateRequestMapper() WebhookUpdateRequestMapper {
+	return WebhookUpdateRequestMapper{}
+}
+
+func (m WebhookUpdateRequestMapper) Map(c echo.Context) (dtos.WebhookSubscriptionDto, error) {
+	var dto dtos.WebhookSubscriptionDto
+	if err := c.Bind(&dto); err != nil {
+		return dto, handlers.NewErr("failed to bind update webhook subscription request", err, 400)
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return dto, handlers.NewErr("failed to parse webhook subscription id", err, 400)
+	}
+	dto.ID = id.String()
+
+	version, err := ifMatch(c)
+	if err != nil {
+		return dto, err
+	}
+	dto.Version = version
+
+	return dto, nil
+}
+
+type WebhookDeleteRequestMapper struct{}
+
+func NewWebhookDeleteRequestMapper() WebhookDeleteRequestMapper {
+	return WebhookDeleteRequestMapper{}
+}
+
+func (m WebhookDeleteRequestMapper) Map(c echo.Context) (dtos.DeleteCommand, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return dtos.DeleteCommand{}, handlers.NewErr("failed to parse webhook subscription id", err, 400)
+	}
+
+	version, err := ifMatch(c)
+	if err != nil {
+		return dtos.DeleteCommand{}, err
+	}
+	return dtos.DeleteCommand{ID: id, Version: version}, nil
+}
+
+type WebhookDeleteResponseMapper struct{}
+
+func NewWebhookDeleteResponseMapper() WebhookDeleteResponseMapper {
+	return WebhookDeleteResponseMapper{}
+}
+
+func (m WebhookDeleteResponseMapper) Map(c echo.Context, _ struct{}) error {
+	return c.NoContent(204)
+}
+
+type WebhookDeliveriesRequestMapper struct{}
+
+func NewWebhookDeliveriesRequestMapper() WebhookDeliveriesRequestMapper {
+	return WebhookDeliveriesRequestMapper{}
+}
+
+func (m WebhookDeliveriesRequestMapper) Map(c echo.Context) (dtos.WebhookDeliveriesQuery, error) {
+	var q dtos.WebhookDeliveriesQuery
+
+	subscriptionId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return q, handlers.NewErr("failed to parse webhook subscription id", err, 400)
+	}
+
+	pageRequest, err := pageRequestMapper(c, entities.WebhookDeliverySortable)
+	if err != nil {
+		return q, handlers.NewErr("invalid page request", err, 400)
+	}
+
+	q.SubscriptionID = subscriptionId
+	q.Status = c.QueryParam("status")
+	q.PageRequest = pageRequest
+
+	return q, nil
+}
+
+type WebhookDeliveriesResponseMapper struct{}
+
+func NewWebhookDeliveriesResponseMapper() WebhookDeliveriesResponseMapper {
+	return WebhookDeliveriesResponseMapper{}
+}
+
+func (m WebhookDeliveriesResponseMapper) Map(c echo.Context, out entities.Page[dtos.WebhookDeliveryDto]) error {
+	return writePage(c, out)
+}
+
+type WebhookRedeliverRequestMapper struct{}
+
+func NewWebhookRedeliverRequestMapper() WebhookRedeliverRequestMapper {
+	return WebhookRedeliverRequestMapper{}
+}
+
+func (m WebhookRedeliverRequestMapper) Map(c echo.Context) (dtos.RedeliverWebhookCommand, error) {
+	var cmd dtos.RedeliverWebhookCommand
+
+	subscriptionId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return cmd, handlers.NewErr("failed to parse webhook subscription id", err, 400)
+	}
+	deliveryId, err := uuid.Parse(c.Param("deliveryId"))
+	if err != nil {
+		return cmd, handlers.NewErr("failed to parse webhook delivery id", err, 400)
+	}
+
+	cmd.SubscriptionID = subscriptionId
+	cmd.DeliveryID = deliveryId
+
+	return cmd, nil
+}
+
+type WebhookDeliveryResponseMapper struct{}
+
+func NewWebhookDeliveryResponseMapper() WebhookDeliveryResponseMapper {
+	return WebhookDeliveryResponseMapper{}
+}
+
+func (m WebhookDeliveryResponseMapper) Map(c echo.Context, out dtos.WebhookDeliveryDto) error {
+	return c.JSON(200, out)
+}
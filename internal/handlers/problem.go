@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/core/services"
+	"github.com/fmiskovic/new-amz/internal/validators"
+	"github.com/labstack/echo/v4"
+)
+
+// MIMEApplicationProblemJSON is the media type of problem details, see RFC 7807.
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// Problem types identify the kind of the problem independently of the status code.
+const (
+	ProblemTypeDefault      = "about:blank"
+	ProblemTypeValidation   = "/problems/validation"
+	ProblemTypeUnauthorized = "/problems/unauthorized"
+	ProblemTypeForbidden    = "/problems/forbidden"
+	ProblemTypeNotFound     = "/problems/not-found"
+	ProblemTypeConflict     = "/problems/conflict"
+	ProblemTypePrecondition = "/problems/precondition"
+	ProblemTypeUnavailable  = "/problems/unavailable"
+)
+
+// Problem is the body of an error response as defined by RFC 7807.
+type Problem struct {
+	Type          string                    `json:"type"`
+	Title         string                    `json:"title"`
+	Status        int                       `json:"status"`
+	Detail        string                    `json:"detail,omitempty"`
+	Instance      string                    `json:"instance,omitempty"`
+	InvalidParams []validators.InvalidParam `json:"invalid_params,omitempty"`
+}
+
+// newProblem creates the Problem of the status code with the given detail.
+func newProblem(code int, detail string) Problem {
+	return Problem{
+		Type:   problemType(code),
+		Title:  http.StatusText(code),
+		Status: code,
+		Detail: detail,
+	}
+}
+
+// problemError wraps the problem into echo.HTTPError, so it is written by ErrorHandler.
+func problemError(p Problem, cause error) *echo.HTTPError {
+	return echo.NewHTTPError(p.Status, p).SetInternal(cause)
+}
+
+func problemType(code int) string {
+	switch code {
+	case http.StatusBadRequest:
+		return ProblemTypeValidation
+	case http.StatusUnauthorized:
+		return ProblemTypeUnauthorized
+	case http.StatusForbidden:
+		return ProblemTypeForbidden
+	case http.StatusNotFound:
+		return ProblemTypeNotFound
+	case http.StatusConflict:
+		return ProblemTypeConflict
+	case http.StatusPreconditionFailed, http.StatusPreconditionRequired:
+		return ProblemTypePrecondition
+	case http.StatusServiceUnavailable:
+		return ProblemTypeUnavailable
+	default:
+		return ProblemTypeDefault
+	}
+}
+
+// errorDetail returns the message of the domain error in the chain of err, which is safe to show to clients.
+// Other errors may reveal internals and are not described.
+func errorDetail(err error) string {
+	var stockErr entities.InsufficientStockError
+	if errors.As(err, &stockErr) {
+		return stockErr.Error()
+	}
+	var domainErr entities.DomainError
+	if errors.As(err, &domainErr) {
+		return domainErr.Error()
+	}
+	if errors.Is(err, services.ErrorInvalidCredentials) {
+		return services.ErrorInvalidCredentials.Error()
+	}
+	return ""
+}
+
+// ErrorHandler is echo.HTTPErrorHandler that writes errors as application/problem+json responses.
+func ErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	p := toProblem(err)
+	p.Instance = c.Request().URL.Path
+
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(p.Status)
+	} else {
+		c.Response().Header().Set(echo.HeaderContentType, MIMEApplicationProblemJSON)
+		err = c.JSON(p.Status, p)
+	}
+	if err != nil {
+		c.Logger().Error(err)
+	}
+}
+
+func toProblem(err error) Problem {
+	var herr *echo.HTTPError
+	if !errors.As(err, &herr) {
+		return newProblem(http.StatusInternalServerError, "")
+	}
+	if p, ok := herr.Message.(Problem); ok {
+		return p
+	}
+
+	// errors raised by echo itself and by middlewares, e.g. unknown route or missing access token
+	detail := fmt.Sprint(herr.Message)
+	if detail == http.StatusText(herr.Code) || herr.Code >= http.StatusInternalServerError {
+		detail = ""
+	}
+	return newProblem(herr.Code, detail)
+}
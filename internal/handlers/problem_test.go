@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/validators"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type testCommand struct {
+	Title string `json:"title" validate:"required"`
+	Stock int    `json:"stock" validate:"gte=0"`
+}
+
+type testRequestMapper struct {
+	cmd testCommand
+	err error
+}
+
+func (m testRequestMapper) Map(echo.Context) (testCommand, error) {
+	return m.cmd, m.err
+}
+
+type testResponseMapper struct{}
+
+func (testResponseMapper) Map(c echo.Context, _ struct{}) error {
+	return c.NoContent(http.StatusNoContent)
+}
+
+func serve(reqMapper testRequestMapper, svcErr error) (*httptest.ResponseRecorder, Problem) {
+	e := echo.New()
+	e.Validator = validators.New()
+	e.HTTPErrorHandler = ErrorHandler
+
+	h := New[testCommand, struct{}](reqMapper, testResponseMapper{}, func(context.Context, testCommand) (struct{}, error) {
+		return struct{}{}, svcErr
+	})
+	e.POST("/test", h.Handle)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/test", nil))
+
+	var p Problem
+	_ = json.Unmarshal(rec.Body.Bytes(), &p)
+	return rec, p
+}
+
+func TestErrorHandler(t *testing.T) {
+	valid := testCommand{Title: "Dune"}
+
+	t.Run("given validation failure should list invalid params", func(t *testing.T) {
+		rec, p := serve(testRequestMapper{cmd: testCommand{Stock: -1}}, nil)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, MIMEApplicationProblemJSON, rec.Header().Get(echo.HeaderContentType))
+		assert.Equal(t, Problem{
+			Type:     ProblemTypeValidation,
+			Title:    "Bad Request",
+			Status:   http.StatusBadRequest,
+			Detail:   "request validation failed",
+			Instance: "/test",
+			InvalidParams: []validators.InvalidParam{
+				{Name: "title", Reason: "is required"},
+				{Name: "stock", Reason: "must be greater than or equal to 0"},
+			},
+		}, p)
+	})
+
+	t.Run("given mapper failure should describe domain cause", func(t *testing.T) {
+		err := NewErr("failed to bind item request", fmt.Errorf("bind: %w", entities.ErrorUnknownCurrency), http.StatusBadRequest)
+		rec, p := serve(testRequestMapper{err: err}, nil)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "failed to bind item request: unknown currency", p.Detail)
+	})
+
+	t.Run("given domain error should describe it", func(t *testing.T) {
+		rec, p := serve(testRequestMapper{cmd: valid}, fmt.Errorf("failed to create account: %w", entities.ErrorEmailNotUnique))
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Equal(t, ProblemTypeConflict, p.Type)
+		assert.Equal(t, entities.ErrorEmailNotUnique.Error(), p.Detail)
+	})
+
+	t.Run("given unexpected error should not reveal it", func(t *testing.T) {
+		rec, p := serve(testRequestMapper{cmd: valid}, errors.New("pq: relation accounts does not exist"))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Equal(t, ProblemTypeDefault, p.Type)
+		assert.Empty(t, p.Detail)
+	})
+
+	t.Run("given echo error should convert it", func(t *testing.T) {
+		e := echo.New()
+		e.HTTPErrorHandler = ErrorHandler
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/unknown", nil))
+
+		var p Problem
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, Problem{Type: ProblemTypeNotFound, Title: "Not Found", Status: http.StatusNotFound, Instance: "/unknown"}, p)
+	})
+}
@@ -0,0 +1,199 @@
+package idempotency
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/fmiskovic/new-amz/internal/core"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/core/repositories"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+const (
+	// HeaderIdempotencyKey carries the key a client chose for a request it may retry.
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderIdempotentReplayed is set on responses replayed from a previous request with the same key.
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	maxKeyLength = 255
+	// DefaultTTL is how long keys are kept when no TTL is configured.
+	DefaultTTL = 24 * time.Hour
+)
+
+// Config configures the idempotency middleware.
+type Config struct {
+	// Skipper selects requests which are passed through without idempotency, like requests which do not create anything.
+	Skipper middleware.Skipper
+	// TTL is how long a key is kept after it was first used, retries within it are answered with the stored response.
+	TTL time.Duration
+}
+
+// Middleware makes requests carrying the Idempotency-Key header safe to retry.
+// The first request with a key is processed and, if it succeeds, its response is stored with the key.
+// Retries with the same key and request are answered with the stored response instead of being processed again.
+// Using the key with a different request is rejected with 422 and retrying while the first request is still
+// processed is rejected with 409. Failed requests are not stored, so they are processed again when retried.
+// The middleware has to run after authentication, as keys are bound to the calling account:
+// every account has keys of its own, so keys chosen by other accounts neither collide with nor reveal its keys.
+func Middleware(repo repositories.IdempotencyRepository, cfg Config) echo.MiddlewareFunc {
+	if cfg.Skipper == nil {
+		cfg.Skipper = middleware.DefaultSkipper
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultTTL
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := strings.TrimSpace(c.Request().Header.Get(HeaderIdempotencyKey))
+			if key == "" || cfg.Skipper(c) {
+				return next(c)
+			}
+			if len(key) > maxKeyLength {
+				return echo.NewHTTPError(http.StatusBadRequest, "idempotency key is too long")
+			}
+
+			fingerprint, err := requestFingerprint(c)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "failed to read request body").SetInternal(err)
+			}
+
+			now := time.Now()
+			record := &entities.IdempotencyKey{
+				AccountID:   callerOf(c),
+				Key:         key,
+				Fingerprint: fingerprint,
+				CreatedAt:   now,
+				ExpiresAt:   now.Add(cfg.TTL),
+			}
+			stored, reserved, err := repo.Reserve(c.Request().Context(), record)
+			if err != nil {
+				return err
+			}
+			if !reserved {
+				return replay(c, stored, fingerprint)
+			}
+
+			return process(c, next, repo, record)
+		}
+	}
+}
+
+// replay answers the retry with the response stored with the key.
+func replay(c echo.Context, stored entities.IdempotencyKey, fingerprint string) error {
+	if stored.Fingerprint != fingerprint {
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, "idempotency key was used with a different request")
+	}
+	if !stored.Completed() {
+		c.Response().Header().Set(echo.HeaderRetryAfter, "1")
+		return echo.NewHTTPError(http.StatusConflict, "request with the same idempotency key is still being processed")
+	}
+
+	c.Response().Header().Set(HeaderIdempotentReplayed, "true")
+	contentType := stored.ContentType
+	if contentType == "" {
+		contentType = echo.MIMEOctetStream
+	}
+	return c.Blob(stored.StatusCode, contentType, stored.Body)
+}
+
+// process handles the request made with the reserved key and stores its response if it succeeds.
+// The key is released otherwise, even if the handler panics, so the request can be retried.
+func process(c echo.Context, next echo.HandlerFunc, repo repositories.IdempotencyRepository, record *entities.IdempotencyKey) (err error) {
+	// the outcome is stored even if the client is gone already
+	ctx := context.WithoutCancel(c.Request().Context())
+	completed := false
+	defer func() {
+		if !completed {
+			if releaseErr := repo.Release(ctx, record.AccountID, record.Key); releaseErr != nil {
+				c.Logger().Error(releaseErr)
+			}
+		}
+	}()
+
+	recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+	c.Response().Writer = recorder
+
+	if err = next(c); err != nil {
+		return err
+	}
+
+	status := c.Response().Status
+	if status < http.StatusOK || status >= http.StatusMultipleChoices {
+		return nil
+	}
+
+	record.StatusCode = status
+	record.ContentType = c.Response().Header().Get(echo.HeaderContentType)
+	record.Body = recorder.body.Bytes()
+	if completeErr := repo.Complete(ctx, record); completeErr != nil {
+		// the response is sent already, the retry will be processed again
+		c.Logger().Error(completeErr)
+		return nil
+	}
+	completed = true
+	return nil
+}
+
+// requestFingerprint hashes the calling account and the request, so a key can only be replayed
+// by the account which used it first and for the same request. The body is restored for the handler.
+func requestFingerprint(c echo.Context) (string, error) {
+	req := c.Request()
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return "", err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	h := sha256.New()
+	for _, part := range []string{callerOf(c).String(), req.Method, req.URL.Path, req.URL.RawQuery} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// callerOf returns the id of the calling account, or the nil id for anonymous callers.
+func callerOf(c echo.Context) uuid.UUID {
+	if p, ok := core.PrincipalFrom(c.Request().Context()); ok {
+		return p.AccountID
+	}
+	return uuid.Nil
+}
+
+// responseRecorder copies the response body while it is written to the client.
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (r *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := r.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+	return nil, nil, errors.New("response writer does not support hijacking")
+}
@@ -0,0 +1,191 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"github.com/fmiskovic/new-amz/internal/core"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/core/repositories"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newRequest(key, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/order", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if key != "" {
+		req.Header.Set(HeaderIdempotencyKey, key)
+	}
+	return req
+}
+
+func created(c echo.Context) error {
+	return c.JSON(http.StatusCreated, map[string]string{"id": "1"})
+}
+
+func TestMiddleware(t *testing.T) {
+	e := echo.New()
+
+	t.Run("should store response of first request with key", func(t *testing.T) {
+		repoMock := repositories.NewIdempotencyRepositoryMock(t)
+		var reserved entities.IdempotencyKey
+		repoMock.On("Reserve", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { reserved = *args.Get(1).(*entities.IdempotencyKey) }).
+			Return(entities.IdempotencyKey{}, true, nil).Once()
+		repoMock.On("Complete", mock.Anything, mock.MatchedBy(func(key *entities.IdempotencyKey) bool {
+			return key.Key == "k1" && key.StatusCode == http.StatusCreated && strings.Contains(string(key.Body), `"id":"1"`)
+		})).Return(nil).Once()
+
+		resp := httptest.NewRecorder()
+		c := e.NewContext(newRequest("k1", `{"a":1}`), resp)
+
+		err := Middleware(repoMock, Config{TTL: time.Hour})(created)(c)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusCreated, resp.Code)
+		assert.Empty(t, resp.Header().Get(HeaderIdempotentReplayed))
+		assert.Equal(t, "k1", reserved.Key)
+		assert.Len(t, reserved.Fingerprint, 64)
+		assert.WithinDuration(t, reserved.CreatedAt.Add(time.Hour), reserved.ExpiresAt, time.Millisecond)
+	})
+
+	t.Run("should replay stored response for retry with same request", func(t *testing.T) {
+		repoMock := repositories.NewIdempotencyRepositoryMock(t)
+		fingerprint, err := requestFingerprint(e.NewContext(newRequest("k1", `{"a":1}`), httptest.NewRecorder()))
+		assert.Nil(t, err)
+		stored := entities.IdempotencyKey{
+			Key:         "k1",
+			Fingerprint: fingerprint,
+			StatusCode:  http.StatusCreated,
+			ContentType: echo.MIMEApplicationJSON,
+			Body:        []byte(`{"id":"1"}`),
+		}
+		repoMock.On("Reserve", mock.Anything, mock.Anything).Return(stored, false, nil).Once()
+
+		resp := httptest.NewRecorder()
+		c := e.NewContext(newRequest("k1", `{"a":1}`), resp)
+
+		err = Middleware(repoMock, Config{})(func(c echo.Context) error {
+			t.Fatal("request must not be processed again")
+			return nil
+		})(c)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusCreated, resp.Code)
+		assert.Equal(t, "true", resp.Header().Get(HeaderIdempotentReplayed))
+		assert.Equal(t, `{"id":"1"}`, resp.Body.String())
+	})
+
+	t.Run("should return 422 for key used with different request", func(t *testing.T) {
+		repoMock := repositories.NewIdempotencyRepositoryMock(t)
+		stored := entities.IdempotencyKey{Key: "k1", Fingerprint: "other", StatusCode: http.StatusCreated}
+		repoMock.On("Reserve", mock.Anything, mock.Anything).Return(stored, false, nil).Once()
+
+		c := e.NewContext(newRequest("k1", `{"a":2}`), httptest.NewRecorder())
+
+		err := Middleware(repoMock, Config{})(created)(c)
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusUnprocessableEntity, err.(*echo.HTTPError).Code)
+	})
+
+	t.Run("should return 409 while first request is processed", func(t *testing.T) {
+		repoMock := repositories.NewIdempotencyRepositoryMock(t)
+		fingerprint, err := requestFingerprint(e.NewContext(newRequest("k1", `{"a":1}`), httptest.NewRecorder()))
+		assert.Nil(t, err)
+		repoMock.On("Reserve", mock.Anything, mock.Anything).
+			Return(entities.IdempotencyKey{Key: "k1", Fingerprint: fingerprint}, false, nil).Once()
+
+		resp := httptest.NewRecorder()
+		c := e.NewContext(newRequest("k1", `{"a":1}`), resp)
+
+		err = Middleware(repoMock, Config{})(created)(c)
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusConflict, err.(*echo.HTTPError).Code)
+		assert.Equal(t, "1", resp.Header().Get(echo.HeaderRetryAfter))
+	})
+
+	t.Run("should release key if request fails", func(t *testing.T) {
+		repoMock := repositories.NewIdempotencyRepositoryMock(t)
+		repoMock.On("Reserve", mock.Anything, mock.Anything).Return(entities.IdempotencyKey{}, true, nil).Once()
+		repoMock.On("Release", mock.Anything, uuid.Nil, "k1").Return(nil).Once()
+
+		c := e.NewContext(newRequest("k1", `{"a":1}`), httptest.NewRecorder())
+
+		err := Middleware(repoMock, Config{})(func(c echo.Context) error {
+			return echo.NewHTTPError(http.StatusConflict, "insufficient stock")
+		})(c)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("should pass requests without key or skipped through", func(t *testing.T) {
+		repoMock := repositories.NewIdempotencyRepositoryMock(t)
+		mw := Middleware(repoMock, Config{Skipper: func(c echo.Context) bool { return c.Request().Method == http.MethodPut }})
+
+		resp := httptest.NewRecorder()
+		assert.Nil(t, mw(created)(e.NewContext(newRequest("", `{}`), resp)))
+		assert.Equal(t, http.StatusCreated, resp.Code)
+
+		req := newRequest("k1", `{}`)
+		req.Method = http.MethodPut
+		assert.Nil(t, mw(created)(e.NewContext(req, httptest.NewRecorder())))
+	})
+
+	t.Run("should reserve key of calling account", func(t *testing.T) {
+		repoMock := repositories.NewIdempotencyRepositoryMock(t)
+		principal := core.Principal{AccountID: uuid.New(), Role: entities.CUSTOMER}
+		repoMock.On("Reserve", mock.Anything, mock.MatchedBy(func(key *entities.IdempotencyKey) bool {
+			return key.AccountID == principal.AccountID && key.Key == "k1"
+		})).Return(entities.IdempotencyKey{}, true, nil).Once()
+		repoMock.On("Release", mock.Anything, principal.AccountID, "k1").Return(nil).Once()
+
+		req := newRequest("k1", `{"a":1}`)
+		c := e.NewContext(req.WithContext(core.WithPrincipal(req.Context(), principal)), httptest.NewRecorder())
+
+		err := Middleware(repoMock, Config{})(func(c echo.Context) error {
+			return echo.NewHTTPError(http.StatusConflict, "insufficient stock")
+		})(c)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("should return error if key can not be reserved", func(t *testing.T) {
+		repoMock := repositories.NewIdempotencyRepositoryMock(t)
+		repoMock.On("Reserve", mock.Anything, mock.Anything).
+			Return(entities.IdempotencyKey{}, false, errors.New("db is down")).Once()
+
+		c := e.NewContext(newRequest("k1", `{}`), httptest.NewRecorder())
+		assert.NotNil(t, Middleware(repoMock, Config{})(created)(c))
+	})
+}
+
+func TestRequestFingerprint(t *testing.T) {
+	e := echo.New()
+
+	fingerprint := func(req *http.Request) string {
+		fp, err := requestFingerprint(e.NewContext(req, httptest.NewRecorder()))
+		assert.Nil(t, err)
+		return fp
+	}
+
+	t.Run("should differ by body and restore body for handler", func(t *testing.T) {
+		req := newRequest("k1", `{"a":1}`)
+		first := fingerprint(req)
+		body, err := io.ReadAll(req.Body)
+		assert.Nil(t, err)
+		assert.Equal(t, `{"a":1}`, string(body))
+		assert.Equal(t, first, fingerprint(newRequest("k1", `{"a":1}`)))
+		assert.NotEqual(t, first, fingerprint(newRequest("k1", `{"a":2}`)))
+	})
+
+	t.Run("should differ by calling account", func(t *testing.T) {
+		principal := core.Principal{AccountID: uuid.New(), Role: entities.CUSTOMER}
+		req := newRequest("k1", `{"a":1}`)
+		req = req.WithContext(core.WithPrincipal(context.Background(), principal))
+		assert.NotEqual(t, fingerprint(req), fingerprint(newRequest("k1", `{"a":1}`)))
+	})
+}
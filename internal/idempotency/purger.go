@@ -0,0 +1,43 @@
+package idempotency
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/fmiskovic/new-amz/internal/core/repositories"
+)
+
+// Purger deletes expired idempotency keys in the background.
+// Expired keys are not replayed anymore, they are only taking up space until they are reused or purged.
+type Purger struct {
+	repo     repositories.IdempotencyRepository
+	interval time.Duration
+}
+
+// NewPurger instantiates new Purger deleting expired keys every interval.
+func NewPurger(repo repositories.IdempotencyRepository, interval time.Duration) *Purger {
+	return &Purger{repo: repo, interval: interval}
+}
+
+// Run deletes expired keys until ctx is done.
+func (p *Purger) Run(ctx context.Context) {
+	slog.Info("Starting idempotency key purger", "interval", p.interval.String())
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := p.repo.DeleteExpired(ctx, time.Now())
+		if err != nil && ctx.Err() == nil {
+			slog.Error("failed to purge expired idempotency keys", "error", err.Error())
+		} else if deleted > 0 {
+			slog.Info("Purged expired idempotency keys", "deleted", deleted)
+		}
+		select {
+		case <-ctx.Done():
+			slog.Info("Stopped idempotency key purger.")
+			return
+		case <-ticker.C:
+		}
+	}
+}
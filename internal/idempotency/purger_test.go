@@ -0,0 +1,34 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fmiskovic/new-amz/internal/core/repositories"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPurger(t *testing.T) {
+	t.Run("should delete expired keys until stopped", func(t *testing.T) {
+		repoMock := repositories.NewIdempotencyRepositoryMock(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		repoMock.On("DeleteExpired", mock.Anything, mock.Anything).Return(3, nil).Once()
+		repoMock.On("DeleteExpired", mock.Anything, mock.Anything).
+			Run(func(mock.Arguments) { cancel() }).
+			Return(0, errors.New("db is down")).Once()
+
+		done := make(chan struct{})
+		go func() {
+			NewPurger(repoMock, time.Millisecond).Run(ctx)
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("purger did not stop")
+		}
+	})
+}
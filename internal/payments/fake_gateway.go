@@ -0,0 +1,164 @@
+package payments
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+)
+
+// FakeProvider is the name of the fake payment provider.
+const FakeProvider = "fake"
+
+// Tokens standing for payment methods of the fake provider with a fixed outcome. Any other token is authorized.
+const (
+	// FakeTokenDeclined stands for a payment method the fake provider declines.
+	FakeTokenDeclined = "tok_declined"
+	// FakeTokenUnavailable stands for a payment method the fake provider fails to reach.
+	FakeTokenUnavailable = "tok_unavailable"
+)
+
+var errFakeUnavailable = entities.NewError(entities.ErrorUnavailable, "payment provider is unavailable")
+
+// FakeGateway is a deterministic in-process implementation of core.PaymentGateway for development and tests.
+// It keeps payments in memory and checks operations the way a real provider does, references are derived
+// from payment ids, so the same payment always gets the same reference. Outcomes of operations are kept
+// by their idempotency keys, operations sent again with a key are answered with the outcome of the first one.
+type FakeGateway struct {
+	mu       sync.Mutex
+	payments map[string]*fakePayment
+	outcomes map[string]error
+}
+
+type fakePayment struct {
+	authorized entities.Money
+	captured   int64
+	refunded   int64
+	voided     bool
+}
+
+// NewFakeGateway instantiates new FakeGateway.
+func NewFakeGateway() *FakeGateway {
+	return &FakeGateway{payments: make(map[string]*fakePayment), outcomes: make(map[string]error)}
+}
+
+// Provider returns the name of the fake provider.
+func (g *FakeGateway) Provider() string {
+	return FakeProvider
+}
+
+// Authorize authorizes the amount, unless the token is FakeTokenDeclined or FakeTokenUnavailable.
+func (g *FakeGateway) Authorize(_ context.Context, paymentId uuid.UUID, amount entities.Money, token string) (string, error) {
+	switch token {
+	case FakeTokenDeclined:
+		return "", fmt.Errorf("%w: insufficient funds", entities.ErrorPaymentDeclined)
+	case FakeTokenUnavailable:
+		return "", errFakeUnavailable
+	}
+	if amount.Amount <= 0 {
+		return "", fmt.Errorf("%w: invalid amount %s", entities.ErrorPaymentRejected, amount)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	reference := FakeReference(paymentId)
+	if _, ok := g.payments[reference]; !ok {
+		g.payments[reference] = &fakePayment{authorized: amount}
+	}
+	return reference, nil
+}
+
+// Capture captures the amount of the authorized payment once.
+func (g *FakeGateway) Capture(_ context.Context, reference string, amount entities.Money, key string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.once(key, func() error { return g.capture(reference, amount) })
+}
+
+func (g *FakeGateway) capture(reference string, amount entities.Money) error {
+	p, err := g.payment(reference, amount)
+	if err != nil {
+		return err
+	}
+	if p.voided || p.captured > 0 || amount.Amount <= 0 || amount.Amount > p.authorized.Amount {
+		return fmt.Errorf("%w: can not capture %s", entities.ErrorPaymentRejected, amount)
+	}
+	p.captured = amount.Amount
+	return nil
+}
+
+// Void voids the payment, unless it has been captured.
+func (g *FakeGateway) Void(_ context.Context, reference string, key string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.once(key, func() error { return g.void(reference) })
+}
+
+func (g *FakeGateway) void(reference string) error {
+	p, ok := g.payments[reference]
+	if !ok {
+		return fmt.Errorf("%w: unknown payment %s", entities.ErrorPaymentRejected, reference)
+	}
+	if p.captured > 0 {
+		return fmt.Errorf("%w: can not void captured payment", entities.ErrorPaymentRejected)
+	}
+	p.voided = true
+	return nil
+}
+
+// Refund refunds the amount, up to the captured amount not refunded yet.
+func (g *FakeGateway) Refund(_ context.Context, reference string, amount entities.Money, key string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.once(key, func() error { return g.refund(reference, amount) })
+}
+
+func (g *FakeGateway) refund(reference string, amount entities.Money) error {
+	p, err := g.payment(reference, amount)
+	if err != nil {
+		return err
+	}
+	if amount.Amount <= 0 || amount.Amount > p.captured-p.refunded {
+		return fmt.Errorf("%w: can not refund %s", entities.ErrorPaymentRejected, amount)
+	}
+	p.refunded += amount.Amount
+	return nil
+}
+
+// once carries out the operation, unless an operation with the key has been carried out already,
+// and returns the outcome of the first operation with the key.
+func (g *FakeGateway) once(key string, operation func() error) error {
+	if err, ok := g.outcomes[key]; ok {
+		return err
+	}
+	err := operation()
+	if key != "" {
+		g.outcomes[key] = err
+	}
+	return err
+}
+
+func (g *FakeGateway) payment(reference string, amount entities.Money) (*fakePayment, error) {
+	p, ok := g.payments[reference]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown payment %s", entities.ErrorPaymentRejected, reference)
+	}
+	if amount.Currency != p.authorized.Currency {
+		return nil, fmt.Errorf("%w: %s and %s", entities.ErrorCurrencyMismatch, amount.Currency, p.authorized.Currency)
+	}
+	return p, nil
+}
+
+// FakeReference returns the reference the fake provider gives to the payment.
+func FakeReference(paymentId uuid.UUID) string {
+	sum := sha256.Sum256(paymentId[:])
+	return "fake_" + hex.EncodeToString(sum[:12])
+}
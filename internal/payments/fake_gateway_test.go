@@ -0,0 +1,76 @@
+package payments
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeGateway(t *testing.T) {
+	ctx := context.Background()
+	amount := entities.NewMoney(2500, entities.EUR)
+
+	t.Run("should authorize, capture and refund payment", func(t *testing.T) {
+		g := NewFakeGateway()
+		paymentId := uuid.New()
+
+		ref, err := g.Authorize(ctx, paymentId, amount, "tok_visa")
+		assert.Nil(t, err)
+		assert.Equal(t, FakeReference(paymentId), ref)
+
+		again, err := g.Authorize(ctx, paymentId, amount, "tok_visa")
+		assert.Nil(t, err)
+		assert.Equal(t, ref, again)
+
+		assert.Nil(t, g.Capture(ctx, ref, amount, "capture-1"))
+		assert.ErrorIs(t, g.Capture(ctx, ref, amount, "capture-2"), entities.ErrorPaymentRejected)
+		assert.ErrorIs(t, g.Void(ctx, ref, "void-1"), entities.ErrorPaymentRejected)
+
+		assert.Nil(t, g.Refund(ctx, ref, entities.NewMoney(1000, entities.EUR), "refund-1"))
+		assert.ErrorIs(t, g.Refund(ctx, ref, entities.NewMoney(1501, entities.EUR), "refund-2"), entities.ErrorPaymentRejected)
+		assert.Nil(t, g.Refund(ctx, ref, entities.NewMoney(1500, entities.EUR), "refund-3"))
+	})
+
+	t.Run("should carry out operation sent again with the same key once", func(t *testing.T) {
+		g := NewFakeGateway()
+		ref, err := g.Authorize(ctx, uuid.New(), amount, "tok_visa")
+		assert.Nil(t, err)
+		assert.Nil(t, g.Capture(ctx, ref, amount, "capture-1"))
+
+		// when
+		assert.Nil(t, g.Refund(ctx, ref, entities.NewMoney(1500, entities.EUR), "refund-1"))
+		assert.Nil(t, g.Refund(ctx, ref, entities.NewMoney(1500, entities.EUR), "refund-1"))
+
+		// then only 1000 are left to refund
+		assert.ErrorIs(t, g.Refund(ctx, ref, entities.NewMoney(1001, entities.EUR), "refund-2"), entities.ErrorPaymentRejected)
+		assert.Nil(t, g.Refund(ctx, ref, entities.NewMoney(1000, entities.EUR), "refund-3"))
+	})
+
+	t.Run("should void authorized payment", func(t *testing.T) {
+		g := NewFakeGateway()
+		ref, err := g.Authorize(ctx, uuid.New(), amount, "tok_visa")
+		assert.Nil(t, err)
+
+		assert.Nil(t, g.Void(ctx, ref, "void-1"))
+		assert.ErrorIs(t, g.Capture(ctx, ref, amount, "capture-1"), entities.ErrorPaymentRejected)
+	})
+
+	t.Run("should decline or fail payments of special tokens", func(t *testing.T) {
+		g := NewFakeGateway()
+
+		_, err := g.Authorize(ctx, uuid.New(), amount, FakeTokenDeclined)
+		assert.ErrorIs(t, err, entities.ErrorPaymentDeclined)
+
+		_, err = g.Authorize(ctx, uuid.New(), amount, FakeTokenUnavailable)
+		assert.ErrorIs(t, err, entities.ErrorUnavailable)
+	})
+
+	t.Run("should reject operations on unknown payments", func(t *testing.T) {
+		g := NewFakeGateway()
+		assert.ErrorIs(t, g.Capture(ctx, "fake_unknown", amount, "capture-1"), entities.ErrorPaymentRejected)
+		assert.ErrorIs(t, g.Refund(ctx, "fake_unknown", amount, "refund-1"), entities.ErrorPaymentRejected)
+	})
+}
@@ -0,0 +1,40 @@
+package payments
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/fmiskovic/new-amz/internal/signature"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// HeaderSignature carries the HMAC-SHA256 signature of a webhook, e.g. "sha256=5f0c...", see signature.Sign.
+	HeaderSignature = "X-Payment-Signature"
+	// HeaderTimestamp carries the unix time a webhook was signed at.
+	HeaderTimestamp = "X-Payment-Timestamp"
+)
+
+// VerifySignature is a middleware rejecting webhooks which are not signed with the secret with 401,
+// signatures older than signature.MaxAge are rejected as replays.
+// The body is restored for the handler.
+func VerifySignature(secret []byte) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "failed to read webhook body").SetInternal(err)
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+
+			err = signature.Verify(secret, req.Header.Get(HeaderTimestamp), req.Header.Get(HeaderSignature), body, time.Now())
+			if err != nil {
+				return echo.NewHTTPError(http.StatusUnauthorized, err.Error()).SetInternal(err)
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package payments
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fmiskovic/new-amz/internal/signature"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifySignature(t *testing.T) {
+	secret := []byte("webhook-secret")
+	body := `{"type":"payment.captured"}`
+	e := echo.New()
+	handler := VerifySignature(secret)(func(c echo.Context) error {
+		restored, err := io.ReadAll(c.Request().Body)
+		assert.Nil(t, err)
+		assert.Equal(t, body, string(restored))
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	t.Run("should pass signed webhook to handler", func(t *testing.T) {
+		now := time.Now()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+		req.Header.Set(HeaderSignature, signature.Sign(secret, now, []byte(body)))
+		resp := httptest.NewRecorder()
+
+		assert.Nil(t, handler(e.NewContext(req, resp)))
+		assert.Equal(t, http.StatusNoContent, resp.Code)
+	})
+
+	t.Run("should reject unsigned webhook with 401", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+
+		err := handler(e.NewContext(req, httptest.NewRecorder()))
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusUnauthorized, err.(*echo.HTTPError).Code)
+	})
+}
@@ -2,10 +2,10 @@ package repositories
 
 import (
 	"context"
-	"database/sql"
 	"github.com/fmiskovic/new-amz/internal/core/entities"
 	"github.com/google/uuid"
 	"github.com/uptrace/bun"
+	"time"
 )
 
 // AccountRepository is the implementation of core repositories.AccountRepository interface.
@@ -22,9 +22,9 @@ func NewAccountRepository(db *bun.DB) AccountRepository {
 func (repo AccountRepository) GetById(ctx context.Context, id uuid.UUID) (entities.Account, error) {
 	var acc = new(entities.Account)
 
-	err := repo.db.NewSelect().Model(acc).Where("? = ?", bun.Ident("id"), id).Scan(ctx)
+	err := conn(ctx, repo.db).NewSelect().Model(acc).Where("? = ?", bun.Ident("id"), id).Scan(ctx)
 	if err != nil {
-		return *acc, err
+		return *acc, dbError(err)
 	}
 
 	return *acc, nil
@@ -34,26 +34,105 @@ func (repo AccountRepository) GetById(ctx context.Context, id uuid.UUID) (entiti
 func (repo AccountRepository) GetByEmail(ctx context.Context, email string) (entities.Account, error) {
 	var u = new(entities.Account)
 
-	err := repo.db.NewSelect().
+	err := conn(ctx, repo.db).NewSelect().
 		Model(u).
 		Where("email = ?", email).
 		Scan(ctx)
 
 	if err != nil {
-		return entities.Account{}, err
+		return entities.Account{}, dbError(err)
 	}
 
 	return *u, nil
 }
 
-// Create persists new account entity.
+// Create persists new account entity together with its entities.EVENT_ACCOUNT_CREATED event.
 func (repo AccountRepository) Create(ctx context.Context, u *entities.Account) error {
 	if u == nil {
 		return ErrNilEntity
 	}
 
-	return repo.db.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
+	err := runInTx(ctx, repo.db, func(ctx context.Context, tx bun.Tx) error {
 		_, err := tx.NewInsert().Model(u).Exec(ctx)
-		return err
+		if isPgError(err, pgUniqueViolation) {
+			return entities.ErrorEmailNotUnique
+		}
+		if err != nil {
+			return err
+		}
+
+		event, err := entities.NewAccountCreated(*u)
+		if err != nil {
+			return err
+		}
+		return storeEvent(ctx, tx, event)
 	})
+	return dbError(err)
+}
+
+// Update persists changes of account profile, if the account is still in the version of u.
+// Password, role and creation time of the account are never changed by update.
+// It returns ErrNotFound if the account does not exist or is deleted, entities.ErrorVersionMismatch
+// if it is in another version and entities.ErrorEmailNotUnique if the email is taken by another account.
+func (repo AccountRepository) Update(ctx context.Context, u *entities.Account) error {
+	if u == nil {
+		return ErrNilEntity
+	}
+
+	u.UpdatedAt = time.Now()
+	res, err := conn(ctx, repo.db).NewUpdate().
+		Model(u).
+		Column("updated_at", "version", "email", "full_name", "date_of_birth", "location", "gender").
+		WherePK().
+		Apply(compareAndSwap(u.Version)).
+		Returning("*").
+		Exec(ctx)
+	if isPgError(err, pgUniqueViolation) {
+		return entities.ErrorEmailNotUnique
+	}
+	if err != nil {
+		return dbError(err)
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return versionError(ctx, conn(ctx, repo.db), (*entities.Account)(nil), u.ID, u.Version)
+	}
+	return nil
+}
+
+// DeleteById soft deletes account by specified id, if it is in the version or the version is zero.
+// It returns ErrNotFound if the account does not exist or is already deleted
+// and entities.ErrorVersionMismatch if it is in another version.
+func (repo AccountRepository) DeleteById(ctx context.Context, id uuid.UUID, version int64) error {
+	res, err := conn(ctx, repo.db).NewDelete().
+		Model((*entities.Account)(nil)).
+		Where("id = ?", id).
+		Apply(whereVersion(version)).
+		Exec(ctx)
+	if err != nil {
+		return dbError(err)
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return versionError(ctx, conn(ctx, repo.db), (*entities.Account)(nil), id, version)
+	}
+	return nil
+}
+
+// ForceDeleteById permanently deletes account by specified id, including soft deleted accounts,
+// if it is in the version or the version is zero. Orders of the account are deleted together with it.
+// It returns ErrNotFound if the account does not exist and entities.ErrorVersionMismatch if it is in another version.
+func (repo AccountRepository) ForceDeleteById(ctx context.Context, id uuid.UUID, version int64) error {
+	res, err := conn(ctx, repo.db).NewDelete().
+		Model((*entities.Account)(nil)).
+		WhereAllWithDeleted().
+		Where("id = ?", id).
+		Apply(whereVersion(version)).
+		ForceDelete().
+		Exec(ctx)
+	if err != nil {
+		return dbError(err)
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return versionError(ctx, conn(ctx, repo.db), (*entities.Account)(nil), id, version)
+	}
+	return nil
 }
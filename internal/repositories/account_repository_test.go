@@ -79,3 +79,114 @@ func (s *RepositoryTestSuite) TestCreateAccount() {
 		s.NotNil(err)
 	})
 }
+
+func (s *RepositoryTestSuite) TestUpdateAccount() {
+	repo := NewAccountRepository(s.testDb.BunDb)
+
+	s.Run("should update account profile", func() {
+		// given
+		acc := entities.NewAccountBuilder().Email("update@mail.com").FullName("Before").Build()
+		s.Nil(repo.Create(s.testDb.Ctx, acc))
+
+		// when
+		acc.FullName = "After"
+		acc.Location = "Vienna/AUT"
+		err := repo.Update(s.testDb.Ctx, acc)
+
+		// then
+		s.Nil(err)
+		updated, err := repo.GetById(s.testDb.Ctx, acc.ID)
+		s.Nil(err)
+		s.Equal("After", updated.FullName)
+		s.Equal("Vienna/AUT", updated.Location)
+		s.Equal(entities.CUSTOMER, updated.Role)
+	})
+
+	s.Run("should return conflict error when email is taken", func() {
+		// given
+		acc := entities.NewAccountBuilder().Email("taken@mail.com").Build()
+		s.Nil(repo.Create(s.testDb.Ctx, acc))
+
+		// when
+		acc.Email = "john@smith.com"
+		err := repo.Update(s.testDb.Ctx, acc)
+
+		// then
+		s.ErrorIs(err, entities.ErrorEmailNotUnique)
+	})
+
+	s.Run("should return error when account does not exist", func() {
+		// given
+		acc := entities.NewAccountBuilder().Email("ghost@mail.com").Build()
+		// when
+		err := repo.Update(s.testDb.Ctx, acc)
+		// then
+		s.ErrorIs(err, ErrNotFound)
+	})
+}
+
+func (s *RepositoryTestSuite) TestCreateAccountWithTakenEmail() {
+	repo := NewAccountRepository(s.testDb.BunDb)
+
+	// given
+	acc := entities.NewAccountBuilder().Email("john@smith.com").Build()
+	// when
+	err := repo.Create(s.testDb.Ctx, acc)
+	// then
+	s.ErrorIs(err, entities.ErrorEmailNotUnique)
+}
+
+func (s *RepositoryTestSuite) TestDeleteAccount() {
+	repo := NewAccountRepository(s.testDb.BunDb)
+
+	s.Run("should soft delete account and release its email", func() {
+		// given
+		acc := entities.NewAccountBuilder().Email("closing@mail.com").Build()
+		s.Nil(repo.Create(s.testDb.Ctx, acc))
+
+		// when
+		err := repo.DeleteById(s.testDb.Ctx, acc.ID, 0)
+
+		// then
+		s.Nil(err)
+		_, err = repo.GetById(s.testDb.Ctx, acc.ID)
+		s.NotNil(err)
+		_, err = repo.GetByEmail(s.testDb.Ctx, acc.Email)
+		s.NotNil(err)
+		exists, err := s.testDb.BunDb.NewSelect().Model((*entities.Account)(nil)).
+			WhereAllWithDeleted().Where("id = ?", acc.ID).Exists(s.testDb.Ctx)
+		s.Nil(err)
+		s.True(exists)
+
+		reopened := entities.NewAccountBuilder().Email(acc.Email).Build()
+		s.Nil(repo.Create(s.testDb.Ctx, reopened))
+	})
+
+	s.Run("should return error when account is already deleted", func() {
+		// given
+		acc := entities.NewAccountBuilder().Email("twice@mail.com").Build()
+		s.Nil(repo.Create(s.testDb.Ctx, acc))
+		s.Nil(repo.DeleteById(s.testDb.Ctx, acc.ID, 0))
+		// when
+		err := repo.DeleteById(s.testDb.Ctx, acc.ID, 0)
+		// then
+		s.ErrorIs(err, ErrNotFound)
+	})
+
+	s.Run("should permanently delete soft deleted account", func() {
+		// given
+		acc := entities.NewAccountBuilder().Email("purge@mail.com").Build()
+		s.Nil(repo.Create(s.testDb.Ctx, acc))
+		s.Nil(repo.DeleteById(s.testDb.Ctx, acc.ID, 0))
+
+		// when
+		err := repo.ForceDeleteById(s.testDb.Ctx, acc.ID, 0)
+
+		// then
+		s.Nil(err)
+		exists, err := s.testDb.BunDb.NewSelect().Model((*entities.Account)(nil)).
+			WhereAllWithDeleted().Where("id = ?", acc.ID).Exists(s.testDb.Ctx)
+		s.Nil(err)
+		s.False(exists)
+	})
+}
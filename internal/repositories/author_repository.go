@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"context"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+// AuthorRepository is the implementation of core repositories.AuthorRepository interface.
+type AuthorRepository struct {
+	bunDb *bun.DB
+}
+
+// NewAuthorRepository instantiates new AuthorRepository.
+func NewAuthorRepository(db *bun.DB) AuthorRepository {
+	return AuthorRepository{db}
+}
+
+// GetById returns author by specified id.
+func (repo AuthorRepository) GetById(ctx context.Context, id uuid.UUID) (entities.Author, error) {
+	author := new(entities.Author)
+
+	err := conn(ctx, repo.bunDb).NewSelect().Model(author).Where("id = ?", id).Scan(ctx)
+	if err != nil {
+		return *author, dbError(err)
+	}
+
+	return *author, nil
+}
+
+// GetPage respond with a page of authors.
+func (repo AuthorRepository) GetPage(ctx context.Context, p entities.Pageable) (entities.Page[entities.Author], error) {
+	var authors []entities.Author
+	if p.Keyset {
+		return keysetPage(ctx, conn(ctx, repo.bunDb).NewSelect().Model(&authors), &authors, entities.AuthorSortable, p)
+	}
+	return offsetPage(ctx, conn(ctx, repo.bunDb).NewSelect().Model(&authors), &authors, entities.AuthorSortable, p)
+}
+
+// Create persists new author entity.
+func (repo AuthorRepository) Create(ctx context.Context, author *entities.Author) error {
+	if author == nil {
+		return ErrNilEntity
+	}
+
+	_, err := conn(ctx, repo.bunDb).NewInsert().Model(author).Exec(ctx)
+	return dbError(err)
+}
+
+// Update persists changes of existing author entity in the version it was read in, see entities.Entity.
+// It returns ErrNotFound if the author does not exist and entities.ErrorVersionMismatch if it was changed meanwhile.
+func (repo AuthorRepository) Update(ctx context.Context, author *entities.Author) error {
+	if author == nil {
+		return ErrNilEntity
+	}
+
+	author.UpdatedAt = time.Now()
+	res, err := conn(ctx, repo.bunDb).NewUpdate().
+		Model(author).
+		Column("updated_at", "version", "name", "biography").
+		WherePK().
+		Apply(compareAndSwap(author.Version)).
+		Returning("created_at, version").
+		Exec(ctx)
+	if err != nil {
+		return dbError(err)
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return versionError(ctx, conn(ctx, repo.bunDb), (*entities.Author)(nil), author.ID, author.Version)
+	}
+	return nil
+}
+
+// DeleteById deletes author by specified id, if it is still in the version or the version is zero.
+// Authors credited on any item can not be deleted and entities.ErrorAuthorHasItems is returned instead.
+func (repo AuthorRepository) DeleteById(ctx context.Context, id uuid.UUID, version int64) error {
+	res, err := conn(ctx, repo.bunDb).NewDelete().
+		Model((*entities.Author)(nil)).
+		Where("id = ?", id).
+		Apply(whereVersion(version)).
+		Exec(ctx)
+	if err != nil {
+		if isPgError(err, pgForeignKeyViolation) {
+			return entities.ErrorAuthorHasItems
+		}
+		return dbError(err)
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return versionError(ctx, conn(ctx, repo.bunDb), (*entities.Author)(nil), id, version)
+	}
+	return nil
+}
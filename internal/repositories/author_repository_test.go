@@ -0,0 +1,135 @@
+package repositories
+
+import (
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+)
+
+var (
+	janeWriterId        = uuid.MustParse("250cea28-b2b0-4051-9eb6-9a99e451af01")
+	joeCoauthorId       = uuid.MustParse("250cea28-b2b0-4051-9eb6-9a99e451af02")
+	unpublishedAuthorId = uuid.MustParse("250cea28-b2b0-4051-9eb6-9a99e451af03")
+)
+
+func (s *RepositoryTestSuite) TestGetAuthorById() {
+	repo := NewAuthorRepository(s.testDb.BunDb)
+
+	s.Run("should return author by id", func() {
+		// when
+		author, err := repo.GetById(s.testDb.Ctx, janeWriterId)
+		// then
+		s.Nil(err)
+		s.Equal("Jane Writer", author.Name)
+		s.Equal("Writes cool books", author.Biography)
+	})
+
+	s.Run("should return error if author not found", func() {
+		// when
+		_, err := repo.GetById(s.testDb.Ctx, uuid.New())
+		// then
+		s.ErrorIs(err, ErrNotFound)
+	})
+}
+
+func (s *RepositoryTestSuite) TestGetAuthorsPage() {
+	repo := NewAuthorRepository(s.testDb.BunDb)
+
+	s.Run("should return page of authors sorted by name", func() {
+		// given
+		pageRequest := entities.Pageable{Size: 2, Sort: entities.NewSort(entities.NewSortOrder(entities.WithProperty("name"), entities.WithDirection(entities.ASC)))}
+		// when
+		page, err := repo.GetPage(s.testDb.Ctx, pageRequest)
+		// then
+		s.Nil(err)
+		s.Equal(3, *page.TotalElements)
+		s.Len(page.Elements, 2)
+		s.Equal("Jane Writer", page.Elements[0].Name)
+		s.Equal("Joe Coauthor", page.Elements[1].Name)
+		s.True(page.HasNext)
+	})
+}
+
+func (s *RepositoryTestSuite) TestManageAuthor() {
+	repo := NewAuthorRepository(s.testDb.BunDb)
+
+	s.Run("should create, update and delete author", func() {
+		// given
+		author := entities.NewAuthorBuilder().Name("New Author").Build()
+
+		// when
+		err := repo.Create(s.testDb.Ctx, author)
+		// then
+		s.Nil(err)
+
+		// when
+		author.Name = "Renamed Author"
+		author.Biography = "Wrote a book at last"
+		err = repo.Update(s.testDb.Ctx, author)
+		// then
+		s.Nil(err)
+		updated, err := repo.GetById(s.testDb.Ctx, author.ID)
+		s.Nil(err)
+		s.Equal("Renamed Author", updated.Name)
+		s.Equal("Wrote a book at last", updated.Biography)
+
+		// when
+		err = repo.DeleteById(s.testDb.Ctx, author.ID, 0)
+		// then
+		s.Nil(err)
+		_, err = repo.GetById(s.testDb.Ctx, author.ID)
+		s.ErrorIs(err, ErrNotFound)
+	})
+
+	s.Run("should update and delete author only in expected version", func() {
+		// given
+		author := entities.NewAuthorBuilder().Name("Versioned Author").Build()
+		s.Require().Nil(repo.Create(s.testDb.Ctx, author))
+		s.Equal(int64(1), author.Version)
+		stale := *author
+
+		// when
+		author.Biography = "First edit"
+		err := repo.Update(s.testDb.Ctx, author)
+		// then
+		s.Nil(err)
+		s.Equal(int64(2), author.Version)
+
+		// when updating stale copy
+		stale.Biography = "Lost edit"
+		err = repo.Update(s.testDb.Ctx, &stale)
+		// then
+		s.ErrorIs(err, entities.ErrorVersionMismatch)
+		s.ErrorIs(err, entities.ErrorPreconditionFailed)
+		found, err := repo.GetById(s.testDb.Ctx, author.ID)
+		s.Nil(err)
+		s.Equal("First edit", found.Biography)
+		s.Equal(int64(2), found.Version)
+
+		// when deleting stale version
+		err = repo.DeleteById(s.testDb.Ctx, author.ID, 1)
+		// then
+		s.ErrorIs(err, entities.ErrorVersionMismatch)
+
+		// when
+		err = repo.DeleteById(s.testDb.Ctx, author.ID, 2)
+		// then
+		s.Nil(err)
+		s.ErrorIs(repo.DeleteById(s.testDb.Ctx, author.ID, 2), ErrNotFound)
+	})
+
+	s.Run("should return error when updating non-existing author", func() {
+		// when
+		err := repo.Update(s.testDb.Ctx, entities.NewAuthorBuilder().Name("Ghost").Build())
+		// then
+		s.ErrorIs(err, ErrNotFound)
+	})
+
+	s.Run("should not delete author credited on items", func() {
+		// when
+		err := repo.DeleteById(s.testDb.Ctx, janeWriterId, 0)
+		// then
+		s.ErrorIs(err, entities.ErrorAuthorHasItems)
+		_, err = repo.GetById(s.testDb.Ctx, janeWriterId)
+		s.Nil(err)
+	})
+}
@@ -0,0 +1,273 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+// Names of the constraints violated by cart changes.
+const (
+	cartItemCartConstraint = "fk_cart"
+	cartItemItemConstraint = "fk_item"
+)
+
+// CartRepository is the implementation of core repositories.CartRepository interface.
+type CartRepository struct {
+	bunDb *bun.DB
+}
+
+// NewCartRepository instantiates new CartRepository.
+func NewCartRepository(db *bun.DB) CartRepository {
+	return CartRepository{db}
+}
+
+// GetById returns cart by specified id with its items, in the order they were added to the cart.
+func (repo CartRepository) GetById(ctx context.Context, id uuid.UUID) (entities.Cart, error) {
+	cart := new(entities.Cart)
+
+	err := withCartItems(conn(ctx, repo.bunDb).NewSelect().Model(cart)).Where("?TableAlias.id = ?", id).Scan(ctx)
+	if err != nil {
+		return entities.Cart{}, dbError(err)
+	}
+
+	return *cart, nil
+}
+
+// GetByAccount returns the cart of the account with its items, an empty cart is created if the account has none yet.
+// It returns ErrNotFound if the account does not exist.
+func (repo CartRepository) GetByAccount(ctx context.Context, accountId uuid.UUID) (entities.Cart, error) {
+	cart := new(entities.Cart)
+
+	err := withCartItems(conn(ctx, repo.bunDb).NewSelect().Model(cart)).Where("?TableAlias.account_id = ?", accountId).Scan(ctx)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return *cart, dbError(err)
+	}
+
+	// concurrent requests may both find the cart missing, the unique account index lets only one create it
+	_, err = conn(ctx, repo.bunDb).NewInsert().
+		Model(entities.NewCartBuilder().AccountID(accountId).Build()).
+		On("CONFLICT (account_id) DO NOTHING").
+		Exec(ctx)
+	if err != nil {
+		if isPgError(err, pgForeignKeyViolation) {
+			return entities.Cart{}, ErrNotFound
+		}
+		return entities.Cart{}, dbError(err)
+	}
+
+	cart = new(entities.Cart)
+	err = withCartItems(conn(ctx, repo.bunDb).NewSelect().Model(cart)).Where("?TableAlias.account_id = ?", accountId).Scan(ctx)
+	return *cart, dbError(err)
+}
+
+// Create persists new cart entity, carts without an account are guest carts.
+func (repo CartRepository) Create(ctx context.Context, cart *entities.Cart) error {
+	if cart == nil {
+		return ErrNilEntity
+	}
+
+	_, err := conn(ctx, repo.bunDb).NewInsert().Model(cart).Exec(ctx)
+	if isPgError(err, pgForeignKeyViolation) {
+		return ErrNotFound
+	}
+	return dbError(err)
+}
+
+// AddItem adds quantity units of the item to the cart, on top of the units already in the cart.
+// It returns entities.InsufficientStockError if the item does not have enough units in stock for the resulting quantity.
+func (repo CartRepository) AddItem(ctx context.Context, cartId uuid.UUID, itemId uuid.UUID, quantity int) error {
+	return repo.putItem(ctx, cartId, itemId, quantity, "quantity = ci.quantity + EXCLUDED.quantity")
+}
+
+// SetItemQuantity sets quantity of the item in the cart, the item is added if it is not in the cart yet.
+// It returns entities.InsufficientStockError if the item does not have enough units in stock.
+func (repo CartRepository) SetItemQuantity(ctx context.Context, cartId uuid.UUID, itemId uuid.UUID, quantity int) error {
+	return repo.putItem(ctx, cartId, itemId, quantity, "quantity = EXCLUDED.quantity")
+}
+
+// putItem inserts the cart item, or updates its quantity by set if the item is already in the cart,
+// and checks the resulting quantity against stock of the item.
+// It returns ErrNotFound if the cart does not exist and entities.ErrorItemNotFound if the item does not.
+func (repo CartRepository) putItem(ctx context.Context, cartId uuid.UUID, itemId uuid.UUID, quantity int, set string) error {
+	now := time.Now()
+	err := runInTx(ctx, repo.bunDb, func(ctx context.Context, tx bun.Tx) error {
+		// the cart is locked, so its items do not change while it is checked out
+		if err := touchCart(ctx, tx, cartId, now); err != nil {
+			return err
+		}
+
+		cartItem := &entities.CartItem{CartID: cartId, ItemID: itemId, Quantity: quantity, CreatedAt: now, UpdatedAt: now}
+		_, err := tx.NewInsert().
+			Model(cartItem).
+			On("CONFLICT (cart_id, item_id) DO UPDATE").
+			Set(set).
+			Set("updated_at = EXCLUDED.updated_at").
+			Returning("quantity").
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		var stock int
+		err = tx.NewSelect().Model((*entities.Item)(nil)).Column("stock").Where("id = ?", itemId).Scan(ctx, &stock)
+		if err != nil {
+			return err
+		}
+		if stock < cartItem.Quantity {
+			return entities.InsufficientStockError{ItemID: itemId, Requested: cartItem.Quantity, Available: stock}
+		}
+		return nil
+	})
+	return cartError(err)
+}
+
+// RemoveItem removes the item from the cart.
+// It returns ErrNotFound if the item is not in the cart.
+func (repo CartRepository) RemoveItem(ctx context.Context, cartId uuid.UUID, itemId uuid.UUID) error {
+	err := runInTx(ctx, repo.bunDb, func(ctx context.Context, tx bun.Tx) error {
+		if err := touchCart(ctx, tx, cartId, time.Now()); err != nil {
+			return err
+		}
+
+		res, err := tx.NewDelete().
+			Model((*entities.CartItem)(nil)).
+			Where("cart_id = ?", cartId).
+			Where("item_id = ?", itemId).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+	return dbError(err)
+}
+
+// Clear removes all items from the cart.
+// It returns ErrNotFound if the cart does not exist.
+func (repo CartRepository) Clear(ctx context.Context, cartId uuid.UUID) error {
+	err := runInTx(ctx, repo.bunDb, func(ctx context.Context, tx bun.Tx) error {
+		if err := touchCart(ctx, tx, cartId, time.Now()); err != nil {
+			return err
+		}
+
+		_, err := tx.NewDelete().Model((*entities.CartItem)(nil)).Where("cart_id = ?", cartId).Exec(ctx)
+		return err
+	})
+	return dbError(err)
+}
+
+// Merge moves items of the guest cart fromId into the cart toId and deletes the guest cart.
+// Quantities of items found in both carts are summed up, stock is checked again at checkout.
+// It returns ErrNotFound if either of the carts does not exist or fromId is not a guest cart.
+func (repo CartRepository) Merge(ctx context.Context, fromId uuid.UUID, toId uuid.UUID) error {
+	err := runInTx(ctx, repo.bunDb, func(ctx context.Context, tx bun.Tx) error {
+		// the guest cart is locked, so concurrent merges of the same cart do not merge it twice
+		var locked uuid.UUID
+		err := tx.NewSelect().
+			Model((*entities.Cart)(nil)).
+			Column("id").
+			Where("id = ?", fromId).
+			Where("account_id IS NULL").
+			For("UPDATE").
+			Scan(ctx, &locked)
+		if err != nil {
+			return err
+		}
+
+		if err = touchCart(ctx, tx, toId, time.Now()); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `INSERT INTO cart_items AS ci (cart_id, item_id, quantity)
+			SELECT ?, item_id, quantity FROM cart_items WHERE cart_id = ?
+			ON CONFLICT (cart_id, item_id) DO UPDATE
+			SET quantity = ci.quantity + EXCLUDED.quantity, updated_at = CURRENT_TIMESTAMP`, toId, fromId)
+		if err != nil {
+			return err
+		}
+
+		// items of the guest cart are deleted with it
+		_, err = tx.NewDelete().Model((*entities.Cart)(nil)).Where("id = ?", fromId).Exec(ctx)
+		return err
+	})
+	return dbError(err)
+}
+
+// Checkout locks the cart, calls place with the cart and its items and clears the cart if place succeeds,
+// all of it in one transaction. The context passed to place carries the transaction, so repositories called
+// by place join it and their changes are rolled back together with the cart if anything fails.
+// It returns ErrNotFound if the cart does not exist and the error of place if it fails.
+func (repo CartRepository) Checkout(ctx context.Context, cartId uuid.UUID, place func(ctx context.Context, cart entities.Cart) error) error {
+	err := runInTx(ctx, repo.bunDb, func(ctx context.Context, tx bun.Tx) error {
+		cart := new(entities.Cart)
+		err := tx.NewSelect().Model(cart).Where("id = ?", cartId).For("UPDATE").Scan(ctx)
+		if err != nil {
+			return err
+		}
+
+		err = tx.NewSelect().
+			Model(&cart.Items).
+			Relation("Item").
+			Where("cart_id = ?", cartId).
+			OrderExpr("?TableAlias.created_at ASC, ?TableAlias.item_id ASC").
+			Scan(ctx)
+		if err != nil {
+			return err
+		}
+
+		if err = place(ctx, *cart); err != nil {
+			return err
+		}
+
+		_, err = tx.NewDelete().Model((*entities.CartItem)(nil)).Where("cart_id = ?", cartId).Exec(ctx)
+		return err
+	})
+	return dbError(err)
+}
+
+// withCartItems selects the cart together with its items and the items they refer to.
+func withCartItems(q *bun.SelectQuery) *bun.SelectQuery {
+	return q.
+		Relation("Items", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.OrderExpr("?TableAlias.created_at ASC, ?TableAlias.item_id ASC")
+		}).
+		Relation("Items.Item")
+}
+
+// touchCart updates modification time and version of the cart and locks it until the end of the transaction.
+// It returns ErrNotFound if the cart does not exist.
+func touchCart(ctx context.Context, tx bun.Tx, cartId uuid.UUID, now time.Time) error {
+	res, err := tx.NewUpdate().
+		Model((*entities.Cart)(nil)).
+		Set("updated_at = ?", now).
+		Set("version = version + 1").
+		Where("id = ?", cartId).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// cartError translates constraint violations of cart changes into domain errors.
+func cartError(err error) error {
+	switch violatedConstraint(err, pgForeignKeyViolation) {
+	case cartItemItemConstraint:
+		return entities.ErrorItemNotFound
+	case cartItemCartConstraint:
+		return ErrNotFound
+	default:
+		return dbError(err)
+	}
+}
@@ -0,0 +1,281 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+)
+
+var (
+	johnCartId  = uuid.MustParse("270cea28-b2b0-4051-9eb6-9a99e451af01")
+	guestCartId = uuid.MustParse("270cea28-b2b0-4051-9eb6-9a99e451af02")
+)
+
+func (s *RepositoryTestSuite) TestGetCartById() {
+	repo := NewCartRepository(s.testDb.BunDb)
+
+	s.Run("should return cart with its items", func() {
+		// when
+		cart, err := repo.GetById(s.testDb.Ctx, guestCartId)
+		// then
+		s.Nil(err)
+		s.True(cart.IsGuest())
+		s.Len(cart.Items, 2)
+		for _, ci := range cart.Items {
+			s.Require().NotNil(ci.Item)
+			s.Equal(ci.ItemID, ci.Item.ID)
+		}
+	})
+
+	s.Run("given unknown cart should return not found error", func() {
+		// when
+		_, err := repo.GetById(s.testDb.Ctx, uuid.New())
+		// then
+		s.ErrorIs(err, ErrNotFound)
+	})
+}
+
+func (s *RepositoryTestSuite) TestGetCartByAccount() {
+	repo := NewCartRepository(s.testDb.BunDb)
+
+	s.Run("should return existing cart of the account", func() {
+		// when
+		cart, err := repo.GetByAccount(s.testDb.Ctx, uuid.MustParse("220cea28-b2b0-4051-9eb6-9a99e451af01"))
+		// then
+		s.Nil(err)
+		s.Equal(johnCartId, cart.ID)
+		s.Len(cart.Items, 2)
+	})
+
+	s.Run("should create empty cart for account without cart only once", func() {
+		// given
+		accountId := uuid.MustParse("220cea28-b2b0-4051-9eb6-9a99e451af02")
+		// when
+		first, err := repo.GetByAccount(s.testDb.Ctx, accountId)
+		s.Require().Nil(err)
+		second, err := repo.GetByAccount(s.testDb.Ctx, accountId)
+		// then
+		s.Nil(err)
+		s.Equal(first.ID, second.ID)
+		s.Equal(accountId, *second.AccountID)
+		s.Empty(second.Items)
+	})
+
+	s.Run("given unknown account should return not found error", func() {
+		// when
+		_, err := repo.GetByAccount(s.testDb.Ctx, uuid.New())
+		// then
+		s.ErrorIs(err, ErrNotFound)
+	})
+}
+
+func (s *RepositoryTestSuite) TestPutCartItem() {
+	repo := NewCartRepository(s.testDb.BunDb)
+	itemId := uuid.MustParse("200cea28-b2b0-4051-9eb6-9a99e451af02")
+
+	cart := entities.NewCartBuilder().Build()
+	s.Require().Nil(repo.Create(s.testDb.Ctx, cart))
+
+	s.Run("should add units on top of units in the cart", func() {
+		// when
+		s.Require().Nil(repo.AddItem(s.testDb.Ctx, cart.ID, itemId, 2))
+		err := repo.AddItem(s.testDb.Ctx, cart.ID, itemId, 3)
+		// then
+		s.Nil(err)
+		got, err := repo.GetById(s.testDb.Ctx, cart.ID)
+		s.Nil(err)
+		s.Require().Len(got.Items, 1)
+		s.Equal(5, got.Items[0].Quantity)
+	})
+
+	s.Run("should set quantity of item in the cart", func() {
+		// when
+		err := repo.SetItemQuantity(s.testDb.Ctx, cart.ID, itemId, 1)
+		// then
+		s.Nil(err)
+		got, err := repo.GetById(s.testDb.Ctx, cart.ID)
+		s.Nil(err)
+		s.Require().Len(got.Items, 1)
+		s.Equal(1, got.Items[0].Quantity)
+	})
+
+	s.Run("should return insufficient stock error and keep quantity unchanged", func() {
+		// given
+		item := new(entities.Item)
+		s.Require().Nil(s.testDb.BunDb.NewSelect().Model(item).Where("id = ?", itemId).Scan(s.testDb.Ctx))
+		// when
+		err := repo.AddItem(s.testDb.Ctx, cart.ID, itemId, item.Stock)
+		// then
+		var stockErr entities.InsufficientStockError
+		s.True(errors.As(err, &stockErr))
+		s.Equal(item.Stock+1, stockErr.Requested)
+		s.Equal(item.Stock, stockErr.Available)
+
+		got, err := repo.GetById(s.testDb.Ctx, cart.ID)
+		s.Nil(err)
+		s.Equal(1, got.Items[0].Quantity)
+	})
+
+	s.Run("given unknown item should return item not found error", func() {
+		// when
+		err := repo.AddItem(s.testDb.Ctx, cart.ID, uuid.New(), 1)
+		// then
+		s.ErrorIs(err, entities.ErrorItemNotFound)
+	})
+
+	s.Run("given unknown cart should return not found error", func() {
+		// when
+		err := repo.SetItemQuantity(s.testDb.Ctx, uuid.New(), itemId, 1)
+		// then
+		s.ErrorIs(err, ErrNotFound)
+	})
+}
+
+func (s *RepositoryTestSuite) TestRemoveCartItems() {
+	repo := NewCartRepository(s.testDb.BunDb)
+	itemId := uuid.MustParse("200cea28-b2b0-4051-9eb6-9a99e451af01")
+
+	cart := entities.NewCartBuilder().Build()
+	s.Require().Nil(repo.Create(s.testDb.Ctx, cart))
+	s.Require().Nil(repo.AddItem(s.testDb.Ctx, cart.ID, itemId, 1))
+	s.Require().Nil(repo.AddItem(s.testDb.Ctx, cart.ID, uuid.MustParse("200cea28-b2b0-4051-9eb6-9a99e451af02"), 1))
+
+	s.Run("should remove item from the cart", func() {
+		// when
+		err := repo.RemoveItem(s.testDb.Ctx, cart.ID, itemId)
+		// then
+		s.Nil(err)
+		got, err := repo.GetById(s.testDb.Ctx, cart.ID)
+		s.Nil(err)
+		s.Len(got.Items, 1)
+	})
+
+	s.Run("given item not in the cart should return not found error", func() {
+		// when
+		err := repo.RemoveItem(s.testDb.Ctx, cart.ID, itemId)
+		// then
+		s.ErrorIs(err, ErrNotFound)
+	})
+
+	s.Run("should clear the cart", func() {
+		// when
+		err := repo.Clear(s.testDb.Ctx, cart.ID)
+		// then
+		s.Nil(err)
+		got, err := repo.GetById(s.testDb.Ctx, cart.ID)
+		s.Nil(err)
+		s.Empty(got.Items)
+	})
+}
+
+func (s *RepositoryTestSuite) TestMergeCart() {
+	repo := NewCartRepository(s.testDb.BunDb)
+	book1 := uuid.MustParse("200cea28-b2b0-4051-9eb6-9a99e451af01")
+	book3 := uuid.MustParse("200cea28-b2b0-4051-9eb6-9a99e451af03")
+
+	s.Run("should move items of guest cart into account cart and delete guest cart", func() {
+		// given
+		account, err := repo.GetByAccount(s.testDb.Ctx, uuid.MustParse("220cea28-b2b0-4051-9eb6-9a99e451af03"))
+		s.Require().Nil(err)
+		s.Require().Nil(repo.SetItemQuantity(s.testDb.Ctx, account.ID, book1, 1))
+
+		guest := entities.NewCartBuilder().Build()
+		s.Require().Nil(repo.Create(s.testDb.Ctx, guest))
+		s.Require().Nil(repo.AddItem(s.testDb.Ctx, guest.ID, book1, 2))
+		s.Require().Nil(repo.AddItem(s.testDb.Ctx, guest.ID, book3, 1))
+		// when
+		err = repo.Merge(s.testDb.Ctx, guest.ID, account.ID)
+		// then
+		s.Nil(err)
+		merged, err := repo.GetById(s.testDb.Ctx, account.ID)
+		s.Nil(err)
+		s.Require().Len(merged.Items, 2)
+		quantities := map[uuid.UUID]int{}
+		for _, ci := range merged.Items {
+			quantities[ci.ItemID] = ci.Quantity
+		}
+		s.Equal(3, quantities[book1])
+		s.Equal(1, quantities[book3])
+
+		_, err = repo.GetById(s.testDb.Ctx, guest.ID)
+		s.ErrorIs(err, ErrNotFound)
+	})
+
+	s.Run("given account cart to merge should return not found error", func() {
+		// when
+		err := repo.Merge(s.testDb.Ctx, johnCartId, guestCartId)
+		// then
+		s.ErrorIs(err, ErrNotFound)
+	})
+}
+
+func (s *RepositoryTestSuite) TestCheckoutCart() {
+	repo := NewCartRepository(s.testDb.BunDb)
+	orders := NewOrderRepository(s.testDb.BunDb)
+	accountId := uuid.MustParse("220cea28-b2b0-4051-9eb6-9a99e451af04")
+	itemId := uuid.MustParse("200cea28-b2b0-4051-9eb6-9a99e451af02")
+
+	cart, err := repo.GetByAccount(s.testDb.Ctx, accountId)
+	s.Require().Nil(err)
+
+	placeOrder := func(order *entities.Order) func(ctx context.Context, cart entities.Cart) error {
+		return func(ctx context.Context, cart entities.Cart) error {
+			for _, ci := range cart.Items {
+				order.OrderItems = append(order.OrderItems, entities.NewOrderItemBuilder().
+					OrderID(order.ID).
+					ItemID(ci.ItemID).
+					Quantity(ci.Quantity).
+					Build())
+			}
+			return orders.Create(ctx, order)
+		}
+	}
+
+	s.Run("should roll back order and keep cart if checkout fails", func() {
+		// given
+		s.Require().Nil(repo.SetItemQuantity(s.testDb.Ctx, cart.ID, itemId, 2))
+		order := entities.NewOrderBuilder().AccountID(accountId).Build()
+		place := placeOrder(order)
+		// when
+		err := repo.Checkout(s.testDb.Ctx, cart.ID, func(ctx context.Context, cart entities.Cart) error {
+			if err := place(ctx, cart); err != nil {
+				return err
+			}
+			return entities.ErrorCartEmpty
+		})
+		// then
+		s.ErrorIs(err, entities.ErrorCartEmpty)
+		_, err = orders.GetById(s.testDb.Ctx, order.ID)
+		s.ErrorIs(err, ErrNotFound)
+		got, err := repo.GetById(s.testDb.Ctx, cart.ID)
+		s.Nil(err)
+		s.Len(got.Items, 1)
+	})
+
+	s.Run("should place order and clear cart", func() {
+		// given
+		order := entities.NewOrderBuilder().AccountID(accountId).Build()
+		// when
+		err := repo.Checkout(s.testDb.Ctx, cart.ID, placeOrder(order))
+		// then
+		s.Nil(err)
+		placed, err := orders.GetById(s.testDb.Ctx, order.ID)
+		s.Nil(err)
+		s.Require().Len(placed.OrderItems, 1)
+		s.Equal(itemId, placed.OrderItems[0].ItemID)
+		s.Equal(2, placed.OrderItems[0].Quantity)
+		got, err := repo.GetById(s.testDb.Ctx, cart.ID)
+		s.Nil(err)
+		s.Empty(got.Items)
+	})
+
+	s.Run("given unknown cart should return not found error", func() {
+		// when
+		err := repo.Checkout(s.testDb.Ctx, uuid.New(), func(ctx context.Context, cart entities.Cart) error {
+			return nil
+		})
+		// then
+		s.ErrorIs(err, ErrNotFound)
+	})
+}
@@ -0,0 +1,239 @@
+package repositories
+
+import (
+	"context"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+// CategoryRepository is the implementation of core repositories.CategoryRepository interface.
+type CategoryRepository struct {
+	bunDb *bun.DB
+}
+
+// NewCategoryRepository instantiates new CategoryRepository.
+func NewCategoryRepository(db *bun.DB) CategoryRepository {
+	return CategoryRepository{db}
+}
+
+// GetById returns category by specified id, without its subcategories.
+func (repo CategoryRepository) GetById(ctx context.Context, id uuid.UUID) (entities.Category, error) {
+	category := new(entities.Category)
+
+	err := conn(ctx, repo.bunDb).NewSelect().Model(category).Where("id = ?", id).Scan(ctx)
+	if err != nil {
+		return *category, dbError(err)
+	}
+
+	return *category, nil
+}
+
+// GetTopLevel returns categories without a parent, ordered by name.
+func (repo CategoryRepository) GetTopLevel(ctx context.Context) ([]entities.Category, error) {
+	categories := make([]entities.Category, 0)
+
+	err := conn(ctx, repo.bunDb).NewSelect().
+		Model(&categories).
+		Where("parent_id IS NULL").
+		Order("name ASC", "id ASC").
+		Scan(ctx)
+
+	return categories, dbError(err)
+}
+
+// GetSubtree returns category by specified id with all of its subcategories nested under it.
+// Subcategories are ordered by name.
+func (repo CategoryRepository) GetSubtree(ctx context.Context, id uuid.UUID) (entities.Category, error) {
+	var categories []entities.Category
+
+	err := conn(ctx, repo.bunDb).NewSelect().
+		WithRecursive(subtreeName, categorySubtree(conn(ctx, repo.bunDb), id)).
+		Model(&categories).
+		Where("?TableAlias.id IN (SELECT id FROM ?)", bun.Ident(subtreeName)).
+		Order("name ASC", "id ASC").
+		Scan(ctx)
+	if err != nil {
+		return entities.Category{}, dbError(err)
+	}
+
+	tree, ok := entities.NewCategoryTree(id, categories)
+	if !ok {
+		return entities.Category{}, ErrNotFound
+	}
+	return tree, nil
+}
+
+// Create persists new category entity.
+// It returns entities.ErrorParentCategoryNotFound if the parent does not exist
+// and entities.ErrorCategoryNotUnique if the parent already has a subcategory of the same name.
+func (repo CategoryRepository) Create(ctx context.Context, category *entities.Category) error {
+	if category == nil {
+		return ErrNilEntity
+	}
+
+	_, err := conn(ctx, repo.bunDb).NewInsert().Model(category).Exec(ctx)
+	return categoryError(err)
+}
+
+// Update persists changes of existing category entity, it can be moved under another parent as well.
+// It returns ErrNotFound if the category does not exist, entities.ErrorVersionMismatch if it is not
+// in the expected version anymore and entities.ErrorCategoryCycle if it would be moved under itself or any of its subcategories.
+func (repo CategoryRepository) Update(ctx context.Context, category *entities.Category) error {
+	if category == nil {
+		return ErrNilEntity
+	}
+
+	category.UpdatedAt = time.Now()
+	err := runInTx(ctx, repo.bunDb, func(ctx context.Context, tx bun.Tx) error {
+		if category.ParentID != nil {
+			// concurrent moves could close a cycle unnoticed, so they are serialized, reads are not blocked
+			if _, err := tx.ExecContext(ctx, "LOCK TABLE categories IN SHARE ROW EXCLUSIVE MODE"); err != nil {
+				return err
+			}
+
+			cycle, err := tx.NewSelect().
+				WithRecursive(subtreeName, categorySubtree(tx, category.ID)).
+				TableExpr("?", bun.Ident(subtreeName)).
+				Where("id = ?", *category.ParentID).
+				Exists(ctx)
+			if err != nil {
+				return err
+			}
+			if cycle {
+				return entities.ErrorCategoryCycle
+			}
+		}
+
+		res, err := tx.NewUpdate().
+			Model(category).
+			Column("updated_at", "version", "name", "description", "parent_id").
+			WherePK().
+			Apply(compareAndSwap(category.Version)).
+			Returning("created_at, version").
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+			return versionError(ctx, tx, (*entities.Category)(nil), category.ID, category.Version)
+		}
+		return nil
+	})
+	return categoryError(err)
+}
+
+// DeleteById deletes category by specified id in the version, or any version if it is zero. Items are unassigned from it.
+// Categories with subcategories can not be deleted and entities.ErrorCategoryHasChildren is returned instead.
+func (repo CategoryRepository) DeleteById(ctx context.Context, id uuid.UUID, version int64) error {
+	res, err := conn(ctx, repo.bunDb).NewDelete().
+		Model((*entities.Category)(nil)).
+		Where("id = ?", id).
+		Apply(whereVersion(version)).
+		Exec(ctx)
+	if err != nil {
+		if isPgError(err, pgForeignKeyViolation) {
+			return entities.ErrorCategoryHasChildren
+		}
+		return dbError(err)
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return versionError(ctx, conn(ctx, repo.bunDb), (*entities.Category)(nil), id, version)
+	}
+	return nil
+}
+
+// GetByItem returns categories the item is assigned to, ordered by name.
+// It returns ErrNotFound if the item does not exist.
+func (repo CategoryRepository) GetByItem(ctx context.Context, itemId uuid.UUID) ([]entities.Category, error) {
+	categories := make([]entities.Category, 0)
+
+	err := conn(ctx, repo.bunDb).NewSelect().
+		Model(&categories).
+		Where("id IN (?)", conn(ctx, repo.bunDb).NewSelect().
+			Model((*entities.ItemCategory)(nil)).
+			Column("category_id").
+			Where("item_id = ?", itemId)).
+		Order("name ASC", "id ASC").
+		Scan(ctx)
+	if err != nil {
+		return categories, dbError(err)
+	}
+
+	if len(categories) == 0 {
+		exists, err := conn(ctx, repo.bunDb).NewSelect().Model((*entities.Item)(nil)).Where("id = ?", itemId).Exists(ctx)
+		if err != nil {
+			return categories, dbError(err)
+		}
+		if !exists {
+			return categories, ErrNotFound
+		}
+	}
+	return categories, nil
+}
+
+// AssignItem replaces categories the item is assigned to.
+// It returns ErrNotFound if the item does not exist and entities.ErrorCategoryNotFound if any of the categories does not.
+func (repo CategoryRepository) AssignItem(ctx context.Context, itemId uuid.UUID, categoryIds []uuid.UUID) error {
+	err := runInTx(ctx, repo.bunDb, func(ctx context.Context, tx bun.Tx) error {
+		// the item is locked, so concurrent assignments replace each other instead of being merged
+		var locked uuid.UUID
+		err := tx.NewSelect().Model((*entities.Item)(nil)).Column("id").Where("id = ?", itemId).For("UPDATE").Scan(ctx, &locked)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.NewDelete().Model((*entities.ItemCategory)(nil)).Where("item_id = ?", itemId).Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		assignments := make([]entities.ItemCategory, 0, len(categoryIds))
+		seen := make(map[uuid.UUID]bool, len(categoryIds))
+		for _, id := range categoryIds {
+			if !seen[id] {
+				seen[id] = true
+				assignments = append(assignments, entities.ItemCategory{ItemID: itemId, CategoryID: id})
+			}
+		}
+		if len(assignments) == 0 {
+			return nil
+		}
+
+		_, err = tx.NewInsert().Model(&assignments).Exec(ctx)
+		if isPgError(err, pgForeignKeyViolation) {
+			return entities.ErrorCategoryNotFound
+		}
+		return err
+	})
+	return dbError(err)
+}
+
+// subtreeName is the name of the common table expression selecting ids of a category subtree.
+const subtreeName = "subtree"
+
+// categorySubtree selects ids of the category and all of its subcategories, to be used as a recursive
+// common table expression named subtreeName. The union stops at categories already selected.
+func categorySubtree(db bun.IDB, id uuid.UUID) *bun.SelectQuery {
+	return db.NewSelect().
+		Model((*entities.Category)(nil)).
+		Column("id").
+		Where("id = ?", id).
+		Union(db.NewSelect().
+			Model((*entities.Category)(nil)).
+			Column("id").
+			Join("JOIN ? ON ?.id = ?TableAlias.parent_id", bun.Ident(subtreeName), bun.Ident(subtreeName)))
+}
+
+// categoryError translates constraint violations of category changes into domain errors.
+func categoryError(err error) error {
+	switch {
+	case isPgError(err, pgUniqueViolation):
+		return entities.ErrorCategoryNotUnique
+	case isPgError(err, pgForeignKeyViolation):
+		return entities.ErrorParentCategoryNotFound
+	default:
+		return dbError(err)
+	}
+}
@@ -0,0 +1,174 @@
+package repositories
+
+import (
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+)
+
+var (
+	fictionId        = uuid.MustParse("240cea28-b2b0-4051-9eb6-9a99e451af01")
+	fantasyId        = uuid.MustParse("240cea28-b2b0-4051-9eb6-9a99e451af02")
+	epicFantasyId    = uuid.MustParse("240cea28-b2b0-4051-9eb6-9a99e451af03")
+	scienceFictionId = uuid.MustParse("240cea28-b2b0-4051-9eb6-9a99e451af04")
+	nonFictionId     = uuid.MustParse("240cea28-b2b0-4051-9eb6-9a99e451af05")
+)
+
+func (s *RepositoryTestSuite) TestGetTopLevelCategories() {
+	repo := NewCategoryRepository(s.testDb.BunDb)
+
+	s.Run("should return categories without parent ordered by name", func() {
+		// when
+		categories, err := repo.GetTopLevel(s.testDb.Ctx)
+		// then
+		s.Nil(err)
+		s.Len(categories, 2)
+		s.Equal("Fiction", categories[0].Name)
+		s.Equal("Non-fiction", categories[1].Name)
+	})
+}
+
+func (s *RepositoryTestSuite) TestGetCategorySubtree() {
+	repo := NewCategoryRepository(s.testDb.BunDb)
+
+	s.Run("should return category with nested subcategories", func() {
+		// when
+		fiction, err := repo.GetSubtree(s.testDb.Ctx, fictionId)
+		// then
+		s.Nil(err)
+		s.Equal("Fiction", fiction.Name)
+		s.Len(fiction.Children, 2)
+		s.Equal("Fantasy", fiction.Children[0].Name)
+		s.Equal("Science Fiction", fiction.Children[1].Name)
+		s.Len(fiction.Children[0].Children, 1)
+		s.Equal("Epic Fantasy", fiction.Children[0].Children[0].Name)
+	})
+
+	s.Run("given leaf category should return it without subcategories", func() {
+		// when
+		epic, err := repo.GetSubtree(s.testDb.Ctx, epicFantasyId)
+		// then
+		s.Nil(err)
+		s.Equal(fantasyId, *epic.ParentID)
+		s.Empty(epic.Children)
+	})
+
+	s.Run("given unknown category should return not found error", func() {
+		// when
+		_, err := repo.GetSubtree(s.testDb.Ctx, uuid.New())
+		// then
+		s.ErrorIs(err, ErrNotFound)
+	})
+}
+
+func (s *RepositoryTestSuite) TestManageCategory() {
+	repo := NewCategoryRepository(s.testDb.BunDb)
+
+	s.Run("should create, update and delete subcategory", func() {
+		// given
+		category := entities.NewCategoryBuilder().Name("Urban Fantasy").Parent(fantasyId).Build()
+		// when
+		err := repo.Create(s.testDb.Ctx, category)
+		// then
+		s.Require().Nil(err)
+
+		// when
+		category.Name = "Dark Fantasy"
+		category.ParentID = &fictionId
+		err = repo.Update(s.testDb.Ctx, category)
+		// then
+		s.Nil(err)
+		got, err := repo.GetById(s.testDb.Ctx, category.ID)
+		s.Nil(err)
+		s.Equal("Dark Fantasy", got.Name)
+		s.Equal(fictionId, *got.ParentID)
+
+		// when
+		err = repo.DeleteById(s.testDb.Ctx, category.ID, 0)
+		// then
+		s.Nil(err)
+		s.ErrorIs(repo.DeleteById(s.testDb.Ctx, category.ID, 0), ErrNotFound)
+	})
+
+	s.Run("given taken name within the parent should return conflict error", func() {
+		// given
+		category := entities.NewCategoryBuilder().Name("epic fantasy").Parent(fantasyId).Build()
+		// when
+		err := repo.Create(s.testDb.Ctx, category)
+		// then
+		s.ErrorIs(err, entities.ErrorCategoryNotUnique)
+	})
+
+	s.Run("given unknown parent should return not found error", func() {
+		// given
+		category := entities.NewCategoryBuilder().Name("Orphan").Parent(uuid.New()).Build()
+		// when
+		err := repo.Create(s.testDb.Ctx, category)
+		// then
+		s.ErrorIs(err, entities.ErrorParentCategoryNotFound)
+	})
+
+	s.Run("moving category under its subcategory should return cycle error", func() {
+		// given
+		fiction, err := repo.GetById(s.testDb.Ctx, fictionId)
+		s.Require().Nil(err)
+		fiction.ParentID = &epicFantasyId
+		// when
+		err = repo.Update(s.testDb.Ctx, &fiction)
+		// then
+		s.ErrorIs(err, entities.ErrorCategoryCycle)
+	})
+
+	s.Run("deleting category with subcategories should return conflict error", func() {
+		// when
+		err := repo.DeleteById(s.testDb.Ctx, fantasyId, 0)
+		// then
+		s.ErrorIs(err, entities.ErrorCategoryHasChildren)
+	})
+}
+
+func (s *RepositoryTestSuite) TestAssignItemCategories() {
+	repo := NewCategoryRepository(s.testDb.BunDb)
+	itemId := uuid.MustParse("200cea28-b2b0-4051-9eb6-9a99e451af05")
+	defer func() { _ = repo.AssignItem(s.testDb.Ctx, itemId, nil) }()
+
+	s.Run("should replace categories of the item", func() {
+		// when
+		err := repo.AssignItem(s.testDb.Ctx, itemId, []uuid.UUID{nonFictionId, fantasyId, fantasyId})
+		// then
+		s.Require().Nil(err)
+		categories, err := repo.GetByItem(s.testDb.Ctx, itemId)
+		s.Nil(err)
+		s.Len(categories, 2)
+		s.Equal("Fantasy", categories[0].Name)
+		s.Equal("Non-fiction", categories[1].Name)
+	})
+
+	s.Run("given unknown category should keep categories of the item", func() {
+		// when
+		err := repo.AssignItem(s.testDb.Ctx, itemId, []uuid.UUID{uuid.New()})
+		// then
+		s.ErrorIs(err, entities.ErrorCategoryNotFound)
+		categories, err := repo.GetByItem(s.testDb.Ctx, itemId)
+		s.Nil(err)
+		s.Len(categories, 2)
+	})
+
+	s.Run("given no categories should unassign the item", func() {
+		// when
+		err := repo.AssignItem(s.testDb.Ctx, itemId, []uuid.UUID{})
+		// then
+		s.Nil(err)
+		categories, err := repo.GetByItem(s.testDb.Ctx, itemId)
+		s.Nil(err)
+		s.Empty(categories)
+	})
+
+	s.Run("given unknown item should return not found error", func() {
+		// when
+		err := repo.AssignItem(s.testDb.Ctx, uuid.New(), []uuid.UUID{fantasyId})
+		// then
+		s.ErrorIs(err, ErrNotFound)
+		_, err = repo.GetByItem(s.testDb.Ctx, uuid.New())
+		s.ErrorIs(err, ErrNotFound)
+	})
+}
@@ -0,0 +1,213 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+)
+
+// cursorTimeFormat formats timestamps in cursors, matching the timestamp columns.
+const cursorTimeFormat = "2006-01-02 15:04:05.999999999"
+
+// keyset is the ordering of a keyset page request: the sort columns followed by id, which breaks ties.
+type keyset struct {
+	columns    []string
+	directions []entities.Direction
+}
+
+// newKeyset resolves the sort orders into a keyset. Sortable columns are not nullable,
+// so the NULLS FIRST / NULLS LAST part of directions is irrelevant and dropped.
+// Listings without sort orders are sorted by creation time, newest first.
+func newKeyset(sortable entities.Sortable, sort entities.Sort) (keyset, error) {
+	var ks keyset
+	for _, o := range sort.Orders {
+		column, err := sortable.Column(o.Property)
+		if err != nil {
+			return ks, err
+		}
+		direction, err := entities.ParseDirection(string(o.Direction))
+		if err != nil {
+			return ks, err
+		}
+		ks.add(column, baseDirection(direction))
+	}
+	if len(ks.columns) == 0 {
+		ks.add("created_at", entities.DESC)
+	}
+	ks.add("id", ks.directions[len(ks.directions)-1])
+	return ks, nil
+}
+
+func (ks *keyset) add(column string, direction entities.Direction) {
+	ks.columns = append(ks.columns, column)
+	ks.directions = append(ks.directions, direction)
+}
+
+// signature identifies the keyset, so cursors cannot be used with different sort orders.
+func (ks keyset) signature() string {
+	orders := make([]string, len(ks.columns))
+	for i := range ks.columns {
+		orders[i] = fmt.Sprintf("%s %s", ks.columns[i], ks.directions[i])
+	}
+	return strings.Join(orders, ",")
+}
+
+// cursor creates the cursor pointing at the element.
+func (ks keyset) cursor(table *schema.Table, element reflect.Value, backward bool) string {
+	keys := make([]string, len(ks.columns))
+	for i, column := range ks.columns {
+		keys[i] = formatCursorKey(table.FieldMap[column].Value(element).Interface())
+	}
+	return entities.Cursor{Sort: ks.signature(), Keys: keys, Backward: backward}.Encode()
+}
+
+// args converts keys of the cursor into values of the corresponding entity fields,
+// so they are passed to the query with the types of their columns.
+func (ks keyset) args(table *schema.Table, c entities.Cursor) ([]interface{}, error) {
+	if c.Sort != ks.signature() || len(c.Keys) != len(ks.columns) {
+		return nil, entities.ErrorInvalidCursor
+	}
+	strct := reflect.New(table.Type).Elem()
+	args := make([]interface{}, len(ks.columns))
+	for i, column := range ks.columns {
+		field := table.FieldMap[column]
+		if err := field.ScanValue(strct, []byte(c.Keys[i])); err != nil {
+			return nil, entities.ErrorInvalidCursor
+		}
+		args[i] = field.Value(strct).Interface()
+	}
+	return args, nil
+}
+
+// apply orders the query by the keyset and, given a cursor, selects only the elements after it:
+// (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ..., where > becomes < for descending keys.
+// Backward cursors reverse the order, so the elements before the cursor are selected.
+func (ks keyset) apply(q *bun.SelectQuery, args []interface{}, backward bool) *bun.SelectQuery {
+	directions := make([]entities.Direction, len(ks.directions))
+	for i, d := range ks.directions {
+		directions[i] = d
+		if backward {
+			directions[i] = reverseDirection(d)
+		}
+	}
+
+	for i, column := range ks.columns {
+		q = q.OrderExpr("?TableAlias.? "+string(directions[i]), bun.Ident(column))
+	}
+	if args == nil {
+		return q
+	}
+
+	return q.WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+		for i := range ks.columns {
+			i := i
+			q = q.WhereGroup(" OR ", func(q *bun.SelectQuery) *bun.SelectQuery {
+				for j := 0; j < i; j++ {
+					q = q.Where("?TableAlias.? = ?", bun.Ident(ks.columns[j]), args[j])
+				}
+				op := ">"
+				if directions[i] == entities.DESC {
+					op = "<"
+				}
+				return q.Where("?TableAlias.? "+op+" ?", bun.Ident(ks.columns[i]), args[i])
+			})
+		}
+		return q
+	})
+}
+
+// keysetPage selects the page of elements requested in keyset mode, see entities.Pageable.Keyset.
+// The query must select into elements and may carry filters, but no ordering or limits.
+func keysetPage[T any](ctx context.Context, q *bun.SelectQuery, elements *[]T, sortable entities.Sortable, p entities.Pageable) (entities.Page[T], error) {
+	ks, err := newKeyset(sortable, p.Sort)
+	if err != nil {
+		return entities.Page[T]{}, err
+	}
+	table := q.DB().Table(reflect.TypeOf((*T)(nil)).Elem())
+
+	var args []interface{}
+	backward := false
+	if p.Cursor != "" {
+		c, err := entities.ParseCursor(p.Cursor)
+		if err != nil {
+			return entities.Page[T]{}, err
+		}
+		if args, err = ks.args(table, c); err != nil {
+			return entities.Page[T]{}, err
+		}
+		backward = c.Backward
+	}
+
+	page := entities.Page[T]{Size: p.Size, Cursors: &entities.PageCursors{Counted: !p.SkipCount}}
+	if !p.SkipCount {
+		count, err := q.Count(ctx)
+		if err != nil {
+			return entities.Page[T]{}, dbError(err)
+		}
+		page.SetTotal(count)
+	}
+
+	q = ks.apply(q, args, backward)
+	if p.Size > 0 {
+		// one more element tells whether there is a page after this one
+		q = q.Limit(p.Size + 1)
+	}
+	if err := q.Scan(ctx); err != nil {
+		return entities.Page[T]{}, dbError(err)
+	}
+
+	more := p.Size > 0 && len(*elements) > p.Size
+	if more {
+		*elements = (*elements)[:p.Size]
+	}
+	if backward {
+		for i, j := 0, len(*elements)-1; i < j; i, j = i+1, j-1 {
+			(*elements)[i], (*elements)[j] = (*elements)[j], (*elements)[i]
+		}
+	}
+	page.Elements = *elements
+
+	if n := len(*elements); n > 0 {
+		// going backward, the page the cursor came from follows; going forward, it precedes
+		if more || backward {
+			page.Cursors.Next = ks.cursor(table, reflect.ValueOf(*elements).Index(n-1), false)
+		}
+		if (more && backward) || (!backward && p.Cursor != "") {
+			page.Cursors.Prev = ks.cursor(table, reflect.ValueOf(*elements).Index(0), true)
+		}
+	}
+	page.HasNext = page.Cursors.Next != ""
+	page.HasPrev = page.Cursors.Prev != ""
+	return page, nil
+}
+
+func formatCursorKey(v interface{}) string {
+	switch v := v.(type) {
+	case time.Time:
+		return v.UTC().Format(cursorTimeFormat)
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func baseDirection(d entities.Direction) entities.Direction {
+	if strings.HasPrefix(string(d), string(entities.DESC)) {
+		return entities.DESC
+	}
+	return entities.ASC
+}
+
+func reverseDirection(d entities.Direction) entities.Direction {
+	if d == entities.DESC {
+		return entities.ASC
+	}
+	return entities.DESC
+}
@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+func TestKeyset(t *testing.T) {
+	// queries are only formatted, the database is never connected
+	bunDb := db.NewService().WrapWithBun(sql.OpenDB(pgdriver.NewConnector()))
+	table := bunDb.Table(reflect.TypeOf(entities.Item{}))
+	sort := entities.NewSort(
+		entities.NewSortOrder(entities.WithProperty("price"), entities.WithDirection(entities.DESC_NULLS_LAST)),
+		entities.NewSortOrder(entities.WithProperty("title"), entities.WithDirection(entities.ASC)),
+	)
+
+	t.Run("should order by sort keys and id", func(t *testing.T) {
+		ks, err := newKeyset(entities.ItemSortable, sort)
+		assert.NoError(t, err)
+		assert.Equal(t, "price_amount DESC,title ASC,id ASC", ks.signature())
+
+		ks, err = newKeyset(entities.ItemSortable, entities.Sort{})
+		assert.NoError(t, err)
+		assert.Equal(t, "created_at DESC,id DESC", ks.signature())
+	})
+
+	t.Run("should select elements after the cursor", func(t *testing.T) {
+		ks, _ := newKeyset(entities.ItemSortable, sort)
+		item := entities.NewItemBuilder().Title("Dune").Price(entities.NewMoney(1299, entities.EUR)).Build()
+		c, err := entities.ParseCursor(ks.cursor(table, reflect.ValueOf(*item), false))
+		assert.NoError(t, err)
+		args, err := ks.args(table, c)
+		assert.NoError(t, err)
+
+		q := ks.apply(bunDb.NewSelect().Model(&[]entities.Item{}).Column("id"), args, c.Backward)
+
+		assert.Equal(t, `SELECT "i"."id" FROM "items" AS "i" WHERE `+
+			`((("i"."price_amount" < 1299)) OR `+
+			`(("i"."price_amount" = 1299) AND ("i"."title" > 'Dune')) OR `+
+			`(("i"."price_amount" = 1299) AND ("i"."title" = 'Dune') AND ("i"."id" > '`+item.ID.String()+`'))) `+
+			`ORDER BY "i"."price_amount" DESC, "i"."title" ASC, "i"."id" ASC`, q.String())
+	})
+
+	t.Run("should reverse order for backward cursor", func(t *testing.T) {
+		ks, _ := newKeyset(entities.ItemSortable, entities.Sort{})
+		q := ks.apply(bunDb.NewSelect().Model(&[]entities.Item{}).Column("id"), nil, true)
+		assert.Equal(t, `SELECT "i"."id" FROM "items" AS "i" ORDER BY "i"."created_at" ASC, "i"."id" ASC`, q.String())
+	})
+
+	t.Run("should reject cursor of different sort or with invalid keys", func(t *testing.T) {
+		ks, _ := newKeyset(entities.ItemSortable, sort)
+		_, err := ks.args(table, entities.Cursor{Sort: "created_at DESC,id DESC", Keys: []string{"2024-01-01 00:00:00", "x"}})
+		assert.ErrorIs(t, err, entities.ErrorInvalidCursor)
+
+		_, err = ks.args(table, entities.Cursor{Sort: ks.signature(), Keys: []string{"cheap", "Dune", "x"}})
+		assert.ErrorIs(t, err, entities.ErrorInvalidCursor)
+	})
+}
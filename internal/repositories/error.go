@@ -1,8 +1,90 @@
 package repositories
 
-import "errors"
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
 
 var (
-	ErrNilEntity = errors.New("entity can not be nil")
-	ErrNotFound  = errors.New("entity not found")
+	ErrNilEntity   = entities.NewError(entities.ErrorValidation, "entity can not be nil")
+	ErrNotFound    = entities.ErrorEntityNotFound
+	ErrUnavailable = entities.NewError(entities.ErrorUnavailable, "database is unavailable")
+)
+
+// PostgreSQL error codes, see https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgForeignKeyViolation = "23503"
+	pgUniqueViolation     = "23505"
+
+	pgConnectionExceptionClass = "08"
+	pgSerializationFailure     = "40001"
+	pgDeadlockDetected         = "40P01"
+	pgTooManyConnections       = "53300"
+	pgAdminShutdown            = "57P01"
+	pgCannotConnectNow         = "57P03"
 )
+
+// isPgError reports whether err is a PostgreSQL error with the given code.
+func isPgError(err error, code string) bool {
+	var pgErr pgdriver.Error
+	return errors.As(err, &pgErr) && pgErr.Field('C') == code
+}
+
+// violatedConstraint returns the name of the constraint violated by err, if it is a PostgreSQL error with the given code.
+func violatedConstraint(err error, code string) string {
+	var pgErr pgdriver.Error
+	if errors.As(err, &pgErr) && pgErr.Field('C') == code {
+		return pgErr.Field('n')
+	}
+	return ""
+}
+
+// dbError translates errors of the database into domain errors,
+// keeping the original error in the chain.
+func dbError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, ErrNotFound), errors.Is(err, ErrUnavailable):
+		// already translated
+		return err
+	case errors.Is(err, sql.ErrNoRows):
+		return fmt.Errorf("%w: %w", ErrNotFound, err)
+	case isTransient(err):
+		return fmt.Errorf("%w: %w", ErrUnavailable, err)
+	default:
+		return err
+	}
+}
+
+// isTransient reports whether the error is caused by a condition which is likely to go away,
+// like a lost connection or an overloaded database, so the operation can be retried.
+func isTransient(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	var pgErr pgdriver.Error
+	if errors.As(err, &pgErr) {
+		code := pgErr.Field('C')
+		switch code {
+		case pgSerializationFailure, pgDeadlockDetected, pgTooManyConnections, pgAdminShutdown, pgCannotConnectNow:
+			return true
+		}
+		return strings.HasPrefix(code, pgConnectionExceptionClass)
+	}
+	return false
+}
@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDbError(t *testing.T) {
+	t.Run("no rows should be translated into not found error", func(t *testing.T) {
+		err := dbError(sql.ErrNoRows)
+		assert.ErrorIs(t, err, entities.ErrorNotFound)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("bad connection should be translated into unavailable error", func(t *testing.T) {
+		err := dbError(driver.ErrBadConn)
+		assert.ErrorIs(t, err, entities.ErrorUnavailable)
+	})
+
+	t.Run("other errors should be kept as they are", func(t *testing.T) {
+		cause := errors.New("syntax error")
+		assert.Equal(t, cause, dbError(cause))
+		assert.Nil(t, dbError(nil))
+	})
+}
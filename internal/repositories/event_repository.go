@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// EventRepository is the implementation of core repositories.EventRepository interface.
+type EventRepository struct {
+	bunDb *bun.DB
+}
+
+// NewEventRepository instantiates new EventRepository.
+func NewEventRepository(db *bun.DB) EventRepository {
+	return EventRepository{db}
+}
+
+// Claim locks the due events, events locked by a concurrent relay are skipped, and leases them,
+// so several instances of the application can relay events of the same outbox.
+func (repo EventRepository) Claim(ctx context.Context, limit int) ([]entities.Event, error) {
+	var events []entities.Event
+	err := runInTx(ctx, repo.bunDb, func(ctx context.Context, tx bun.Tx) error {
+		now := time.Now()
+		err := tx.NewSelect().
+			Model(&events).
+			Where("published_at IS NULL").
+			Where("parked_at IS NULL").
+			Where("next_attempt_at IS NULL OR next_attempt_at <= ?", now).
+			Order("created_at ASC", "id ASC").
+			Limit(limit).
+			For("UPDATE SKIP LOCKED").
+			Scan(ctx)
+		if err != nil || len(events) == 0 {
+			return err
+		}
+
+		ids := make([]uuid.UUID, len(events))
+		for i := range events {
+			events[i].Claim(now)
+			ids[i] = events[i].ID
+		}
+		_, err = tx.NewUpdate().
+			Model((*entities.Event)(nil)).
+			Set("attempts = attempts + 1").
+			Set("next_attempt_at = ?", events[0].NextAttemptAt).
+			Where("id IN (?)", bun.In(ids)).
+			Exec(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, dbError(err)
+	}
+	return events, nil
+}
+
+// Update persists the outcome of the attempt recorded on the claimed event.
+func (repo EventRepository) Update(ctx context.Context, event *entities.Event) error {
+	_, err := conn(ctx, repo.bunDb).NewUpdate().
+		Model(event).
+		Column("published_at", "last_error", "next_attempt_at", "parked_at").
+		WherePK().
+		Exec(ctx)
+	return dbError(err)
+}
+
+// storeEvent adds the event to the outbox within the transaction persisting the change the event describes.
+func storeEvent(ctx context.Context, tx bun.Tx, event *entities.Event) error {
+	_, err := tx.NewInsert().Model(event).Exec(ctx)
+	return err
+}
@@ -0,0 +1,176 @@
+package repositories
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+	"time"
+)
+
+func (s *RepositoryTestSuite) TestOutboxEvents() {
+	repo := NewEventRepository(s.testDb.BunDb)
+	accounts := NewAccountRepository(s.testDb.BunDb)
+	orders := NewOrderRepository(s.testDb.BunDb)
+
+	eventsOf := func(aggregateId uuid.UUID) []entities.Event {
+		var events []entities.Event
+		err := s.testDb.BunDb.NewSelect().Model(&events).Where("aggregate_id = ?", aggregateId).Scan(s.testDb.Ctx)
+		s.Require().Nil(err)
+		return events
+	}
+	// relay publishes the claimed events with the publish func and records the outcome like events.Relay does
+	relay := func(limit int, publish func(event entities.Event) error) int {
+		events, err := repo.Claim(s.testDb.Ctx, limit)
+		s.Require().Nil(err)
+		published := 0
+		for i := range events {
+			if err := publish(events[i]); err != nil {
+				events[i].Failed(err.Error(), time.Now())
+			} else {
+				events[i].Published(time.Now())
+				published++
+			}
+			s.Require().Nil(repo.Update(s.testDb.Ctx, &events[i]))
+		}
+		return published
+	}
+	drain := func() {
+		relay(1000, func(entities.Event) error { return nil })
+	}
+
+	s.Run("should store events together with created account and placed order", func() {
+		// given
+		account := entities.NewAccountBuilder().Email("outbox@smith.com").Build()
+
+		// when
+		err := accounts.Create(s.testDb.Ctx, account)
+		// then
+		s.Require().Nil(err)
+		events := eventsOf(account.ID)
+		s.Len(events, 1)
+		s.Equal(entities.EVENT_ACCOUNT_CREATED, events[0].Type)
+
+		// when
+		order := s.placeOrder(orders, map[string]int{"200cea28-b2b0-4051-9eb6-9a99e451af01": 1})
+		// then
+		events = eventsOf(order.ID)
+		s.Len(events, 1)
+		s.Equal(entities.EVENT_ORDER_PLACED, events[0].Type)
+		var payload entities.OrderPlaced
+		s.Nil(json.Unmarshal(events[0].Payload, &payload))
+		s.Equal(order.AccountID, payload.AccountID)
+		s.Len(payload.Items, 1)
+	})
+
+	s.Run("should not store event when account is not created", func() {
+		// given
+		account := entities.NewAccountBuilder().Email("outbox@smith.com").Build()
+		// when
+		err := accounts.Create(s.testDb.Ctx, account)
+		// then
+		s.ErrorIs(err, entities.ErrorEmailNotUnique)
+		s.Empty(eventsOf(account.ID))
+	})
+
+	s.Run("should mark published events and retry failed ones with backoff", func() {
+		// given
+		drain()
+		first := entities.NewAccountBuilder().Email("first@outbox.com").Build()
+		second := entities.NewAccountBuilder().Email("second@outbox.com").Build()
+		third := entities.NewAccountBuilder().Email("third@outbox.com").Build()
+		s.Require().Nil(accounts.Create(s.testDb.Ctx, first))
+		s.Require().Nil(accounts.Create(s.testDb.Ctx, second))
+		s.Require().Nil(accounts.Create(s.testDb.Ctx, third))
+		var published []uuid.UUID
+		failing := func(event entities.Event) error {
+			if event.AggregateID == second.ID {
+				return errors.New("connection refused")
+			}
+			published = append(published, event.AggregateID)
+			return nil
+		}
+
+		// when
+		n := relay(10, failing)
+		// then the failed event does not hold up the later one
+		s.Equal(2, n)
+		s.Equal([]uuid.UUID{first.ID, third.ID}, published)
+		s.False(eventsOf(first.ID)[0].PublishedAt.IsZero())
+		s.False(eventsOf(third.ID)[0].PublishedAt.IsZero())
+		failed := eventsOf(second.ID)[0]
+		s.True(failed.PublishedAt.IsZero())
+		s.Equal(1, failed.Attempts)
+		s.Equal("connection refused", failed.LastError)
+		s.True(failed.NextAttemptAt.After(time.Now()))
+
+		// when relayed before the retry is due
+		// then the event is skipped
+		s.Equal(0, relay(10, func(entities.Event) error { return nil }))
+
+		// when the retry is due
+		_, err := s.testDb.BunDb.NewUpdate().Model((*entities.Event)(nil)).
+			Set("next_attempt_at = ?", time.Now().Add(-time.Second)).
+			Where("id = ?", failed.ID).
+			Exec(s.testDb.Ctx)
+		s.Require().Nil(err)
+		n = relay(10, func(entities.Event) error { return nil })
+		// then
+		s.Equal(1, n)
+		retried := eventsOf(second.ID)[0]
+		s.False(retried.PublishedAt.IsZero())
+		s.Equal(2, retried.Attempts)
+		s.Empty(retried.LastError)
+	})
+
+	s.Run("should skip claimed events until the claim expires", func() {
+		// given
+		drain()
+		account := entities.NewAccountBuilder().Email("claimed@outbox.com").Build()
+		s.Require().Nil(accounts.Create(s.testDb.Ctx, account))
+
+		// when
+		claimed, err := repo.Claim(s.testDb.Ctx, 10)
+		// then
+		s.Require().Nil(err)
+		s.Len(claimed, 1)
+		s.Equal(1, claimed[0].Attempts)
+		again, err := repo.Claim(s.testDb.Ctx, 10)
+		s.Require().Nil(err)
+		s.Empty(again)
+
+		// when the relay stopped without recording the outcome
+		_, err = s.testDb.BunDb.NewUpdate().Model((*entities.Event)(nil)).
+			Set("next_attempt_at = ?", time.Now().Add(-time.Second)).
+			Where("id = ?", claimed[0].ID).
+			Exec(s.testDb.Ctx)
+		s.Require().Nil(err)
+		again, err = repo.Claim(s.testDb.Ctx, 10)
+		// then
+		s.Require().Nil(err)
+		s.Len(again, 1)
+		s.Equal(2, again[0].Attempts)
+		drain()
+	})
+
+	s.Run("should not relay parked events", func() {
+		// given
+		drain()
+		account := entities.NewAccountBuilder().Email("parked@outbox.com").Build()
+		s.Require().Nil(accounts.Create(s.testDb.Ctx, account))
+		_, err := s.testDb.BunDb.NewUpdate().Model((*entities.Event)(nil)).
+			Set("attempts = ?", entities.EventMaxAttempts-1).
+			Where("aggregate_id = ?", account.ID).
+			Exec(s.testDb.Ctx)
+		s.Require().Nil(err)
+
+		// when
+		relay(10, func(entities.Event) error { return errors.New("connection refused") })
+		// then
+		parked := eventsOf(account.ID)[0]
+		s.False(parked.ParkedAt.IsZero())
+		s.Equal(entities.EventMaxAttempts, parked.Attempts)
+		s.Equal(0, relay(10, func(entities.Event) error { return nil }))
+		s.True(eventsOf(account.ID)[0].PublishedAt.IsZero())
+	})
+}
@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+// IdempotencyRepository is the implementation of core repositories.IdempotencyRepository interface.
+type IdempotencyRepository struct {
+	bunDb *bun.DB
+}
+
+// NewIdempotencyRepository instantiates new IdempotencyRepository.
+func NewIdempotencyRepository(db *bun.DB) IdempotencyRepository {
+	return IdempotencyRepository{db}
+}
+
+// Reserve stores the key if the account has not used it yet or it has expired and returns true.
+// Otherwise, it returns the stored key and false. Concurrent reservations of the same key are decided
+// by the primary key, only one of them succeeds. Keys of other accounts are never seen.
+func (repo IdempotencyRepository) Reserve(ctx context.Context, key *entities.IdempotencyKey) (entities.IdempotencyKey, bool, error) {
+	if key == nil {
+		return entities.IdempotencyKey{}, false, ErrNilEntity
+	}
+
+	// the stored key could be released between the insert and the select, the reservation is tried once more then
+	for attempt := 0; attempt < 2; attempt++ {
+		res, err := conn(ctx, repo.bunDb).NewInsert().
+			Model(key).
+			On("CONFLICT (account_id, key) DO UPDATE").
+			Set("fingerprint = EXCLUDED.fingerprint").
+			Set("created_at = EXCLUDED.created_at").
+			Set("expires_at = EXCLUDED.expires_at").
+			Set("status_code = NULL, content_type = NULL, body = NULL").
+			Where("ik.expires_at <= EXCLUDED.created_at").
+			Exec(ctx)
+		if err != nil {
+			return entities.IdempotencyKey{}, false, dbError(err)
+		}
+		if affected, err := res.RowsAffected(); err == nil && affected > 0 {
+			return *key, true, nil
+		}
+
+		stored := new(entities.IdempotencyKey)
+		err = conn(ctx, repo.bunDb).NewSelect().Model(stored).
+			Where("account_id = ?", key.AccountID).
+			Where("key = ?", key.Key).
+			Scan(ctx)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return entities.IdempotencyKey{}, false, dbError(err)
+		}
+		return *stored, false, nil
+	}
+	return entities.IdempotencyKey{}, false, ErrUnavailable
+}
+
+// Complete stores the response of the request made with the key.
+// It returns ErrNotFound if the key is not reserved.
+func (repo IdempotencyRepository) Complete(ctx context.Context, key *entities.IdempotencyKey) error {
+	if key == nil {
+		return ErrNilEntity
+	}
+
+	res, err := conn(ctx, repo.bunDb).NewUpdate().
+		Model(key).
+		Column("status_code", "content_type", "body").
+		WherePK().
+		Where("fingerprint = ?", key.Fingerprint).
+		Exec(ctx)
+	if err != nil {
+		return dbError(err)
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Release deletes the key of the account, so the request can be made with the key again.
+func (repo IdempotencyRepository) Release(ctx context.Context, accountId uuid.UUID, key string) error {
+	_, err := conn(ctx, repo.bunDb).NewDelete().
+		Model((*entities.IdempotencyKey)(nil)).
+		Where("account_id = ?", accountId).
+		Where("key = ?", key).
+		Exec(ctx)
+	return dbError(err)
+}
+
+// DeleteExpired deletes the keys which expired by now and returns how many were deleted.
+func (repo IdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	res, err := conn(ctx, repo.bunDb).NewDelete().
+		Model((*entities.IdempotencyKey)(nil)).
+		Where("expires_at <= ?", now).
+		Exec(ctx)
+	if err != nil {
+		return 0, dbError(err)
+	}
+	deleted, err := res.RowsAffected()
+	return int(deleted), err
+}
@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+	"net/http"
+	"time"
+)
+
+func (s *RepositoryTestSuite) TestIdempotencyKeys() {
+	repo := NewIdempotencyRepository(s.testDb.BunDb)
+	now := time.Now()
+
+	s.Run("should reserve key once and return stored response afterwards", func() {
+		// given
+		key := &entities.IdempotencyKey{Key: "key-1", Fingerprint: "fp-1", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+		// when
+		_, reserved, err := repo.Reserve(s.testDb.Ctx, key)
+		// then
+		s.Require().Nil(err)
+		s.True(reserved)
+
+		// when reserved again before completion
+		retry := &entities.IdempotencyKey{Key: "key-1", Fingerprint: "fp-1", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+		stored, reserved, err := repo.Reserve(s.testDb.Ctx, retry)
+		// then
+		s.Require().Nil(err)
+		s.False(reserved)
+		s.False(stored.Completed())
+
+		// when completed
+		key.StatusCode = http.StatusCreated
+		key.ContentType = "application/json"
+		key.Body = []byte(`{"id":"1"}`)
+		s.Require().Nil(repo.Complete(s.testDb.Ctx, key))
+		stored, reserved, err = repo.Reserve(s.testDb.Ctx, retry)
+		// then
+		s.Require().Nil(err)
+		s.False(reserved)
+		s.True(stored.Completed())
+		s.Equal("fp-1", stored.Fingerprint)
+		s.Equal(http.StatusCreated, stored.StatusCode)
+		s.Equal("application/json", stored.ContentType)
+		s.Equal(`{"id":"1"}`, string(stored.Body))
+	})
+
+	s.Run("should reserve expired key again", func() {
+		// given
+		expired := &entities.IdempotencyKey{Key: "key-2", Fingerprint: "fp-1", CreatedAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Hour)}
+		_, reserved, err := repo.Reserve(s.testDb.Ctx, expired)
+		s.Require().Nil(err)
+		s.Require().True(reserved)
+		// when
+		key := &entities.IdempotencyKey{Key: "key-2", Fingerprint: "fp-2", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+		_, reserved, err = repo.Reserve(s.testDb.Ctx, key)
+		// then
+		s.Nil(err)
+		s.True(reserved)
+	})
+
+	s.Run("should reserve released key again", func() {
+		// given
+		key := &entities.IdempotencyKey{Key: "key-3", Fingerprint: "fp-1", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+		_, reserved, err := repo.Reserve(s.testDb.Ctx, key)
+		s.Require().Nil(err)
+		s.Require().True(reserved)
+		// when
+		s.Require().Nil(repo.Release(s.testDb.Ctx, key.AccountID, key.Key))
+		_, reserved, err = repo.Reserve(s.testDb.Ctx, key)
+		// then
+		s.Nil(err)
+		s.True(reserved)
+	})
+
+	s.Run("given key with different fingerprint completion should return not found error", func() {
+		// given
+		key := &entities.IdempotencyKey{Key: "key-4", Fingerprint: "fp-1", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+		_, _, err := repo.Reserve(s.testDb.Ctx, key)
+		s.Require().Nil(err)
+		// when
+		err = repo.Complete(s.testDb.Ctx, &entities.IdempotencyKey{Key: "key-4", Fingerprint: "fp-2", StatusCode: http.StatusCreated})
+		// then
+		s.ErrorIs(err, ErrNotFound)
+	})
+
+	s.Run("should keep keys of accounts apart", func() {
+		// given
+		first := &entities.IdempotencyKey{AccountID: uuid.New(), Key: "key-5", Fingerprint: "fp-1", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+		_, reserved, err := repo.Reserve(s.testDb.Ctx, first)
+		s.Require().Nil(err)
+		s.Require().True(reserved)
+		// when another account uses the key
+		second := &entities.IdempotencyKey{AccountID: uuid.New(), Key: "key-5", Fingerprint: "fp-2", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+		_, reserved, err = repo.Reserve(s.testDb.Ctx, second)
+		// then
+		s.Require().Nil(err)
+		s.True(reserved)
+
+		// when the other account releases its key
+		s.Require().Nil(repo.Release(s.testDb.Ctx, second.AccountID, second.Key))
+		stored, reserved, err := repo.Reserve(s.testDb.Ctx, &entities.IdempotencyKey{AccountID: first.AccountID, Key: "key-5", Fingerprint: "fp-1", CreatedAt: now, ExpiresAt: now.Add(time.Hour)})
+		// then
+		s.Require().Nil(err)
+		s.False(reserved)
+		s.Equal("fp-1", stored.Fingerprint)
+	})
+
+	s.Run("should delete expired keys only", func() {
+		// given
+		expired := &entities.IdempotencyKey{Key: "key-6", Fingerprint: "fp-1", CreatedAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Hour)}
+		_, _, err := repo.Reserve(s.testDb.Ctx, expired)
+		s.Require().Nil(err)
+		// when
+		deleted, err := repo.DeleteExpired(s.testDb.Ctx, now)
+		// then
+		s.Require().Nil(err)
+		s.GreaterOrEqual(deleted, 1)
+		_, reserved, err := repo.Reserve(s.testDb.Ctx, &entities.IdempotencyKey{Key: "key-1", Fingerprint: "fp-1", CreatedAt: now, ExpiresAt: now.Add(time.Hour)})
+		s.Require().Nil(err)
+		s.False(reserved)
+	})
+}
@@ -5,48 +5,336 @@ import (
 	"github.com/fmiskovic/new-amz/internal/core/entities"
 	"github.com/google/uuid"
 	"github.com/uptrace/bun"
+	"strconv"
+	"time"
 )
 
+// defaultSimilarityThreshold is the minimal word similarity of titles found by fuzzy search if not configured otherwise.
+const defaultSimilarityThreshold = 0.3
+
 // ItemRepository is the implementation of core repositories.ItemRepository interface.
 type ItemRepository struct {
-	bunDb *bun.DB
+	bunDb               *bun.DB
+	similarityThreshold float64
+}
+
+// ItemRepositoryOption function is used for configuring new ItemRepository.
+type ItemRepositoryOption func(*ItemRepository)
+
+// WithSimilarityThreshold sets the minimal word similarity, between 0 and 1, of titles found by fuzzy search.
+// Lower thresholds tolerate more typos but find more unrelated titles.
+func WithSimilarityThreshold(threshold float64) ItemRepositoryOption {
+	return func(repo *ItemRepository) {
+		if threshold > 0 && threshold <= 1 {
+			repo.similarityThreshold = threshold
+		}
+	}
 }
 
 // NewItemRepository instantiates new ItemRepository.
-func NewItemRepository(db *bun.DB) ItemRepository {
-	return ItemRepository{db}
+func NewItemRepository(db *bun.DB, opts ...ItemRepositoryOption) ItemRepository {
+	repo := ItemRepository{bunDb: db, similarityThreshold: defaultSimilarityThreshold}
+	for _, opt := range opts {
+		opt(&repo)
+	}
+	return repo
 }
 
-// GetById returns item by specified id.
+// Constraints of items and their authors, see violatedConstraint.
+const (
+	itemIsbnConstraint      = "items_isbn_idx"
+	itemPublisherConstraint = "fk_publisher"
+	itemAuthorConstraint    = "fk_author"
+)
+
+// GetById returns item by specified id, with its publisher and authors.
 func (repo ItemRepository) GetById(ctx context.Context, id uuid.UUID) (entities.Item, error) {
 	item := new(entities.Item)
 
-	err := repo.bunDb.NewSelect().Model(item).Where("id = ?", id).Scan(ctx)
+	err := withBookDetails(conn(ctx, repo.bunDb).NewSelect().Model(item)).Where("?TableAlias.id = ?", id).Scan(ctx)
 	if err != nil {
-		return *item, err
+		return *item, dbError(err)
 	}
 
 	return *item, nil
 }
 
-// GetPage respond with a page of items.
+// GetByISBN returns item by its ISBN-13, with its publisher and authors.
+func (repo ItemRepository) GetByISBN(ctx context.Context, isbn string) (entities.Item, error) {
+	item := new(entities.Item)
+
+	err := withBookDetails(conn(ctx, repo.bunDb).NewSelect().Model(item)).Where("?TableAlias.isbn = ?", isbn).Scan(ctx)
+	if err != nil {
+		return *item, dbError(err)
+	}
+
+	return *item, nil
+}
+
+// GetPage respond with a page of items, with their publishers and authors.
 func (repo ItemRepository) GetPage(ctx context.Context, p entities.Pageable) (entities.Page[entities.Item], error) {
 	var items []entities.Item
-	count, err := repo.bunDb.NewSelect().
-		Model(&items).
-		Limit(p.Size).
-		Offset(p.Offset).
-		Order(entities.StringifyOrders(p.Sort)...).
-		ScanAndCount(ctx)
-
-	totalPages := 0
-	if count != 0 && p.Size != 0 {
-		totalPages = (len(items) + p.Size - 1) / p.Size
-	}
-
-	return entities.Page[entities.Item]{
-		TotalPages:    totalPages,
-		TotalElements: count,
-		Elements:      items,
-	}, err
+	q := withBookDetails(conn(ctx, repo.bunDb).NewSelect().Model(&items))
+	if p.Keyset {
+		return keysetPage(ctx, q, &items, entities.ItemSortable, p)
+	}
+	return offsetPage(ctx, q, &items, entities.ItemSortable, p)
+}
+
+// GetPageByCategory respond with a page of items assigned to the category or any of its subcategories.
+func (repo ItemRepository) GetPageByCategory(ctx context.Context, categoryId uuid.UUID, p entities.Pageable) (entities.Page[entities.Item], error) {
+	var items []entities.Item
+	q := withBookDetails(conn(ctx, repo.bunDb).NewSelect().
+		WithRecursive(subtreeName, categorySubtree(conn(ctx, repo.bunDb), categoryId)).
+		Model(&items)).
+		Where("EXISTS (?)", conn(ctx, repo.bunDb).NewSelect().
+			Model((*entities.ItemCategory)(nil)).
+			Where("?TableAlias.item_id = i.id").
+			Where("?TableAlias.category_id IN (SELECT id FROM ?)", bun.Ident(subtreeName)))
+	if p.Keyset {
+		return keysetPage(ctx, q, &items, entities.ItemSortable, p)
+	}
+	return offsetPage(ctx, q, &items, entities.ItemSortable, p)
+}
+
+// GetPageByAuthor respond with a page of items the author is credited on.
+// It returns ErrNotFound if the author does not exist.
+func (repo ItemRepository) GetPageByAuthor(ctx context.Context, authorId uuid.UUID, p entities.Pageable) (entities.Page[entities.Item], error) {
+	var items []entities.Item
+	q := withBookDetails(conn(ctx, repo.bunDb).NewSelect().Model(&items)).
+		Where("EXISTS (?)", conn(ctx, repo.bunDb).NewSelect().
+			Model((*entities.ItemAuthor)(nil)).
+			Where("?TableAlias.item_id = i.id").
+			Where("?TableAlias.author_id = ?", authorId))
+
+	var page entities.Page[entities.Item]
+	var err error
+	if p.Keyset {
+		page, err = keysetPage(ctx, q, &items, entities.ItemSortable, p)
+	} else {
+		page, err = offsetPage(ctx, q, &items, entities.ItemSortable, p)
+	}
+	if err != nil || len(page.Elements) > 0 {
+		return page, err
+	}
+
+	exists, err := conn(ctx, repo.bunDb).NewSelect().Model((*entities.Author)(nil)).Where("id = ?", authorId).Exists(ctx)
+	if err != nil {
+		return page, dbError(err)
+	}
+	if !exists {
+		return page, ErrNotFound
+	}
+	return page, nil
+}
+
+// Options of ts_headline: titles are highlighted in whole, descriptions are shortened to fragments around the matches.
+const (
+	titleHighlightOptions       = "StartSel=<mark>, StopSel=</mark>, HighlightAll=true"
+	descriptionHighlightOptions = "StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10, FragmentDelimiter=\" ... \""
+)
+
+// Search returns a page of items matching the full-text query, ranked by relevance.
+// The query is interpreted like web search engines do, e.g. "fantasy -dragons" or "\"space opera\"".
+func (repo ItemRepository) Search(ctx context.Context, query string, p entities.Pageable) (entities.Page[entities.ItemMatch], error) {
+	if p.Keyset || len(p.Sort.Orders) > 0 {
+		return entities.Page[entities.ItemMatch]{}, entities.ErrorSearchPaging
+	}
+
+	var matches []entities.ItemMatch
+	q := conn(ctx, repo.bunDb).NewSelect().
+		Model(&matches).
+		ColumnExpr("?TableColumns").
+		ColumnExpr("ts_rank(?TableAlias.search_vector, query) AS rank").
+		ColumnExpr("ts_headline('english', ?TableAlias.title, query, ?) AS title_highlight", titleHighlightOptions).
+		ColumnExpr("ts_headline('english', coalesce(?TableAlias.description, ''), query, ?) AS description_highlight", descriptionHighlightOptions).
+		TableExpr("websearch_to_tsquery('english', ?) AS query", query).
+		Where("?TableAlias.search_vector @@ query").
+		OrderExpr("rank DESC, ?TableAlias.id")
+
+	return offsetPage(ctx, q, &matches, nil, p)
+}
+
+// SearchSimilar returns a page of items with titles resembling the query, most similar first.
+// Titles are compared by trigram word similarity, which tolerates typos and matches parts of titles,
+// e.g. "harry poter" finds "Harry Potter and the Philosopher's Stone".
+func (repo ItemRepository) SearchSimilar(ctx context.Context, query string, p entities.Pageable) (entities.Page[entities.ItemMatch], error) {
+	if p.Keyset || len(p.Sort.Orders) > 0 {
+		return entities.Page[entities.ItemMatch]{}, entities.ErrorSearchPaging
+	}
+
+	var page entities.Page[entities.ItemMatch]
+	err := runInReadOnlyTx(ctx, repo.bunDb, func(ctx context.Context, tx bun.Tx) error {
+		if err := repo.setSimilarityThreshold(ctx, tx); err != nil {
+			return err
+		}
+
+		var matches []entities.ItemMatch
+		q := tx.NewSelect().
+			Model(&matches).
+			ColumnExpr("?TableColumns").
+			ColumnExpr("word_similarity(?, ?TableAlias.title) AS rank", query).
+			Where("? <% ?TableAlias.title", query).
+			OrderExpr("rank DESC, ?TableAlias.id")
+
+		var err error
+		page, err = offsetPage(ctx, q, &matches, nil, p)
+		return err
+	})
+	return page, dbError(err)
+}
+
+// Suggest returns up to limit distinct item titles closest to the query, to be offered as "did you mean" corrections.
+func (repo ItemRepository) Suggest(ctx context.Context, query string, limit int) ([]string, error) {
+	titles := make([]string, 0, limit)
+	err := runInReadOnlyTx(ctx, repo.bunDb, func(ctx context.Context, tx bun.Tx) error {
+		if err := repo.setSimilarityThreshold(ctx, tx); err != nil {
+			return err
+		}
+
+		return tx.NewSelect().
+			Model((*entities.Item)(nil)).
+			Column("title").
+			Where("? <% ?TableAlias.title", query).
+			GroupExpr("?TableAlias.title").
+			OrderExpr("word_similarity(?, ?TableAlias.title) DESC", query).
+			OrderExpr("similarity(?, ?TableAlias.title) DESC", query).
+			OrderExpr("?TableAlias.title").
+			Limit(limit).
+			Scan(ctx, &titles)
+	})
+	return titles, dbError(err)
+}
+
+// setSimilarityThreshold configures the threshold of the <% operator for the rest of the transaction,
+// so the operator can use the trigram index with the configured threshold.
+func (repo ItemRepository) setSimilarityThreshold(ctx context.Context, tx bun.Tx) error {
+	threshold := strconv.FormatFloat(repo.similarityThreshold, 'f', -1, 64)
+	_, err := tx.ExecContext(ctx, "SELECT set_config('pg_trgm.word_similarity_threshold', ?, true)", threshold)
+	return dbError(err)
+}
+
+// Create persists new item entity with its authors, the publisher and authors are loaded into the item.
+// It returns entities.ErrorIsbnNotUnique if another item has the ISBN,
+// entities.ErrorPublisherNotFound or entities.ErrorAuthorNotFound if the publisher or any of the authors does not exist.
+func (repo ItemRepository) Create(ctx context.Context, item *entities.Item) error {
+	if item == nil {
+		return ErrNilEntity
+	}
+
+	err := runInTx(ctx, repo.bunDb, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewInsert().Model(item).Exec(ctx); err != nil {
+			return err
+		}
+		if err := insertItemAuthors(ctx, tx, item); err != nil {
+			return err
+		}
+		return loadBookDetails(ctx, tx, item)
+	})
+	return itemError(err)
+}
+
+// Update persists changes of existing item entity and replaces its authors,
+// the publisher and authors are loaded into the item.
+// It returns ErrNotFound if the item does not exist, entities.ErrorVersionMismatch if another update
+// of the item came first, other errors are the ones of Create.
+func (repo ItemRepository) Update(ctx context.Context, item *entities.Item) error {
+	if item == nil {
+		return ErrNilEntity
+	}
+
+	item.UpdatedAt = time.Now()
+	err := runInTx(ctx, repo.bunDb, func(ctx context.Context, tx bun.Tx) error {
+		res, err := tx.NewUpdate().
+			Model(item).
+			ExcludeColumn("id", "created_at").
+			WherePK().
+			Apply(compareAndSwap(item.Version)).
+			Returning("created_at, version").
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+			return versionError(ctx, tx, (*entities.Item)(nil), item.ID, item.Version)
+		}
+
+		_, err = tx.NewDelete().Model((*entities.ItemAuthor)(nil)).Where("item_id = ?", item.ID).Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if err := insertItemAuthors(ctx, tx, item); err != nil {
+			return err
+		}
+		return loadBookDetails(ctx, tx, item)
+	})
+	return itemError(err)
+}
+
+// insertItemAuthors credits the authors of the item in their order, repeated authors are credited once.
+func insertItemAuthors(ctx context.Context, tx bun.Tx, item *entities.Item) error {
+	credits := make([]entities.ItemAuthor, 0, len(item.Authors))
+	seen := make(map[uuid.UUID]bool, len(item.Authors))
+	for _, author := range item.Authors {
+		if author != nil && !seen[author.ID] {
+			seen[author.ID] = true
+			credits = append(credits, entities.ItemAuthor{ItemID: item.ID, AuthorID: author.ID, Position: len(credits)})
+		}
+	}
+	if len(credits) == 0 {
+		return nil
+	}
+
+	_, err := tx.NewInsert().Model(&credits).Exec(ctx)
+	return err
+}
+
+// loadBookDetails reloads the item with its publisher and authors, replacing the ones it was given with.
+// Relations are cleared first, the publisher would be kept zeroed instead of nil and authors would be appended.
+func loadBookDetails(ctx context.Context, tx bun.Tx, item *entities.Item) error {
+	item.Publisher, item.Authors = nil, nil
+	return withBookDetails(tx.NewSelect().Model(item)).WherePK().Scan(ctx)
+}
+
+// withBookDetails loads the publisher and authors of the selected items, authors in the order they are credited.
+func withBookDetails(q *bun.SelectQuery) *bun.SelectQuery {
+	return q.
+		Relation("Publisher").
+		Relation("Authors", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.OrderExpr("ia.position ASC")
+		})
+}
+
+// itemError translates constraint violations of item changes into domain errors.
+func itemError(err error) error {
+	switch {
+	case violatedConstraint(err, pgUniqueViolation) == itemIsbnConstraint:
+		return entities.ErrorIsbnNotUnique
+	case violatedConstraint(err, pgForeignKeyViolation) == itemPublisherConstraint:
+		return entities.ErrorPublisherNotFound
+	case violatedConstraint(err, pgForeignKeyViolation) == itemAuthorConstraint:
+		return entities.ErrorAuthorNotFound
+	default:
+		return dbError(err)
+	}
+}
+
+// DeleteById deletes item by specified id, unless it is in another version than the non-zero version.
+// Items which are part of any order can not be deleted and entities.ErrorItemInUse is returned instead.
+func (repo ItemRepository) DeleteById(ctx context.Context, id uuid.UUID, version int64) error {
+	res, err := conn(ctx, repo.bunDb).NewDelete().
+		Model((*entities.Item)(nil)).
+		Where("id = ?", id).
+		Apply(whereVersion(version)).
+		Exec(ctx)
+	if err != nil {
+		if isPgError(err, pgForeignKeyViolation) {
+			return entities.ErrorItemInUse
+		}
+		return dbError(err)
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return versionError(ctx, conn(ctx, repo.bunDb), (*entities.Item)(nil), id, version)
+	}
+	return nil
 }
@@ -18,6 +18,25 @@ func (s *RepositoryTestSuite) TestGetItemById() {
 		s.Equal("Cool Book 1", item.Title)
 	})
 
+	s.Run("should return item with its book details", func() {
+		// given
+		itemId := uuid.MustParse("200cea28-b2b0-4051-9eb6-9a99e451af01")
+		// when
+		item, err := repo.GetById(s.testDb.Ctx, itemId)
+		// then
+		s.Nil(err)
+		s.Equal("9780306406157", item.ISBN)
+		s.Equal("2nd", item.Edition)
+		s.Equal("2001-05-01", item.PublicationDate.Format("2006-01-02"))
+		s.Equal(320, item.PageCount)
+		s.Equal("en", item.Language)
+		s.Require().NotNil(item.Publisher)
+		s.Equal("Cool Press", item.Publisher.Name)
+		s.Require().Len(item.Authors, 2)
+		s.Equal("Jane Writer", item.Authors[0].Name)
+		s.Equal("Joe Coauthor", item.Authors[1].Name)
+	})
+
 	s.Run("should return error if item not found", func() {
 		// given
 		itemId := uuid.MustParse("200cea28-b2b0-4051-9eb6-9a99e451af10")
@@ -28,6 +47,27 @@ func (s *RepositoryTestSuite) TestGetItemById() {
 	})
 }
 
+func (s *RepositoryTestSuite) TestGetItemByISBN() {
+	repo := NewItemRepository(s.testDb.BunDb)
+
+	s.Run("should return item by isbn", func() {
+		// when
+		item, err := repo.GetByISBN(s.testDb.Ctx, "9783161484100")
+		// then
+		s.Nil(err)
+		s.Equal("Cool Book 2", item.Title)
+		s.Equal("Cool Press", item.Publisher.Name)
+		s.Len(item.Authors, 1)
+	})
+
+	s.Run("should return error if isbn not found", func() {
+		// when
+		_, err := repo.GetByISBN(s.testDb.Ctx, "9780747532699")
+		// then
+		s.ErrorIs(err, ErrNotFound)
+	})
+}
+
 func (s *RepositoryTestSuite) TestGetItemsPage() {
 	repo := NewItemRepository(s.testDb.BunDb)
 
@@ -50,10 +90,39 @@ func (s *RepositoryTestSuite) TestGetItemsPage() {
 		// then
 		s.Nil(err)
 		s.Len(page.Elements, 2)
-		s.Equal(5, page.TotalElements)
+		s.Equal(5, *page.TotalElements)
+		s.Equal(3, *page.TotalPages)
+		s.Equal(0, page.Number)
+		s.True(page.HasNext)
+		s.False(page.HasPrev)
 		s.Equal("Cool Book 5", page.Elements[0].Title)
 	})
 
+	s.Run("given offset of last page should return last page", func() {
+		// given
+		pageRequest := entities.Pageable{Size: 2, Offset: 4}
+		// when
+		page, err := repo.GetPage(s.testDb.Ctx, pageRequest)
+		// then
+		s.Nil(err)
+		s.Len(page.Elements, 1)
+		s.Equal(2, page.Number)
+		s.False(page.HasNext)
+		s.True(page.HasPrev)
+	})
+
+	s.Run("given page request without count should tell whether there is next page", func() {
+		// given
+		pageRequest := entities.Pageable{Size: 4, SkipCount: true}
+		// when
+		page, err := repo.GetPage(s.testDb.Ctx, pageRequest)
+		// then
+		s.Nil(err)
+		s.Len(page.Elements, 4)
+		s.Nil(page.TotalElements)
+		s.True(page.HasNext)
+	})
+
 	s.Run("given zero page request should return page of items", func() {
 		// given
 		pageRequest := entities.Pageable{}
@@ -62,6 +131,433 @@ func (s *RepositoryTestSuite) TestGetItemsPage() {
 		// then
 		s.Nil(err)
 		s.Len(page.Elements, 5)
-		s.Equal(5, page.TotalElements)
+		s.Equal(5, *page.TotalElements)
+	})
+
+	s.Run("given keyset page request should page forward and backward by cursor", func() {
+		// given
+		pageRequest := entities.Pageable{
+			Size:   2,
+			Keyset: true,
+			Sort:   entities.NewSort(entities.NewSortOrder(entities.WithProperty("title"), entities.WithDirection(entities.ASC))),
+		}
+		var titles []string
+
+		// when
+		first, err := repo.GetPage(s.testDb.Ctx, pageRequest)
+		s.Nil(err)
+		pageRequest.Cursor = first.Cursors.Next
+		second, err := repo.GetPage(s.testDb.Ctx, pageRequest)
+		s.Nil(err)
+		pageRequest.Cursor = second.Cursors.Next
+		last, err := repo.GetPage(s.testDb.Ctx, pageRequest)
+		s.Nil(err)
+		pageRequest.Cursor = last.Cursors.Prev
+		back, err := repo.GetPage(s.testDb.Ctx, pageRequest)
+		s.Nil(err)
+
+		// then
+		for _, page := range []entities.Page[entities.Item]{first, second, last} {
+			for _, item := range page.Elements {
+				titles = append(titles, item.Title)
+			}
+		}
+		s.Equal([]string{"Cool Book 1", "Cool Book 2", "Cool Book 3", "Cool Book 4", "Cool Book 5"}, titles)
+		s.Equal(5, *first.TotalElements)
+		s.Empty(first.Cursors.Prev)
+		s.NotEmpty(second.Cursors.Prev)
+		s.Empty(last.Cursors.Next)
+		s.False(last.HasNext)
+		s.True(last.HasPrev)
+		s.Equal(second.Elements, back.Elements)
+	})
+
+	s.Run("given keyset page request without count should skip total", func() {
+		// given
+		pageRequest := entities.Pageable{Size: 10, Keyset: true, SkipCount: true}
+		// when
+		page, err := repo.GetPage(s.testDb.Ctx, pageRequest)
+		// then
+		s.Nil(err)
+		s.Len(page.Elements, 5)
+		s.False(page.Cursors.Counted)
+		s.Nil(page.TotalElements)
+		s.Empty(page.Cursors.Next)
+	})
+
+	s.Run("given cursor of different sort should return validation error", func() {
+		// given
+		pageRequest := entities.Pageable{Size: 2, Keyset: true}
+		page, err := repo.GetPage(s.testDb.Ctx, pageRequest)
+		s.Nil(err)
+		pageRequest.Cursor = page.Cursors.Next
+		pageRequest.Sort = entities.NewSort(entities.NewSortOrder(entities.WithProperty("stock")))
+		// when
+		_, err = repo.GetPage(s.testDb.Ctx, pageRequest)
+		// then
+		s.ErrorIs(err, entities.ErrorInvalidCursor)
+	})
+
+	s.Run("given non sortable property should return validation error", func() {
+		// given
+		pageRequest := entities.Pageable{
+			Sort: entities.NewSort(entities.NewSortOrder(entities.WithProperty("title; DROP TABLE items"))),
+		}
+		// when
+		_, err := repo.GetPage(s.testDb.Ctx, pageRequest)
+		// then
+		s.ErrorIs(err, entities.ErrorValidation)
+	})
+
+	s.Run("given unsupported direction should return validation error", func() {
+		// given
+		pageRequest := entities.Pageable{
+			Sort: entities.NewSort(entities.NewSortOrder(
+				entities.WithProperty("price"),
+				entities.WithDirection("DESC, (SELECT 1)"),
+			)),
+		}
+		// when
+		_, err := repo.GetPage(s.testDb.Ctx, pageRequest)
+		// then
+		s.ErrorIs(err, entities.ErrorValidation)
+	})
+}
+
+func (s *RepositoryTestSuite) TestGetItemsPageByCategory() {
+	repo := NewItemRepository(s.testDb.BunDb)
+
+	s.Run("should return items of the category and its subcategories", func() {
+		// given
+		pageRequest := entities.Pageable{Size: 10, Sort: entities.NewSort(entities.NewSortOrder(entities.WithProperty("title"), entities.WithDirection(entities.ASC)))}
+		// when
+		page, err := repo.GetPageByCategory(s.testDb.Ctx, fictionId, pageRequest)
+		// then
+		s.Nil(err)
+		s.Equal(3, *page.TotalElements)
+		s.Equal("Cool Book 1", page.Elements[0].Title)
+		s.Equal("Cool Book 2", page.Elements[1].Title)
+		s.Equal("Cool Book 3", page.Elements[2].Title)
+	})
+
+	s.Run("given leaf category should return its items only", func() {
+		// when
+		page, err := repo.GetPageByCategory(s.testDb.Ctx, epicFantasyId, entities.Pageable{Size: 10})
+		// then
+		s.Nil(err)
+		s.Equal(1, *page.TotalElements)
+		s.Equal("Cool Book 1", page.Elements[0].Title)
+	})
+
+	s.Run("should return items of the category by cursor", func() {
+		// given
+		pageRequest := entities.Pageable{Size: 1, Keyset: true}
+		// when
+		first, err := repo.GetPageByCategory(s.testDb.Ctx, fantasyId, pageRequest)
+		s.Require().Nil(err)
+		pageRequest.Cursor = first.Cursors.Next
+		second, err := repo.GetPageByCategory(s.testDb.Ctx, fantasyId, pageRequest)
+		// then
+		s.Nil(err)
+		s.Equal(2, *first.TotalElements)
+		s.Len(second.Elements, 1)
+		s.NotEqual(first.Elements[0].ID, second.Elements[0].ID)
+		s.False(second.HasNext)
+	})
+
+	s.Run("given unknown category should return empty page", func() {
+		// when
+		page, err := repo.GetPageByCategory(s.testDb.Ctx, uuid.New(), entities.Pageable{Size: 10})
+		// then
+		s.Nil(err)
+		s.Empty(page.Elements)
+	})
+}
+
+func (s *RepositoryTestSuite) TestGetItemsPageByAuthor() {
+	repo := NewItemRepository(s.testDb.BunDb)
+
+	s.Run("should return items the author is credited on", func() {
+		// given
+		pageRequest := entities.Pageable{Size: 10, Sort: entities.NewSort(entities.NewSortOrder(entities.WithProperty("title"), entities.WithDirection(entities.ASC)))}
+		// when
+		page, err := repo.GetPageByAuthor(s.testDb.Ctx, janeWriterId, pageRequest)
+		// then
+		s.Nil(err)
+		s.Equal(2, *page.TotalElements)
+		s.Equal("Cool Book 1", page.Elements[0].Title)
+		s.Equal("Cool Book 2", page.Elements[1].Title)
+		s.Len(page.Elements[0].Authors, 2)
+	})
+
+	s.Run("should return items of the author by cursor", func() {
+		// given
+		pageRequest := entities.Pageable{Size: 1, Keyset: true}
+		// when
+		first, err := repo.GetPageByAuthor(s.testDb.Ctx, janeWriterId, pageRequest)
+		s.Require().Nil(err)
+		pageRequest.Cursor = first.Cursors.Next
+		second, err := repo.GetPageByAuthor(s.testDb.Ctx, janeWriterId, pageRequest)
+		// then
+		s.Nil(err)
+		s.Len(second.Elements, 1)
+		s.NotEqual(first.Elements[0].ID, second.Elements[0].ID)
+		s.False(second.HasNext)
+	})
+
+	s.Run("given author without items should return empty page", func() {
+		// when
+		page, err := repo.GetPageByAuthor(s.testDb.Ctx, unpublishedAuthorId, entities.Pageable{Size: 10})
+		// then
+		s.Nil(err)
+		s.Empty(page.Elements)
+	})
+
+	s.Run("given unknown author should return error", func() {
+		// when
+		_, err := repo.GetPageByAuthor(s.testDb.Ctx, uuid.New(), entities.Pageable{Size: 10})
+		// then
+		s.ErrorIs(err, ErrNotFound)
+	})
+}
+
+func (s *RepositoryTestSuite) TestSearchItems() {
+	repo := NewItemRepository(s.testDb.BunDb)
+
+	s.Run("should return ranked items with highlighted matches", func() {
+		// given
+		item := entities.NewItemBuilder().
+			Title("Dune").
+			Description("Politics and spice on the desert planet Arrakis").
+			Price(entities.NewMoney(1500, entities.EUR)).
+			Build()
+		s.Require().Nil(repo.Create(s.testDb.Ctx, item))
+		defer func() { _ = repo.DeleteById(s.testDb.Ctx, item.ID, 0) }()
+
+		// when
+		page, err := repo.Search(s.testDb.Ctx, "desert planets", entities.Pageable{Size: 10})
+
+		// then
+		s.Nil(err)
+		s.Equal(1, *page.TotalElements)
+		s.Equal(item.ID, page.Elements[0].ID)
+		s.Greater(page.Elements[0].Rank, 0.0)
+		s.Equal("Dune", page.Elements[0].TitleHighlight)
+		s.Contains(page.Elements[0].DescriptionHighlight, "<mark>desert</mark> <mark>planet</mark>")
+	})
+
+	s.Run("should rank title matches above description matches", func() {
+		// given
+		item := entities.NewItemBuilder().
+			Title("Book of Cool").
+			Price(entities.NewMoney(1500, entities.EUR)).
+			Build()
+		s.Require().Nil(repo.Create(s.testDb.Ctx, item))
+		defer func() { _ = repo.DeleteById(s.testDb.Ctx, item.ID, 0) }()
+
+		// when
+		page, err := repo.Search(s.testDb.Ctx, "cool book", entities.Pageable{Size: 2})
+
+		// then
+		s.Nil(err)
+		s.Equal(6, *page.TotalElements)
+		s.Len(page.Elements, 2)
+		s.True(page.HasNext)
+		s.GreaterOrEqual(page.Elements[0].Rank, page.Elements[1].Rank)
+		s.Contains(page.Elements[0].TitleHighlight, "<mark>Cool</mark>")
+	})
+
+	s.Run("given query without matches should return empty page", func() {
+		// when
+		page, err := repo.Search(s.testDb.Ctx, "nonexistent", entities.Pageable{Size: 10})
+		// then
+		s.Nil(err)
+		s.Empty(page.Elements)
+		s.Equal(0, *page.TotalElements)
+	})
+
+	s.Run("given keyset page request should return validation error", func() {
+		// when
+		_, err := repo.Search(s.testDb.Ctx, "cool", entities.Pageable{Size: 10, Keyset: true})
+		// then
+		s.ErrorIs(err, entities.ErrorSearchPaging)
+	})
+}
+
+func (s *RepositoryTestSuite) TestSearchSimilarItems() {
+	repo := NewItemRepository(s.testDb.BunDb)
+
+	s.Run("given mistyped title should return items with similar titles", func() {
+		// when
+		page, err := repo.SearchSimilar(s.testDb.Ctx, "Kool Bok 3", entities.Pageable{Size: 10})
+		// then
+		s.Nil(err)
+		s.NotEmpty(page.Elements)
+		s.Equal("Cool Book 3", page.Elements[0].Title)
+		s.Greater(page.Elements[0].Rank, 0.0)
+		for i := 1; i < len(page.Elements); i++ {
+			s.GreaterOrEqual(page.Elements[i-1].Rank, page.Elements[i].Rank)
+		}
+	})
+
+	s.Run("given unrelated query should return empty page", func() {
+		// when
+		page, err := repo.SearchSimilar(s.testDb.Ctx, "xyz", entities.Pageable{Size: 10})
+		// then
+		s.Nil(err)
+		s.Empty(page.Elements)
+	})
+
+	s.Run("given high threshold should return only close titles", func() {
+		// given
+		strict := NewItemRepository(s.testDb.BunDb, WithSimilarityThreshold(0.95))
+		// when
+		page, err := strict.SearchSimilar(s.testDb.Ctx, "Kool Bok 3", entities.Pageable{Size: 10})
+		// then
+		s.Nil(err)
+		s.Empty(page.Elements)
+	})
+}
+
+func (s *RepositoryTestSuite) TestSuggestItems() {
+	repo := NewItemRepository(s.testDb.BunDb)
+
+	s.Run("given mistyped title should suggest closest titles", func() {
+		// when
+		titles, err := repo.Suggest(s.testDb.Ctx, "cool bok 4", 3)
+		// then
+		s.Nil(err)
+		s.Len(titles, 3)
+		s.Equal("Cool Book 4", titles[0])
+	})
+
+	s.Run("given unrelated query should suggest nothing", func() {
+		// when
+		titles, err := repo.Suggest(s.testDb.Ctx, "xyz", 3)
+		// then
+		s.Nil(err)
+		s.Empty(titles)
+	})
+}
+
+func (s *RepositoryTestSuite) TestManageItem() {
+	repo := NewItemRepository(s.testDb.BunDb)
+
+	s.Run("should create, update and delete item", func() {
+		// given
+		item := entities.NewItemBuilder().
+			Title("New Book").
+			Description("A brand new book").
+			Price(entities.NewMoney(1999, entities.EUR)).
+			Stock(10).
+			Build()
+
+		// when
+		err := repo.Create(s.testDb.Ctx, item)
+		// then
+		s.Nil(err)
+
+		// when
+		item.Title = "Renamed Book"
+		item.Stock = 5
+		err = repo.Update(s.testDb.Ctx, item)
+		// then
+		s.Nil(err)
+		updated, err := repo.GetById(s.testDb.Ctx, item.ID)
+		s.Nil(err)
+		s.Equal("Renamed Book", updated.Title)
+		s.Equal(5, updated.Stock)
+		s.Equal(entities.NewMoney(1999, entities.EUR), updated.Price)
+
+		// when
+		err = repo.DeleteById(s.testDb.Ctx, item.ID, 0)
+		// then
+		s.Nil(err)
+		_, err = repo.GetById(s.testDb.Ctx, item.ID)
+		s.NotNil(err)
+	})
+
+	s.Run("should create and update book with its publisher and authors", func() {
+		// given
+		item := entities.NewItemBuilder().
+			Title("New Novel").
+			Price(entities.NewMoney(1999, entities.EUR)).
+			ISBN("9780804429573").
+			Publisher(smallPressId).
+			PageCount(250).
+			Authors(joeCoauthorId, janeWriterId).
+			Build()
+
+		// when
+		err := repo.Create(s.testDb.Ctx, item)
+		// then
+		s.Nil(err)
+		s.Equal("Small Press", item.Publisher.Name)
+		s.Require().Len(item.Authors, 2)
+		s.Equal("Joe Coauthor", item.Authors[0].Name)
+		s.Equal("Jane Writer", item.Authors[1].Name)
+
+		// when
+		item.Authors = []*entities.Author{{Entity: entities.Entity{ID: janeWriterId}}}
+		item.PublisherID = nil
+		err = repo.Update(s.testDb.Ctx, item)
+		// then
+		s.Nil(err)
+		s.Nil(item.Publisher)
+		s.Len(item.Authors, 1)
+		updated, err := repo.GetByISBN(s.testDb.Ctx, "9780804429573")
+		s.Nil(err)
+		s.Nil(updated.Publisher)
+		s.Require().Len(updated.Authors, 1)
+		s.Equal("Jane Writer", updated.Authors[0].Name)
+
+		s.Nil(repo.DeleteById(s.testDb.Ctx, item.ID, 0))
+	})
+
+	s.Run("should not create book with taken isbn", func() {
+		// given
+		item := entities.NewItemBuilder().Title("Copy").Price(entities.NewMoney(100, entities.EUR)).ISBN("9780306406157").Build()
+		// when
+		err := repo.Create(s.testDb.Ctx, item)
+		// then
+		s.ErrorIs(err, entities.ErrorIsbnNotUnique)
+	})
+
+	s.Run("should not create book of unknown publisher or author", func() {
+		// given
+		item := entities.NewItemBuilder().Title("Orphan").Price(entities.NewMoney(100, entities.EUR)).Publisher(uuid.New()).Build()
+		// when
+		err := repo.Create(s.testDb.Ctx, item)
+		// then
+		s.ErrorIs(err, entities.ErrorPublisherNotFound)
+
+		// given
+		item = entities.NewItemBuilder().Title("Orphan").Price(entities.NewMoney(100, entities.EUR)).Authors(uuid.New()).Build()
+		// when
+		err = repo.Create(s.testDb.Ctx, item)
+		// then
+		s.ErrorIs(err, entities.ErrorAuthorNotFound)
+		_, err = repo.GetById(s.testDb.Ctx, item.ID)
+		s.ErrorIs(err, ErrNotFound)
+	})
+
+	s.Run("should return error when updating non-existing item", func() {
+		// given
+		item := entities.NewItemBuilder().Title("Ghost").Price(entities.NewMoney(100, entities.EUR)).Build()
+		// when
+		err := repo.Update(s.testDb.Ctx, item)
+		// then
+		s.ErrorIs(err, ErrNotFound)
+	})
+
+	s.Run("should not delete item which is part of an order", func() {
+		// given
+		itemId := uuid.MustParse("200cea28-b2b0-4051-9eb6-9a99e451af01")
+		// when
+		err := repo.DeleteById(s.testDb.Ctx, itemId, 0)
+		// then
+		s.ErrorIs(err, entities.ErrorItemInUse)
+		_, err = repo.GetById(s.testDb.Ctx, itemId)
+		s.Nil(err)
 	})
 }
@@ -0,0 +1,155 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/repositories"
+	"github.com/google/uuid"
+)
+
+// AccountRepository is the in-memory implementation of core repositories.AccountRepository interface.
+type AccountRepository struct {
+	store *Store
+}
+
+// NewAccountRepository instantiates new AccountRepository keeping accounts in the store.
+func NewAccountRepository(store *Store) AccountRepository {
+	return AccountRepository{store}
+}
+
+// GetById returns account by specified id, soft deleted accounts are not found.
+func (repo AccountRepository) GetById(_ context.Context, id uuid.UUID) (entities.Account, error) {
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	acc, ok := repo.store.accounts[id]
+	if !ok || !acc.DeletedAt.IsZero() {
+		return entities.Account{}, repositories.ErrNotFound
+	}
+	return acc, nil
+}
+
+// GetByEmail returns active account by email.
+func (repo AccountRepository) GetByEmail(_ context.Context, email string) (entities.Account, error) {
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	if acc, ok := repo.store.activeAccountByEmail(email); ok {
+		return acc, nil
+	}
+	return entities.Account{}, repositories.ErrNotFound
+}
+
+// Create stores new account, accounts without a role become customers.
+// It returns entities.ErrorEmailNotUnique if an active account has the email.
+func (repo AccountRepository) Create(ctx context.Context, u *entities.Account) error {
+	if u == nil {
+		return repositories.ErrNilEntity
+	}
+
+	defer repo.store.lock(ctx)()
+
+	if _, exists := repo.store.accounts[u.ID]; exists {
+		return errDuplicateId
+	}
+	if _, taken := repo.store.activeAccountByEmail(u.Email); taken {
+		return entities.ErrorEmailNotUnique
+	}
+
+	initEntity(&u.Entity)
+	if u.Role == "" {
+		u.Role = entities.CUSTOMER
+	}
+	u.Orders = nil
+	repo.store.accounts[u.ID] = *u
+	return nil
+}
+
+// Update stores changes of account profile, if the account is still in the version of u,
+// and loads the stored account into u. Password, role and creation time of the account are never changed by update.
+// Errors are the ones of the PostgreSQL repository: repositories.ErrNotFound, entities.ErrorVersionMismatch
+// and entities.ErrorEmailNotUnique.
+func (repo AccountRepository) Update(ctx context.Context, u *entities.Account) error {
+	if u == nil {
+		return repositories.ErrNilEntity
+	}
+
+	defer repo.store.lock(ctx)()
+
+	acc, ok := repo.store.accounts[u.ID]
+	if !ok || !acc.DeletedAt.IsZero() {
+		return repositories.ErrNotFound
+	}
+	if !versionMatches(acc.Entity, u.Version) {
+		return entities.ErrorVersionMismatch
+	}
+	if other, taken := repo.store.activeAccountByEmail(u.Email); taken && other.ID != u.ID {
+		return entities.ErrorEmailNotUnique
+	}
+
+	acc.Email = u.Email
+	acc.FullName = u.FullName
+	acc.DateOfBirth = u.DateOfBirth
+	acc.Location = u.Location
+	acc.Gender = u.Gender
+	acc.UpdatedAt = now()
+	acc.Version++
+	repo.store.accounts[acc.ID] = acc
+	*u = acc
+	return nil
+}
+
+// DeleteById soft deletes account by specified id, if it is in the version or the version is zero.
+// It returns repositories.ErrNotFound if the account does not exist or is already deleted
+// and entities.ErrorVersionMismatch if it is in another version.
+func (repo AccountRepository) DeleteById(ctx context.Context, id uuid.UUID, version int64) error {
+	defer repo.store.lock(ctx)()
+
+	acc, ok := repo.store.accounts[id]
+	if !ok || !acc.DeletedAt.IsZero() {
+		return repositories.ErrNotFound
+	}
+	if !versionMatches(acc.Entity, version) {
+		return entities.ErrorVersionMismatch
+	}
+
+	acc.DeletedAt = now()
+	repo.store.accounts[id] = acc
+	return nil
+}
+
+// ForceDeleteById permanently deletes account by specified id, including soft deleted accounts,
+// if it is in the version or the version is zero. Orders of the account are deleted together with it.
+// It returns repositories.ErrNotFound if the account does not exist and entities.ErrorVersionMismatch if it is in another version.
+func (repo AccountRepository) ForceDeleteById(ctx context.Context, id uuid.UUID, version int64) error {
+	defer repo.store.lock(ctx)()
+
+	acc, ok := repo.store.accounts[id]
+	if !ok {
+		return repositories.ErrNotFound
+	}
+	if !versionMatches(acc.Entity, version) {
+		return entities.ErrorVersionMismatch
+	}
+
+	for orderId, order := range repo.store.orders {
+		if order.AccountID == id {
+			delete(repo.store.orders, orderId)
+			delete(repo.store.history, orderId)
+			delete(repo.store.refunds, orderId)
+		}
+	}
+	delete(repo.store.accounts, id)
+	return nil
+}
+
+// activeAccountByEmail finds the account which is not deleted and has the email.
+func (s *Store) activeAccountByEmail(email string) (entities.Account, bool) {
+	for _, acc := range s.accounts {
+		if acc.Email == email && acc.DeletedAt.IsZero() {
+			return acc, true
+		}
+	}
+	return entities.Account{}, false
+}
@@ -0,0 +1,94 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/repositories"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewAccountRepository(NewStore())
+
+	t.Run("should create customer account and find it by id and email", func(t *testing.T) {
+		// given
+		acc := entities.NewAccountBuilder().Email("jane@example.com").FullName("Jane").Build()
+		// when
+		err := repo.Create(ctx, acc)
+		// then
+		require.NoError(t, err)
+		assert.NotEqual(t, uuid.Nil, acc.ID)
+		assert.Equal(t, int64(1), acc.Version)
+		assert.Equal(t, entities.CUSTOMER, acc.Role)
+		found, err := repo.GetById(ctx, acc.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "Jane", found.FullName)
+		found, err = repo.GetByEmail(ctx, "jane@example.com")
+		assert.NoError(t, err)
+		assert.Equal(t, acc.ID, found.ID)
+	})
+
+	t.Run("should reject email of active account but reuse email of deleted one", func(t *testing.T) {
+		// given
+		acc := entities.NewAccountBuilder().Email("john@example.com").Build()
+		require.NoError(t, repo.Create(ctx, acc))
+
+		// when
+		err := repo.Create(ctx, entities.NewAccountBuilder().Email("john@example.com").Build())
+		// then
+		assert.ErrorIs(t, err, entities.ErrorEmailNotUnique)
+
+		// when
+		require.NoError(t, repo.DeleteById(ctx, acc.ID, acc.Version))
+		err = repo.Create(ctx, entities.NewAccountBuilder().Email("john@example.com").Build())
+		// then
+		assert.NoError(t, err)
+		_, err = repo.GetById(ctx, acc.ID)
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
+	})
+
+	t.Run("should update profile of account in expected version only", func(t *testing.T) {
+		// given
+		acc := entities.NewAccountBuilder().Email("ann@example.com").PasswordHash("hash").Build()
+		require.NoError(t, repo.Create(ctx, acc))
+		update := &entities.Account{Entity: entities.Entity{ID: acc.ID, Version: acc.Version}, Email: "ann@example.org", Location: "Berlin"}
+
+		// when
+		err := repo.Update(ctx, update)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), update.Version)
+		assert.Equal(t, "hash", update.PasswordHash)
+		assert.Equal(t, entities.CUSTOMER, update.Role)
+
+		// when updated in the old version again
+		update.Version = acc.Version
+		err = repo.Update(ctx, update)
+		// then
+		assert.ErrorIs(t, err, entities.ErrorVersionMismatch)
+	})
+
+	t.Run("should force delete account together with its orders", func(t *testing.T) {
+		// given
+		store := NewStore()
+		accounts, orders := NewAccountRepository(store), NewOrderRepository(store)
+		acc := entities.NewAccountBuilder().Email("max@example.com").Build()
+		require.NoError(t, accounts.Create(ctx, acc))
+		order := entities.NewOrderBuilder().AccountID(acc.ID).Build()
+		require.NoError(t, orders.Create(ctx, order))
+		require.NoError(t, accounts.DeleteById(ctx, acc.ID, 0))
+
+		// when
+		err := accounts.ForceDeleteById(ctx, acc.ID, 0)
+		// then
+		require.NoError(t, err)
+		_, err = orders.GetById(ctx, order.ID)
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
+		assert.ErrorIs(t, accounts.ForceDeleteById(ctx, acc.ID, 0), repositories.ErrNotFound)
+	})
+}
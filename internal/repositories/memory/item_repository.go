@@ -0,0 +1,308 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/repositories"
+	"github.com/google/uuid"
+)
+
+// defaultSimilarityThreshold is the minimal word similarity of titles found by fuzzy search if not configured otherwise.
+const defaultSimilarityThreshold = 0.3
+
+// ItemRepository is the in-memory implementation of core repositories.ItemRepository interface.
+// Publishers, authors and categories are not kept in memory, so items can not refer to any of them.
+type ItemRepository struct {
+	store               *Store
+	similarityThreshold float64
+}
+
+// ItemRepositoryOption function is used for configuring new ItemRepository.
+type ItemRepositoryOption func(*ItemRepository)
+
+// WithSimilarityThreshold sets the minimal word similarity, between 0 and 1, of titles found by fuzzy search.
+func WithSimilarityThreshold(threshold float64) ItemRepositoryOption {
+	return func(repo *ItemRepository) {
+		if threshold > 0 && threshold <= 1 {
+			repo.similarityThreshold = threshold
+		}
+	}
+}
+
+// NewItemRepository instantiates new ItemRepository keeping items in the store.
+func NewItemRepository(store *Store, opts ...ItemRepositoryOption) ItemRepository {
+	repo := ItemRepository{store: store, similarityThreshold: defaultSimilarityThreshold}
+	for _, opt := range opts {
+		opt(&repo)
+	}
+	return repo
+}
+
+// GetById returns item by specified id.
+func (repo ItemRepository) GetById(_ context.Context, id uuid.UUID) (entities.Item, error) {
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	item, ok := repo.store.items[id]
+	if !ok {
+		return entities.Item{}, repositories.ErrNotFound
+	}
+	return item, nil
+}
+
+// GetByISBN returns item by its ISBN-13.
+func (repo ItemRepository) GetByISBN(_ context.Context, isbn string) (entities.Item, error) {
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	for _, item := range repo.store.items {
+		if isbn != "" && item.ISBN == isbn {
+			return item, nil
+		}
+	}
+	return entities.Item{}, repositories.ErrNotFound
+}
+
+// GetPage respond with a page of items.
+func (repo ItemRepository) GetPage(_ context.Context, p entities.Pageable) (entities.Page[entities.Item], error) {
+	repo.store.mu.RLock()
+	items := make([]entities.Item, 0, len(repo.store.items))
+	for _, item := range repo.store.items {
+		items = append(items, item)
+	}
+	repo.store.mu.RUnlock()
+
+	return pageOf(items, itemColumn, entities.ItemSortable, p)
+}
+
+// GetPageByCategory respond with an empty page, as no items are assigned to categories in memory.
+func (repo ItemRepository) GetPageByCategory(_ context.Context, _ uuid.UUID, p entities.Pageable) (entities.Page[entities.Item], error) {
+	return pageOf(nil, itemColumn, entities.ItemSortable, p)
+}
+
+// GetPageByAuthor respond with an empty page and repositories.ErrNotFound, as authors are not kept in memory.
+func (repo ItemRepository) GetPageByAuthor(_ context.Context, _ uuid.UUID, p entities.Pageable) (entities.Page[entities.Item], error) {
+	page, err := pageOf(nil, itemColumn, entities.ItemSortable, p)
+	if err != nil {
+		return page, err
+	}
+	return page, repositories.ErrNotFound
+}
+
+// Search returns a page of items matching the web search query in title or description, ranked by relevance:
+// the number of matched words, words of the title counting double. Matched words are highlighted.
+func (repo ItemRepository) Search(_ context.Context, query string, p entities.Pageable) (entities.Page[entities.ItemMatch], error) {
+	if p.Keyset || len(p.Sort.Orders) > 0 {
+		return entities.Page[entities.ItemMatch]{}, entities.ErrorSearchPaging
+	}
+
+	q := parseTextQuery(query)
+	var matches []entities.ItemMatch
+	repo.store.mu.RLock()
+	for _, item := range repo.store.items {
+		ok, matched := q.match(item.Title, item.Description)
+		if !ok {
+			continue
+		}
+		matches = append(matches, entities.ItemMatch{
+			Item:                 item,
+			Rank:                 float64(2*len(matched[0]) + len(matched[1])),
+			TitleHighlight:       highlight(item.Title, matched[0]),
+			DescriptionHighlight: highlight(item.Description, matched[1]),
+		})
+	}
+	repo.store.mu.RUnlock()
+
+	sortByRank(matches)
+	return offsetPage(matches, p), nil
+}
+
+// SearchSimilar returns a page of items with titles resembling the query, most similar first.
+// Titles are compared by trigram word similarity, which tolerates typos and matches parts of titles.
+func (repo ItemRepository) SearchSimilar(_ context.Context, query string, p entities.Pageable) (entities.Page[entities.ItemMatch], error) {
+	if p.Keyset || len(p.Sort.Orders) > 0 {
+		return entities.Page[entities.ItemMatch]{}, entities.ErrorSearchPaging
+	}
+
+	var matches []entities.ItemMatch
+	repo.store.mu.RLock()
+	for _, item := range repo.store.items {
+		if rank := wordSimilarity(query, item.Title); rank >= repo.similarityThreshold {
+			matches = append(matches, entities.ItemMatch{Item: item, Rank: rank})
+		}
+	}
+	repo.store.mu.RUnlock()
+
+	sortByRank(matches)
+	return offsetPage(matches, p), nil
+}
+
+// Suggest returns up to limit distinct item titles closest to the query, to be offered as "did you mean" corrections.
+func (repo ItemRepository) Suggest(_ context.Context, query string, limit int) ([]string, error) {
+	type suggestion struct {
+		title                           string
+		wordSimilarity, wholeSimilarity float64
+	}
+
+	seen := make(map[string]bool)
+	var suggestions []suggestion
+	repo.store.mu.RLock()
+	for _, item := range repo.store.items {
+		if seen[item.Title] {
+			continue
+		}
+		if ws := wordSimilarity(query, item.Title); ws >= repo.similarityThreshold {
+			seen[item.Title] = true
+			suggestions = append(suggestions, suggestion{item.Title, ws, similarity(trigrams(query), trigrams(item.Title))})
+		}
+	}
+	repo.store.mu.RUnlock()
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		a, b := suggestions[i], suggestions[j]
+		if a.wordSimilarity != b.wordSimilarity {
+			return a.wordSimilarity > b.wordSimilarity
+		}
+		if a.wholeSimilarity != b.wholeSimilarity {
+			return a.wholeSimilarity > b.wholeSimilarity
+		}
+		return a.title < b.title
+	})
+
+	titles := make([]string, 0, limit)
+	for i := 0; i < len(suggestions) && i < limit; i++ {
+		titles = append(titles, suggestions[i].title)
+	}
+	return titles, nil
+}
+
+// Create stores new item entity.
+// It returns entities.ErrorIsbnNotUnique if another item has the ISBN,
+// entities.ErrorPublisherNotFound or entities.ErrorAuthorNotFound if the item refers to a publisher or authors.
+func (repo ItemRepository) Create(ctx context.Context, item *entities.Item) error {
+	if item == nil {
+		return repositories.ErrNilEntity
+	}
+
+	defer repo.store.lock(ctx)()
+
+	if _, exists := repo.store.items[item.ID]; exists {
+		return errDuplicateId
+	}
+	if err := repo.store.checkItem(item); err != nil {
+		return err
+	}
+	initEntity(&item.Entity)
+	repo.store.items[item.ID] = storedItem(*item)
+	return nil
+}
+
+// Update stores changes of existing item entity, all but its id and creation time.
+// It returns repositories.ErrNotFound if the item does not exist, entities.ErrorVersionMismatch if another update
+// of the item came first, other errors are the ones of Create.
+func (repo ItemRepository) Update(ctx context.Context, item *entities.Item) error {
+	if item == nil {
+		return repositories.ErrNilEntity
+	}
+
+	defer repo.store.lock(ctx)()
+
+	stored, ok := repo.store.items[item.ID]
+	if !ok {
+		return repositories.ErrNotFound
+	}
+	if !versionMatches(stored.Entity, item.Version) {
+		return entities.ErrorVersionMismatch
+	}
+	if err := repo.store.checkItem(item); err != nil {
+		return err
+	}
+
+	item.CreatedAt = stored.CreatedAt
+	item.UpdatedAt = now()
+	item.Version = stored.Version + 1
+	repo.store.items[item.ID] = storedItem(*item)
+	return nil
+}
+
+// DeleteById deletes item by specified id, unless it is in another version than the non-zero version.
+// Items which are part of any order can not be deleted and entities.ErrorItemInUse is returned instead.
+func (repo ItemRepository) DeleteById(ctx context.Context, id uuid.UUID, version int64) error {
+	defer repo.store.lock(ctx)()
+
+	item, ok := repo.store.items[id]
+	if !ok {
+		return repositories.ErrNotFound
+	}
+	if !versionMatches(item.Entity, version) {
+		return entities.ErrorVersionMismatch
+	}
+	for _, order := range repo.store.orders {
+		for _, oi := range order.OrderItems {
+			if oi.ItemID == id {
+				return entities.ErrorItemInUse
+			}
+		}
+	}
+
+	delete(repo.store.items, id)
+	return nil
+}
+
+// checkItem enforces the constraints of items the database would: unique ISBNs and existing publishers and authors.
+func (s *Store) checkItem(item *entities.Item) error {
+	if item.PublisherID != nil {
+		return entities.ErrorPublisherNotFound
+	}
+	for _, author := range item.Authors {
+		if author != nil {
+			return entities.ErrorAuthorNotFound
+		}
+	}
+	if item.ISBN == "" {
+		return nil
+	}
+	for _, other := range s.items {
+		if other.ID != item.ID && other.ISBN == item.ISBN {
+			return entities.ErrorIsbnNotUnique
+		}
+	}
+	return nil
+}
+
+// storedItem returns the item without relations, which are never stored together with it.
+func storedItem(item entities.Item) entities.Item {
+	item.Publisher, item.Authors, item.OrderItems = nil, nil, nil
+	return item
+}
+
+func itemColumn(item entities.Item, column string) any {
+	switch column {
+	case "id":
+		return item.ID
+	case "title":
+		return item.Title
+	case "price_amount":
+		return item.Price.Amount
+	case "stock":
+		return item.Stock
+	case "created_at":
+		return item.CreatedAt
+	case "updated_at":
+		return item.UpdatedAt
+	default:
+		return nil
+	}
+}
+
+// sortByRank sorts matches by rank, the best first, and matches of equal rank by id.
+func sortByRank(matches []entities.ItemMatch) {
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Rank != matches[j].Rank {
+			return matches[i].Rank > matches[j].Rank
+		}
+		return matches[i].ID.String() < matches[j].ID.String()
+	})
+}
@@ -0,0 +1,176 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/repositories"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemRepository(t *testing.T) {
+	ctx := context.Background()
+
+	newItem := func(title string, description string, price int64) *entities.Item {
+		return entities.NewItemBuilder().
+			Title(title).
+			Description(description).
+			Price(entities.NewMoney(price, entities.EUR)).
+			Stock(10).
+			Build()
+	}
+
+	t.Run("should create, update and delete item", func(t *testing.T) {
+		// given
+		repo := NewItemRepository(NewStore())
+		item := newItem("Dune", "Desert planet", 1299)
+		item.ISBN = "9780441013593"
+		require.NoError(t, repo.Create(ctx, item))
+
+		// when
+		item.Stock = 5
+		err := repo.Update(ctx, item)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), item.Version)
+		found, err := repo.GetByISBN(ctx, "9780441013593")
+		assert.NoError(t, err)
+		assert.Equal(t, 5, found.Stock)
+
+		// when
+		err = repo.DeleteById(ctx, item.ID, 1)
+		// then
+		assert.ErrorIs(t, err, entities.ErrorVersionMismatch)
+		assert.NoError(t, repo.DeleteById(ctx, item.ID, 2))
+		_, err = repo.GetById(ctx, item.ID)
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
+	})
+
+	t.Run("should enforce constraints of items", func(t *testing.T) {
+		// given
+		repo := NewItemRepository(NewStore())
+		item := newItem("Dune", "", 1299)
+		item.ISBN = "9780441013593"
+		require.NoError(t, repo.Create(ctx, item))
+
+		// when another item has the ISBN
+		other := newItem("Dune Messiah", "", 1099)
+		other.ISBN = item.ISBN
+		// then
+		assert.ErrorIs(t, repo.Create(ctx, other), entities.ErrorIsbnNotUnique)
+
+		// when items refer to publishers or authors
+		assert.ErrorIs(t, repo.Create(ctx, entities.NewItemBuilder().Publisher(uuid.New()).Build()), entities.ErrorPublisherNotFound)
+		assert.ErrorIs(t, repo.Create(ctx, entities.NewItemBuilder().Authors(uuid.New()).Build()), entities.ErrorAuthorNotFound)
+
+		// when updating an unknown item
+		assert.ErrorIs(t, repo.Update(ctx, newItem("Unknown", "", 100)), repositories.ErrNotFound)
+	})
+
+	t.Run("should not delete ordered item", func(t *testing.T) {
+		// given
+		store := NewStore()
+		repo := NewItemRepository(store)
+		item := newItem("Dune", "", 1299)
+		require.NoError(t, repo.Create(ctx, item))
+		acc := entities.NewAccountBuilder().Email("jane@example.com").Build()
+		require.NoError(t, NewAccountRepository(store).Create(ctx, acc))
+		order := entities.NewOrderBuilder().
+			AccountID(acc.ID).
+			OrderItems([]*entities.OrderItem{entities.NewOrderItemBuilder().ItemID(item.ID).Quantity(1).Build()}).
+			Build()
+		require.NoError(t, NewOrderRepository(store).Create(ctx, order))
+
+		// when
+		err := repo.DeleteById(ctx, item.ID, 0)
+
+		// then
+		assert.ErrorIs(t, err, entities.ErrorItemInUse)
+	})
+
+	t.Run("should return page of items sorted by price", func(t *testing.T) {
+		// given
+		repo := NewItemRepository(NewStore())
+		for i, title := range []string{"A", "B", "C"} {
+			require.NoError(t, repo.Create(ctx, newItem(title, "", int64(300-i*100))))
+		}
+		p := entities.Pageable{
+			Size:   2,
+			Offset: 0,
+			Sort:   entities.NewSort(entities.NewSortOrder(entities.WithProperty("price"), entities.WithDirection(entities.ASC))),
+		}
+
+		// when
+		page, err := repo.GetPage(ctx, p)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 3, *page.TotalElements)
+		assert.Equal(t, 2, *page.TotalPages)
+		assert.True(t, page.HasNext)
+		require.Len(t, page.Elements, 2)
+		assert.Equal(t, "C", page.Elements[0].Title)
+		assert.Equal(t, "B", page.Elements[1].Title)
+
+		// when sorted by unknown property
+		p.Sort = entities.NewSort(entities.NewSortOrder(entities.WithProperty("isbn")))
+		_, err = repo.GetPage(ctx, p)
+		// then
+		assert.ErrorIs(t, err, entities.ErrorValidation)
+	})
+
+	t.Run("should search items and highlight matches", func(t *testing.T) {
+		// given
+		repo := NewItemRepository(NewStore())
+		require.NoError(t, repo.Create(ctx, newItem("Dragon Rider", "A boy and his dragon", 100)))
+		require.NoError(t, repo.Create(ctx, newItem("Space Opera", "Fantasy in space, with dragons", 100)))
+		require.NoError(t, repo.Create(ctx, newItem("Elven Fantasy", "No dragons at all", 100)))
+
+		// when
+		page, err := repo.Search(ctx, "dragon -elven", entities.Pageable{Size: 10})
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, page.Elements, 2)
+		assert.Equal(t, "Dragon Rider", page.Elements[0].Title)
+		assert.Equal(t, "<mark>Dragon</mark> Rider", page.Elements[0].TitleHighlight)
+		assert.Equal(t, "A boy and his <mark>dragon</mark>", page.Elements[0].DescriptionHighlight)
+
+		// when searching by phrase
+		page, err = repo.Search(ctx, `"space opera"`, entities.Pageable{})
+		// then
+		require.NoError(t, err)
+		require.Len(t, page.Elements, 1)
+		assert.Equal(t, "Space Opera", page.Elements[0].Title)
+
+		// when paged in keyset mode
+		_, err = repo.Search(ctx, "dragon", entities.Pageable{Keyset: true})
+		// then
+		assert.ErrorIs(t, err, entities.ErrorSearchPaging)
+	})
+
+	t.Run("should find similar titles and suggest them", func(t *testing.T) {
+		// given
+		repo := NewItemRepository(NewStore())
+		require.NoError(t, repo.Create(ctx, newItem("Harry Potter and the Philosopher's Stone", "", 100)))
+		require.NoError(t, repo.Create(ctx, newItem("The Hobbit", "", 100)))
+
+		// when
+		page, err := repo.SearchSimilar(ctx, "harry poter", entities.Pageable{Size: 10})
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, page.Elements, 1)
+		assert.Equal(t, "Harry Potter and the Philosopher's Stone", page.Elements[0].Title)
+		assert.Greater(t, page.Elements[0].Rank, 0.3)
+
+		// when
+		titles, err := repo.Suggest(ctx, "hobit", 5)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"The Hobbit"}, titles)
+	})
+}
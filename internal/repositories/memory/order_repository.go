@@ -0,0 +1,304 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/repositories"
+	"github.com/google/uuid"
+)
+
+// OrderRepository is the in-memory implementation of core repositories.OrderRepository interface.
+// Orders reserve and release stock of the items kept by the ItemRepository of the same store.
+type OrderRepository struct {
+	store *Store
+}
+
+// NewOrderRepository instantiates new OrderRepository keeping orders in the store.
+func NewOrderRepository(store *Store) *OrderRepository {
+	return &OrderRepository{store}
+}
+
+// GetById returns order by specified id, with its order items.
+func (repo *OrderRepository) GetById(_ context.Context, id uuid.UUID) (entities.Order, error) {
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	order, ok := repo.store.orders[id]
+	if !ok {
+		return entities.Order{}, repositories.ErrNotFound
+	}
+	return copyOrder(order), nil
+}
+
+// Search returns a page of orders placed by the account.
+func (repo *OrderRepository) Search(_ context.Context, accountId uuid.UUID, p entities.Pageable) (entities.Page[entities.Order], error) {
+	repo.store.mu.RLock()
+	var orders []entities.Order
+	for _, order := range repo.store.orders {
+		if order.AccountID == accountId {
+			orders = append(orders, copyOrder(order))
+		}
+	}
+	repo.store.mu.RUnlock()
+
+	return pageOf(orders, orderColumn, entities.OrderSortable, p)
+}
+
+// Create stores the order together with its order items and reserves stock of the ordered items.
+// Order items capture current title and price of their items.
+// It returns repositories.ErrNotFound if the account or any of the items does not exist,
+// entities.ErrorCurrencyMismatch if the items are priced in different currencies
+// and entities.InsufficientStockError if any of the items does not have enough units in stock.
+// Nothing is stored if an error is returned.
+func (repo *OrderRepository) Create(ctx context.Context, order *entities.Order) error {
+	if order == nil {
+		return repositories.ErrNilEntity
+	}
+
+	defer repo.store.lock(ctx)()
+
+	if _, exists := repo.store.orders[order.ID]; exists {
+		return errDuplicateId
+	}
+	if acc, ok := repo.store.accounts[order.AccountID]; !ok || !acc.DeletedAt.IsZero() {
+		return repositories.ErrNotFound
+	}
+	if err := repo.store.snapshotPrices(order.OrderItems); err != nil {
+		return err
+	}
+	if err := repo.store.reserveStock(order.OrderItems); err != nil {
+		return err
+	}
+
+	initEntity(&order.Entity)
+	if order.Status == "" {
+		order.Status = entities.ORDER_PENDING
+	}
+	for _, oi := range order.OrderItems {
+		if oi != nil {
+			oi.OrderID = order.ID
+			initEntity(&oi.Entity)
+		}
+	}
+	repo.store.orders[order.ID] = copyOrder(*order)
+	return nil
+}
+
+// snapshotPrices captures current title and price of ordered items into order items.
+// It returns repositories.ErrNotFound if any of the ordered items does not exist
+// and entities.ErrorCurrencyMismatch if the items are priced in different currencies.
+func (s *Store) snapshotPrices(orderItems []*entities.OrderItem) error {
+	var currency entities.Currency
+	for _, oi := range orderItems {
+		if oi == nil {
+			continue
+		}
+		item, ok := s.items[oi.ItemID]
+		if !ok {
+			return repositories.ErrNotFound
+		}
+		if currency != "" && item.Price.Currency != currency {
+			return entities.ErrorCurrencyMismatch
+		}
+		currency = item.Price.Currency
+	}
+
+	for _, oi := range orderItems {
+		if oi != nil {
+			item := s.items[oi.ItemID]
+			oi.Title = item.Title
+			oi.UnitPrice = item.Price
+		}
+	}
+	return nil
+}
+
+// reserveStock decrements stock of ordered items, all of them or none if any has not enough units in stock.
+func (s *Store) reserveStock(orderItems []*entities.OrderItem) error {
+	ids, quantities := orderedQuantities(orderItems)
+	for _, id := range ids {
+		if available := s.items[id].Stock; available < quantities[id] {
+			return entities.InsufficientStockError{ItemID: id, Requested: quantities[id], Available: available}
+		}
+	}
+	for _, id := range ids {
+		s.addStock(id, -quantities[id])
+	}
+	return nil
+}
+
+// releaseStock increments stock of ordered items by their ordered quantities, items deleted meanwhile are skipped.
+func (s *Store) releaseStock(orderItems []*entities.OrderItem) {
+	ids, quantities := orderedQuantities(orderItems)
+	for _, id := range ids {
+		if _, ok := s.items[id]; ok {
+			s.addStock(id, quantities[id])
+		}
+	}
+}
+
+// addStock changes stock of the item, which is part of the item, so edits of the item expecting the old version fail.
+func (s *Store) addStock(id uuid.UUID, quantity int) {
+	item := s.items[id]
+	item.Stock += quantity
+	item.Version++
+	s.items[id] = item
+}
+
+// orderedQuantities sums the ordered quantities by item, items are listed in a stable order.
+func orderedQuantities(orderItems []*entities.OrderItem) ([]uuid.UUID, map[uuid.UUID]int) {
+	quantities := make(map[uuid.UUID]int)
+	var ids []uuid.UUID
+	for _, oi := range orderItems {
+		if oi == nil {
+			continue
+		}
+		if _, ok := quantities[oi.ItemID]; !ok {
+			ids = append(ids, oi.ItemID)
+		}
+		quantities[oi.ItemID] += oi.Quantity
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+	return ids, quantities
+}
+
+// UpdateStatus stores status of the order and records the status change in the order history.
+// The status is only updated if the order is still in the status the change was made from,
+// otherwise entities.ErrorOrderStatusChanged is returned. Cancelled orders give their reserved stock back.
+func (repo *OrderRepository) UpdateStatus(ctx context.Context, order *entities.Order, change *entities.OrderHistory) error {
+	if order == nil || change == nil {
+		return repositories.ErrNilEntity
+	}
+
+	defer repo.store.lock(ctx)()
+
+	if err := repo.store.updateStatus(order, change); err != nil {
+		return err
+	}
+	if change.ToStatus == entities.ORDER_CANCELLED {
+		repo.store.releaseStock(repo.store.orders[order.ID].OrderItems)
+	}
+	return nil
+}
+
+// updateStatus stores status of the order, if it is still in the status the change was made from,
+// and records the change in the order history.
+func (s *Store) updateStatus(order *entities.Order, change *entities.OrderHistory) error {
+	stored, ok := s.orders[order.ID]
+	if !ok || stored.Status != change.FromStatus {
+		return entities.ErrorOrderStatusChanged
+	}
+
+	stored.Status = order.Status
+	stored.CancellationReason = order.CancellationReason
+	stored.UpdatedAt = order.UpdatedAt.Round(0)
+	if stored.UpdatedAt.IsZero() {
+		stored.UpdatedAt = now()
+	}
+	stored.Version++
+	s.orders[order.ID] = stored
+
+	initEntity(&change.Entity)
+	s.history[order.ID] = append(s.history[order.ID], *change)
+	return nil
+}
+
+// GetHistory returns status changes of the order in the order they were made.
+func (repo *OrderRepository) GetHistory(_ context.Context, orderId uuid.UUID) ([]entities.OrderHistory, error) {
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	return append(make([]entities.OrderHistory, 0, len(repo.store.history[orderId])), repo.store.history[orderId]...), nil
+}
+
+// GetByIdForUpdate returns order by specified id, with its order items. Units of work are serialized
+// by the store, so the order needs no lock of its own, see TxManager.
+func (repo *OrderRepository) GetByIdForUpdate(ctx context.Context, id uuid.UUID) (entities.Order, error) {
+	return repo.GetById(ctx, id)
+}
+
+// CreateRefund stores the refund of the order together with the status change, if it is not nil.
+func (repo *OrderRepository) CreateRefund(ctx context.Context, order *entities.Order, refund *entities.Refund, change *entities.OrderHistory) error {
+	if order == nil || refund == nil {
+		return repositories.ErrNilEntity
+	}
+
+	defer repo.store.lock(ctx)()
+
+	if _, ok := repo.store.orders[order.ID]; !ok {
+		return repositories.ErrNotFound
+	}
+	if change != nil {
+		if err := repo.store.updateStatus(order, change); err != nil {
+			return err
+		}
+	}
+
+	initEntity(&refund.Entity)
+	for _, ri := range refund.Items {
+		initEntity(&ri.Entity)
+	}
+	repo.store.refunds[order.ID] = append(repo.store.refunds[order.ID], copyRefund(*refund))
+	return nil
+}
+
+// GetRefunds returns refunds of the order in the order they were made.
+func (repo *OrderRepository) GetRefunds(_ context.Context, orderId uuid.UUID) ([]entities.Refund, error) {
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	return repo.store.getRefunds(orderId), nil
+}
+
+func (s *Store) getRefunds(orderId uuid.UUID) []entities.Refund {
+	refunds := make([]entities.Refund, 0, len(s.refunds[orderId]))
+	for _, r := range s.refunds[orderId] {
+		refunds = append(refunds, copyRefund(r))
+	}
+	return refunds
+}
+
+// copyOrder copies the order with its order items, without the account it refers to.
+func copyOrder(order entities.Order) entities.Order {
+	order.Account = entities.Account{}
+	orderItems := make([]*entities.OrderItem, 0, len(order.OrderItems))
+	for _, oi := range order.OrderItems {
+		if oi != nil {
+			c := *oi
+			c.Order, c.Item = nil, nil
+			orderItems = append(orderItems, &c)
+		}
+	}
+	order.OrderItems = orderItems
+	return order
+}
+
+// copyRefund copies the refund with its refund items.
+func copyRefund(refund entities.Refund) entities.Refund {
+	items := make([]*entities.RefundItem, 0, len(refund.Items))
+	for _, ri := range refund.Items {
+		if ri != nil {
+			c := *ri
+			items = append(items, &c)
+		}
+	}
+	refund.Items = items
+	return refund
+}
+
+func orderColumn(order entities.Order, column string) any {
+	switch column {
+	case "id":
+		return order.ID
+	case "status":
+		return string(order.Status)
+	case "created_at":
+		return order.CreatedAt
+	case "updated_at":
+		return order.UpdatedAt
+	default:
+		return nil
+	}
+}
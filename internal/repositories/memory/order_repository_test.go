@@ -0,0 +1,160 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/repositories"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderRepository(t *testing.T) {
+	ctx := context.Background()
+
+	// setup creates an account and an item with the stock
+	setup := func(t *testing.T, stock int) (*OrderRepository, ItemRepository, uuid.UUID, uuid.UUID) {
+		store := NewStore()
+		acc := entities.NewAccountBuilder().Email("jane@example.com").Build()
+		require.NoError(t, NewAccountRepository(store).Create(ctx, acc))
+		items := NewItemRepository(store)
+		item := entities.NewItemBuilder().Title("Dune").Price(entities.NewMoney(750, entities.EUR)).Stock(stock).Build()
+		require.NoError(t, items.Create(ctx, item))
+		return NewOrderRepository(store), items, acc.ID, item.ID
+	}
+	newOrder := func(accountId uuid.UUID, itemId uuid.UUID, quantity int) *entities.Order {
+		return entities.NewOrderBuilder().
+			AccountID(accountId).
+			OrderItems([]*entities.OrderItem{entities.NewOrderItemBuilder().ItemID(itemId).Quantity(quantity).Build()}).
+			Build()
+	}
+
+	t.Run("should create order capturing prices and reserving stock", func(t *testing.T) {
+		// given
+		repo, items, accountId, itemId := setup(t, 5)
+
+		// when
+		err := repo.Create(ctx, newOrder(accountId, itemId, 2))
+
+		// then
+		require.NoError(t, err)
+		page, err := repo.Search(ctx, accountId, entities.Pageable{Size: 10})
+		require.NoError(t, err)
+		require.Len(t, page.Elements, 1)
+		order := page.Elements[0]
+		assert.Equal(t, entities.ORDER_PENDING, order.Status)
+		assert.Equal(t, "Dune", order.OrderItems[0].Title)
+		assert.Equal(t, entities.NewMoney(750, entities.EUR), order.OrderItems[0].UnitPrice)
+		item, err := items.GetById(ctx, itemId)
+		require.NoError(t, err)
+		assert.Equal(t, 3, item.Stock)
+		assert.Equal(t, int64(2), item.Version)
+	})
+
+	t.Run("should reject order of unknown account or item and of more than in stock", func(t *testing.T) {
+		// given
+		repo, items, accountId, itemId := setup(t, 1)
+
+		// then
+		assert.ErrorIs(t, repo.Create(ctx, newOrder(uuid.New(), itemId, 1)), repositories.ErrNotFound)
+		assert.ErrorIs(t, repo.Create(ctx, newOrder(accountId, uuid.New(), 1)), repositories.ErrNotFound)
+		err := repo.Create(ctx, newOrder(accountId, itemId, 2))
+		var stockErr entities.InsufficientStockError
+		require.ErrorAs(t, err, &stockErr)
+		assert.Equal(t, 1, stockErr.Available)
+		item, _ := items.GetById(ctx, itemId)
+		assert.Equal(t, 1, item.Stock)
+	})
+
+	t.Run("should never sell more than in stock to concurrent orders", func(t *testing.T) {
+		// given
+		repo, items, accountId, itemId := setup(t, 3)
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		placed := 0
+
+		// when
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if repo.Create(ctx, newOrder(accountId, itemId, 1)) == nil {
+					mu.Lock()
+					placed++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		// then
+		assert.Equal(t, 3, placed)
+		item, _ := items.GetById(ctx, itemId)
+		assert.Equal(t, 0, item.Stock)
+	})
+
+	t.Run("should update status once and release stock of cancelled order", func(t *testing.T) {
+		// given
+		repo, items, accountId, itemId := setup(t, 2)
+		order := newOrder(accountId, itemId, 2)
+		require.NoError(t, repo.Create(ctx, order))
+		stale := *order
+		change, err := order.TransitionTo(entities.ORDER_CANCELLED, uuid.Nil, "changed my mind")
+		require.NoError(t, err)
+
+		// when
+		err = repo.UpdateStatus(ctx, order, change)
+
+		// then
+		require.NoError(t, err)
+		item, _ := items.GetById(ctx, itemId)
+		assert.Equal(t, 2, item.Stock)
+		history, err := repo.GetHistory(ctx, order.ID)
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		assert.Equal(t, entities.ORDER_CANCELLED, history[0].ToStatus)
+
+		// when the order is changed from the status it had before
+		change, err = stale.TransitionTo(entities.ORDER_PAID, uuid.Nil, "")
+		require.NoError(t, err)
+		err = repo.UpdateStatus(ctx, &stale, change)
+		// then
+		assert.ErrorIs(t, err, entities.ErrorOrderStatusChanged)
+	})
+
+	t.Run("should refund order and record its refunds", func(t *testing.T) {
+		// given
+		repo, _, accountId, itemId := setup(t, 2)
+		order := newOrder(accountId, itemId, 2)
+		require.NoError(t, repo.Create(ctx, order))
+		change, err := order.TransitionTo(entities.ORDER_PAID, uuid.Nil, "")
+		require.NoError(t, err)
+		require.NoError(t, repo.UpdateStatus(ctx, order, change))
+
+		// when
+		locked, err := repo.GetByIdForUpdate(ctx, order.ID)
+		require.NoError(t, err)
+		refund, change, err := locked.Refund(nil, nil, uuid.Nil, "damaged")
+		require.NoError(t, err)
+		err = repo.CreateRefund(ctx, &locked, refund, change)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, entities.NewMoney(1500, entities.EUR), refund.Amount)
+		refunded, err := repo.GetById(ctx, order.ID)
+		require.NoError(t, err)
+		assert.Equal(t, entities.ORDER_REFUNDED, refunded.Status)
+		refunds, err := repo.GetRefunds(ctx, order.ID)
+		require.NoError(t, err)
+		require.Len(t, refunds, 1)
+		assert.Len(t, refunds[0].Items, 1)
+
+		// when refunded again
+		_, _, err = refunded.Refund(refunds, nil, uuid.Nil, "")
+		// then
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,299 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+)
+
+// cursorTimeFormat formats timestamps in cursors, the way the PostgreSQL repositories do.
+const cursorTimeFormat = "2006-01-02 15:04:05.999999999"
+
+// columnFunc returns the value of the column of the element, for the columns the element can be sorted by and its id.
+// Values are strings, integers, timestamps or UUIDs.
+type columnFunc[T any] func(element T, column string) any
+
+// ordering is a resolved sort: the columns in the order they are compared and their directions, ASC or DESC.
+type ordering struct {
+	columns    []string
+	directions []entities.Direction
+}
+
+// newOrdering resolves the sort orders into columns through the sortable declaration.
+// Sortable columns are not nullable, so the NULLS FIRST / NULLS LAST part of directions is dropped.
+func newOrdering(sortable entities.Sortable, s entities.Sort) (ordering, error) {
+	var o ordering
+	for _, order := range s.Orders {
+		column, err := sortable.Column(order.Property)
+		if err != nil {
+			return o, err
+		}
+		direction, err := entities.ParseDirection(string(order.Direction))
+		if err != nil {
+			return o, err
+		}
+		o.add(column, baseDirection(direction))
+	}
+	return o, nil
+}
+
+func (o *ordering) add(column string, direction entities.Direction) {
+	o.columns = append(o.columns, column)
+	o.directions = append(o.directions, direction)
+}
+
+// signature identifies the ordering, so cursors cannot be used with different sort orders.
+func (o ordering) signature() string {
+	orders := make([]string, len(o.columns))
+	for i := range o.columns {
+		orders[i] = fmt.Sprintf("%s %s", o.columns[i], o.directions[i])
+	}
+	return strings.Join(orders, ",")
+}
+
+// compare compares the keys of two elements, given in the order of the columns, reversing descending columns.
+func (o ordering) compare(a []any, b []any, backward bool) int {
+	for i := range o.columns {
+		c := compareValues(a[i], b[i])
+		if o.directions[i] == entities.DESC {
+			c = -c
+		}
+		if backward {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// keys returns the values of the columns of the ordering of the element.
+func keys[T any](o ordering, column columnFunc[T], element T) []any {
+	keys := make([]any, len(o.columns))
+	for i, c := range o.columns {
+		keys[i] = column(element, c)
+	}
+	return keys
+}
+
+// sortElements sorts the elements by the sort orders, elements with equal sort keys by their ids.
+func sortElements[T any](elements []T, column columnFunc[T], sortable entities.Sortable, s entities.Sort) error {
+	o, err := newOrdering(sortable, s)
+	if err != nil {
+		return err
+	}
+	o.add("id", entities.ASC)
+	sortBy(elements, column, o, false)
+	return nil
+}
+
+// sortBy sorts the elements by the ordering, reversed if backward.
+func sortBy[T any](elements []T, column columnFunc[T], o ordering, backward bool) {
+	keyed := make([][]any, len(elements))
+	for i, e := range elements {
+		keyed[i] = keys(o, column, e)
+	}
+	sort.Sort(byKeys[T]{elements: elements, keys: keyed, o: o, backward: backward})
+}
+
+// byKeys sorts elements together with their keys.
+type byKeys[T any] struct {
+	elements []T
+	keys     [][]any
+	o        ordering
+	backward bool
+}
+
+func (b byKeys[T]) Len() int { return len(b.elements) }
+
+func (b byKeys[T]) Less(i, j int) bool { return b.o.compare(b.keys[i], b.keys[j], b.backward) < 0 }
+
+func (b byKeys[T]) Swap(i, j int) {
+	b.elements[i], b.elements[j] = b.elements[j], b.elements[i]
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+}
+
+// pageOf selects the page of the elements requested by offset, or by cursor in keyset mode, see entities.Pageable.
+// The elements are sorted in place.
+func pageOf[T any](elements []T, column columnFunc[T], sortable entities.Sortable, p entities.Pageable) (entities.Page[T], error) {
+	if p.Keyset {
+		return keysetPage(elements, column, sortable, p)
+	}
+	if err := sortElements(elements, column, sortable, p.Sort); err != nil {
+		return entities.Page[T]{}, err
+	}
+	return offsetPage(elements, p), nil
+}
+
+// offsetPage selects the page of sorted elements requested by offset, see entities.Pageable.
+func offsetPage[T any](elements []T, p entities.Pageable) entities.Page[T] {
+	total := len(elements)
+	from := min(max(p.Offset, 0), total)
+	to := total
+	if p.Size > 0 {
+		to = min(from+p.Size, total)
+	}
+	page := append(make([]T, 0, to-from), elements[from:to]...)
+	return entities.NewPage(page, p, total, to < total)
+}
+
+// keysetPage selects the page of elements requested in keyset mode, see entities.Pageable.Keyset.
+// Elements are sorted by the sort orders followed by id, which breaks ties; listings without sort orders
+// are sorted by creation time, newest first. Cursors are compatible with the ones of the PostgreSQL repositories.
+func keysetPage[T any](elements []T, column columnFunc[T], sortable entities.Sortable, p entities.Pageable) (entities.Page[T], error) {
+	o, err := newOrdering(sortable, p.Sort)
+	if err != nil {
+		return entities.Page[T]{}, err
+	}
+	if len(o.columns) == 0 {
+		o.add("created_at", entities.DESC)
+	}
+	o.add("id", o.directions[len(o.directions)-1])
+
+	var after []string
+	backward := false
+	if p.Cursor != "" {
+		c, err := entities.ParseCursor(p.Cursor)
+		if err != nil {
+			return entities.Page[T]{}, err
+		}
+		if c.Sort != o.signature() || len(c.Keys) != len(o.columns) {
+			return entities.Page[T]{}, entities.ErrorInvalidCursor
+		}
+		after, backward = c.Keys, c.Backward
+	}
+
+	page := entities.Page[T]{Size: p.Size, Cursors: &entities.PageCursors{Counted: !p.SkipCount}}
+	if !p.SkipCount {
+		page.SetTotal(len(elements))
+	}
+
+	sortBy(elements, column, o, backward)
+	selected := make([]T, 0, len(elements))
+	var cursorKeys []any
+	for _, e := range elements {
+		if after != nil {
+			elementKeys := keys(o, column, e)
+			if cursorKeys == nil {
+				// keys are parsed into values of the types of the columns, which the elements tell
+				if cursorKeys, err = parseKeys(elementKeys, after); err != nil {
+					return entities.Page[T]{}, err
+				}
+			}
+			if o.compare(elementKeys, cursorKeys, backward) <= 0 {
+				continue
+			}
+		}
+		selected = append(selected, e)
+	}
+
+	more := p.Size > 0 && len(selected) > p.Size
+	if more {
+		selected = selected[:p.Size]
+	}
+	if backward {
+		for i, j := 0, len(selected)-1; i < j; i, j = i+1, j-1 {
+			selected[i], selected[j] = selected[j], selected[i]
+		}
+	}
+	page.Elements = selected
+
+	if n := len(selected); n > 0 {
+		// going backward, the page the cursor came from follows; going forward, it precedes
+		if more || backward {
+			page.Cursors.Next = cursor(o, keys(o, column, selected[n-1]), false)
+		}
+		if (more && backward) || (!backward && p.Cursor != "") {
+			page.Cursors.Prev = cursor(o, keys(o, column, selected[0]), true)
+		}
+	}
+	page.HasNext = page.Cursors.Next != ""
+	page.HasPrev = page.Cursors.Prev != ""
+	return page, nil
+}
+
+// cursor creates the cursor pointing at the element with the keys.
+func cursor(o ordering, elementKeys []any, backward bool) string {
+	formatted := make([]string, len(elementKeys))
+	for i, value := range elementKeys {
+		formatted[i] = formatKey(value)
+	}
+	return entities.Cursor{Sort: o.signature(), Keys: formatted, Backward: backward}.Encode()
+}
+
+func formatKey(v any) string {
+	switch v := v.(type) {
+	case time.Time:
+		return v.UTC().Format(cursorTimeFormat)
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// parseKeys converts keys of a cursor into values of the types of the sample keys.
+func parseKeys(samples []any, keys []string) ([]any, error) {
+	values := make([]any, len(keys))
+	for i, key := range keys {
+		var err error
+		switch samples[i].(type) {
+		case time.Time:
+			values[i], err = time.ParseInLocation(cursorTimeFormat, key, time.UTC)
+		case int:
+			values[i], err = strconv.Atoi(key)
+		case int64:
+			values[i], err = strconv.ParseInt(key, 10, 64)
+		case uuid.UUID:
+			values[i], err = uuid.Parse(key)
+		default:
+			values[i] = key
+		}
+		if err != nil {
+			return nil, entities.ErrorInvalidCursor
+		}
+	}
+	return values, nil
+}
+
+// compareValues compares two values of the same type returned by a columnFunc.
+func compareValues(a any, b any) int {
+	switch a := a.(type) {
+	case time.Time:
+		return a.Compare(b.(time.Time))
+	case int:
+		return compareOrdered(a, b.(int))
+	case int64:
+		return compareOrdered(a, b.(int64))
+	case uuid.UUID:
+		return strings.Compare(a.String(), b.(uuid.UUID).String())
+	case string:
+		return strings.Compare(a, b.(string))
+	default:
+		panic(fmt.Sprintf("memory: can not compare values of type %T", a))
+	}
+}
+
+func compareOrdered[T int | int64](a T, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func baseDirection(d entities.Direction) entities.Direction {
+	if strings.HasPrefix(string(d), string(entities.DESC)) {
+		return entities.DESC
+	}
+	return entities.ASC
+}
@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeysetPage(t *testing.T) {
+	ctx := context.Background()
+	repo := NewItemRepository(NewStore())
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, price := range []int64{500, 300, 300, 100, 400} {
+		item := entities.NewItemBuilder().Price(entities.NewMoney(price, entities.EUR)).Build()
+		item.CreatedAt = created.Add(time.Duration(i) * time.Microsecond)
+		require.NoError(t, repo.Create(ctx, item))
+	}
+	byPrice := entities.NewSort(entities.NewSortOrder(entities.WithProperty("price"), entities.WithDirection(entities.ASC)))
+
+	walk := func(p entities.Pageable) ([]int64, entities.Page[entities.Item]) {
+		var prices []int64
+		var page entities.Page[entities.Item]
+		for {
+			var err error
+			page, err = repo.GetPage(ctx, p)
+			require.NoError(t, err)
+			for _, item := range page.Elements {
+				prices = append(prices, item.Price.Amount)
+			}
+			if page.Cursors.Next == "" {
+				return prices, page
+			}
+			p.Cursor = page.Cursors.Next
+		}
+	}
+
+	t.Run("should walk all items by cursor", func(t *testing.T) {
+		// when
+		prices, last := walk(entities.Pageable{Size: 2, Sort: byPrice, Keyset: true})
+
+		// then
+		assert.Equal(t, []int64{100, 300, 300, 400, 500}, prices)
+		assert.Equal(t, 5, *last.TotalElements)
+		assert.True(t, last.HasPrev)
+		assert.False(t, last.HasNext)
+	})
+
+	t.Run("should walk back by previous cursor", func(t *testing.T) {
+		// given
+		_, last := walk(entities.Pageable{Size: 2, Sort: byPrice, Keyset: true, SkipCount: true})
+
+		// when
+		page, err := repo.GetPage(ctx, entities.Pageable{Size: 2, Sort: byPrice, Keyset: true, Cursor: last.Cursors.Prev})
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, page.Elements, 2)
+		assert.Equal(t, int64(300), page.Elements[0].Price.Amount)
+		assert.Equal(t, int64(400), page.Elements[1].Price.Amount)
+		assert.True(t, page.HasNext)
+		assert.True(t, page.HasPrev)
+	})
+
+	t.Run("should sort by newest first without sort orders", func(t *testing.T) {
+		// when
+		prices, _ := walk(entities.Pageable{Size: 3, Keyset: true})
+
+		// then
+		assert.Equal(t, []int64{400, 100, 300, 300, 500}, prices)
+	})
+
+	t.Run("should reject cursor of another sort", func(t *testing.T) {
+		// given
+		page, err := repo.GetPage(ctx, entities.Pageable{Size: 2, Keyset: true})
+		require.NoError(t, err)
+
+		// when
+		_, err = repo.GetPage(ctx, entities.Pageable{Size: 2, Sort: byPrice, Keyset: true, Cursor: page.Cursors.Next})
+
+		// then
+		assert.ErrorIs(t, err, entities.ErrorInvalidCursor)
+	})
+}
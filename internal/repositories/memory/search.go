@@ -0,0 +1,215 @@
+package memory
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Highlight marks, the ones ts_headline is configured with by the PostgreSQL item repository.
+const (
+	startSel = "<mark>"
+	stopSel  = "</mark>"
+)
+
+// textQuery is a parsed web search query: words all of which have to match, phrases given in quotes
+// and words prefixed by a minus which must not match, e.g. `"space opera" -dragons`.
+// It approximates websearch_to_tsquery: words match words of the text they prefix, ignoring case,
+// which stands in for stemming, e.g. "dragon" matches "Dragons".
+type textQuery struct {
+	include [][]string
+	exclude [][]string
+}
+
+func parseTextQuery(query string) textQuery {
+	var q textQuery
+	for len(query) > 0 {
+		query = strings.TrimLeftFunc(query, unicode.IsSpace)
+		if query == "" {
+			break
+		}
+		negated := query[0] == '-'
+		if negated {
+			query = query[1:]
+		}
+
+		var term string
+		if strings.HasPrefix(query, `"`) {
+			end := strings.Index(query[1:], `"`)
+			if end < 0 {
+				term, query = query[1:], ""
+			} else {
+				term, query = query[1:end+1], query[end+2:]
+			}
+		} else {
+			end := strings.IndexFunc(query, unicode.IsSpace)
+			if end < 0 {
+				end = len(query)
+			}
+			term, query = query[:end], query[end:]
+		}
+
+		phrase := words(term)
+		if len(phrase) == 0 {
+			continue
+		}
+		if negated {
+			q.exclude = append(q.exclude, phrase)
+		} else {
+			q.include = append(q.include, phrase)
+		}
+	}
+	return q
+}
+
+// match finds the phrases of the query in the texts. It reports whether all included phrases occur in any of the texts
+// and none of the excluded ones does, and returns the matched words of each text by their positions.
+func (q textQuery) match(texts ...string) (bool, []map[int]bool) {
+	if len(q.include) == 0 {
+		return false, nil
+	}
+	textWords := make([][]string, len(texts))
+	for i, text := range texts {
+		textWords[i] = words(text)
+	}
+
+	for _, phrase := range q.exclude {
+		for _, tw := range textWords {
+			if len(findPhrase(tw, phrase)) > 0 {
+				return false, nil
+			}
+		}
+	}
+
+	matched := make([]map[int]bool, len(texts))
+	for i := range matched {
+		matched[i] = make(map[int]bool)
+	}
+	for _, phrase := range q.include {
+		found := false
+		for i, tw := range textWords {
+			for _, start := range findPhrase(tw, phrase) {
+				found = true
+				for j := range phrase {
+					matched[i][start+j] = true
+				}
+			}
+		}
+		if !found {
+			return false, nil
+		}
+	}
+	return true, matched
+}
+
+// findPhrase returns the positions the phrase starts at in the words.
+func findPhrase(words []string, phrase []string) []int {
+	var starts []int
+	for i := 0; i+len(phrase) <= len(words); i++ {
+		found := true
+		for j, w := range phrase {
+			if !strings.HasPrefix(words[i+j], w) {
+				found = false
+				break
+			}
+		}
+		if found {
+			starts = append(starts, i)
+		}
+	}
+	return starts
+}
+
+// highlight wraps the matched words of the text, given by their positions, in highlight marks.
+func highlight(text string, matched map[int]bool) string {
+	var b strings.Builder
+	position := 0
+	for i, span := range wordSpans(text) {
+		b.WriteString(text[position:span[0]])
+		word := text[span[0]:span[1]]
+		if matched[i] {
+			word = startSel + word + stopSel
+		}
+		b.WriteString(word)
+		position = span[1]
+	}
+	b.WriteString(text[position:])
+	return b.String()
+}
+
+// words splits the text into lower case words of letters and digits.
+func words(text string) []string {
+	spans := wordSpans(text)
+	ws := make([]string, len(spans))
+	for i, span := range spans {
+		ws[i] = strings.ToLower(text[span[0]:span[1]])
+	}
+	return ws
+}
+
+// wordSpans returns the start and end offsets of the words of the text.
+func wordSpans(text string) [][2]int {
+	var spans [][2]int
+	start := -1
+	for i, r := range text {
+		isWordRune := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case isWordRune && start < 0:
+			start = i
+		case !isWordRune && start >= 0:
+			spans = append(spans, [2]int{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, [2]int{start, len(text)})
+	}
+	return spans
+}
+
+// trigrams returns the trigrams of the words of the text the way pg_trgm extracts them:
+// each lower case word is padded with two spaces in front and one behind.
+func trigrams(text string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range words(text) {
+		addTrigrams(set, w)
+	}
+	return set
+}
+
+func addTrigrams(set map[string]bool, word string) {
+	padded := []rune("  " + word + " ")
+	for i := 0; i+3 <= len(padded); i++ {
+		set[string(padded[i:i+3])] = true
+	}
+}
+
+// similarity is the share of trigrams two sets have in common, like pg_trgm similarity.
+func similarity(a map[string]bool, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	common := 0
+	for t := range a {
+		if b[t] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(a)+len(b)-common)
+}
+
+// wordSimilarity is the greatest similarity of the query to any run of consecutive words of the text,
+// so parts of long titles match as well as whole short ones. It approximates pg_trgm strict_word_similarity,
+// which is close to the word_similarity the PostgreSQL item repository ranks by.
+func wordSimilarity(query string, text string) float64 {
+	q := trigrams(query)
+	ws := words(text)
+	best := 0.0
+	for i := range ws {
+		extent := make(map[string]bool)
+		for j := i; j < len(ws); j++ {
+			addTrigrams(extent, ws[j])
+			best = max(best, similarity(q, extent))
+		}
+	}
+	return best
+}
@@ -0,0 +1,123 @@
+// Package memory implements repository ports in memory, for local demos and tests without a database.
+//
+// The repositories share a Store, which guards all entities by a single lock, so changes spanning
+// several entities, like placing an order reserving stock of items, are atomic. Entities are copied
+// in and out of the store, callers never share them with the store or other callers.
+//
+// Units of work of the TxManager run one at a time, changes made outside of them wait until the running unit
+// is done. A unit sees its own changes and its changes are undone if it fails, other callers reading the
+// store meanwhile see the changes before the unit is done though.
+//
+// Unlike the PostgreSQL repositories, the in-memory ones do not record domain events.
+package memory
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+)
+
+// errDuplicateId is returned when an entity is created with the id of an existing one,
+// which the primary key of its table rejects in the database.
+var errDuplicateId = entities.NewError(entities.ErrorConflict, "entity with the id exists already")
+
+// Store holds the entities of the in-memory repositories.
+type Store struct {
+	// writer is held by the running unit of work, or for a single change made outside of units
+	writer   sync.Mutex
+	mu       sync.RWMutex
+	accounts map[uuid.UUID]entities.Account
+	items    map[uuid.UUID]entities.Item
+	orders   map[uuid.UUID]entities.Order
+	history  map[uuid.UUID][]entities.OrderHistory
+	refunds  map[uuid.UUID][]entities.Refund
+}
+
+// NewStore creates an empty Store.
+func NewStore() *Store {
+	return &Store{
+		accounts: make(map[uuid.UUID]entities.Account),
+		items:    make(map[uuid.UUID]entities.Item),
+		orders:   make(map[uuid.UUID]entities.Order),
+		history:  make(map[uuid.UUID][]entities.OrderHistory),
+		refunds:  make(map[uuid.UUID][]entities.Refund),
+	}
+}
+
+// lock locks the store for a change made with ctx and returns the func unlocking it.
+// Changes of the unit carried by ctx are made under the writer lock the unit holds already.
+func (s *Store) lock(ctx context.Context) func() {
+	inUnit := s.inUnit(ctx)
+	if !inUnit {
+		s.writer.Lock()
+	}
+	s.mu.Lock()
+	return func() {
+		s.mu.Unlock()
+		if !inUnit {
+			s.writer.Unlock()
+		}
+	}
+}
+
+// state is a copy of the entities of the store, the entities are copied in and out of the store,
+// so copying the maps is enough to keep them.
+type state struct {
+	accounts map[uuid.UUID]entities.Account
+	items    map[uuid.UUID]entities.Item
+	orders   map[uuid.UUID]entities.Order
+	history  map[uuid.UUID][]entities.OrderHistory
+	refunds  map[uuid.UUID][]entities.Refund
+}
+
+func (s *Store) save() state {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return state{
+		accounts: maps.Clone(s.accounts),
+		items:    maps.Clone(s.items),
+		orders:   maps.Clone(s.orders),
+		history:  maps.Clone(s.history),
+		refunds:  maps.Clone(s.refunds),
+	}
+}
+
+func (s *Store) restore(saved state) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.accounts, s.items, s.orders, s.history, s.refunds = saved.accounts, saved.items, saved.orders, saved.history, saved.refunds
+}
+
+// initEntity fills the columns the database would default: id, creation and update time, and the first version.
+// Given times are kept, without their monotonic clock reading.
+func initEntity(e *entities.Entity) {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	t := now()
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = t
+	}
+	if e.UpdatedAt.IsZero() {
+		e.UpdatedAt = t
+	}
+	e.CreatedAt, e.UpdatedAt = e.CreatedAt.Round(0), e.UpdatedAt.Round(0)
+	e.Version = 1
+}
+
+// versionMatches reports whether the entity is in the version a change expects, any version matches zero.
+func versionMatches(e entities.Entity, version int64) bool {
+	return version == 0 || e.Version == version
+}
+
+// now returns the current time without the monotonic clock reading,
+// as timestamps are compared with the ones parsed from cursors.
+func now() time.Time {
+	return time.Now().Round(0)
+}
@@ -0,0 +1,41 @@
+package memory
+
+import "context"
+
+// unitKey is the context key of the store whose unit of work is carried by a context.
+type unitKey struct{}
+
+// TxManager is the in-memory implementation of core.TxManager interface.
+// It runs units of work one at a time and undoes the changes of failed units, see Store.
+type TxManager struct {
+	store *Store
+}
+
+// NewTxManager instantiates new TxManager running units of work on the store.
+func NewTxManager(store *Store) TxManager {
+	return TxManager{store}
+}
+
+// WithinTx runs fn as a unit of work, changes made by repositories called with the context passed to fn
+// are undone if fn fails. Units nested in another unit join it, only their own changes are undone if they fail.
+// The error of fn is returned as is.
+func (m TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if !m.store.inUnit(ctx) {
+		m.store.writer.Lock()
+		defer m.store.writer.Unlock()
+		ctx = context.WithValue(ctx, unitKey{}, m.store)
+	}
+
+	saved := m.store.save()
+	if err := fn(ctx); err != nil {
+		m.store.restore(saved)
+		return err
+	}
+	return nil
+}
+
+// inUnit reports whether ctx carries a unit of work running on the store.
+func (s *Store) inUnit(ctx context.Context) bool {
+	store, ok := ctx.Value(unitKey{}).(*Store)
+	return ok && store == s
+}
@@ -0,0 +1,120 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxManager(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (TxManager, AccountRepository, *OrderRepository, *entities.Item) {
+		store := NewStore()
+		item := entities.NewItemBuilder().Title("Dune").Price(entities.NewMoney(750, entities.EUR)).Stock(1).Build()
+		require.NoError(t, NewItemRepository(store).Create(ctx, item))
+		return NewTxManager(store), NewAccountRepository(store), NewOrderRepository(store), item
+	}
+	newOrder := func(acc *entities.Account, item *entities.Item, quantity int) *entities.Order {
+		return entities.NewOrderBuilder().
+			AccountID(acc.ID).
+			OrderItems([]*entities.OrderItem{entities.NewOrderItemBuilder().ItemID(item.ID).Quantity(quantity).Build()}).
+			Build()
+	}
+
+	t.Run("should keep changes of unit which succeeded", func(t *testing.T) {
+		txManager, accounts, orders, item := setup(t)
+		acc := entities.NewAccountBuilder().Email("jane@example.com").Build()
+		order := newOrder(acc, item, 1)
+
+		err := txManager.WithinTx(ctx, func(ctx context.Context) error {
+			if err := accounts.Create(ctx, acc); err != nil {
+				return err
+			}
+			return orders.Create(ctx, order)
+		})
+
+		assert.Nil(t, err)
+		_, err = accounts.GetById(ctx, acc.ID)
+		assert.Nil(t, err)
+		_, err = orders.GetById(ctx, order.ID)
+		assert.Nil(t, err)
+	})
+
+	t.Run("should undo changes of unit which failed", func(t *testing.T) {
+		txManager, accounts, orders, item := setup(t)
+		acc := entities.NewAccountBuilder().Email("jane@example.com").Build()
+
+		err := txManager.WithinTx(ctx, func(ctx context.Context) error {
+			if err := accounts.Create(ctx, acc); err != nil {
+				return err
+			}
+			return orders.Create(ctx, newOrder(acc, item, 2))
+		})
+
+		var stockErr entities.InsufficientStockError
+		assert.ErrorAs(t, err, &stockErr)
+		_, err = accounts.GetById(ctx, acc.ID)
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
+	})
+
+	t.Run("should undo only changes of nested unit which failed", func(t *testing.T) {
+		txManager, accounts, _, _ := setup(t)
+		outer := entities.NewAccountBuilder().Email("jane@example.com").Build()
+		nested := entities.NewAccountBuilder().Email("john@example.com").Build()
+		failure := errors.New("nested unit failed")
+
+		err := txManager.WithinTx(ctx, func(ctx context.Context) error {
+			if err := accounts.Create(ctx, outer); err != nil {
+				return err
+			}
+			err := txManager.WithinTx(ctx, func(ctx context.Context) error {
+				if err := accounts.Create(ctx, nested); err != nil {
+					return err
+				}
+				return failure
+			})
+			assert.Same(t, failure, err)
+			return nil
+		})
+
+		assert.Nil(t, err)
+		_, err = accounts.GetById(ctx, outer.ID)
+		assert.Nil(t, err)
+		_, err = accounts.GetById(ctx, nested.ID)
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
+	})
+
+	t.Run("should make changes outside of unit wait until unit is done", func(t *testing.T) {
+		txManager, accounts, _, _ := setup(t)
+		unitAccount := entities.NewAccountBuilder().Email("jane@example.com").Build()
+		otherAccount := entities.NewAccountBuilder().Email("john@example.com").Build()
+		created := make(chan error)
+
+		err := txManager.WithinTx(ctx, func(ctx context.Context) error {
+			if err := accounts.Create(ctx, unitAccount); err != nil {
+				return err
+			}
+			go func() { created <- accounts.Create(context.Background(), otherAccount) }()
+			select {
+			case <-created:
+				t.Fatal("account was created while unit was running")
+			case <-time.After(50 * time.Millisecond):
+			}
+			return errors.New("unit failed")
+		})
+
+		assert.NotNil(t, err)
+		assert.Nil(t, <-created)
+		_, err = accounts.GetById(ctx, unitAccount.ID)
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
+		_, err = accounts.GetById(ctx, otherAccount.ID)
+		assert.Nil(t, err)
+	})
+}
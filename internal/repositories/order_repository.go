@@ -2,71 +2,60 @@ package repositories
 
 import (
 	"context"
-	"database/sql"
 	"github.com/fmiskovic/new-amz/internal/core/entities"
 	"github.com/google/uuid"
 	"github.com/uptrace/bun"
-	"sync"
+	"sort"
 )
 
 type OrderRepository struct {
 	bunDb *bun.DB
-	mutex sync.RWMutex
 }
 
 func NewOrderRepository(db *bun.DB) *OrderRepository {
-	return &OrderRepository{db, sync.RWMutex{}}
+	return &OrderRepository{db}
 }
 
 func (repo *OrderRepository) GetById(ctx context.Context, id uuid.UUID) (entities.Order, error) {
 	var order = new(entities.Order)
 
-	err := repo.bunDb.NewSelect().
+	err := conn(ctx, repo.bunDb).NewSelect().
 		Model(order).
 		Relation("OrderItems").
 		Where("id = ?", id).
 		Scan(ctx)
 
 	if err != nil {
-		return entities.Order{}, err
+		return entities.Order{}, dbError(err)
 	}
 
 	return *order, nil
 }
 
+// Search returns a page of orders placed by the account.
 func (repo *OrderRepository) Search(ctx context.Context, accountId uuid.UUID, p entities.Pageable) (entities.Page[entities.Order], error) {
 	var orders []entities.Order
 
-	count, err := repo.bunDb.NewSelect().
+	q := conn(ctx, repo.bunDb).NewSelect().
 		Model(&orders).
 		Relation("OrderItems").
-		Where("account_id = ?", accountId).
-		Limit(p.Size).
-		Offset(p.Offset).
-		Order(entities.StringifyOrders(p.Sort)...).
-		ScanAndCount(ctx)
-
-	totalPages := 0
-	if count != 0 && p.Size != 0 {
-		totalPages = (len(orders) + p.Size - 1) / p.Size
+		Where("account_id = ?", accountId)
+	if p.Keyset {
+		return keysetPage(ctx, q, &orders, entities.OrderSortable, p)
 	}
-
-	return entities.Page[entities.Order]{
-		TotalPages:    totalPages,
-		TotalElements: count,
-		Elements:      orders,
-	}, err
+	return offsetPage(ctx, q, &orders, entities.OrderSortable, p)
 }
 
+// Create persists the order together with its order items and its entities.EVENT_ORDER_PLACED event
+// and reserves stock of the ordered items.
+// If any of the items does not have enough units in stock, entities.InsufficientStockError is returned
+// and nothing is persisted. If ctx carries a transaction, the order is created within it.
 func (repo *OrderRepository) Create(ctx context.Context, order *entities.Order) error {
-	repo.mutex.Lock()
-	defer repo.mutex.Unlock()
-
 	if order == nil {
 		return ErrNilEntity
 	}
 
-	return repo.bunDb.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
+	err := runInTx(ctx, repo.bunDb, func(ctx context.Context, tx bun.Tx) error {
 		ok, err := tx.NewSelect().Model(&entities.Account{}).Where("id = ?", order.AccountID).Exists(ctx)
 		if err != nil {
 			return err
@@ -75,6 +64,14 @@ func (repo *OrderRepository) Create(ctx context.Context, order *entities.Order)
 			return ErrNotFound
 		}
 
+		if err = snapshotPrices(ctx, tx, order.OrderItems); err != nil {
+			return err
+		}
+
+		if err = reserveStock(ctx, tx, order.OrderItems); err != nil {
+			return err
+		}
+
 		_, err = tx.NewInsert().Model(order).Exec(ctx)
 		if err != nil {
 			return err
@@ -92,6 +89,288 @@ func (repo *OrderRepository) Create(ctx context.Context, order *entities.Order)
 				}
 			}
 		}
+
+		event, err := entities.NewOrderPlaced(*order)
+		if err != nil {
+			return err
+		}
+		return storeEvent(ctx, tx, event)
+	})
+	return dbError(err)
+}
+
+// snapshotPrices captures current title and price of ordered items into order items.
+// It returns ErrNotFound if any of the ordered items does not exist
+// and entities.ErrorCurrencyMismatch if the items are priced in different currencies.
+func snapshotPrices(ctx context.Context, tx bun.Tx, orderItems []*entities.OrderItem) error {
+	var ids []uuid.UUID
+	for _, oi := range orderItems {
+		if oi != nil {
+			ids = append(ids, oi.ItemID)
+		}
+	}
+	if len(ids) == 0 {
 		return nil
+	}
+
+	var snapshots []entities.Item
+	err := tx.NewSelect().
+		Model(&snapshots).
+		Column("id", "title", "price_amount", "price_currency").
+		Where("id IN (?)", bun.In(ids)).
+		Scan(ctx)
+	if err != nil {
+		return err
+	}
+	for _, snapshot := range snapshots {
+		if snapshot.Price.Currency != snapshots[0].Price.Currency {
+			return entities.ErrorCurrencyMismatch
+		}
+	}
+
+	for _, oi := range orderItems {
+		if oi == nil {
+			continue
+		}
+		found := false
+		for _, snapshot := range snapshots {
+			if snapshot.ID == oi.ItemID {
+				oi.Title = snapshot.Title
+				oi.UnitPrice = snapshot.Price
+				found = true
+				break
+			}
+		}
+		if !found {
+			return ErrNotFound
+		}
+	}
+	return nil
+}
+
+// reserveStock decrements stock of ordered items.
+// Each decrement is a single conditional update, so concurrent orders can never take stock below zero.
+// Items are updated in a stable order to keep concurrent transactions from deadlocking.
+func reserveStock(ctx context.Context, tx bun.Tx, orderItems []*entities.OrderItem) error {
+	ids, quantities := orderedQuantities(orderItems)
+	for _, id := range ids {
+		quantity := quantities[id]
+		res, err := tx.NewUpdate().
+			Model((*entities.Item)(nil)).
+			Set("stock = stock - ?", quantity).
+			// stock is part of the item, so changing it makes edits of the item expecting the old version fail
+			Set("version = version + 1").
+			Where("id = ?", id).
+			Where("stock >= ?", quantity).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			var available int
+			err = tx.NewSelect().Model((*entities.Item)(nil)).Column("stock").Where("id = ?", id).Scan(ctx, &available)
+			if err != nil {
+				return err
+			}
+			return entities.InsufficientStockError{ItemID: id, Requested: quantity, Available: available}
+		}
+	}
+	return nil
+}
+
+// UpdateStatus persists status of the order and records the status change in the order history.
+// The status is only updated if the order is still in the status the change was made from,
+// otherwise entities.ErrorOrderStatusChanged is returned. Cancelled orders give their reserved stock back.
+// Orders paid by an active payment are neither cancelled nor refunded, entities.ErrorOrderPaymentActive is returned,
+// as their money is released or given back by voiding or refunding the payment.
+func (repo *OrderRepository) UpdateStatus(ctx context.Context, order *entities.Order, change *entities.OrderHistory) error {
+	if order == nil || change == nil {
+		return ErrNilEntity
+	}
+
+	err := runInTx(ctx, repo.bunDb, func(ctx context.Context, tx bun.Tx) error {
+		if change.ToStatus == entities.ORDER_CANCELLED || change.ToStatus == entities.ORDER_REFUNDED {
+			if err := checkNoActivePayment(ctx, tx, order.ID); err != nil {
+				return err
+			}
+		}
+		if err := updateStatus(ctx, tx, order, change); err != nil {
+			return err
+		}
+		if change.ToStatus != entities.ORDER_CANCELLED {
+			return nil
+		}
+
+		var orderItems []*entities.OrderItem
+		err := tx.NewSelect().Model(&orderItems).Where("order_id = ?", order.ID).Scan(ctx)
+		if err != nil {
+			return err
+		}
+		return releaseStock(ctx, tx, orderItems)
+	})
+	return dbError(err)
+}
+
+// updateStatus persists status of the order, if it is still in the status the change was made from,
+// and records the change in the order history and as entities.EVENT_ORDER_STATUS_CHANGED event.
+func updateStatus(ctx context.Context, tx bun.Tx, order *entities.Order, change *entities.OrderHistory) error {
+	res, err := tx.NewUpdate().
+		Model(order).
+		Column("status", "cancellation_reason", "updated_at", "version").
+		Apply(incrementVersion).
+		Where("id = ?", order.ID).
+		Where("status = ?", change.FromStatus).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return entities.ErrorOrderStatusChanged
+	}
+
+	_, err = tx.NewInsert().Model(change).Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	event, err := entities.NewOrderStatusChanged(*order, *change)
+	if err != nil {
+		return err
+	}
+	return storeEvent(ctx, tx, event)
+}
+
+// releaseStock increments stock of ordered items by their ordered quantities.
+// Items are updated in the same stable order as they are reserved in to keep concurrent transactions from deadlocking.
+func releaseStock(ctx context.Context, tx bun.Tx, orderItems []*entities.OrderItem) error {
+	ids, quantities := orderedQuantities(orderItems)
+	for _, id := range ids {
+		_, err := tx.NewUpdate().
+			Model((*entities.Item)(nil)).
+			Set("stock = stock + ?", quantities[id]).
+			Set("version = version + 1").
+			Where("id = ?", id).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// orderedQuantities sums the ordered quantities by item, items are listed in a stable order.
+func orderedQuantities(orderItems []*entities.OrderItem) ([]uuid.UUID, map[uuid.UUID]int) {
+	quantities := make(map[uuid.UUID]int)
+	var ids []uuid.UUID
+	for _, oi := range orderItems {
+		if oi == nil {
+			continue
+		}
+		if _, ok := quantities[oi.ItemID]; !ok {
+			ids = append(ids, oi.ItemID)
+		}
+		quantities[oi.ItemID] += oi.Quantity
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+	return ids, quantities
+}
+
+// GetHistory returns status changes of the order in the order they were made.
+func (repo *OrderRepository) GetHistory(ctx context.Context, orderId uuid.UUID) ([]entities.OrderHistory, error) {
+	history := make([]entities.OrderHistory, 0)
+
+	err := conn(ctx, repo.bunDb).NewSelect().
+		Model(&history).
+		Where("order_id = ?", orderId).
+		Order("created_at ASC").
+		Scan(ctx)
+
+	return history, dbError(err)
+}
+
+// GetByIdForUpdate returns the order with its order items and locks it until the transaction carried by ctx ends.
+func (repo *OrderRepository) GetByIdForUpdate(ctx context.Context, id uuid.UUID) (entities.Order, error) {
+	db := conn(ctx, repo.bunDb)
+	order := new(entities.Order)
+	err := db.NewSelect().Model(order).Where("id = ?", id).For("UPDATE").Scan(ctx)
+	if err != nil {
+		return entities.Order{}, dbError(err)
+	}
+	err = db.NewSelect().Model(&order.OrderItems).Where("order_id = ?", id).Order("created_at ASC").Scan(ctx)
+	if err != nil {
+		return entities.Order{}, dbError(err)
+	}
+	return *order, nil
+}
+
+// CreateRefund persists the refund of the order with its items together with the status change, if it is not nil.
+// Orders paid by an active payment are refunded by refunding the payment, entities.ErrorOrderPaymentActive is returned for them.
+// The order should be locked by GetByIdForUpdate, so concurrent refunds can not refund more than was ordered.
+func (repo *OrderRepository) CreateRefund(ctx context.Context, order *entities.Order, refund *entities.Refund, change *entities.OrderHistory) error {
+	if order == nil || refund == nil {
+		return ErrNilEntity
+	}
+
+	err := runInTx(ctx, repo.bunDb, func(ctx context.Context, tx bun.Tx) error {
+		if err := checkNoActivePayment(ctx, tx, order.ID); err != nil {
+			return err
+		}
+		if _, err := tx.NewInsert().Model(refund).Exec(ctx); err != nil {
+			return err
+		}
+		if len(refund.Items) > 0 {
+			if _, err := tx.NewInsert().Model(&refund.Items).Exec(ctx); err != nil {
+				return err
+			}
+		}
+		if change == nil {
+			return nil
+		}
+		return updateStatus(ctx, tx, order, change)
 	})
+	return dbError(err)
+}
+
+// checkNoActivePayment returns entities.ErrorOrderPaymentActive if the order is paid by an authorized or captured payment.
+func checkNoActivePayment(ctx context.Context, tx bun.Tx, orderId uuid.UUID) error {
+	active, err := tx.NewSelect().
+		Model((*entities.Payment)(nil)).
+		Where("order_id = ?", orderId).
+		Where("status IN (?)", bun.In([]entities.PaymentStatus{entities.PAYMENT_AUTHORIZED, entities.PAYMENT_CAPTURED})).
+		Exists(ctx)
+	if err != nil {
+		return err
+	}
+	if active {
+		return entities.ErrorOrderPaymentActive
+	}
+	return nil
+}
+
+// GetRefunds returns refunds of the order in the order they were made.
+func (repo *OrderRepository) GetRefunds(ctx context.Context, orderId uuid.UUID) ([]entities.Refund, error) {
+	refunds, err := getRefunds(ctx, conn(ctx, repo.bunDb), orderId)
+	return refunds, dbError(err)
+}
+
+func getRefunds(ctx context.Context, db bun.IDB, orderId uuid.UUID) ([]entities.Refund, error) {
+	refunds := make([]entities.Refund, 0)
+	err := db.NewSelect().
+		Model(&refunds).
+		Relation("Items", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.OrderExpr("?TableAlias.created_at ASC, ?TableAlias.id ASC")
+		}).
+		Where("order_id = ?", orderId).
+		Order("created_at ASC").
+		Scan(ctx)
+	return refunds, err
 }
@@ -1,8 +1,11 @@
 package repositories
 
 import (
+	"context"
+	"errors"
 	"github.com/fmiskovic/new-amz/internal/core/entities"
 	"github.com/google/uuid"
+	"sync"
 )
 
 func (s *RepositoryTestSuite) TestGetOrderById() {
@@ -58,6 +61,31 @@ func (s *RepositoryTestSuite) TestSearchAccountOrders() {
 		s.Equal(accountId, page.Elements[0].AccountID)
 	})
 
+	s.Run("given keyset page request should walk all account orders by cursor", func() {
+		// given
+		accountId := uuid.MustParse("220cea28-b2b0-4051-9eb6-9a99e451af01")
+		all, err := repo.Search(s.testDb.Ctx, accountId, entities.Pageable{Size: 100, Keyset: true})
+		s.Nil(err)
+		pageRequest := entities.Pageable{Size: 1, Keyset: true, SkipCount: true}
+		var walked []entities.Order
+
+		// when
+		for {
+			page, err := repo.Search(s.testDb.Ctx, accountId, pageRequest)
+			s.Require().Nil(err)
+			walked = append(walked, page.Elements...)
+			if page.Cursors.Next == "" {
+				break
+			}
+			pageRequest.Cursor = page.Cursors.Next
+		}
+
+		// then
+		s.Equal(*all.TotalElements, len(walked))
+		s.Equal(all.Elements, walked)
+		s.NotEmpty(walked[0].OrderItems)
+	})
+
 	s.Run("given invalid account id should return empty page", func() {
 		// given
 		accountId := uuid.MustParse("220cea28-b2b0-4051-9eb6-9a99e451af10")
@@ -103,6 +131,118 @@ func (s *RepositoryTestSuite) TestCreateOrder() {
 		s.Equal(order.AccountID, createdOrder.AccountID)
 		s.NotNil(createdOrder.OrderItems)
 		s.Equal(order.OrderItems[0].ItemID, createdOrder.OrderItems[0].ItemID)
+		s.Equal("Cool Book 1", orderItem1.Title)
+		s.Equal(entities.NewMoney(750, entities.EUR), orderItem1.UnitPrice)
+		total, err := createdOrder.Total()
+		s.Nil(err)
+		s.Equal(entities.NewMoney(1749, entities.EUR), total)
+	})
+
+	s.Run("should return error if ordered item does not exist", func() {
+		// given
+		order := entities.NewOrderBuilder().
+			AccountID(uuid.MustParse("220cea28-b2b0-4051-9eb6-9a99e451af01")).
+			Build()
+		order.OrderItems = []*entities.OrderItem{
+			entities.NewOrderItemBuilder().
+				ItemID(uuid.New()).
+				OrderID(order.ID).
+				Quantity(1).
+				Build(),
+		}
+		// when
+		err := repo.Create(s.testDb.Ctx, order)
+		// then
+		s.ErrorIs(err, ErrNotFound)
+	})
+
+	s.Run("should decrement stock of ordered items", func() {
+		// given
+		itemId := uuid.MustParse("200cea28-b2b0-4051-9eb6-9a99e451af03")
+		before := new(entities.Item)
+		s.Nil(s.testDb.BunDb.NewSelect().Model(before).Where("id = ?", itemId).Scan(s.testDb.Ctx))
+
+		order := entities.NewOrderBuilder().
+			AccountID(uuid.MustParse("220cea28-b2b0-4051-9eb6-9a99e451af01")).
+			Build()
+		order.OrderItems = []*entities.OrderItem{
+			entities.NewOrderItemBuilder().ItemID(itemId).OrderID(order.ID).Quantity(2).Build(),
+			entities.NewOrderItemBuilder().ItemID(itemId).OrderID(order.ID).Quantity(1).Build(),
+		}
+		// when
+		err := repo.Create(s.testDb.Ctx, order)
+		// then
+		s.Nil(err)
+		after := new(entities.Item)
+		s.Nil(s.testDb.BunDb.NewSelect().Model(after).Where("id = ?", itemId).Scan(s.testDb.Ctx))
+		s.Equal(before.Stock-3, after.Stock)
+	})
+
+	s.Run("should return insufficient stock error and keep stock unchanged", func() {
+		// given
+		itemId := uuid.MustParse("200cea28-b2b0-4051-9eb6-9a99e451af04")
+		before := new(entities.Item)
+		s.Nil(s.testDb.BunDb.NewSelect().Model(before).Where("id = ?", itemId).Scan(s.testDb.Ctx))
+
+		order := entities.NewOrderBuilder().
+			AccountID(uuid.MustParse("220cea28-b2b0-4051-9eb6-9a99e451af01")).
+			Build()
+		order.OrderItems = []*entities.OrderItem{
+			entities.NewOrderItemBuilder().ItemID(itemId).OrderID(order.ID).Quantity(before.Stock + 1).Build(),
+		}
+		// when
+		err := repo.Create(s.testDb.Ctx, order)
+		// then
+		var stockErr entities.InsufficientStockError
+		s.True(errors.As(err, &stockErr))
+		s.Equal(itemId, stockErr.ItemID)
+		s.Equal(before.Stock, stockErr.Available)
+
+		after := new(entities.Item)
+		s.Nil(s.testDb.BunDb.NewSelect().Model(after).Where("id = ?", itemId).Scan(s.testDb.Ctx))
+		s.Equal(before.Stock, after.Stock)
+
+		_, err = repo.GetById(s.testDb.Ctx, order.ID)
+		s.NotNil(err)
+	})
+
+	s.Run("should not oversell the last unit to concurrent orders", func() {
+		// given item with single unit in stock
+		itemId := uuid.MustParse("200cea28-b2b0-4051-9eb6-9a99e451af05")
+		const buyers = 5
+
+		var wg sync.WaitGroup
+		errs := make(chan error, buyers)
+		for i := 0; i < buyers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				order := entities.NewOrderBuilder().
+					AccountID(uuid.MustParse("220cea28-b2b0-4051-9eb6-9a99e451af01")).
+					Build()
+				order.OrderItems = []*entities.OrderItem{
+					entities.NewOrderItemBuilder().ItemID(itemId).OrderID(order.ID).Quantity(1).Build(),
+				}
+				errs <- repo.Create(s.testDb.Ctx, order)
+			}()
+		}
+		// when
+		wg.Wait()
+		close(errs)
+		// then
+		succeeded := 0
+		for err := range errs {
+			if err == nil {
+				succeeded++
+				continue
+			}
+			s.ErrorIs(err, entities.ErrorInsufficientStock)
+		}
+		s.Equal(1, succeeded)
+
+		item := new(entities.Item)
+		s.Nil(s.testDb.BunDb.NewSelect().Model(item).Where("id = ?", itemId).Scan(s.testDb.Ctx))
+		s.Equal(0, item.Stock)
 	})
 
 	s.Run("should return error if order is nil", func() {
@@ -114,3 +254,230 @@ func (s *RepositoryTestSuite) TestCreateOrder() {
 		s.NotNil(err)
 	})
 }
+
+func (s *RepositoryTestSuite) TestUpdateOrderStatus() {
+	repo := NewOrderRepository(s.testDb.BunDb)
+
+	s.Run("should update order status and record history", func() {
+		// given
+		orderId := uuid.MustParse("210cea28-b2b0-4051-9eb6-9a99e451af02")
+		actorId := uuid.MustParse("220cea28-b2b0-4051-9eb6-9a99e451af04")
+		order, err := repo.GetById(s.testDb.Ctx, orderId)
+		s.Nil(err)
+		change, err := order.TransitionTo(entities.ORDER_PAID, actorId, "paid by card")
+		s.Nil(err)
+		// when
+		err = repo.UpdateStatus(s.testDb.Ctx, &order, change)
+		// then
+		s.Nil(err)
+
+		updated, err := repo.GetById(s.testDb.Ctx, orderId)
+		s.Nil(err)
+		s.Equal(entities.ORDER_PAID, updated.Status)
+
+		history, err := repo.GetHistory(s.testDb.Ctx, orderId)
+		s.Nil(err)
+		s.Len(history, 1)
+		s.Equal(entities.ORDER_PENDING, history[0].FromStatus)
+		s.Equal(entities.ORDER_PAID, history[0].ToStatus)
+		s.Equal(actorId, history[0].ActorID.UUID)
+	})
+
+	s.Run("should return error if order status has been changed in the meantime", func() {
+		// given
+		orderId := uuid.MustParse("210cea28-b2b0-4051-9eb6-9a99e451af03")
+		order, err := repo.GetById(s.testDb.Ctx, orderId)
+		s.Nil(err)
+		stale := order
+		change, err := order.TransitionTo(entities.ORDER_CANCELLED, uuid.Nil, "")
+		s.Nil(err)
+		s.Nil(repo.UpdateStatus(s.testDb.Ctx, &order, change))
+		staleChange, err := stale.TransitionTo(entities.ORDER_PAID, uuid.Nil, "")
+		s.Nil(err)
+		// when
+		err = repo.UpdateStatus(s.testDb.Ctx, &stale, staleChange)
+		// then
+		s.ErrorIs(err, entities.ErrorOrderStatusChanged)
+	})
+}
+
+// placeOrder creates a pending order of the items and quantities for the fixture account Emily.
+func (s *RepositoryTestSuite) placeOrder(repo *OrderRepository, quantities map[string]int) entities.Order {
+	var orderItems []*entities.OrderItem
+	for itemId, quantity := range quantities {
+		orderItems = append(orderItems, entities.NewOrderItemBuilder().ItemID(uuid.MustParse(itemId)).Quantity(quantity).Build())
+	}
+	order := entities.NewOrderBuilder().
+		AccountID(uuid.MustParse("220cea28-b2b0-4051-9eb6-9a99e451af03")).
+		OrderItems(orderItems).
+		Build()
+	s.Require().Nil(repo.Create(s.testDb.Ctx, order))
+
+	created, err := repo.GetById(s.testDb.Ctx, order.ID)
+	s.Require().Nil(err)
+	return created
+}
+
+func (s *RepositoryTestSuite) stockOf(itemId string) int {
+	var stock int
+	err := s.testDb.BunDb.NewSelect().Model((*entities.Item)(nil)).Column("stock").Where("id = ?", itemId).Scan(s.testDb.Ctx, &stock)
+	s.Require().Nil(err)
+	return stock
+}
+
+func (s *RepositoryTestSuite) TestCancelOrder() {
+	repo := NewOrderRepository(s.testDb.BunDb)
+	itemId := "200cea28-b2b0-4051-9eb6-9a99e451af02"
+
+	s.Run("should give reserved stock back and record reason", func() {
+		// given
+		stock := s.stockOf(itemId)
+		order := s.placeOrder(repo, map[string]int{itemId: 3})
+		s.Equal(stock-3, s.stockOf(itemId))
+		change, err := order.TransitionTo(entities.ORDER_CANCELLED, uuid.Nil, "changed my mind")
+		s.Require().Nil(err)
+		// when
+		err = repo.UpdateStatus(s.testDb.Ctx, &order, change)
+		// then
+		s.Nil(err)
+		s.Equal(stock, s.stockOf(itemId))
+		cancelled, err := repo.GetById(s.testDb.Ctx, order.ID)
+		s.Nil(err)
+		s.Equal(entities.ORDER_CANCELLED, cancelled.Status)
+		s.Equal("changed my mind", cancelled.CancellationReason)
+	})
+
+	s.Run("given order paid by active payment should keep it and its stock", func() {
+		// given
+		order := s.placeOrder(repo, map[string]int{itemId: 1})
+		total, err := order.Total()
+		s.Require().Nil(err)
+		s.Require().Nil(NewPaymentRepository(s.testDb.BunDb).Create(s.testDb.Ctx, entities.NewPayment(order.ID, "fake", total)))
+		stock := s.stockOf(itemId)
+		change, err := order.TransitionTo(entities.ORDER_CANCELLED, uuid.Nil, "changed my mind")
+		s.Require().Nil(err)
+		// when
+		err = repo.UpdateStatus(s.testDb.Ctx, &order, change)
+		// then
+		s.ErrorIs(err, entities.ErrorOrderPaymentActive)
+		s.Equal(stock, s.stockOf(itemId))
+		pending, err := repo.GetById(s.testDb.Ctx, order.ID)
+		s.Nil(err)
+		s.Equal(entities.ORDER_PENDING, pending.Status)
+	})
+
+	s.Run("should not give stock back for other transitions", func() {
+		// given
+		order := s.placeOrder(repo, map[string]int{itemId: 1})
+		stock := s.stockOf(itemId)
+		change, err := order.TransitionTo(entities.ORDER_PAID, uuid.Nil, "")
+		s.Require().Nil(err)
+		// when
+		err = repo.UpdateStatus(s.testDb.Ctx, &order, change)
+		// then
+		s.Nil(err)
+		s.Equal(stock, s.stockOf(itemId))
+	})
+}
+
+func (s *RepositoryTestSuite) TestRefundOrder() {
+	repo := NewOrderRepository(s.testDb.BunDb)
+	txManager := NewTxManager(s.testDb.BunDb)
+	actorId := uuid.MustParse("220cea28-b2b0-4051-9eb6-9a99e451af04")
+
+	// refund locks the order and records the refund of the lines the way the order service does
+	refund := func(orderId uuid.UUID, lines []entities.RefundLine) (*entities.Refund, error) {
+		var refund *entities.Refund
+		err := txManager.WithinTx(s.testDb.Ctx, func(ctx context.Context) error {
+			order, err := repo.GetByIdForUpdate(ctx, orderId)
+			if err != nil {
+				return err
+			}
+			refunds, err := repo.GetRefunds(ctx, orderId)
+			if err != nil {
+				return err
+			}
+			r, change, err := order.Refund(refunds, lines, actorId, "damaged")
+			if err != nil {
+				return err
+			}
+			refund = r
+			return repo.CreateRefund(ctx, &order, refund, change)
+		})
+		return refund, err
+	}
+
+	s.Run("should persist partial refunds up to ordered quantities and then move order into refunded status", func() {
+		// given
+		order := s.placeOrder(repo, map[string]int{"200cea28-b2b0-4051-9eb6-9a99e451af01": 2})
+		change, err := order.TransitionTo(entities.ORDER_PAID, uuid.Nil, "")
+		s.Require().Nil(err)
+		s.Require().Nil(repo.UpdateStatus(s.testDb.Ctx, &order, change))
+		line := entities.RefundLine{OrderItemID: order.OrderItems[0].ID, Quantity: 1}
+
+		// when
+		first, err := refund(order.ID, []entities.RefundLine{line})
+		// then
+		s.Require().Nil(err)
+		s.Equal(entities.NewMoney(750, entities.EUR), first.Amount)
+
+		// when refunding more than is left
+		line.Quantity = 2
+		_, err = refund(order.ID, []entities.RefundLine{line})
+		// then
+		s.ErrorIs(err, entities.ErrorRefundExceedsQuantity)
+
+		// when refunding the rest
+		_, err = refund(order.ID, nil)
+		// then
+		s.Require().Nil(err)
+		refunds, err := repo.GetRefunds(s.testDb.Ctx, order.ID)
+		s.Nil(err)
+		s.Len(refunds, 2)
+		s.Equal(first.ID, refunds[0].ID)
+		s.Len(refunds[1].Items, 1)
+		s.Equal(1, refunds[1].Items[0].Quantity)
+		refunded, err := repo.GetById(s.testDb.Ctx, order.ID)
+		s.Nil(err)
+		s.Equal(entities.ORDER_REFUNDED, refunded.Status)
+	})
+
+	s.Run("given order paid by captured payment should return error", func() {
+		// given
+		order := s.placeOrder(repo, map[string]int{"200cea28-b2b0-4051-9eb6-9a99e451af01": 1})
+		total, err := order.Total()
+		s.Require().Nil(err)
+		payment := entities.NewPayment(order.ID, "fake", total)
+		s.Require().Nil(payment.Capture(total))
+		s.Require().Nil(NewPaymentRepository(s.testDb.BunDb).Create(s.testDb.Ctx, payment))
+		change, err := order.TransitionTo(entities.ORDER_PAID, uuid.Nil, "")
+		s.Require().Nil(err)
+		s.Require().Nil(repo.UpdateStatus(s.testDb.Ctx, &order, change))
+		// when
+		_, err = refund(order.ID, nil)
+		// then
+		s.ErrorIs(err, entities.ErrorOrderPaymentActive)
+		refunds, err := repo.GetRefunds(s.testDb.Ctx, order.ID)
+		s.Nil(err)
+		s.Empty(refunds)
+	})
+
+	s.Run("given pending order should return error", func() {
+		// given
+		order := s.placeOrder(repo, map[string]int{"200cea28-b2b0-4051-9eb6-9a99e451af01": 1})
+		// when
+		_, err := refund(order.ID, nil)
+		// then
+		s.ErrorIs(err, entities.ErrorOrderNotRefundable)
+		refunds, err := repo.GetRefunds(s.testDb.Ctx, order.ID)
+		s.Nil(err)
+		s.Empty(refunds)
+	})
+
+	s.Run("given unknown order should return not found error", func() {
+		// when
+		_, err := refund(uuid.New(), nil)
+		// then
+		s.ErrorIs(err, ErrNotFound)
+	})
+}
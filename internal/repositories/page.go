@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/uptrace/bun"
+)
+
+// orderBy applies sort orders to the query. Properties are resolved to columns through the sortable declaration
+// and quoted as identifiers, so that only declared columns and supported directions reach the query.
+func orderBy(q *bun.SelectQuery, sortable entities.Sortable, sort entities.Sort) (*bun.SelectQuery, error) {
+	for _, o := range sort.Orders {
+		column, err := sortable.Column(o.Property)
+		if err != nil {
+			return q, err
+		}
+		direction, err := entities.ParseDirection(string(o.Direction))
+		if err != nil {
+			return q, err
+		}
+		q = q.OrderExpr("?TableAlias.? "+string(direction), bun.Ident(column))
+	}
+	return q, nil
+}
+
+// offsetPage selects the page of elements requested by offset, see entities.Pageable.
+// The query must select into elements and may carry filters, but no ordering or limits.
+func offsetPage[T any](ctx context.Context, q *bun.SelectQuery, elements *[]T, sortable entities.Sortable, p entities.Pageable) (entities.Page[T], error) {
+	q, err := orderBy(q, sortable, p.Sort)
+	if err != nil {
+		return entities.Page[T]{}, err
+	}
+	q = q.Offset(p.Offset)
+
+	if !p.SkipCount {
+		count, err := q.Limit(p.Size).ScanAndCount(ctx)
+		if err != nil {
+			return entities.Page[T]{}, dbError(err)
+		}
+		return entities.NewPage(*elements, p, count, p.Offset+len(*elements) < count), nil
+	}
+
+	if p.Size > 0 {
+		// one more element tells whether there is a page after this one
+		q = q.Limit(p.Size + 1)
+	}
+	if err := q.Scan(ctx); err != nil {
+		return entities.Page[T]{}, dbError(err)
+	}
+	more := p.Size > 0 && len(*elements) > p.Size
+	if more {
+		*elements = (*elements)[:p.Size]
+	}
+	return entities.NewPage(*elements, p, 0, more), nil
+}
@@ -0,0 +1,129 @@
+package repositories
+
+import (
+	"context"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// Names of the constraints violated by payment changes.
+const (
+	paymentOrderConstraint       = "fk_order"
+	paymentActiveOrderConstraint = "payments_order_active_idx"
+)
+
+// PaymentRepository is the implementation of core repositories.PaymentRepository interface.
+type PaymentRepository struct {
+	bunDb *bun.DB
+}
+
+// NewPaymentRepository instantiates new PaymentRepository.
+func NewPaymentRepository(db *bun.DB) PaymentRepository {
+	return PaymentRepository{db}
+}
+
+// GetById returns payment by specified id.
+func (repo PaymentRepository) GetById(ctx context.Context, id uuid.UUID) (entities.Payment, error) {
+	payment := new(entities.Payment)
+	err := conn(ctx, repo.bunDb).NewSelect().Model(payment).Where("id = ?", id).Scan(ctx)
+	if err != nil {
+		return entities.Payment{}, dbError(err)
+	}
+	return *payment, nil
+}
+
+// GetByReference returns payment by the reference it has at the provider.
+func (repo PaymentRepository) GetByReference(ctx context.Context, provider string, reference string) (entities.Payment, error) {
+	payment := new(entities.Payment)
+	err := conn(ctx, repo.bunDb).NewSelect().
+		Model(payment).
+		Where("provider = ?", provider).
+		Where("reference = ?", reference).
+		Scan(ctx)
+	if err != nil {
+		return entities.Payment{}, dbError(err)
+	}
+	return *payment, nil
+}
+
+// GetByOrder returns payments of the order in the order they were made.
+func (repo PaymentRepository) GetByOrder(ctx context.Context, orderId uuid.UUID) ([]entities.Payment, error) {
+	payments := make([]entities.Payment, 0)
+	err := conn(ctx, repo.bunDb).NewSelect().
+		Model(&payments).
+		Where("order_id = ?", orderId).
+		Order("created_at ASC").
+		Scan(ctx)
+	return payments, dbError(err)
+}
+
+// Create persists the payment.
+// It returns ErrNotFound if the order does not exist and entities.ErrorOrderPaymentExists
+// if the payment is active and the order has another active payment already.
+func (repo PaymentRepository) Create(ctx context.Context, payment *entities.Payment) error {
+	if payment == nil {
+		return ErrNilEntity
+	}
+
+	_, err := conn(ctx, repo.bunDb).NewInsert().Model(payment).Exec(ctx)
+	return paymentError(err)
+}
+
+// Update locks the payment, so operations on the payment are applied one after another,
+// and persists the payment changed by the update func together with the order status change it returns, if any.
+func (repo PaymentRepository) Update(
+	ctx context.Context,
+	id uuid.UUID,
+	update func(ctx context.Context, payment *entities.Payment, order entities.Order) (*entities.OrderHistory, error),
+) (entities.Payment, error) {
+	payment := new(entities.Payment)
+	err := runInTx(ctx, repo.bunDb, func(ctx context.Context, tx bun.Tx) error {
+		err := tx.NewSelect().Model(payment).Where("id = ?", id).For("UPDATE").Scan(ctx)
+		if err != nil {
+			return err
+		}
+		order := new(entities.Order)
+		err = tx.NewSelect().Model(order).Relation("OrderItems").Where("?TableAlias.id = ?", payment.OrderID).Scan(ctx)
+		if err != nil {
+			return err
+		}
+
+		change, err := update(ctx, payment, *order)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.NewUpdate().
+			Model(payment).
+			Column("status", "captured_amount", "refunded_amount", "failure_reason").
+			Column("pending_operation", "pending_amount", "pending_key", "updated_at", "version").
+			WherePK().
+			Apply(incrementVersion).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if change == nil {
+			return nil
+		}
+		order.Status = change.ToStatus
+		order.UpdatedAt = change.CreatedAt
+		return updateStatus(ctx, tx, order, change)
+	})
+	if err != nil {
+		return entities.Payment{}, paymentError(err)
+	}
+	return *payment, nil
+}
+
+func paymentError(err error) error {
+	switch {
+	case violatedConstraint(err, pgForeignKeyViolation) == paymentOrderConstraint:
+		return ErrNotFound
+	case violatedConstraint(err, pgUniqueViolation) == paymentActiveOrderConstraint:
+		return entities.ErrorOrderPaymentExists
+	default:
+		return dbError(err)
+	}
+}
@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"context"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+)
+
+func (s *RepositoryTestSuite) TestPayments() {
+	repo := NewPaymentRepository(s.testDb.BunDb)
+	orders := NewOrderRepository(s.testDb.BunDb)
+	itemId := "200cea28-b2b0-4051-9eb6-9a99e451af01"
+
+	s.Run("should allow one active payment per order and find it by reference", func() {
+		// given
+		order := s.placeOrder(orders, map[string]int{itemId: 1})
+		total, err := order.Total()
+		s.Require().Nil(err)
+		payment := entities.NewPayment(order.ID, "fake", total)
+		payment.Reference = "ref-" + payment.ID.String()
+
+		// when
+		err = repo.Create(s.testDb.Ctx, payment)
+		// then
+		s.Require().Nil(err)
+		found, err := repo.GetByReference(s.testDb.Ctx, "fake", payment.Reference)
+		s.Nil(err)
+		s.Equal(payment.ID, found.ID)
+		s.Equal(total, found.Amount)
+
+		// when another active payment is created
+		other := entities.NewPayment(order.ID, "fake", total)
+		other.Reference = "ref-" + other.ID.String()
+		err = repo.Create(s.testDb.Ctx, other)
+		// then
+		s.ErrorIs(err, entities.ErrorOrderPaymentExists)
+
+		// when a failed payment is created
+		failed := entities.NewPayment(order.ID, "fake", total)
+		s.Require().Nil(failed.Fail("declined"))
+		err = repo.Create(s.testDb.Ctx, failed)
+		// then
+		s.Nil(err)
+		payments, err := repo.GetByOrder(s.testDb.Ctx, order.ID)
+		s.Nil(err)
+		s.Len(payments, 2)
+	})
+
+	s.Run("should persist captured payment together with order status change", func() {
+		// given
+		order := s.placeOrder(orders, map[string]int{itemId: 1})
+		total, err := order.Total()
+		s.Require().Nil(err)
+		payment := entities.NewPayment(order.ID, "fake", total)
+		payment.Reference = "ref-" + payment.ID.String()
+		s.Require().Nil(repo.Create(s.testDb.Ctx, payment))
+
+		// when
+		captured, err := repo.Update(s.testDb.Ctx, payment.ID, func(_ context.Context, payment *entities.Payment, order entities.Order) (*entities.OrderHistory, error) {
+			if err := payment.Capture(payment.Amount); err != nil {
+				return nil, err
+			}
+			return order.TransitionTo(entities.ORDER_PAID, uuid.Nil, "payment captured")
+		})
+
+		// then
+		s.Require().Nil(err)
+		s.Equal(entities.PAYMENT_CAPTURED, captured.Status)
+		found, err := repo.GetById(s.testDb.Ctx, payment.ID)
+		s.Nil(err)
+		s.Equal(total, found.CapturedAmount())
+		paid, err := orders.GetById(s.testDb.Ctx, order.ID)
+		s.Nil(err)
+		s.Equal(entities.ORDER_PAID, paid.Status)
+	})
+
+	s.Run("should not persist anything when update fails", func() {
+		// given
+		order := s.placeOrder(orders, map[string]int{itemId: 1})
+		total, err := order.Total()
+		s.Require().Nil(err)
+		payment := entities.NewPayment(order.ID, "fake", total)
+		s.Require().Nil(repo.Create(s.testDb.Ctx, payment))
+
+		// when refunding the payment which is not captured
+		_, err = repo.Update(s.testDb.Ctx, payment.ID, func(_ context.Context, payment *entities.Payment, _ entities.Order) (*entities.OrderHistory, error) {
+			return nil, payment.Refund(payment.Amount)
+		})
+
+		// then
+		s.ErrorIs(err, entities.ErrorInvalidPaymentOperation)
+		found, err := repo.GetById(s.testDb.Ctx, payment.ID)
+		s.Nil(err)
+		s.Equal(entities.PAYMENT_AUTHORIZED, found.Status)
+	})
+
+	s.Run("given unknown payment should return not found error", func() {
+		// when
+		_, err := repo.Update(s.testDb.Ctx, uuid.New(), func(context.Context, *entities.Payment, entities.Order) (*entities.OrderHistory, error) {
+			return nil, nil
+		})
+		// then
+		s.ErrorIs(err, ErrNotFound)
+	})
+}
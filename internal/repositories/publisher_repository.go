@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"context"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+// PublisherRepository is the implementation of core repositories.PublisherRepository interface.
+type PublisherRepository struct {
+	bunDb *bun.DB
+}
+
+// NewPublisherRepository instantiates new PublisherRepository.
+func NewPublisherRepository(db *bun.DB) PublisherRepository {
+	return PublisherRepository{db}
+}
+
+// GetById returns publisher by specified id.
+func (repo PublisherRepository) GetById(ctx context.Context, id uuid.UUID) (entities.Publisher, error) {
+	publisher := new(entities.Publisher)
+
+	err := conn(ctx, repo.bunDb).NewSelect().Model(publisher).Where("id = ?", id).Scan(ctx)
+	if err != nil {
+		return *publisher, dbError(err)
+	}
+
+	return *publisher, nil
+}
+
+// GetPage respond with a page of publishers.
+func (repo PublisherRepository) GetPage(ctx context.Context, p entities.Pageable) (entities.Page[entities.Publisher], error) {
+	var publishers []entities.Publisher
+	if p.Keyset {
+		return keysetPage(ctx, conn(ctx, repo.bunDb).NewSelect().Model(&publishers), &publishers, entities.PublisherSortable, p)
+	}
+	return offsetPage(ctx, conn(ctx, repo.bunDb).NewSelect().Model(&publishers), &publishers, entities.PublisherSortable, p)
+}
+
+// Create persists new publisher entity.
+// It returns entities.ErrorPublisherNotUnique if a publisher of the same name exists.
+func (repo PublisherRepository) Create(ctx context.Context, publisher *entities.Publisher) error {
+	if publisher == nil {
+		return ErrNilEntity
+	}
+
+	_, err := conn(ctx, repo.bunDb).NewInsert().Model(publisher).Exec(ctx)
+	return publisherError(err)
+}
+
+// Update persists changes of existing publisher entity.
+// It returns ErrNotFound if the publisher does not exist, entities.ErrorVersionMismatch if it is in another version
+// and entities.ErrorPublisherNotUnique if another publisher has the same name.
+func (repo PublisherRepository) Update(ctx context.Context, publisher *entities.Publisher) error {
+	if publisher == nil {
+		return ErrNilEntity
+	}
+
+	publisher.UpdatedAt = time.Now()
+	res, err := conn(ctx, repo.bunDb).NewUpdate().
+		Model(publisher).
+		Column("updated_at", "version", "name").
+		WherePK().
+		Apply(compareAndSwap(publisher.Version)).
+		Returning("created_at, version").
+		Exec(ctx)
+	if err != nil {
+		return publisherError(err)
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return versionError(ctx, conn(ctx, repo.bunDb), (*entities.Publisher)(nil), publisher.ID, publisher.Version)
+	}
+	return nil
+}
+
+// DeleteById deletes publisher by specified id, if it is in the version or the version is zero.
+// Publishers of any item can not be deleted and entities.ErrorPublisherHasItems is returned instead.
+func (repo PublisherRepository) DeleteById(ctx context.Context, id uuid.UUID, version int64) error {
+	res, err := conn(ctx, repo.bunDb).NewDelete().
+		Model((*entities.Publisher)(nil)).
+		Where("id = ?", id).
+		Apply(whereVersion(version)).
+		Exec(ctx)
+	if err != nil {
+		if isPgError(err, pgForeignKeyViolation) {
+			return entities.ErrorPublisherHasItems
+		}
+		return dbError(err)
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return versionError(ctx, conn(ctx, repo.bunDb), (*entities.Publisher)(nil), id, version)
+	}
+	return nil
+}
+
+// publisherError translates constraint violations of publisher changes into domain errors.
+func publisherError(err error) error {
+	if isPgError(err, pgUniqueViolation) {
+		return entities.ErrorPublisherNotUnique
+	}
+	return dbError(err)
+}
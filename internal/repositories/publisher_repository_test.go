@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+)
+
+var (
+	coolPressId  = uuid.MustParse("260cea28-b2b0-4051-9eb6-9a99e451af01")
+	smallPressId = uuid.MustParse("260cea28-b2b0-4051-9eb6-9a99e451af02")
+)
+
+func (s *RepositoryTestSuite) TestGetPublishersPage() {
+	repo := NewPublisherRepository(s.testDb.BunDb)
+
+	s.Run("should return page of publishers sorted by name", func() {
+		// given
+		pageRequest := entities.Pageable{Size: 10, Sort: entities.NewSort(entities.NewSortOrder(entities.WithProperty("name"), entities.WithDirection(entities.DESC)))}
+		// when
+		page, err := repo.GetPage(s.testDb.Ctx, pageRequest)
+		// then
+		s.Nil(err)
+		s.Equal(2, *page.TotalElements)
+		s.Equal("Small Press", page.Elements[0].Name)
+		s.Equal("Cool Press", page.Elements[1].Name)
+	})
+}
+
+func (s *RepositoryTestSuite) TestManagePublisher() {
+	repo := NewPublisherRepository(s.testDb.BunDb)
+
+	s.Run("should create, update and delete publisher", func() {
+		// given
+		publisher := entities.NewPublisherBuilder().Name("New Press").Build()
+
+		// when
+		err := repo.Create(s.testDb.Ctx, publisher)
+		// then
+		s.Nil(err)
+
+		// when
+		publisher.Name = "Renamed Press"
+		err = repo.Update(s.testDb.Ctx, publisher)
+		// then
+		s.Nil(err)
+		updated, err := repo.GetById(s.testDb.Ctx, publisher.ID)
+		s.Nil(err)
+		s.Equal("Renamed Press", updated.Name)
+
+		// when
+		err = repo.DeleteById(s.testDb.Ctx, publisher.ID, 0)
+		// then
+		s.Nil(err)
+		_, err = repo.GetById(s.testDb.Ctx, publisher.ID)
+		s.ErrorIs(err, ErrNotFound)
+	})
+
+	s.Run("should not create publisher with taken name in other case", func() {
+		// when
+		err := repo.Create(s.testDb.Ctx, entities.NewPublisherBuilder().Name("COOL PRESS").Build())
+		// then
+		s.ErrorIs(err, entities.ErrorPublisherNotUnique)
+	})
+
+	s.Run("should not rename publisher to taken name", func() {
+		// given
+		publisher, err := repo.GetById(s.testDb.Ctx, smallPressId)
+		s.Require().Nil(err)
+		publisher.Name = "Cool Press"
+		// when
+		err = repo.Update(s.testDb.Ctx, &publisher)
+		// then
+		s.ErrorIs(err, entities.ErrorPublisherNotUnique)
+	})
+
+	s.Run("should not delete publisher of items", func() {
+		// when
+		err := repo.DeleteById(s.testDb.Ctx, coolPressId, 0)
+		// then
+		s.ErrorIs(err, entities.ErrorPublisherHasItems)
+	})
+}
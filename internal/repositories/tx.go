@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/uptrace/bun"
+)
+
+// txKey is the context key of the transaction carried by a context, see withTx.
+type txKey struct{}
+
+// withTx returns a copy of ctx carrying the transaction, so repositories called with it join the transaction.
+func withTx(ctx context.Context, tx bun.Tx) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// conn returns the transaction carried by ctx, or db if there is none.
+// Queries run outside of runInTx use it to see and lock the rows of the carried transaction.
+func conn(ctx context.Context, db *bun.DB) bun.IDB {
+	if tx, ok := ctx.Value(txKey{}).(bun.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// runInTx runs fn in a new transaction, or in a savepoint of the transaction carried by ctx.
+// Joining the carried transaction makes the changes of fn commit or roll back together with it.
+// The context passed to fn carries the transaction, so repositories called by fn join it as well.
+func runInTx(ctx context.Context, db *bun.DB, fn func(ctx context.Context, tx bun.Tx) error) error {
+	return conn(ctx, db).RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
+		return fn(withTx(ctx, tx), tx)
+	})
+}
+
+// runInReadOnlyTx runs fn like runInTx, except that a new transaction is started read only.
+func runInReadOnlyTx(ctx context.Context, db *bun.DB, fn func(ctx context.Context, tx bun.Tx) error) error {
+	return conn(ctx, db).RunInTx(ctx, &sql.TxOptions{ReadOnly: true}, func(ctx context.Context, tx bun.Tx) error {
+		return fn(withTx(ctx, tx), tx)
+	})
+}
+
+// TxManager is the implementation of core.TxManager interface.
+type TxManager struct {
+	bunDb *bun.DB
+}
+
+// NewTxManager instantiates new TxManager.
+func NewTxManager(db *bun.DB) TxManager {
+	return TxManager{db}
+}
+
+// WithinTx runs fn in a new transaction, or in a savepoint of the transaction carried by ctx.
+// Repositories called with the context passed to fn join the transaction. The error of fn is returned as is,
+// errors of starting, committing or rolling back the transaction are translated like errors of the repositories.
+func (m TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	var fnErr error
+	err := runInTx(ctx, m.bunDb, func(ctx context.Context, _ bun.Tx) error {
+		fnErr = fn(ctx)
+		return fnErr
+	})
+	if err != nil && err == fnErr {
+		return err
+	}
+	return dbError(err)
+}
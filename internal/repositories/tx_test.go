@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+)
+
+func (s *RepositoryTestSuite) TestTxManager() {
+	txManager := NewTxManager(s.testDb.BunDb)
+	accounts := NewAccountRepository(s.testDb.BunDb)
+	orders := NewOrderRepository(s.testDb.BunDb)
+	itemId := "200cea28-b2b0-4051-9eb6-9a99e451af01"
+
+	// signUp creates an account and its first order of the quantity of the item in one unit of work.
+	signUp := func(ctx context.Context, email string, quantity int) (*entities.Account, *entities.Order, error) {
+		account := entities.NewAccountBuilder().Email(email).Build()
+		order := entities.NewOrderBuilder().
+			OrderItems([]*entities.OrderItem{
+				entities.NewOrderItemBuilder().ItemID(uuid.MustParse(itemId)).Quantity(quantity).Build(),
+			}).
+			Build()
+		err := txManager.WithinTx(ctx, func(ctx context.Context) error {
+			if err := accounts.Create(ctx, account); err != nil {
+				return err
+			}
+			order.AccountID = account.ID
+			return orders.Create(ctx, order)
+		})
+		return account, order, err
+	}
+
+	s.Run("should commit changes of several repositories together", func() {
+		// when
+		account, order, err := signUp(s.testDb.Ctx, "unit.of.work@mail.com", 1)
+
+		// then
+		s.Require().Nil(err)
+		_, err = accounts.GetById(s.testDb.Ctx, account.ID)
+		s.Nil(err)
+		created, err := orders.GetById(s.testDb.Ctx, order.ID)
+		s.Nil(err)
+		s.Equal(account.ID, created.AccountID)
+	})
+
+	s.Run("should roll back changes of several repositories together", func() {
+		// given
+		stock := s.stockOf(itemId)
+
+		// when ordering more than is in stock
+		account, _, err := signUp(s.testDb.Ctx, "rolled.back@mail.com", stock+1)
+
+		// then
+		s.ErrorIs(err, entities.ErrorInsufficientStock)
+		_, err = accounts.GetById(s.testDb.Ctx, account.ID)
+		s.ErrorIs(err, ErrNotFound)
+		_, err = accounts.GetByEmail(s.testDb.Ctx, "rolled.back@mail.com")
+		s.ErrorIs(err, ErrNotFound)
+		s.Equal(stock, s.stockOf(itemId))
+	})
+
+	s.Run("should roll back nested unit only with outer one", func() {
+		// given
+		var account *entities.Account
+
+		// when
+		err := txManager.WithinTx(s.testDb.Ctx, func(ctx context.Context) error {
+			var err error
+			if account, _, err = signUp(ctx, "nested@mail.com", 1); err != nil {
+				return err
+			}
+			// the nested unit is visible within the outer one
+			if _, err = accounts.GetById(ctx, account.ID); err != nil {
+				return err
+			}
+			return entities.ErrorOrderStatusChanged
+		})
+
+		// then
+		s.ErrorIs(err, entities.ErrorOrderStatusChanged)
+		_, err = accounts.GetById(s.testDb.Ctx, account.ID)
+		s.ErrorIs(err, ErrNotFound)
+	})
+
+	s.Run("should return error of the unit as is", func() {
+		// when
+		err := txManager.WithinTx(s.testDb.Ctx, func(ctx context.Context) error {
+			return sql.ErrNoRows
+		})
+
+		// then
+		s.Equal(sql.ErrNoRows, err)
+		s.NotErrorIs(err, ErrNotFound)
+	})
+}
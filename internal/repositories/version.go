@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"context"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// incrementVersion makes the update it is applied to increment the version of the entity.
+// The version column has to be one of the updated columns.
+func incrementVersion(q *bun.UpdateQuery) *bun.UpdateQuery {
+	return q.Value("version", "?TableAlias.version + 1")
+}
+
+// compareAndSwap makes the update it is applied to increment the version of the entity.
+// If the expected version is not zero, the entity is only updated while it is in that version.
+// Requests always expect a version, If-Match: * is rejected, zero is left to callers changing the entity unconditionally.
+// The version column has to be one of the updated columns, the update returns it to learn the new version.
+func compareAndSwap(version int64) func(q *bun.UpdateQuery) *bun.UpdateQuery {
+	return func(q *bun.UpdateQuery) *bun.UpdateQuery {
+		if version != 0 {
+			q = q.Where("?TableAlias.version = ?", version)
+		}
+		return incrementVersion(q)
+	}
+}
+
+// whereVersion makes the delete it is applied to only delete the entity while it is in the expected version,
+// if the version is not zero.
+func whereVersion(version int64) func(q *bun.DeleteQuery) *bun.DeleteQuery {
+	return func(q *bun.DeleteQuery) *bun.DeleteQuery {
+		if version != 0 {
+			q = q.Where("?TableAlias.version = ?", version)
+		}
+		return q
+	}
+}
+
+// versionError tells why the update or delete of the entity with the id, expecting the version, affected no rows.
+// It returns entities.ErrorVersionMismatch if the entity exists in another version and ErrNotFound otherwise.
+func versionError(ctx context.Context, db bun.IDB, model any, id uuid.UUID, version int64) error {
+	if version == 0 {
+		return ErrNotFound
+	}
+	exists, err := db.NewSelect().Model(model).Where("?TableAlias.id = ?", id).Exists(ctx)
+	if err != nil {
+		return dbError(err)
+	}
+	if !exists {
+		return ErrNotFound
+	}
+	return entities.ErrorVersionMismatch
+}
@@ -0,0 +1,256 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// WebhookSubscriptionRepository is the implementation of core repositories.WebhookSubscriptionRepository interface.
+type WebhookSubscriptionRepository struct {
+	bunDb *bun.DB
+}
+
+// NewWebhookSubscriptionRepository instantiates new WebhookSubscriptionRepository.
+func NewWebhookSubscriptionRepository(db *bun.DB) WebhookSubscriptionRepository {
+	return WebhookSubscriptionRepository{db}
+}
+
+// GetById returns webhook subscription by specified id.
+func (repo WebhookSubscriptionRepository) GetById(ctx context.Context, id uuid.UUID) (entities.WebhookSubscription, error) {
+	subscription := new(entities.WebhookSubscription)
+
+	err := conn(ctx, repo.bunDb).NewSelect().Model(subscription).Where("id = ?", id).Scan(ctx)
+	if err != nil {
+		return *subscription, dbError(err)
+	}
+
+	return *subscription, nil
+}
+
+// GetPage respond with a page of webhook subscriptions.
+func (repo WebhookSubscriptionRepository) GetPage(ctx context.Context, p entities.Pageable) (entities.Page[entities.WebhookSubscription], error) {
+	var subscriptions []entities.WebhookSubscription
+	q := conn(ctx, repo.bunDb).NewSelect().Model(&subscriptions)
+	if p.Keyset {
+		return keysetPage(ctx, q, &subscriptions, entities.WebhookSubscriptionSortable, p)
+	}
+	return offsetPage(ctx, q, &subscriptions, entities.WebhookSubscriptionSortable, p)
+}
+
+// GetSubscribed returns enabled subscriptions to events of the type.
+func (repo WebhookSubscriptionRepository) GetSubscribed(ctx context.Context, eventType entities.EventType) ([]entities.WebhookSubscription, error) {
+	var subscriptions []entities.WebhookSubscription
+	err := conn(ctx, repo.bunDb).NewSelect().
+		Model(&subscriptions).
+		Where("disabled = FALSE").
+		Where("? = ANY(event_types)", eventType).
+		Scan(ctx)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	return subscriptions, nil
+}
+
+// Create persists new webhook subscription entity.
+func (repo WebhookSubscriptionRepository) Create(ctx context.Context, subscription *entities.WebhookSubscription) error {
+	if subscription == nil {
+		return ErrNilEntity
+	}
+
+	_, err := conn(ctx, repo.bunDb).NewInsert().Model(subscription).Exec(ctx)
+	return dbError(err)
+}
+
+// Update persists changes of existing webhook subscription entity.
+// It returns ErrNotFound if the subscription does not exist and entities.ErrorVersionMismatch if it is in another version.
+func (repo WebhookSubscriptionRepository) Update(ctx context.Context, subscription *entities.WebhookSubscription) error {
+	if subscription == nil {
+		return ErrNilEntity
+	}
+
+	subscription.UpdatedAt = time.Now()
+	res, err := conn(ctx, repo.bunDb).NewUpdate().
+		Model(subscription).
+		Column("updated_at", "version", "url", "event_types", "secret", "disabled").
+		WherePK().
+		Apply(compareAndSwap(subscription.Version)).
+		Returning("created_at, version").
+		Exec(ctx)
+	if err != nil {
+		return dbError(err)
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return versionError(ctx, conn(ctx, repo.bunDb), (*entities.WebhookSubscription)(nil), subscription.ID, subscription.Version)
+	}
+	return nil
+}
+
+// DeleteById deletes webhook subscription by specified id together with its deliveries,
+// if the subscription is in the version or the version is zero.
+func (repo WebhookSubscriptionRepository) DeleteById(ctx context.Context, id uuid.UUID, version int64) error {
+	res, err := conn(ctx, repo.bunDb).NewDelete().
+		Model((*entities.WebhookSubscription)(nil)).
+		Where("id = ?", id).
+		Apply(whereVersion(version)).
+		Exec(ctx)
+	if err != nil {
+		return dbError(err)
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return versionError(ctx, conn(ctx, repo.bunDb), (*entities.WebhookSubscription)(nil), id, version)
+	}
+	return nil
+}
+
+// WebhookDeliveryRepository is the implementation of core repositories.WebhookDeliveryRepository interface.
+type WebhookDeliveryRepository struct {
+	bunDb *bun.DB
+}
+
+// NewWebhookDeliveryRepository instantiates new WebhookDeliveryRepository.
+func NewWebhookDeliveryRepository(db *bun.DB) WebhookDeliveryRepository {
+	return WebhookDeliveryRepository{db}
+}
+
+// GetPage respond with a page of deliveries to the subscription.
+// It returns ErrNotFound if the subscription does not exist.
+func (repo WebhookDeliveryRepository) GetPage(
+	ctx context.Context,
+	subscriptionId uuid.UUID,
+	status entities.DeliveryStatus,
+	p entities.Pageable,
+) (entities.Page[entities.WebhookDelivery], error) {
+	var deliveries []entities.WebhookDelivery
+	q := conn(ctx, repo.bunDb).NewSelect().Model(&deliveries).Where("subscription_id = ?", subscriptionId)
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+
+	var page entities.Page[entities.WebhookDelivery]
+	var err error
+	if p.Keyset {
+		page, err = keysetPage(ctx, q, &deliveries, entities.WebhookDeliverySortable, p)
+	} else {
+		page, err = offsetPage(ctx, q, &deliveries, entities.WebhookDeliverySortable, p)
+	}
+	if err != nil || len(page.Elements) > 0 {
+		return page, err
+	}
+
+	exists, err := conn(ctx, repo.bunDb).NewSelect().Model((*entities.WebhookSubscription)(nil)).Where("id = ?", subscriptionId).Exists(ctx)
+	if err != nil {
+		return page, dbError(err)
+	}
+	if !exists {
+		return page, ErrNotFound
+	}
+	return page, nil
+}
+
+// Create stores the deliveries. Deliveries of an event to a subscription which exist already are skipped,
+// so relaying the same event again does not deliver it twice.
+func (repo WebhookDeliveryRepository) Create(ctx context.Context, deliveries []*entities.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	_, err := conn(ctx, repo.bunDb).NewInsert().
+		Model(&deliveries).
+		On("CONFLICT (subscription_id, event_id) DO NOTHING").
+		// skipped rows return nothing, so returned columns could not be matched with the deliveries
+		Returning("NULL").
+		Exec(ctx)
+	return dbError(err)
+}
+
+// Deliver locks the due deliveries, deliveries locked by a concurrent worker are skipped,
+// so several instances of the application can deliver webhooks.
+func (repo WebhookDeliveryRepository) Deliver(
+	ctx context.Context,
+	limit int,
+	deliver func(ctx context.Context, subscription entities.WebhookSubscription, delivery *entities.WebhookDelivery) error,
+) (int, error) {
+	attempted := 0
+	err := runInTx(ctx, repo.bunDb, func(ctx context.Context, tx bun.Tx) error {
+		var deliveries []entities.WebhookDelivery
+		err := tx.NewSelect().
+			Model(&deliveries).
+			Where("status = ?", entities.DELIVERY_PENDING).
+			Where("next_attempt_at <= ?", time.Now()).
+			// deliveries to disabled subscriptions wait until the subscription is enabled again
+			Where("EXISTS (?)", tx.NewSelect().
+				Model((*entities.WebhookSubscription)(nil)).
+				Where("?TableAlias.id = wd.subscription_id").
+				Where("?TableAlias.disabled = FALSE")).
+			Order("next_attempt_at ASC").
+			Limit(limit).
+			For("UPDATE SKIP LOCKED").
+			Scan(ctx)
+		if err != nil {
+			return err
+		}
+
+		for i := range deliveries {
+			if err := repo.attempt(ctx, tx, &deliveries[i], deliver); err != nil {
+				return err
+			}
+			attempted++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, dbError(err)
+	}
+	return attempted, nil
+}
+
+// Update locks the delivery and persists the outcome the update func recorded on it.
+// It returns ErrNotFound if the delivery does not exist.
+func (repo WebhookDeliveryRepository) Update(
+	ctx context.Context,
+	id uuid.UUID,
+	update func(ctx context.Context, subscription entities.WebhookSubscription, delivery *entities.WebhookDelivery) error,
+) (entities.WebhookDelivery, error) {
+	delivery := new(entities.WebhookDelivery)
+	err := runInTx(ctx, repo.bunDb, func(ctx context.Context, tx bun.Tx) error {
+		err := tx.NewSelect().Model(delivery).Where("id = ?", id).For("UPDATE").Scan(ctx)
+		if err != nil {
+			return err
+		}
+		return repo.attempt(ctx, tx, delivery, update)
+	})
+	if err != nil {
+		return entities.WebhookDelivery{}, dbError(err)
+	}
+	return *delivery, nil
+}
+
+// attempt hands the delivery together with its subscription to the func and persists the recorded outcome.
+func (repo WebhookDeliveryRepository) attempt(
+	ctx context.Context,
+	tx bun.Tx,
+	delivery *entities.WebhookDelivery,
+	fn func(ctx context.Context, subscription entities.WebhookSubscription, delivery *entities.WebhookDelivery) error,
+) error {
+	subscription := new(entities.WebhookSubscription)
+	err := tx.NewSelect().Model(subscription).Where("id = ?", delivery.SubscriptionID).Scan(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(ctx, *subscription, delivery); err != nil {
+		return err
+	}
+
+	_, err = tx.NewUpdate().
+		Model(delivery).
+		Column("updated_at", "version", "status", "attempts", "next_attempt_at", "last_status_code", "last_error", "delivered_at").
+		WherePK().
+		Apply(incrementVersion).
+		Exec(ctx)
+	return err
+}
@@ -0,0 +1,200 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/google/uuid"
+)
+
+func (s *RepositoryTestSuite) TestWebhooks() {
+	repo := NewWebhookSubscriptionRepository(s.testDb.BunDb)
+	deliveries := NewWebhookDeliveryRepository(s.testDb.BunDb)
+
+	subscribe := func(disabled bool, eventTypes ...entities.EventType) entities.WebhookSubscription {
+		subscription := &entities.WebhookSubscription{
+			Entity:     entities.Entity{ID: uuid.New(), CreatedAt: time.Now(), UpdatedAt: time.Now()},
+			URL:        "https://partner.example.com/hooks",
+			EventTypes: eventTypes,
+			Secret:     "whsec_test",
+			Disabled:   disabled,
+		}
+		s.Require().Nil(repo.Create(s.testDb.Ctx, subscription))
+		return *subscription
+	}
+	newEvent := func(eventType entities.EventType) entities.Event {
+		event, err := entities.NewEvent(eventType, uuid.New(), map[string]string{"status": "pending"})
+		s.Require().Nil(err)
+		return *event
+	}
+	newDelivery := func(subscription entities.WebhookSubscription, event entities.Event) *entities.WebhookDelivery {
+		delivery, err := entities.NewWebhookDelivery(subscription, event)
+		s.Require().Nil(err)
+		return delivery
+	}
+	// deliverAll attempts every due delivery, recording the outcome returned by the status func.
+	deliverAll := func(status func(entities.WebhookDelivery) int) map[uuid.UUID]entities.WebhookDelivery {
+		attempted := make(map[uuid.UUID]entities.WebhookDelivery)
+		_, err := deliveries.Deliver(s.testDb.Ctx, 100, func(_ context.Context, subscription entities.WebhookSubscription, delivery *entities.WebhookDelivery) error {
+			s.Equal(delivery.SubscriptionID, subscription.ID)
+			if code := status(*delivery); code < 300 {
+				delivery.Succeeded(code, time.Now())
+			} else {
+				delivery.Failed(code, "failed", time.Now())
+			}
+			attempted[delivery.ID] = *delivery
+			return nil
+		})
+		s.Require().Nil(err)
+		return attempted
+	}
+
+	s.Run("should create, update and delete subscription", func() {
+		// given
+		subscription := subscribe(false, entities.EVENT_ORDER_PLACED)
+
+		// when
+		subscription.EventTypes = []entities.EventType{entities.EVENT_ORDER_PLACED, entities.EVENT_ORDER_STATUS_CHANGED}
+		subscription.Disabled = true
+		err := repo.Update(s.testDb.Ctx, &subscription)
+
+		// then
+		s.Require().Nil(err)
+		found, err := repo.GetById(s.testDb.Ctx, subscription.ID)
+		s.Nil(err)
+		s.Equal(subscription.EventTypes, found.EventTypes)
+		s.True(found.Disabled)
+		s.Equal(subscription.Secret, found.Secret)
+
+		// when
+		err = repo.DeleteById(s.testDb.Ctx, subscription.ID, 0)
+		// then
+		s.Nil(err)
+		_, err = repo.GetById(s.testDb.Ctx, subscription.ID)
+		s.ErrorIs(err, ErrNotFound)
+		s.ErrorIs(repo.DeleteById(s.testDb.Ctx, subscription.ID, 0), ErrNotFound)
+		s.ErrorIs(repo.Update(s.testDb.Ctx, &subscription), ErrNotFound)
+	})
+
+	s.Run("should return enabled subscriptions to event type", func() {
+		// given
+		eventType := entities.EventType("test." + uuid.NewString())
+		subscribed := subscribe(false, entities.EVENT_ORDER_PLACED, eventType)
+		subscribe(true, eventType)
+		subscribe(false, entities.EVENT_ORDER_PLACED)
+
+		// when
+		subscriptions, err := repo.GetSubscribed(s.testDb.Ctx, eventType)
+
+		// then
+		s.Nil(err)
+		s.Len(subscriptions, 1)
+		s.Equal(subscribed.ID, subscriptions[0].ID)
+	})
+
+	s.Run("should create delivery of event to subscription once", func() {
+		// given
+		subscription := subscribe(false, entities.EVENT_ORDER_PLACED)
+		event := newEvent(entities.EVENT_ORDER_PLACED)
+		s.Require().Nil(deliveries.Create(s.testDb.Ctx, []*entities.WebhookDelivery{newDelivery(subscription, event)}))
+
+		// when the event is relayed again
+		err := deliveries.Create(s.testDb.Ctx, []*entities.WebhookDelivery{
+			newDelivery(subscription, event),
+			newDelivery(subscription, newEvent(entities.EVENT_ORDER_PLACED)),
+		})
+
+		// then
+		s.Nil(err)
+		page, err := deliveries.GetPage(s.testDb.Ctx, subscription.ID, "", entities.Pageable{Size: 10})
+		s.Nil(err)
+		s.Equal(2, *page.TotalElements)
+	})
+
+	s.Run("should deliver due deliveries to enabled subscriptions and log the outcome", func() {
+		// given
+		enabled := subscribe(false, entities.EVENT_ORDER_PLACED)
+		disabled := subscribe(true, entities.EVENT_ORDER_PLACED)
+		delivered := newDelivery(enabled, newEvent(entities.EVENT_ORDER_PLACED))
+		failed := newDelivery(enabled, newEvent(entities.EVENT_ORDER_PLACED))
+		waiting := newDelivery(disabled, newEvent(entities.EVENT_ORDER_PLACED))
+		notDue := newDelivery(enabled, newEvent(entities.EVENT_ORDER_PLACED))
+		notDue.NextAttemptAt = time.Now().Add(time.Hour)
+		s.Require().Nil(deliveries.Create(s.testDb.Ctx, []*entities.WebhookDelivery{delivered, failed, waiting, notDue}))
+
+		// when
+		attempted := deliverAll(func(delivery entities.WebhookDelivery) int {
+			if delivery.ID == failed.ID {
+				return 503
+			}
+			return 200
+		})
+
+		// then
+		s.Contains(attempted, delivered.ID)
+		s.Contains(attempted, failed.ID)
+		s.NotContains(attempted, waiting.ID)
+		s.NotContains(attempted, notDue.ID)
+
+		page, err := deliveries.GetPage(s.testDb.Ctx, enabled.ID, entities.DELIVERY_DELIVERED, entities.Pageable{Size: 10})
+		s.Nil(err)
+		s.Require().Len(page.Elements, 1)
+		s.Equal(delivered.ID, page.Elements[0].ID)
+		s.Equal(200, page.Elements[0].LastStatusCode)
+		s.False(page.Elements[0].DeliveredAt.IsZero())
+
+		page, err = deliveries.GetPage(s.testDb.Ctx, enabled.ID, entities.DELIVERY_PENDING, entities.Pageable{Size: 10})
+		s.Nil(err)
+		s.Len(page.Elements, 2)
+		for _, delivery := range page.Elements {
+			if delivery.ID == failed.ID {
+				s.Equal(1, delivery.Attempts)
+				s.Equal(503, delivery.LastStatusCode)
+				s.True(delivery.NextAttemptAt.After(time.Now()))
+			}
+		}
+
+		// when delivering again, the failed delivery is not due yet
+		attempted = deliverAll(func(entities.WebhookDelivery) int { return 200 })
+		// then
+		s.NotContains(attempted, failed.ID)
+	})
+
+	s.Run("should redeliver dead delivery", func() {
+		// given
+		subscription := subscribe(false, entities.EVENT_ORDER_PLACED)
+		delivery := newDelivery(subscription, newEvent(entities.EVENT_ORDER_PLACED))
+		delivery.Status = entities.DELIVERY_DEAD
+		delivery.Attempts = entities.WebhookMaxAttempts
+		s.Require().Nil(deliveries.Create(s.testDb.Ctx, []*entities.WebhookDelivery{delivery}))
+
+		// when
+		updated, err := deliveries.Update(s.testDb.Ctx, delivery.ID, func(_ context.Context, _ entities.WebhookSubscription, delivery *entities.WebhookDelivery) error {
+			delivery.Redeliver(time.Now())
+			delivery.Succeeded(204, time.Now())
+			return nil
+		})
+
+		// then
+		s.Nil(err)
+		s.Equal(entities.DELIVERY_DELIVERED, updated.Status)
+		page, err := deliveries.GetPage(s.testDb.Ctx, subscription.ID, entities.DELIVERY_DEAD, entities.Pageable{Size: 10})
+		s.Nil(err)
+		s.Empty(page.Elements)
+	})
+
+	s.Run("given unknown subscription or delivery should return not found error", func() {
+		// when
+		_, err := deliveries.GetPage(s.testDb.Ctx, uuid.New(), "", entities.Pageable{Size: 10})
+		// then
+		s.ErrorIs(err, ErrNotFound)
+
+		// when
+		_, err = deliveries.Update(s.testDb.Ctx, uuid.New(), func(context.Context, entities.WebhookSubscription, *entities.WebhookDelivery) error {
+			return nil
+		})
+		// then
+		s.ErrorIs(err, ErrNotFound)
+	})
+}
@@ -1,18 +1,36 @@
 package server
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/fmiskovic/new-amz/internal/utils"
 )
 
+// Storages the repositories of accounts, items and orders can keep their entities in.
+const (
+	StoragePostgres = "postgres"
+	StorageMemory   = "memory"
+)
+
 // Config represents the server configuration.
 type Config struct {
-	addr            string        // addr is the server address.
-	readTimeout     time.Duration // readTimeout is the maximum duration (seconds) for reading the entire request.
-	writeTimeout    time.Duration // writeTimeout is the maximum duration (seconds) before timing out writes of the response.
-	shutdownTimeout time.Duration // shutdownTimeout is the maximum duration (seconds) before timing out server shutdown.
-	secret          string        // secret is being used to encrypt session store.
+	addr             string        // addr is the server address.
+	readTimeout      time.Duration // readTimeout is the maximum duration (seconds) for reading the entire request.
+	writeTimeout     time.Duration // writeTimeout is the maximum duration (seconds) before timing out writes of the response.
+	shutdownTimeout  time.Duration // shutdownTimeout is the maximum duration (seconds) before timing out server shutdown.
+	secret           string        // secret is being used to sign JWT access tokens.
+	tokenTTL         time.Duration // tokenTTL is the duration (minutes) for which an issued access token is valid.
+	similarity       float64       // similarity is the minimal trigram similarity (0-1) of titles found by fuzzy search.
+	idempotencyTTL   time.Duration // idempotencyTTL is the duration (hours) for which responses to idempotency keys are replayed.
+	webhookSecret    string        // webhookSecret is being used to verify signatures of payment webhooks.
+	eventPublisher   string        // eventPublisher is where domain events are published to: stdout, file or webhook.
+	eventTarget      string        // eventTarget is the file path or the URL events are published to.
+	relayInterval    time.Duration // relayInterval is the duration (seconds) between checks of the outbox for unpublished events.
+	deliveryInterval time.Duration // deliveryInterval is the duration (seconds) between checks for due webhook deliveries.
+	storage          string        // storage is where entities are kept: postgres, or memory for demos without a database.
+	adminEmail       string        // adminEmail is the email of the admin account created in memory storage.
+	adminPassword    string        // adminPassword is the password of the admin account created in memory storage, none is created without it.
 }
 
 // ConfigBuilder is a builder for creating Config instances.
@@ -53,6 +71,63 @@ func (b *ConfigBuilder) WithSecret(secret string) *ConfigBuilder {
 	return b
 }
 
+// WithTokenTTL sets the duration for which an issued access token is valid.
+func (b *ConfigBuilder) WithTokenTTL(ttl time.Duration) *ConfigBuilder {
+	b.config.tokenTTL = ttl
+	return b
+}
+
+// WithSimilarityThreshold sets the minimal similarity of titles found by fuzzy search.
+func (b *ConfigBuilder) WithSimilarityThreshold(threshold float64) *ConfigBuilder {
+	b.config.similarity = threshold
+	return b
+}
+
+// WithIdempotencyTTL sets the duration for which responses to idempotency keys are replayed.
+func (b *ConfigBuilder) WithIdempotencyTTL(ttl time.Duration) *ConfigBuilder {
+	b.config.idempotencyTTL = ttl
+	return b
+}
+
+// WithWebhookSecret sets the secret used to verify signatures of payment webhooks.
+func (b *ConfigBuilder) WithWebhookSecret(secret string) *ConfigBuilder {
+	b.config.webhookSecret = secret
+	return b
+}
+
+// WithEventPublisher sets where domain events are published to, the target is the file path or the URL.
+func (b *ConfigBuilder) WithEventPublisher(publisher string, target string) *ConfigBuilder {
+	b.config.eventPublisher = publisher
+	b.config.eventTarget = target
+	return b
+}
+
+// WithRelayInterval sets the duration between checks of the outbox for unpublished events.
+func (b *ConfigBuilder) WithRelayInterval(interval time.Duration) *ConfigBuilder {
+	b.config.relayInterval = interval
+	return b
+}
+
+// WithDeliveryInterval sets the duration between checks for due webhook deliveries.
+func (b *ConfigBuilder) WithDeliveryInterval(interval time.Duration) *ConfigBuilder {
+	b.config.deliveryInterval = interval
+	return b
+}
+
+// WithStorage sets where entities are kept, StoragePostgres or StorageMemory.
+func (b *ConfigBuilder) WithStorage(storage string) *ConfigBuilder {
+	b.config.storage = storage
+	return b
+}
+
+// WithMemoryAdmin sets the credentials of the admin account created in memory storage,
+// where the admin role can not be granted in the database.
+func (b *ConfigBuilder) WithMemoryAdmin(email string, password string) *ConfigBuilder {
+	b.config.adminEmail = email
+	b.config.adminPassword = password
+	return b
+}
+
 // Build creates a new Config instance based on the builder's configuration.
 // If any configuration values are not set, default values will be used.
 func (b *ConfigBuilder) Build() Config {
@@ -73,15 +148,87 @@ func (b *ConfigBuilder) Build() Config {
 	}
 
 	if b.config.secret == "" {
-		b.config.secret = utils.GetOrDefault("AUTH_JWT_SECRET", "changeme")
+		b.config.secret = utils.GetOrDefault("AUTH_JWT_SECRET", "")
+	}
+	if b.config.tokenTTL == 0 {
+		ttl := utils.GetOrDefaultInt("AUTH_JWT_TTL", 60)
+		b.config.tokenTTL = time.Duration(ttl) * time.Minute
+	}
+	if b.config.similarity == 0 {
+		b.config.similarity = utils.GetOrDefaultFloat("SEARCH_SIMILARITY_THRESHOLD", 0.3)
+	}
+	if b.config.idempotencyTTL == 0 {
+		ttl := utils.GetOrDefaultInt("IDEMPOTENCY_KEY_TTL", 24)
+		b.config.idempotencyTTL = time.Duration(ttl) * time.Hour
+	}
+	if b.config.webhookSecret == "" {
+		b.config.webhookSecret = utils.GetOrDefault("PAYMENT_WEBHOOK_SECRET", "")
+	}
+	if b.config.eventPublisher == "" {
+		b.config.eventPublisher = utils.GetOrDefault("EVENT_PUBLISHER", "stdout")
+		b.config.eventTarget = utils.GetOrDefault("EVENT_PUBLISHER_TARGET", "")
+	}
+	if b.config.relayInterval == 0 {
+		interval := utils.GetOrDefaultInt("EVENT_RELAY_INTERVAL", 5)
+		b.config.relayInterval = time.Duration(interval) * time.Second
+	}
+	if b.config.deliveryInterval == 0 {
+		interval := utils.GetOrDefaultInt("WEBHOOK_DELIVERY_INTERVAL", 5)
+		b.config.deliveryInterval = time.Duration(interval) * time.Second
+	}
+	if b.config.storage == "" {
+		b.config.storage = utils.GetOrDefault("STORAGE", StoragePostgres)
+	}
+	if b.config.adminPassword == "" {
+		b.config.adminEmail = utils.GetOrDefault("MEMORY_ADMIN_EMAIL", "admin@example.com")
+		b.config.adminPassword = utils.GetOrDefault("MEMORY_ADMIN_PASSWORD", "")
 	}
 	return *b.config
 }
 
+// Validate checks that the configuration is safe to serve with. Secrets have no defaults, as anyone knowing
+// the JWT secret can issue admin tokens and anyone knowing the payment webhook secret can mark orders paid,
+// so they must be set and long enough not to be guessed. Payments are not served with the memory storage,
+// which does not need the payment webhook secret then.
+func (c Config) Validate() error {
+	if err := validateSecret("AUTH_JWT_SECRET", c.secret); err != nil {
+		return err
+	}
+	if c.storage == StorageMemory {
+		return nil
+	}
+	return validateSecret("PAYMENT_WEBHOOK_SECRET", c.webhookSecret)
+}
+
+// minSecretLength is the minimal length of secrets, e.g. 32 characters of `openssl rand -hex 32`.
+const minSecretLength = 32
+
+func validateSecret(name string, secret string) error {
+	switch {
+	case secret == "":
+		return fmt.Errorf("%s is not set", name)
+	case len(secret) < minSecretLength:
+		return fmt.Errorf("%s must be at least %d characters long", name, minSecretLength)
+	default:
+		return nil
+	}
+}
+
 func (c *Config) IsZero() bool {
 	return c.addr == "" &&
 		c.readTimeout == time.Duration(0) &&
 		c.writeTimeout == time.Duration(0) &&
 		c.shutdownTimeout == time.Duration(0) &&
-		c.secret == ""
+		c.secret == "" &&
+		c.tokenTTL == time.Duration(0) &&
+		c.similarity == 0 &&
+		c.idempotencyTTL == time.Duration(0) &&
+		c.webhookSecret == "" &&
+		c.eventPublisher == "" &&
+		c.eventTarget == "" &&
+		c.relayInterval == time.Duration(0) &&
+		c.deliveryInterval == time.Duration(0) &&
+		c.storage == "" &&
+		c.adminEmail == "" &&
+		c.adminPassword == ""
 }
@@ -0,0 +1,35 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigValidate(t *testing.T) {
+	secret := strings.Repeat("s", minSecretLength)
+
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr string
+	}{
+		{name: "valid config", config: NewConfig().WithSecret(secret).WithWebhookSecret(secret).Build()},
+		{name: "missing jwt secret", config: Config{webhookSecret: secret}, wantErr: "AUTH_JWT_SECRET is not set"},
+		{name: "short jwt secret", config: Config{secret: "changeme", webhookSecret: secret}, wantErr: "AUTH_JWT_SECRET must be at least 32 characters long"},
+		{name: "missing webhook secret", config: Config{secret: secret}, wantErr: "PAYMENT_WEBHOOK_SECRET is not set"},
+		{name: "short webhook secret", config: Config{secret: secret, webhookSecret: "changeme"}, wantErr: "PAYMENT_WEBHOOK_SECRET must be at least 32 characters long"},
+		{name: "memory storage without webhook secret", config: Config{secret: secret, storage: StorageMemory}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantErr == "" {
+				assert.Nil(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
@@ -1,42 +1,123 @@
 package server
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/fmiskovic/new-amz/internal/auth"
+	"github.com/fmiskovic/new-amz/internal/core"
 	"github.com/fmiskovic/new-amz/internal/core/dtos"
 	"github.com/fmiskovic/new-amz/internal/core/entities"
+	ports "github.com/fmiskovic/new-amz/internal/core/repositories"
 	"github.com/fmiskovic/new-amz/internal/core/services"
 	"github.com/fmiskovic/new-amz/internal/db"
+	"github.com/fmiskovic/new-amz/internal/events"
 	"github.com/fmiskovic/new-amz/internal/handlers"
 	"github.com/fmiskovic/new-amz/internal/handlers/mappers"
+	"github.com/fmiskovic/new-amz/internal/idempotency"
+	"github.com/fmiskovic/new-amz/internal/payments"
 	"github.com/fmiskovic/new-amz/internal/repositories"
+	"github.com/fmiskovic/new-amz/internal/repositories/memory"
+	"github.com/fmiskovic/new-amz/internal/webhooks"
 	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+	"golang.org/x/crypto/bcrypt"
 )
 
 // This struct is used to wire dependencies with server.
 // It is created by the bootstrap function.
 // Add any new dependencies here.
 type dependencies struct {
+	storage           string
+	jwt               auth.JWT
+	idempotencyKeys   repositories.IdempotencyRepository
+	idempotencyPurger *idempotency.Purger
+	webhookSecret     []byte
+	relay             *events.Relay
+	webhookWorker     *webhooks.Worker
+
 	// handlers
-	createAccountHandler       handlers.Handler[dtos.CreateAccountCommand, dtos.CreateAccountAnswer]
-	getAccountByIdHandler      handlers.Handler[uuid.UUID, dtos.AccountDto]
-	getItemByIdHandler         handlers.Handler[uuid.UUID, dtos.ItemDto]
-	getItemsPageHandler        handlers.Handler[entities.Pageable, entities.Page[dtos.ItemDto]]
-	createOrderHandler         handlers.Handler[dtos.CreateOrderCommand, dtos.CreateOrderAnswer]
-	getOrderByIdHandler        handlers.Handler[uuid.UUID, dtos.OrderDto]
-	searchAccountOrdersHandler handlers.Handler[dtos.OrderFilter, entities.Page[dtos.OrderDto]]
+	loginHandler                 handlers.Handler[dtos.LoginCommand, dtos.TokenDto]
+	createAccountHandler         handlers.Handler[dtos.CreateAccountCommand, dtos.CreateAccountAnswer]
+	getAccountByIdHandler        handlers.Handler[uuid.UUID, dtos.AccountDto]
+	updateAccountHandler         handlers.Handler[dtos.UpdateAccountCommand, dtos.AccountDto]
+	patchAccountHandler          handlers.Handler[dtos.PatchAccountCommand, dtos.AccountDto]
+	deleteAccountHandler         handlers.Handler[dtos.DeleteAccountCommand, struct{}]
+	getItemByIdHandler           handlers.Handler[uuid.UUID, dtos.ItemDto]
+	getItemByIsbnHandler         handlers.Handler[dtos.ItemIsbnQuery, dtos.ItemDto]
+	getItemsPageHandler          handlers.Handler[dtos.ItemFilter, entities.Page[dtos.ItemDto]]
+	searchItemsHandler           handlers.Handler[dtos.ItemSearchQuery, entities.Page[dtos.ItemMatchDto]]
+	suggestItemsHandler          handlers.Handler[dtos.ItemSuggestQuery, dtos.ItemSuggestionsDto]
+	createItemHandler            handlers.Handler[dtos.ItemDto, dtos.ItemDto]
+	updateItemHandler            handlers.Handler[dtos.ItemDto, dtos.ItemDto]
+	deleteItemHandler            handlers.Handler[dtos.DeleteCommand, struct{}]
+	getItemCategoriesHandler     handlers.Handler[uuid.UUID, []dtos.CategoryDto]
+	assignItemCategoriesHandler  handlers.Handler[dtos.AssignItemCategoriesCommand, []dtos.CategoryDto]
+	getTopLevelCategoriesHandler handlers.Handler[struct{}, []dtos.CategoryDto]
+	getCategorySubtreeHandler    handlers.Handler[uuid.UUID, dtos.CategoryDto]
+	createCategoryHandler        handlers.Handler[dtos.CategoryDto, dtos.CategoryDto]
+	updateCategoryHandler        handlers.Handler[dtos.CategoryDto, dtos.CategoryDto]
+	deleteCategoryHandler        handlers.Handler[dtos.DeleteCommand, struct{}]
+	getAuthorByIdHandler         handlers.Handler[uuid.UUID, dtos.AuthorDto]
+	getAuthorsPageHandler        handlers.Handler[entities.Pageable, entities.Page[dtos.AuthorDto]]
+	getAuthorItemsHandler        handlers.Handler[dtos.AuthorItemsQuery, entities.Page[dtos.ItemDto]]
+	createAuthorHandler          handlers.Handler[dtos.AuthorDto, dtos.AuthorDto]
+	updateAuthorHandler          handlers.Handler[dtos.AuthorDto, dtos.AuthorDto]
+	deleteAuthorHandler          handlers.Handler[dtos.DeleteCommand, struct{}]
+	getPublisherByIdHandler      handlers.Handler[uuid.UUID, dtos.PublisherDto]
+	getPublishersPageHandler     handlers.Handler[entities.Pageable, entities.Page[dtos.PublisherDto]]
+	createPublisherHandler       handlers.Handler[dtos.PublisherDto, dtos.PublisherDto]
+	updatePublisherHandler       handlers.Handler[dtos.PublisherDto, dtos.PublisherDto]
+	deletePublisherHandler       handlers.Handler[dtos.DeleteCommand, struct{}]
+	createOrderHandler           handlers.Handler[dtos.CreateOrderCommand, dtos.CreateOrderAnswer]
+	getOrderByIdHandler          handlers.Handler[uuid.UUID, dtos.OrderDto]
+	searchAccountOrdersHandler   handlers.Handler[dtos.OrderFilter, entities.Page[dtos.OrderDto]]
+	transitionOrderHandler       handlers.Handler[dtos.TransitionOrderCommand, dtos.OrderTransitionDto]
+	getOrderTransitionsHandler   handlers.Handler[uuid.UUID, []dtos.OrderTransitionDto]
+	cancelOrderHandler           handlers.Handler[dtos.CancelOrderCommand, dtos.OrderDto]
+	refundOrderHandler           handlers.Handler[dtos.RefundOrderCommand, dtos.RefundDto]
+	getOrderRefundsHandler       handlers.Handler[uuid.UUID, []dtos.RefundDto]
+	getCartHandler               handlers.Handler[dtos.CartQuery, dtos.CartDto]
+	createGuestCartHandler       handlers.Handler[struct{}, dtos.CartDto]
+	addCartItemHandler           handlers.Handler[dtos.CartItemCommand, dtos.CartDto]
+	setCartItemHandler           handlers.Handler[dtos.CartItemCommand, dtos.CartDto]
+	removeCartItemHandler        handlers.Handler[dtos.RemoveCartItemCommand, dtos.CartDto]
+	clearCartHandler             handlers.Handler[dtos.CartQuery, struct{}]
+	mergeCartHandler             handlers.Handler[dtos.MergeCartCommand, dtos.CartDto]
+	checkoutCartHandler          handlers.Handler[dtos.CartQuery, dtos.CreateOrderAnswer]
+	authorizePaymentHandler      handlers.Handler[dtos.AuthorizePaymentCommand, dtos.PaymentDto]
+	getOrderPaymentsHandler      handlers.Handler[uuid.UUID, []dtos.PaymentDto]
+	capturePaymentHandler        handlers.Handler[dtos.PaymentAmountCommand, dtos.PaymentDto]
+	voidPaymentHandler           handlers.Handler[uuid.UUID, dtos.PaymentDto]
+	refundPaymentHandler         handlers.Handler[dtos.PaymentAmountCommand, dtos.PaymentDto]
+	paymentWebhookHandler        handlers.Handler[dtos.PaymentEventCommand, dtos.PaymentDto]
+	getWebhooksPageHandler       handlers.Handler[entities.Pageable, entities.Page[dtos.WebhookSubscriptionDto]]
+	getWebhookByIdHandler        handlers.Handler[uuid.UUID, dtos.WebhookSubscriptionDto]
+	createWebhookHandler         handlers.Handler[dtos.WebhookSubscriptionDto, dtos.WebhookSubscriptionDto]
+	updateWebhookHandler         handlers.Handler[dtos.WebhookSubscriptionDto, dtos.WebhookSubscriptionDto]
+	deleteWebhookHandler         handlers.Handler[dtos.DeleteCommand, struct{}]
+	getWebhookDeliveriesHandler  handlers.Handler[dtos.WebhookDeliveriesQuery, entities.Page[dtos.WebhookDeliveryDto]]
+	redeliverWebhookHandler      handlers.Handler[dtos.RedeliverWebhookCommand, dtos.WebhookDeliveryDto]
 }
 
 // bootstrap creates and wires up all dependencies.
-func bootstrap() dependencies {
-	dbSvc := db.NewService()
-	sqlDb, err := dbSvc.Connect()
+func bootstrap(cfg Config) dependencies {
+	store, bunDb, err := newStorage(cfg)
 	if err != nil {
 		panic(err)
 	}
-	bunDb := dbSvc.WrapWithBun(sqlDb)
+
+	// Accounts and orders of new accounts are created in one unit of work
+	uow := services.NewUnitOfWork(store.txManager)
+	orderRepository := store.orders
+	orderService := services.NewOrderService(orderRepository, uow)
 
 	// Account
-	accountRepository := repositories.NewAccountRepository(bunDb)
-	accountService := services.NewAccountService(accountRepository)
+	accountRepository := store.accounts
+	accountService := services.NewAccountService(accountRepository, uow, orderService.Create)
 	createAccountHandler := handlers.New(
 		mappers.NewCreateAccountRequestMapper(),
 		mappers.NewCreateAccountResponseMapper(),
@@ -47,24 +128,76 @@ func bootstrap() dependencies {
 		mappers.NewGetAccountByIdResponseMapper(),
 		accountService.GetById,
 	)
+	updateAccountHandler := handlers.New(
+		mappers.NewUpdateAccountRequestMapper(),
+		mappers.NewGetAccountByIdResponseMapper(),
+		accountService.Update,
+	)
+	patchAccountHandler := handlers.New(
+		mappers.NewPatchAccountRequestMapper(),
+		mappers.NewGetAccountByIdResponseMapper(),
+		accountService.Patch,
+	)
+	deleteAccountHandler := handlers.New(
+		mappers.NewDeleteAccountRequestMapper(),
+		mappers.NewDeleteAccountResponseMapper(),
+		accountService.Delete,
+	)
+
+	// Auth
+	jwt := auth.NewJWT(cfg.secret, cfg.tokenTTL)
+	authService := services.NewAuthService(accountRepository, jwt)
+	loginHandler := handlers.New(
+		mappers.NewAuthLoginRequestMapper(),
+		mappers.NewAuthLoginResponseMapper(),
+		authService.Login,
+	)
 
 	// Item
-	itemRepository := repositories.NewItemRepository(bunDb)
+	itemRepository := store.items
 	itemService := services.NewItemService(itemRepository)
 	getItemByIdHandler := handlers.New(
 		mappers.NewItemGetByIdRequestMapper(),
 		mappers.NewItemGetByIdResponseMapper(),
 		itemService.GetById,
 	)
+	getItemByIsbnHandler := handlers.New(
+		mappers.NewItemGetByISBNRequestMapper(),
+		mappers.NewItemGetByIdResponseMapper(),
+		itemService.GetByISBN,
+	)
 	getItemsPageHandler := handlers.New(
 		mappers.NewItemGetPageRequestMapper(),
 		mappers.NewItemGetPageResponseMapper(),
 		itemService.GetPage,
 	)
+	searchItemsHandler := handlers.New(
+		mappers.NewItemSearchRequestMapper(),
+		mappers.NewItemSearchResponseMapper(),
+		itemService.Search,
+	)
+	suggestItemsHandler := handlers.New(
+		mappers.NewItemSuggestRequestMapper(),
+		mappers.NewItemSuggestResponseMapper(),
+		itemService.Suggest,
+	)
+	createItemHandler := handlers.New(
+		mappers.NewItemCreateRequestMapper(),
+		mappers.NewItemCreateResponseMapper(),
+		itemService.Create,
+	)
+	updateItemHandler := handlers.New(
+		mappers.NewItemUpdateRequestMapper(),
+		mappers.NewItemGetByIdResponseMapper(),
+		itemService.Update,
+	)
+	deleteItemHandler := handlers.New(
+		mappers.NewItemDeleteRequestMapper(),
+		mappers.NewItemDeleteResponseMapper(),
+		itemService.DeleteById,
+	)
 
 	// Order
-	orderRepository := repositories.NewOrderRepository(bunDb)
-	orderService := services.NewOrderService(orderRepository)
 	createOrderHandler := handlers.New(
 		mappers.NewOrderCreateRequestMapper(),
 		mappers.NewOrderCreateResponseMapper(),
@@ -80,14 +213,443 @@ func bootstrap() dependencies {
 		mappers.NewOrderSearchResponseMapper(),
 		orderService.Search,
 	)
+	transitionOrderHandler := handlers.New(
+		mappers.NewOrderTransitionRequestMapper(),
+		mappers.NewOrderTransitionResponseMapper(),
+		orderService.Transition,
+	)
+	getOrderTransitionsHandler := handlers.New(
+		mappers.NewOrderGetByIdRequestMapper(),
+		mappers.NewOrderGetTransitionsResponseMapper(),
+		orderService.GetTransitions,
+	)
+	cancelOrderHandler := handlers.New(
+		mappers.NewOrderCancelRequestMapper(),
+		mappers.NewOrderGetByIdResponseMapper(),
+		orderService.Cancel,
+	)
+	refundOrderHandler := handlers.New(
+		mappers.NewOrderRefundRequestMapper(),
+		mappers.NewOrderRefundResponseMapper(),
+		orderService.Refund,
+	)
+	getOrderRefundsHandler := handlers.New(
+		mappers.NewOrderGetByIdRequestMapper(),
+		mappers.NewOrderGetRefundsResponseMapper(),
+		orderService.GetRefunds,
+	)
 
-	return dependencies{
+	dep := dependencies{
+		storage:                    cfg.storage,
+		jwt:                        jwt,
+		loginHandler:               loginHandler,
 		createAccountHandler:       createAccountHandler,
 		getAccountByIdHandler:      getAccountByIdHandler,
+		updateAccountHandler:       updateAccountHandler,
+		patchAccountHandler:        patchAccountHandler,
+		deleteAccountHandler:       deleteAccountHandler,
 		getItemByIdHandler:         getItemByIdHandler,
+		getItemByIsbnHandler:       getItemByIsbnHandler,
 		getItemsPageHandler:        getItemsPageHandler,
+		searchItemsHandler:         searchItemsHandler,
+		suggestItemsHandler:        suggestItemsHandler,
+		createItemHandler:          createItemHandler,
+		updateItemHandler:          updateItemHandler,
+		deleteItemHandler:          deleteItemHandler,
 		createOrderHandler:         createOrderHandler,
 		getOrderByIdHandler:        getOrderByIdHandler,
 		searchAccountOrdersHandler: searchAccountOrdersHandler,
+		transitionOrderHandler:     transitionOrderHandler,
+		getOrderTransitionsHandler: getOrderTransitionsHandler,
+		cancelOrderHandler:         cancelOrderHandler,
+		refundOrderHandler:         refundOrderHandler,
+		getOrderRefundsHandler:     getOrderRefundsHandler,
+	}
+
+	// bunDb is nil with the memory storage, only accounts, items and orders are served then, see initRoutes
+	if bunDb != nil {
+		dep.wirePostgres(cfg, bunDb, orderRepository, itemService, orderService)
+	}
+	return dep
+}
+
+// wirePostgres creates and wires up the dependencies kept in PostgreSQL only: the catalogue besides items, carts,
+// payments, webhooks, domain events and idempotency keys.
+func (dep *dependencies) wirePostgres(
+	cfg Config,
+	bunDb *bun.DB,
+	orderRepository ports.OrderRepository[uuid.UUID],
+	itemService services.ItemService,
+	orderService services.OrderService,
+) {
+	idempotencyKeys := repositories.NewIdempotencyRepository(bunDb)
+	idempotencyPurger := idempotency.NewPurger(idempotencyKeys, idempotencyPurgeInterval)
+
+	// Webhook
+	webhookService := services.NewWebhookService(
+		repositories.NewWebhookSubscriptionRepository(bunDb),
+		repositories.NewWebhookDeliveryRepository(bunDb),
+		webhooks.NewHTTPSender(10*time.Second),
+	)
+	webhookWorker := webhooks.NewWorker(webhookService.Deliver, cfg.deliveryInterval, webhookBatchSize)
+	getWebhooksPageHandler := handlers.New(
+		mappers.NewWebhookGetPageRequestMapper(),
+		mappers.NewWebhookGetPageResponseMapper(),
+		webhookService.GetPage,
+	)
+	getWebhookByIdHandler := handlers.New(
+		mappers.NewWebhookGetByIdRequestMapper(),
+		mappers.NewWebhookGetByIdResponseMapper(),
+		webhookService.GetById,
+	)
+	createWebhookHandler := handlers.New(
+		mappers.NewWebhookCreateRequestMapper(),
+		mappers.NewWebhookCreateResponseMapper(),
+		webhookService.Create,
+	)
+	updateWebhookHandler := handlers.New(
+		mappers.NewWebhookUpdateRequestMapper(),
+		mappers.NewWebhookGetByIdResponseMapper(),
+		webhookService.Update,
+	)
+	deleteWebhookHandler := handlers.New(
+		mappers.NewWebhookDeleteRequestMapper(),
+		mappers.NewWebhookDeleteResponseMapper(),
+		webhookService.DeleteById,
+	)
+	getWebhookDeliveriesHandler := handlers.New(
+		mappers.NewWebhookDeliveriesRequestMapper(),
+		mappers.NewWebhookDeliveriesResponseMapper(),
+		webhookService.GetDeliveries,
+	)
+	redeliverWebhookHandler := handlers.New(
+		mappers.NewWebhookRedeliverRequestMapper(),
+		mappers.NewWebhookDeliveryResponseMapper(),
+		webhookService.Redeliver,
+	)
+
+	// Events are published to the configured publisher and the subscribed webhooks
+	eventPublisher, err := newEventPublisher(cfg)
+	if err != nil {
+		panic(err)
+	}
+	relay := events.NewRelay(
+		repositories.NewEventRepository(bunDb),
+		events.NewMultiPublisher(eventPublisher, webhookService),
+		cfg.relayInterval,
+		relayBatchSize,
+	)
+
+	// Category
+	categoryRepository := repositories.NewCategoryRepository(bunDb)
+	categoryService := services.NewCategoryService(categoryRepository)
+	getTopLevelCategoriesHandler := handlers.New(
+		mappers.NewCategoryGetTopLevelRequestMapper(),
+		mappers.NewCategoryListResponseMapper(),
+		categoryService.GetTopLevel,
+	)
+	getCategorySubtreeHandler := handlers.New(
+		mappers.NewCategoryGetByIdRequestMapper(),
+		mappers.NewCategoryGetByIdResponseMapper(),
+		categoryService.GetSubtree,
+	)
+	createCategoryHandler := handlers.New(
+		mappers.NewCategoryCreateRequestMapper(),
+		mappers.NewCategoryCreateResponseMapper(),
+		categoryService.Create,
+	)
+	updateCategoryHandler := handlers.New(
+		mappers.NewCategoryUpdateRequestMapper(),
+		mappers.NewCategoryGetByIdResponseMapper(),
+		categoryService.Update,
+	)
+	deleteCategoryHandler := handlers.New(
+		mappers.NewCategoryDeleteRequestMapper(),
+		mappers.NewCategoryDeleteResponseMapper(),
+		categoryService.DeleteById,
+	)
+	getItemCategoriesHandler := handlers.New(
+		mappers.NewItemGetByIdRequestMapper(),
+		mappers.NewCategoryListResponseMapper(),
+		categoryService.GetByItem,
+	)
+	assignItemCategoriesHandler := handlers.New(
+		mappers.NewItemAssignCategoriesRequestMapper(),
+		mappers.NewCategoryListResponseMapper(),
+		categoryService.AssignItem,
+	)
+
+	// Author
+	authorRepository := repositories.NewAuthorRepository(bunDb)
+	authorService := services.NewAuthorService(authorRepository)
+	getAuthorByIdHandler := handlers.New(
+		mappers.NewAuthorGetByIdRequestMapper(),
+		mappers.NewAuthorGetByIdResponseMapper(),
+		authorService.GetById,
+	)
+	getAuthorsPageHandler := handlers.New(
+		mappers.NewAuthorGetPageRequestMapper(),
+		mappers.NewAuthorGetPageResponseMapper(),
+		authorService.GetPage,
+	)
+	getAuthorItemsHandler := handlers.New(
+		mappers.NewAuthorItemsRequestMapper(),
+		mappers.NewItemGetPageResponseMapper(),
+		itemService.GetPageByAuthor,
+	)
+	createAuthorHandler := handlers.New(
+		mappers.NewAuthorCreateRequestMapper(),
+		mappers.NewAuthorCreateResponseMapper(),
+		authorService.Create,
+	)
+	updateAuthorHandler := handlers.New(
+		mappers.NewAuthorUpdateRequestMapper(),
+		mappers.NewAuthorGetByIdResponseMapper(),
+		authorService.Update,
+	)
+	deleteAuthorHandler := handlers.New(
+		mappers.NewAuthorDeleteRequestMapper(),
+		mappers.NewAuthorDeleteResponseMapper(),
+		authorService.DeleteById,
+	)
+
+	// Publisher
+	publisherRepository := repositories.NewPublisherRepository(bunDb)
+	publisherService := services.NewPublisherService(publisherRepository)
+	getPublisherByIdHandler := handlers.New(
+		mappers.NewPublisherGetByIdRequestMapper(),
+		mappers.NewPublisherGetByIdResponseMapper(),
+		publisherService.GetById,
+	)
+	getPublishersPageHandler := handlers.New(
+		mappers.NewPublisherGetPageRequestMapper(),
+		mappers.NewPublisherGetPageResponseMapper(),
+		publisherService.GetPage,
+	)
+	createPublisherHandler := handlers.New(
+		mappers.NewPublisherCreateRequestMapper(),
+		mappers.NewPublisherCreateResponseMapper(),
+		publisherService.Create,
+	)
+	updatePublisherHandler := handlers.New(
+		mappers.NewPublisherUpdateRequestMapper(),
+		mappers.NewPublisherGetByIdResponseMapper(),
+		publisherService.Update,
+	)
+	deletePublisherHandler := handlers.New(
+		mappers.NewPublisherDeleteRequestMapper(),
+		mappers.NewPublisherDeleteResponseMapper(),
+		publisherService.DeleteById,
+	)
+
+	// Cart
+	cartRepository := repositories.NewCartRepository(bunDb)
+	cartService := services.NewCartService(cartRepository, orderService.Create)
+	getCartHandler := handlers.New(
+		mappers.NewCartGetRequestMapper(),
+		mappers.NewCartGetResponseMapper(),
+		cartService.Get,
+	)
+	createGuestCartHandler := handlers.New(
+		mappers.NewCartCreateGuestRequestMapper(),
+		mappers.NewCartCreateGuestResponseMapper(),
+		cartService.CreateGuest,
+	)
+	addCartItemHandler := handlers.New(
+		mappers.NewCartAddItemRequestMapper(),
+		mappers.NewCartGetResponseMapper(),
+		cartService.AddItem,
+	)
+	setCartItemHandler := handlers.New(
+		mappers.NewCartSetItemRequestMapper(),
+		mappers.NewCartGetResponseMapper(),
+		cartService.SetItemQuantity,
+	)
+	removeCartItemHandler := handlers.New(
+		mappers.NewCartRemoveItemRequestMapper(),
+		mappers.NewCartGetResponseMapper(),
+		cartService.RemoveItem,
+	)
+	clearCartHandler := handlers.New(
+		mappers.NewCartGetRequestMapper(),
+		mappers.NewCartClearResponseMapper(),
+		cartService.Clear,
+	)
+	mergeCartHandler := handlers.New(
+		mappers.NewCartMergeRequestMapper(),
+		mappers.NewCartGetResponseMapper(),
+		cartService.Merge,
+	)
+	checkoutCartHandler := handlers.New(
+		mappers.NewCartGetRequestMapper(),
+		mappers.NewOrderCreateResponseMapper(),
+		cartService.Checkout,
+	)
+
+	// Payment
+	paymentRepository := repositories.NewPaymentRepository(bunDb)
+	paymentService := services.NewPaymentService(paymentRepository, orderRepository, payments.NewFakeGateway())
+	authorizePaymentHandler := handlers.New(
+		mappers.NewPaymentAuthorizeRequestMapper(),
+		mappers.NewPaymentAuthorizeResponseMapper(),
+		paymentService.Authorize,
+	)
+	getOrderPaymentsHandler := handlers.New(
+		mappers.NewOrderGetByIdRequestMapper(),
+		mappers.NewPaymentsResponseMapper(),
+		paymentService.GetByOrder,
+	)
+	capturePaymentHandler := handlers.New(
+		mappers.NewPaymentAmountRequestMapper(),
+		mappers.NewPaymentResponseMapper(),
+		paymentService.Capture,
+	)
+	voidPaymentHandler := handlers.New(
+		mappers.NewPaymentGetByIdRequestMapper(),
+		mappers.NewPaymentResponseMapper(),
+		paymentService.Void,
+	)
+	refundPaymentHandler := handlers.New(
+		mappers.NewPaymentAmountRequestMapper(),
+		mappers.NewPaymentResponseMapper(),
+		paymentService.Refund,
+	)
+	paymentWebhookHandler := handlers.New(
+		mappers.NewPaymentEventRequestMapper(),
+		mappers.NewPaymentResponseMapper(),
+		paymentService.HandleEvent,
+	)
+
+	dep.idempotencyKeys = idempotencyKeys
+	dep.idempotencyPurger = idempotencyPurger
+	dep.webhookSecret = []byte(cfg.webhookSecret)
+	dep.relay = relay
+	dep.webhookWorker = webhookWorker
+	dep.getItemCategoriesHandler = getItemCategoriesHandler
+	dep.assignItemCategoriesHandler = assignItemCategoriesHandler
+	dep.getTopLevelCategoriesHandler = getTopLevelCategoriesHandler
+	dep.getCategorySubtreeHandler = getCategorySubtreeHandler
+	dep.createCategoryHandler = createCategoryHandler
+	dep.updateCategoryHandler = updateCategoryHandler
+	dep.deleteCategoryHandler = deleteCategoryHandler
+	dep.getAuthorByIdHandler = getAuthorByIdHandler
+	dep.getAuthorsPageHandler = getAuthorsPageHandler
+	dep.getAuthorItemsHandler = getAuthorItemsHandler
+	dep.createAuthorHandler = createAuthorHandler
+	dep.updateAuthorHandler = updateAuthorHandler
+	dep.deleteAuthorHandler = deleteAuthorHandler
+	dep.getPublisherByIdHandler = getPublisherByIdHandler
+	dep.getPublishersPageHandler = getPublishersPageHandler
+	dep.createPublisherHandler = createPublisherHandler
+	dep.updatePublisherHandler = updatePublisherHandler
+	dep.deletePublisherHandler = deletePublisherHandler
+	dep.getCartHandler = getCartHandler
+	dep.createGuestCartHandler = createGuestCartHandler
+	dep.addCartItemHandler = addCartItemHandler
+	dep.setCartItemHandler = setCartItemHandler
+	dep.removeCartItemHandler = removeCartItemHandler
+	dep.clearCartHandler = clearCartHandler
+	dep.mergeCartHandler = mergeCartHandler
+	dep.checkoutCartHandler = checkoutCartHandler
+	dep.authorizePaymentHandler = authorizePaymentHandler
+	dep.getOrderPaymentsHandler = getOrderPaymentsHandler
+	dep.capturePaymentHandler = capturePaymentHandler
+	dep.voidPaymentHandler = voidPaymentHandler
+	dep.refundPaymentHandler = refundPaymentHandler
+	dep.paymentWebhookHandler = paymentWebhookHandler
+	dep.getWebhooksPageHandler = getWebhooksPageHandler
+	dep.getWebhookByIdHandler = getWebhookByIdHandler
+	dep.createWebhookHandler = createWebhookHandler
+	dep.updateWebhookHandler = updateWebhookHandler
+	dep.deleteWebhookHandler = deleteWebhookHandler
+	dep.getWebhookDeliveriesHandler = getWebhookDeliveriesHandler
+	dep.redeliverWebhookHandler = redeliverWebhookHandler
+}
+
+const (
+	// relayBatchSize is the number of events published within a single outbox transaction.
+	relayBatchSize = 100
+	// webhookBatchSize is the number of webhook deliveries attempted within a single transaction.
+	webhookBatchSize = 10
+	// idempotencyPurgeInterval is how often expired idempotency keys are deleted.
+	idempotencyPurgeInterval = time.Hour
+)
+
+// newEventPublisher creates the publisher of domain events chosen by the configuration.
+func newEventPublisher(cfg Config) (core.EventPublisher, error) {
+	switch cfg.eventPublisher {
+	case "stdout":
+		return events.NewStreamPublisher(os.Stdout), nil
+	case "file":
+		return events.NewFilePublisher(cfg.eventTarget)
+	case "webhook":
+		if cfg.eventTarget == "" {
+			return nil, errors.New("event webhook url is missing")
+		}
+		return events.NewWebhookPublisher(cfg.eventTarget, 10*time.Second), nil
+	default:
+		return nil, fmt.Errorf("unknown event publisher: %s", cfg.eventPublisher)
+	}
+}
+
+// workers returns the workers to run in the background of the server. The memory storage keeps no events,
+// webhook deliveries or idempotency keys, so nothing is running in the background then.
+func (dep dependencies) workers() []worker {
+	if dep.storage == StorageMemory {
+		return nil
+	}
+	return []worker{dep.relay, dep.webhookWorker, dep.idempotencyPurger}
+}
+
+// storage holds the repositories of accounts, items and orders, which can be kept in memory,
+// and the transaction manager combining their operations.
+type storage struct {
+	accounts  ports.AccountRepository[uuid.UUID]
+	items     ports.ItemRepository[uuid.UUID]
+	orders    ports.OrderRepository[uuid.UUID]
+	txManager core.TxManager
+}
+
+// newStorage creates the repositories of accounts, items and orders in the storage chosen by the configuration.
+// The PostgreSQL storage returns the database connection as well, the memory storage creates the configured admin account.
+func newStorage(cfg Config) (storage, *bun.DB, error) {
+	switch cfg.storage {
+	case StoragePostgres:
+		dbSvc := db.NewService()
+		sqlDb, err := dbSvc.Connect()
+		if err != nil {
+			return storage{}, nil, err
+		}
+		bunDb := dbSvc.WrapWithBun(sqlDb)
+		return storage{
+			accounts:  repositories.NewAccountRepository(bunDb),
+			items:     repositories.NewItemRepository(bunDb, repositories.WithSimilarityThreshold(cfg.similarity)),
+			orders:    repositories.NewOrderRepository(bunDb),
+			txManager: repositories.NewTxManager(bunDb),
+		}, bunDb, nil
+	case StorageMemory:
+		store := memory.NewStore()
+		s := storage{
+			accounts:  memory.NewAccountRepository(store),
+			items:     memory.NewItemRepository(store, memory.WithSimilarityThreshold(cfg.similarity)),
+			orders:    memory.NewOrderRepository(store),
+			txManager: memory.NewTxManager(store),
+		}
+		return s, nil, createAdmin(cfg, s.accounts)
+	default:
+		return storage{}, nil, fmt.Errorf("unknown storage: %s", cfg.storage)
+	}
+}
+
+// createAdmin creates the admin account configured by WithMemoryAdmin, if it has a password.
+func createAdmin(cfg Config, accounts ports.AccountRepository[uuid.UUID]) error {
+	if cfg.adminPassword == "" {
+		return nil
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(cfg.adminPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
 	}
+	admin := entities.NewAccountBuilder().Email(cfg.adminEmail).PasswordHash(string(hash)).Role(entities.ADMIN).Build()
+	return accounts.Create(context.Background(), admin)
 }
@@ -2,16 +2,90 @@ package server
 
 import (
 	doc "github.com/fmiskovic/new-amz/docs/v1"
+	"github.com/fmiskovic/new-amz/internal/auth"
+	"github.com/fmiskovic/new-amz/internal/handlers"
+	"github.com/fmiskovic/new-amz/internal/idempotency"
+	"github.com/fmiskovic/new-amz/internal/payments"
 	"github.com/fmiskovic/new-amz/internal/validators"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/labstack/gommon/log"
 	echoSwagger "github.com/swaggo/echo-swagger"
 
+	"log/slog"
 	"net/http"
 )
 
-func initRouter() http.Handler {
+// publicRoutes lists routes which can be called without an access token.
+var publicRoutes = map[string]struct{}{
+	http.MethodPost + " /api/v1/auth/token":         {},
+	http.MethodPost + " /api/v1/account":            {},
+	http.MethodGet + " /api/v1/item":                {},
+	http.MethodGet + " /api/v1/item/:id":            {},
+	http.MethodGet + " /api/v1/item/isbn/:isbn":     {},
+	http.MethodGet + " /api/v1/item/search":         {},
+	http.MethodGet + " /api/v1/item/suggest":        {},
+	http.MethodGet + " /api/v1/item/:id/categories": {},
+	http.MethodGet + " /api/v1/category":            {},
+	http.MethodGet + " /api/v1/category/:id":        {},
+	http.MethodGet + " /api/v1/author":              {},
+	http.MethodGet + " /api/v1/author/:id":          {},
+	http.MethodGet + " /api/v1/author/:id/items":    {},
+	http.MethodGet + " /api/v1/publisher":           {},
+	http.MethodGet + " /api/v1/publisher/:id":       {},
+	// guest carts are accessed by their id
+	http.MethodPost + " /api/v1/cart/guest":                     {},
+	http.MethodGet + " /api/v1/cart/guest/:id":                  {},
+	http.MethodDelete + " /api/v1/cart/guest/:id":               {},
+	http.MethodPost + " /api/v1/cart/guest/:id/items":           {},
+	http.MethodPut + " /api/v1/cart/guest/:id/items/:itemId":    {},
+	http.MethodDelete + " /api/v1/cart/guest/:id/items/:itemId": {},
+	// payment webhooks are authenticated by their signature
+	http.MethodPost + " /api/v1/payment/webhook": {},
+}
+
+func isPublicRoute(c echo.Context) bool {
+	_, ok := publicRoutes[c.Request().Method+" "+c.Path()]
+	return ok
+}
+
+// isRegisteredRoute reports whether the request matches one of the routes, requests matching none are not
+// authenticated, so they are answered with 404 or 405 rather than 401. E.g. public routes of the catalogue
+// are not registered with the memory storage.
+func isRegisteredRoute(routes map[string]struct{}, c echo.Context) bool {
+	_, ok := routes[c.Request().Method+" "+c.Path()]
+	return ok
+}
+
+// idempotentRoutes lists routes creating resources, which honour the Idempotency-Key header.
+var idempotentRoutes = map[string]struct{}{
+	http.MethodPost + " /api/v1/account":            {},
+	http.MethodPost + " /api/v1/order":              {},
+	http.MethodPost + " /api/v1/cart/checkout":      {},
+	http.MethodPost + " /api/v1/order/:id/refunds":  {},
+	http.MethodPost + " /api/v1/order/:id/payments": {},
+	http.MethodPost + " /api/v1/payment/:id/refund": {},
+	http.MethodPost + " /api/v1/webhook":            {},
+}
+
+func isNotIdempotentRoute(c echo.Context) bool {
+	_, ok := idempotentRoutes[c.Request().Method+" "+c.Path()]
+	return !ok
+}
+
+// memoryDisabledRoutes lists route groups which are not registered with the memory storage.
+var memoryDisabledRoutes = []string{
+	"/api/v1/item/:id/categories",
+	"/api/v1/category",
+	"/api/v1/author",
+	"/api/v1/publisher",
+	"/api/v1/cart",
+	"/api/v1/order/:id/payments",
+	"/api/v1/payment",
+	"/api/v1/webhook",
+}
+
+func initRouter(cfg Config, dep dependencies) http.Handler {
 	e := echo.New()
 
 	// middlewares
@@ -24,8 +98,31 @@ func initRouter() http.Handler {
 	// validator
 	e.Validator = validators.New()
 
+	// errors are written as RFC 7807 problem details
+	e.HTTPErrorHandler = handlers.ErrorHandler
+
+	// api requests are authenticated first, so idempotency keys are bound to the calling account
+	v1 := e.Group("/api/v1")
+	routes := make(map[string]struct{})
+	v1.Use(auth.Middleware(dep.jwt, func(c echo.Context) bool {
+		return isPublicRoute(c) || !isRegisteredRoute(routes, c)
+	}))
+	if dep.storage != StorageMemory {
+		v1.Use(idempotency.Middleware(dep.idempotencyKeys, idempotency.Config{
+			Skipper: isNotIdempotentRoute,
+			TTL:     cfg.idempotencyTTL,
+		}))
+	} else {
+		slog.Warn("Idempotency-Key headers are ignored by the memory storage")
+	}
+
 	// routes
-	initRoutes(e)
+	initRoutes(v1, dep)
+	for _, r := range e.Routes() {
+		if r.Method != echo.RouteNotFound {
+			routes[r.Method+" "+r.Path] = struct{}{}
+		}
+	}
 
 	// Open API
 	e.GET("/docs/*", echoSwagger.WrapHandler)
@@ -34,21 +131,98 @@ func initRouter() http.Handler {
 	return e
 }
 
-func initRoutes(r *echo.Echo) {
-	dep := bootstrap()
-
-	v1 := r.Group("/api/v1")
+func initRoutes(v1 *echo.Group, dep dependencies) {
+	authGroup := v1.Group("/auth")
+	authGroup.POST("/token", dep.loginHandler.Handle)
 
 	account := v1.Group("/account")
 	account.POST("", dep.createAccountHandler.Handle)
 	account.GET("/:id", dep.getAccountByIdHandler.Handle)
+	account.PUT("/:id", dep.updateAccountHandler.Handle)
+	account.PATCH("/:id", dep.patchAccountHandler.Handle)
+	account.DELETE("/:id", dep.deleteAccountHandler.Handle)
 	account.GET("/:id/orders", dep.searchAccountOrdersHandler.Handle)
 
 	item := v1.Group("/item")
+	item.GET("/search", dep.searchItemsHandler.Handle)
+	item.GET("/suggest", dep.suggestItemsHandler.Handle)
+	item.GET("/isbn/:isbn", dep.getItemByIsbnHandler.Handle)
 	item.GET("/:id", dep.getItemByIdHandler.Handle)
 	item.GET("", dep.getItemsPageHandler.Handle)
+	item.POST("", dep.createItemHandler.Handle)
+	item.PUT("/:id", dep.updateItemHandler.Handle)
+	item.DELETE("/:id", dep.deleteItemHandler.Handle)
 
 	order := v1.Group("/order")
 	order.POST("", dep.createOrderHandler.Handle)
 	order.GET("/:id", dep.getOrderByIdHandler.Handle)
+	order.GET("/:id/transitions", dep.getOrderTransitionsHandler.Handle)
+	order.POST("/:id/transitions", dep.transitionOrderHandler.Handle)
+	order.POST("/:id/cancel", dep.cancelOrderHandler.Handle)
+	order.GET("/:id/refunds", dep.getOrderRefundsHandler.Handle)
+	order.POST("/:id/refunds", dep.refundOrderHandler.Handle)
+
+	if dep.storage == StorageMemory {
+		// only accounts, items and orders are kept in memory
+		slog.Warn("Routes are disabled by the memory storage", "routes", memoryDisabledRoutes)
+		return
+	}
+
+	item.GET("/:id/categories", dep.getItemCategoriesHandler.Handle)
+	item.PUT("/:id/categories", dep.assignItemCategoriesHandler.Handle)
+
+	category := v1.Group("/category")
+	category.GET("", dep.getTopLevelCategoriesHandler.Handle)
+	category.GET("/:id", dep.getCategorySubtreeHandler.Handle)
+	category.POST("", dep.createCategoryHandler.Handle)
+	category.PUT("/:id", dep.updateCategoryHandler.Handle)
+	category.DELETE("/:id", dep.deleteCategoryHandler.Handle)
+
+	author := v1.Group("/author")
+	author.GET("", dep.getAuthorsPageHandler.Handle)
+	author.GET("/:id", dep.getAuthorByIdHandler.Handle)
+	author.POST("", dep.createAuthorHandler.Handle)
+	author.PUT("/:id", dep.updateAuthorHandler.Handle)
+	author.DELETE("/:id", dep.deleteAuthorHandler.Handle)
+	author.GET("/:id/items", dep.getAuthorItemsHandler.Handle)
+
+	publisher := v1.Group("/publisher")
+	publisher.GET("", dep.getPublishersPageHandler.Handle)
+	publisher.GET("/:id", dep.getPublisherByIdHandler.Handle)
+	publisher.POST("", dep.createPublisherHandler.Handle)
+	publisher.PUT("/:id", dep.updatePublisherHandler.Handle)
+	publisher.DELETE("/:id", dep.deletePublisherHandler.Handle)
+
+	cart := v1.Group("/cart")
+	cart.GET("", dep.getCartHandler.Handle)
+	cart.DELETE("", dep.clearCartHandler.Handle)
+	cart.POST("/items", dep.addCartItemHandler.Handle)
+	cart.PUT("/items/:itemId", dep.setCartItemHandler.Handle)
+	cart.DELETE("/items/:itemId", dep.removeCartItemHandler.Handle)
+	cart.POST("/merge", dep.mergeCartHandler.Handle)
+	cart.POST("/checkout", dep.checkoutCartHandler.Handle)
+	cart.POST("/guest", dep.createGuestCartHandler.Handle)
+	cart.GET("/guest/:id", dep.getCartHandler.Handle)
+	cart.DELETE("/guest/:id", dep.clearCartHandler.Handle)
+	cart.POST("/guest/:id/items", dep.addCartItemHandler.Handle)
+	cart.PUT("/guest/:id/items/:itemId", dep.setCartItemHandler.Handle)
+	cart.DELETE("/guest/:id/items/:itemId", dep.removeCartItemHandler.Handle)
+
+	order.GET("/:id/payments", dep.getOrderPaymentsHandler.Handle)
+	order.POST("/:id/payments", dep.authorizePaymentHandler.Handle)
+
+	payment := v1.Group("/payment")
+	payment.POST("/:id/capture", dep.capturePaymentHandler.Handle)
+	payment.POST("/:id/void", dep.voidPaymentHandler.Handle)
+	payment.POST("/:id/refund", dep.refundPaymentHandler.Handle)
+	payment.POST("/webhook", dep.paymentWebhookHandler.Handle, payments.VerifySignature(dep.webhookSecret))
+
+	webhook := v1.Group("/webhook")
+	webhook.GET("", dep.getWebhooksPageHandler.Handle)
+	webhook.GET("/:id", dep.getWebhookByIdHandler.Handle)
+	webhook.POST("", dep.createWebhookHandler.Handle)
+	webhook.PUT("/:id", dep.updateWebhookHandler.Handle)
+	webhook.DELETE("/:id", dep.deleteWebhookHandler.Handle)
+	webhook.GET("/:id/deliveries", dep.getWebhookDeliveriesHandler.Handle)
+	webhook.POST("/:id/deliveries/:deliveryId/redeliver", dep.redeliverWebhookHandler.Handle)
 }
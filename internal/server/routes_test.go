@@ -0,0 +1,50 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouterWithMemoryStorage(t *testing.T) {
+	cfg := NewConfig().WithSecret("test-secret").WithStorage(StorageMemory).Build()
+	dep := bootstrap(cfg)
+	router := initRouter(cfg, dep)
+
+	serve := func(method string, target string, body string) int {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp.Code
+	}
+
+	t.Run("should not wire dependencies kept in PostgreSQL only", func(t *testing.T) {
+		assert.Nil(t, dep.relay)
+		assert.Nil(t, dep.webhookWorker)
+		assert.Nil(t, dep.idempotencyPurger)
+		assert.Empty(t, dep.workers())
+	})
+
+	t.Run("should serve public routes of memory storage", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/api/v1/item", ""))
+	})
+
+	t.Run("should answer public routes not served by memory storage with 404", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/api/v1/category", ""))
+		assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/api/v1/author", ""))
+		assert.Equal(t, http.StatusNotFound, serve(http.MethodPost, "/api/v1/cart/guest", "{}"))
+	})
+
+	t.Run("should answer unknown routes with 404", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/api/v1/unknown", ""))
+	})
+
+	t.Run("should reject protected routes without access token", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, serve(http.MethodGet, "/api/v1/account/8d9c8c4e-4a4e-4c1c-9f1c-2a7b6f1c6a10", ""))
+		assert.Equal(t, http.StatusUnauthorized, serve(http.MethodPost, "/api/v1/order", "{}"))
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 
 	"log"
 	"log/slog"
@@ -13,14 +14,21 @@ import (
 
 // Server represents an HTTP server.
 type Server struct {
-	config Config
-	router http.Handler
+	config  Config
+	router  http.Handler
+	workers []worker
+}
+
+// worker runs in the background of the server until ctx is done.
+type worker interface {
+	Run(ctx context.Context)
 }
 
 // Builder creates a new server instance.
 type Builder struct {
-	config Config
-	router http.Handler
+	config  Config
+	router  http.Handler
+	workers []worker
 }
 
 // NewBuilder creates a new Builder instance.
@@ -44,11 +52,18 @@ func (b *Builder) Build() Server {
 		b.config = NewConfig().Build()
 	}
 	if b.router == nil {
-		b.router = initRouter()
+		// the application is not served with an unsafe configuration, e.g. a guessable JWT secret
+		if err := b.config.Validate(); err != nil {
+			panic(err)
+		}
+		dep := bootstrap(b.config)
+		b.router = initRouter(b.config, dep)
+		b.workers = dep.workers()
 	}
 	return Server{
-		config: b.config,
-		router: b.router,
+		config:  b.config,
+		router:  b.router,
+		workers: b.workers,
 	}
 }
 
@@ -75,6 +90,17 @@ func (s Server) Start() {
 		slog.Info("Stopped serving new connections.")
 	}()
 
+	// Start the workers, like the event relay, in the background until the server is shut down.
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	for _, w := range s.workers {
+		workers.Add(1)
+		go func(w worker) {
+			defer workers.Done()
+			w.Run(workersCtx)
+		}(w)
+	}
+
 	// Wait for interrupt signal to gracefully shutdown the server with a timeout.
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer stop()
@@ -86,5 +112,7 @@ func (s Server) Start() {
 	if err := server.Shutdown(ctx); err != nil {
 		log.Fatalf("HTTP shutdown error: %v", err)
 	}
+	stopWorkers()
+	workers.Wait()
 	slog.Info("Graceful shutdown completed.")
 }
@@ -0,0 +1,63 @@
+// Package signature signs and verifies webhook bodies exchanged with other services, the payment webhooks
+// received from the provider as well as the webhooks delivered to subscribers.
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	prefix = "sha256="
+	// MaxAge is how old a signature may be, older bodies are rejected as replays.
+	MaxAge = 5 * time.Minute
+)
+
+var (
+	ErrMissing = errors.New("webhook signature is missing")
+	ErrInvalid = errors.New("webhook signature is invalid")
+	ErrExpired = errors.New("webhook signature has expired")
+)
+
+// Sign returns the signature of the body sent at the timestamp, "sha256=" followed by the hex encoded
+// HMAC-SHA256 of the timestamp and the body separated by a dot.
+func Sign(secret []byte, timestamp time.Time, body []byte) string {
+	return prefix + hex.EncodeToString(mac(secret, strconv.FormatInt(timestamp.Unix(), 10), body))
+}
+
+// Verify checks that the signature of the body was made with the secret at the timestamp, given as unix time,
+// which must not be older than MaxAge.
+func Verify(secret []byte, timestamp string, signature string, body []byte, now time.Time) error {
+	if timestamp == "" || signature == "" {
+		return ErrMissing
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalid
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > MaxAge || age < -MaxAge {
+		return ErrExpired
+	}
+
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, prefix))
+	if err != nil || !strings.HasPrefix(signature, prefix) {
+		return ErrInvalid
+	}
+	if !hmac.Equal(got, mac(secret, timestamp, body)) {
+		return ErrInvalid
+	}
+	return nil
+}
+
+func mac(secret []byte, timestamp string, body []byte) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(timestamp))
+	h.Write([]byte{'.'})
+	h.Write(body)
+	return h.Sum(nil)
+}
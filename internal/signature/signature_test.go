@@ -0,0 +1,49 @@
+package signature
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerify(t *testing.T) {
+	secret := []byte("webhook-secret")
+	body := []byte(`{"type":"payment.captured"}`)
+	now := time.Now()
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	signature := Sign(secret, now, body)
+
+	tests := []struct {
+		name      string
+		timestamp string
+		signature string
+		body      []byte
+		secret    []byte
+		want      error
+	}{
+		{name: "valid signature", timestamp: timestamp, signature: signature, body: body, secret: secret},
+		{name: "missing signature", timestamp: timestamp, body: body, secret: secret, want: ErrMissing},
+		{name: "missing timestamp", signature: signature, body: body, secret: secret, want: ErrMissing},
+		{name: "tampered body", timestamp: timestamp, signature: signature, body: []byte(`{"type":"payment.refunded"}`), secret: secret, want: ErrInvalid},
+		{name: "other secret", timestamp: timestamp, signature: signature, body: body, secret: []byte("other"), want: ErrInvalid},
+		{name: "other timestamp", timestamp: strconv.FormatInt(now.Unix()-1, 10), signature: signature, body: body, secret: secret, want: ErrInvalid},
+		{name: "malformed timestamp", timestamp: "yesterday", signature: signature, body: body, secret: secret, want: ErrInvalid},
+		{name: "malformed signature", timestamp: timestamp, signature: "md5=abc", body: body, secret: secret, want: ErrInvalid},
+		{
+			name:      "expired signature",
+			timestamp: strconv.FormatInt(now.Add(-time.Hour).Unix(), 10),
+			signature: Sign(secret, now.Add(-time.Hour), body),
+			body:      body,
+			secret:    secret,
+			want:      ErrExpired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.secret, tt.timestamp, tt.signature, tt.body, now)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
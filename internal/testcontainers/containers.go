@@ -81,8 +81,16 @@ func SetUpDb() (*TestDB, error) {
 				(*entities.Entity)(nil),
 				(*entities.Account)(nil),
 				(*entities.Order)(nil),
+				(*entities.Author)(nil),
+				(*entities.Publisher)(nil),
+				(*entities.ItemAuthor)(nil),
 				(*entities.Item)(nil),
 				(*entities.OrderItem)(nil),
+				(*entities.OrderHistory)(nil),
+				(*entities.Category)(nil),
+				(*entities.ItemCategory)(nil),
+				(*entities.Cart)(nil),
+				(*entities.CartItem)(nil),
 			)
 			fixture := dbfixture.New(bunDb, dbfixture.WithTruncateTables())
 			err = fixture.Load(ctx, os.DirFS("testdata"), "fixture.yml")
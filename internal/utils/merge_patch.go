@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+var ErrInvalidMergePatch = errors.New("invalid merge patch")
+
+// MergePatch applies JSON Merge Patch (RFC 7386) to the original JSON document.
+// Members of the patch replace members of the original, null members remove them
+// and nested objects are merged recursively. A patch which is not an object replaces the whole document.
+func MergePatch(original, patch []byte) ([]byte, error) {
+	var p interface{}
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, errors.Join(ErrInvalidMergePatch, err)
+	}
+
+	var o interface{}
+	if len(original) > 0 {
+		if err := json.Unmarshal(original, &o); err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(mergeValue(o, p))
+}
+
+func mergeValue(original, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	o, ok := original.(map[string]interface{})
+	if !ok {
+		o = make(map[string]interface{})
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(o, k)
+			continue
+		}
+		o[k] = mergeValue(o[k], v)
+	}
+	return o
+}
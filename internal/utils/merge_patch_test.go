@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergePatch(t *testing.T) {
+	// cases are taken from RFC 7386, Appendix A
+	tests := []struct {
+		name     string
+		original string
+		patch    string
+		want     string
+	}{
+		{name: "replace member", original: `{"a":"b"}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{name: "add member", original: `{"a":"b"}`, patch: `{"b":"c"}`, want: `{"a":"b","b":"c"}`},
+		{name: "remove member", original: `{"a":"b"}`, patch: `{"a":null}`, want: `{}`},
+		{name: "remove one of members", original: `{"a":"b","b":"c"}`, patch: `{"a":null}`, want: `{"b":"c"}`},
+		{name: "replace array", original: `{"a":["b"]}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{name: "replace with array", original: `{"a":"c"}`, patch: `{"a":["b"]}`, want: `{"a":["b"]}`},
+		{name: "merge nested objects", original: `{"a":{"b":"c"}}`, patch: `{"a":{"b":"d","c":null}}`, want: `{"a":{"b":"d"}}`},
+		{name: "replace array of objects", original: `{"a":[{"b":"c"}]}`, patch: `{"a":[1]}`, want: `{"a":[1]}`},
+		{name: "replace whole document with array", original: `["a","b"]`, patch: `["c","d"]`, want: `["c","d"]`},
+		{name: "replace object with array", original: `{"a":"b"}`, patch: `["c"]`, want: `["c"]`},
+		{name: "replace object with null", original: `{"a":"foo"}`, patch: `null`, want: `null`},
+		{name: "replace object with string", original: `{"a":"foo"}`, patch: `"bar"`, want: `"bar"`},
+		{name: "keep null members of patch out of result", original: `{"e":null}`, patch: `{"a":1}`, want: `{"e":null,"a":1}`},
+		{name: "patch array into object", original: `[1,2]`, patch: `{"a":"b","c":null}`, want: `{"a":"b"}`},
+		{name: "create nested objects", original: `{}`, patch: `{"a":{"bb":{"ccc":null}}}`, want: `{"a":{"bb":{}}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MergePatch([]byte(tt.original), []byte(tt.patch))
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestMergePatch_InvalidPatch(t *testing.T) {
+	_, err := MergePatch([]byte(`{"a":"b"}`), []byte(`{"a":`))
+	assert.ErrorIs(t, err, ErrInvalidMergePatch)
+}
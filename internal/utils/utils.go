@@ -43,6 +43,18 @@ func GetOrDefaultInt(key string, def int) int {
 	return def
 }
 
+func GetOrDefaultFloat(key string, def float64) float64 {
+	env, ok := os.LookupEnv(key)
+	if ok && IsNotBlank(env) {
+		f, err := strconv.ParseFloat(env, 64)
+		if err != nil {
+			return def
+		}
+		return f
+	}
+	return def
+}
+
 func IsBlank(s string) bool {
 	return strings.TrimSpace(s) == ""
 }
@@ -0,0 +1,71 @@
+package validators
+
+import (
+	"strings"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/go-playground/validator/v10"
+)
+
+var ErrorInvalidISBN = entities.NewError(entities.ErrorValidation, "invalid ISBN")
+
+// ParseISBN checks the ISBN-10 or ISBN-13 including its check digit and returns it as ISBN-13 without hyphens,
+// the form ISBNs are stored and looked up in. Hyphens and spaces separating the parts of the ISBN are ignored.
+func ParseISBN(s string) (string, error) {
+	isbn := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(s))
+	switch {
+	case len(isbn) == 10 && isISBN10(isbn):
+		return isbn10To13(isbn), nil
+	case len(isbn) == 13 && isISBN13(isbn):
+		return isbn, nil
+	default:
+		return "", ErrorInvalidISBN
+	}
+}
+
+// isISBN10 checks nine digits followed by a check digit, or X standing for 10,
+// so that the sum of the digits weighted 10 down to 1 is divisible by 11.
+func isISBN10(isbn string) bool {
+	sum := 0
+	for i, r := range isbn {
+		var digit int
+		switch {
+		case r >= '0' && r <= '9':
+			digit = int(r - '0')
+		case r == 'X' && i == 9:
+			digit = 10
+		default:
+			return false
+		}
+		sum += digit * (10 - i)
+	}
+	return sum%11 == 0
+}
+
+// isISBN13 checks thirteen digits, so that the sum of the digits weighted alternately 1 and 3 is divisible by 10.
+func isISBN13(isbn string) bool {
+	sum := 0
+	for i, r := range isbn {
+		if r < '0' || r > '9' {
+			return false
+		}
+		sum += int(r-'0') * (1 + 2*(i%2))
+	}
+	return sum%10 == 0
+}
+
+// isbn10To13 prefixes the ISBN-10 with the 978 Bookland prefix and recomputes the check digit.
+func isbn10To13(isbn string) string {
+	isbn13 := "978" + isbn[:9]
+	sum := 0
+	for i, r := range isbn13 {
+		sum += int(r-'0') * (1 + 2*(i%2))
+	}
+	return isbn13 + string(rune('0'+(10-sum%10)%10))
+}
+
+// validateISBN is the isbn rule, it replaces the built-in one so requests are validated like ISBNs are parsed.
+func validateISBN(fl validator.FieldLevel) bool {
+	_, err := ParseISBN(fl.Field().String())
+	return err == nil
+}
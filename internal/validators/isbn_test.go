@@ -0,0 +1,59 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseISBN(t *testing.T) {
+	tests := []struct {
+		name    string
+		isbn    string
+		want    string
+		wantErr error
+	}{
+		{name: "given ISBN-13 should return it", isbn: "9783161484100", want: "9783161484100"},
+		{name: "given hyphenated ISBN-13 should strip hyphens", isbn: "978-3-16-148410-0", want: "9783161484100"},
+		{name: "given ISBN-10 should convert it to ISBN-13", isbn: "0-306-40615-2", want: "9780306406157"},
+		{name: "given ISBN-10 with X check digit should convert it", isbn: "0 8044 2957 x", want: "9780804429573"},
+		{name: "given ISBN-13 with wrong check digit should return error", isbn: "9783161484101", wantErr: ErrorInvalidISBN},
+		{name: "given ISBN-10 with wrong check digit should return error", isbn: "0306406153", wantErr: ErrorInvalidISBN},
+		{name: "given X within ISBN-10 should return error", isbn: "03064X6152", wantErr: ErrorInvalidISBN},
+		{name: "given wrong length should return error", isbn: "97831614841", wantErr: ErrorInvalidISBN},
+		{name: "given empty ISBN should return error", isbn: "", wantErr: ErrorInvalidISBN},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseISBN(tt.isbn)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+type BookData struct {
+	ISBN string `validate:"omitempty,isbn" json:"isbn"`
+}
+
+func TestValidator_ValidateISBN(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   BookData
+		reason string
+	}{
+		{name: "given valid ISBN should return no errors", data: BookData{ISBN: "978-0-306-40615-7"}},
+		{name: "given no ISBN should return no errors", data: BookData{}},
+		{name: "given wrong check digit should return error", data: BookData{ISBN: "978-0-306-40615-8"}, reason: "must be a valid ISBN-10 or ISBN-13"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := New().Validate(tt.data)
+			if tt.reason == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Equal(t, []InvalidParam{{Name: "isbn", Reason: tt.reason}}, InvalidParams(err))
+		})
+	}
+}
@@ -1,6 +1,12 @@
 package validators
 
 import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 )
@@ -12,7 +18,21 @@ type Validator struct {
 
 // New instantiate new Validator.
 func New() Validator {
-	return Validator{validate: validator.New()}
+	validate := validator.New()
+	// validate money by its amount, so rules like gt=0 can be used on money fields
+	validate.RegisterCustomTypeFunc(func(v reflect.Value) interface{} {
+		return v.Interface().(entities.Money).Amount
+	}, entities.Money{})
+	_ = validate.RegisterValidation("isbn", validateISBN)
+	// report fields by their json names, so clients can match errors with the fields they sent
+	validate.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return Validator{validate: validate}
 }
 
 // Validate incoming request object and returns error(s) if validation fails.
@@ -23,3 +43,72 @@ func (v Validator) Validate(data interface{}) error {
 	}
 	return v.validate.Struct(data)
 }
+
+// InvalidParam describes a request field which failed validation.
+type InvalidParam struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// InvalidParams lists the fields which failed validation, as reported by Validator.Validate.
+// Fields are named by their path in the request body, e.g. items[0].quantity.
+func InvalidParams(err error) []InvalidParam {
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return nil
+	}
+	params := make([]InvalidParam, 0, len(verrs))
+	for _, fe := range verrs {
+		params = append(params, InvalidParam{Name: paramName(fe), Reason: reason(fe)})
+	}
+	return params
+}
+
+// paramName returns the namespace of the field without the name of the validated struct.
+func paramName(fe validator.FieldError) string {
+	if _, name, found := strings.Cut(fe.Namespace(), "."); found {
+		return name
+	}
+	return fe.Field()
+}
+
+func reason(fe validator.FieldError) string {
+	unit := ""
+	switch fe.Kind() {
+	case reflect.String:
+		unit = " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		unit = " elements"
+	}
+
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		return fmt.Sprintf("must have at least %s%s", fe.Param(), unit)
+	case "max":
+		return fmt.Sprintf("must have at most %s%s", fe.Param(), unit)
+	case "gt":
+		return fmt.Sprintf("must be greater than %s", fe.Param())
+	case "gte":
+		return fmt.Sprintf("must be greater than or equal to %s", fe.Param())
+	case "lt":
+		return fmt.Sprintf("must be less than %s", fe.Param())
+	case "lte":
+		return fmt.Sprintf("must be less than or equal to %s", fe.Param())
+	case "oneof":
+		return fmt.Sprintf("must be one of: %s", strings.ReplaceAll(fe.Param(), " ", ", "))
+	case "email":
+		return "must be a valid email address"
+	case "uuid":
+		return "must be a valid UUID"
+	case "isbn":
+		return "must be a valid ISBN-10 or ISBN-13"
+	case "datetime":
+		return fmt.Sprintf("must be a date formatted as %s", fe.Param())
+	case "bcp47_language_tag":
+		return "must be a BCP 47 language tag, e.g. en or pt-BR"
+	default:
+		return fmt.Sprintf("failed on the '%s' rule", fe.Tag())
+	}
+}
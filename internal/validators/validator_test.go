@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/fmiskovic/new-amz/internal/core/entities"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -39,13 +40,13 @@ func TestValidator_Validate(t *testing.T) {
 			name:    "given invalid email should return error",
 			args:    args{data: TestData{Email: "t@"}},
 			wantErr: true,
-			want:    errors.New("Key: 'TestData.Email' Error:Field validation for 'Email' failed on the 'min' tag"),
+			want:    errors.New("Key: 'TestData.email' Error:Field validation for 'email' failed on the 'min' tag"),
 		},
 		{
 			name:    "given nil email should return error",
 			args:    args{data: TestData{}},
 			wantErr: true,
-			want:    errors.New("Key: 'TestData.Email' Error:Field validation for 'Email' failed on the 'required' tag"),
+			want:    errors.New("Key: 'TestData.email' Error:Field validation for 'email' failed on the 'required' tag"),
 		},
 	}
 	for _, tt := range tests {
@@ -58,3 +59,53 @@ func TestValidator_Validate(t *testing.T) {
 		})
 	}
 }
+
+type PricedData struct {
+	Price entities.Money `validate:"required,gt=0"`
+}
+
+func TestValidator_ValidateMoney(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    PricedData
+		wantErr bool
+	}{
+		{name: "given positive price should return no errors", data: PricedData{Price: entities.NewMoney(1299, entities.EUR)}},
+		{name: "given zero price should return error", data: PricedData{Price: entities.Zero(entities.EUR)}, wantErr: true},
+		{name: "given negative price should return error", data: PricedData{Price: entities.NewMoney(-1, entities.EUR)}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := New().Validate(tt.data)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+type OrderData struct {
+	Items []OrderLineData `json:"items" validate:"required,min=1,dive"`
+	Note  string          `json:"note" validate:"max=5"`
+}
+
+type OrderLineData struct {
+	Quantity int `json:"quantity" validate:"required,gte=1"`
+}
+
+func TestInvalidParams(t *testing.T) {
+	t.Run("given validation errors should list fields by json path", func(t *testing.T) {
+		err := New().Validate(OrderData{Items: []OrderLineData{{Quantity: 1}, {Quantity: -1}}, Note: "too long"})
+		assert.Equal(t, []InvalidParam{
+			{Name: "items[1].quantity", Reason: "must be greater than or equal to 1"},
+			{Name: "note", Reason: "must have at most 5 characters"},
+		}, InvalidParams(err))
+	})
+
+	t.Run("given missing field should report it as required", func(t *testing.T) {
+		err := New().Validate(OrderData{})
+		assert.Equal(t, []InvalidParam{{Name: "items", Reason: "is required"}}, InvalidParams(err))
+	})
+
+	t.Run("given other error should return no params", func(t *testing.T) {
+		assert.Nil(t, InvalidParams(errors.New("boom")))
+	})
+}
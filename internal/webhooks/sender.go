@@ -0,0 +1,58 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/events"
+	"github.com/fmiskovic/new-amz/internal/signature"
+)
+
+const (
+	// HeaderDeliveryID carries the ID of the delivery, it is the same for every attempt of the delivery.
+	HeaderDeliveryID = "X-Webhook-ID"
+	// HeaderTimestamp carries the unix time the delivery was signed at.
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	// HeaderSignature carries the signature of the delivery, see signature.Sign.
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// HTTPSender sends webhook deliveries as JSON posted to the URLs of their subscriptions.
+type HTTPSender struct {
+	client *http.Client
+}
+
+// NewHTTPSender instantiates new HTTPSender giving up on receivers which do not respond within the timeout.
+func NewHTTPSender(timeout time.Duration) *HTTPSender {
+	return &HTTPSender{client: &http.Client{Timeout: timeout}}
+}
+
+// Send posts the payload of the delivery signed with the secret of the subscription.
+func (s *HTTPSender) Send(ctx context.Context, subscription entities.WebhookSubscription, delivery entities.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderDeliveryID, delivery.ID.String())
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(HeaderSignature, signature.Sign([]byte(subscription.Secret), now, delivery.Payload))
+	req.Header.Set(events.HeaderEventID, delivery.EventID.String())
+	req.Header.Set(events.HeaderEventType, string(delivery.EventType))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// the body is drained, so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,71 @@
+package webhooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/signature"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPSender(t *testing.T) {
+	ctx := context.Background()
+	subscription := entities.WebhookSubscription{Entity: entities.Entity{ID: uuid.New()}, Secret: "whsec_test"}
+	event, err := entities.NewEvent(entities.EVENT_ORDER_PLACED, uuid.New(), map[string]string{"status": "pending"})
+	assert.Nil(t, err)
+	delivery, err := entities.NewWebhookDelivery(subscription, *event)
+	assert.Nil(t, err)
+
+	t.Run("should post signed payload", func(t *testing.T) {
+		var body []byte
+		var header http.Header
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header = r.Header
+			body, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer srv.Close()
+		subscription.URL = srv.URL
+
+		statusCode, err := NewHTTPSender(time.Second).Send(ctx, subscription, *delivery)
+
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusAccepted, statusCode)
+		assert.JSONEq(t, string(delivery.Payload), string(body))
+		assert.Equal(t, delivery.ID.String(), header.Get(HeaderDeliveryID))
+		assert.Equal(t, event.ID.String(), header.Get("X-Event-ID"))
+		assert.Equal(t, "order.placed", header.Get("X-Event-Type"))
+		timestamp, sig := header.Get(HeaderTimestamp), header.Get(HeaderSignature)
+		assert.Nil(t, signature.Verify([]byte("whsec_test"), timestamp, sig, body, time.Now()))
+		assert.ErrorIs(t, signature.Verify([]byte("other"), timestamp, sig, body, time.Now()), signature.ErrInvalid)
+	})
+
+	t.Run("should return status code of failed response", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusGone)
+		}))
+		defer srv.Close()
+		subscription.URL = srv.URL
+
+		statusCode, err := NewHTTPSender(time.Second).Send(ctx, subscription, *delivery)
+
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusGone, statusCode)
+	})
+
+	t.Run("should return error when receiver is unreachable", func(t *testing.T) {
+		srv := httptest.NewServer(http.NotFoundHandler())
+		srv.Close()
+		subscription.URL = srv.URL
+
+		_, err := NewHTTPSender(time.Second).Send(ctx, subscription, *delivery)
+
+		assert.NotNil(t, err)
+	})
+}
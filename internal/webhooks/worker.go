@@ -0,0 +1,45 @@
+package webhooks
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Worker attempts webhook deliveries which are due in the background.
+type Worker struct {
+	deliver   func(ctx context.Context, limit int) (int, error)
+	interval  time.Duration
+	batchSize int
+}
+
+// NewWorker instantiates new Worker calling the deliver func every interval
+// to attempt at most batchSize due deliveries at a time.
+func NewWorker(deliver func(ctx context.Context, limit int) (int, error), interval time.Duration, batchSize int) *Worker {
+	return &Worker{deliver: deliver, interval: interval, batchSize: batchSize}
+}
+
+// Run attempts due deliveries until ctx is done.
+func (w *Worker) Run(ctx context.Context) {
+	slog.Info("Starting webhook worker", "interval", w.interval.String())
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			attempted, err := w.deliver(ctx, w.batchSize)
+			if err != nil && ctx.Err() == nil {
+				slog.Error("failed to deliver webhooks", "error", err.Error())
+			}
+			if err != nil || attempted < w.batchSize {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			slog.Info("Stopped webhook worker.")
+			return
+		case <-ticker.C:
+		}
+	}
+}
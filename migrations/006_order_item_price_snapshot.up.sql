@@ -0,0 +1,9 @@
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS title VARCHAR(100);
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS unit_price DECIMAL(10, 2);
+
+UPDATE order_items oi SET title = i.title, unit_price = i.price
+FROM items i
+WHERE oi.item_id = i.id AND oi.unit_price IS NULL;
+
+ALTER TABLE order_items ALTER COLUMN title SET NOT NULL;
+ALTER TABLE order_items ALTER COLUMN unit_price SET NOT NULL;
@@ -0,0 +1,12 @@
+-- prices are stored as integer minor units (e.g. cents) together with an ISO-4217 currency code
+ALTER TABLE items ADD COLUMN IF NOT EXISTS price_amount BIGINT;
+ALTER TABLE items ADD COLUMN IF NOT EXISTS price_currency CHAR(3) NOT NULL DEFAULT 'EUR';
+UPDATE items SET price_amount = ROUND(price * 100) WHERE price_amount IS NULL;
+ALTER TABLE items ALTER COLUMN price_amount SET NOT NULL;
+ALTER TABLE items DROP COLUMN IF EXISTS price;
+
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS unit_price_amount BIGINT;
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS unit_price_currency CHAR(3) NOT NULL DEFAULT 'EUR';
+UPDATE order_items SET unit_price_amount = ROUND(unit_price * 100) WHERE unit_price_amount IS NULL;
+ALTER TABLE order_items ALTER COLUMN unit_price_amount SET NOT NULL;
+ALTER TABLE order_items DROP COLUMN IF EXISTS unit_price;
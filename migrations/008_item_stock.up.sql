@@ -0,0 +1,5 @@
+ALTER TABLE items ADD COLUMN IF NOT EXISTS stock INT NOT NULL DEFAULT 0;
+ALTER TABLE items ADD CONSTRAINT chk_items_stock_non_negative CHECK (stock >= 0);
+
+-- make seeded items available
+UPDATE items SET stock = 100 WHERE title IN ('Item 1', 'Item 2', 'Item 3');
@@ -0,0 +1,3 @@
+-- items referenced by orders must not be deleted together with order history
+ALTER TABLE order_items DROP CONSTRAINT IF EXISTS fk_item;
+ALTER TABLE order_items ADD CONSTRAINT fk_item FOREIGN KEY(item_id) REFERENCES items(id) ON DELETE RESTRICT;
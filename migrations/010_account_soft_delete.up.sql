@@ -0,0 +1,5 @@
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS deleted_at timestamp;
+
+-- email has to be unique among active accounts only, so it can be reused once an account is closed
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS accounts_email_key;
+CREATE UNIQUE INDEX IF NOT EXISTS accounts_email_active_idx ON accounts (email) WHERE deleted_at IS NULL;
@@ -0,0 +1,4 @@
+-- typo tolerant lookup of item titles by trigram similarity
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_items_title_trgm ON items USING GIN (title gin_trgm_ops);
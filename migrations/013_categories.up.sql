@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS categories (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    parent_id UUID,
+    -- categories with subcategories must not be deleted, subcategories would be left without a parent
+    CONSTRAINT fk_parent FOREIGN KEY(parent_id) REFERENCES categories(id) ON DELETE RESTRICT,
+    CONSTRAINT chk_categories_parent CHECK (parent_id <> id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_categories_parent ON categories (parent_id);
+
+-- names have to be unique among the subcategories of the same parent, top level categories included
+CREATE UNIQUE INDEX IF NOT EXISTS categories_parent_name_idx
+    ON categories (COALESCE(parent_id, '00000000-0000-0000-0000-000000000000'), lower(name));
+
+CREATE TABLE IF NOT EXISTS item_categories (
+    item_id UUID NOT NULL,
+    category_id UUID NOT NULL,
+    PRIMARY KEY (item_id, category_id),
+    CONSTRAINT fk_item FOREIGN KEY(item_id) REFERENCES items(id) ON DELETE CASCADE,
+    CONSTRAINT fk_category FOREIGN KEY(category_id) REFERENCES categories(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_item_categories_category ON item_categories (category_id);
@@ -0,0 +1,46 @@
+CREATE TABLE IF NOT EXISTS authors (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    name VARCHAR(255) NOT NULL,
+    biography TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_authors_name ON authors (name);
+
+CREATE TABLE IF NOT EXISTS publishers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    name VARCHAR(255) NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS publishers_name_idx ON publishers (lower(name));
+
+-- ISBNs are stored as ISBN-13 without hyphens, ISBN-10s are converted before they are stored
+ALTER TABLE items
+    ADD COLUMN IF NOT EXISTS isbn VARCHAR(13),
+    ADD COLUMN IF NOT EXISTS publisher_id UUID,
+    ADD COLUMN IF NOT EXISTS edition VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS publication_date DATE,
+    ADD COLUMN IF NOT EXISTS page_count INT,
+    ADD COLUMN IF NOT EXISTS language VARCHAR(35),
+    -- publishers of items must not be deleted, the items would lose their publisher unnoticed
+    ADD CONSTRAINT fk_publisher FOREIGN KEY(publisher_id) REFERENCES publishers(id) ON DELETE RESTRICT,
+    ADD CONSTRAINT chk_items_page_count CHECK (page_count > 0);
+
+CREATE UNIQUE INDEX IF NOT EXISTS items_isbn_idx ON items (isbn);
+CREATE INDEX IF NOT EXISTS idx_items_publisher ON items (publisher_id);
+
+-- authors of an item are listed in the order of the position, e.g. as they are credited on the cover
+CREATE TABLE IF NOT EXISTS item_authors (
+    item_id UUID NOT NULL,
+    author_id UUID NOT NULL,
+    position INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (item_id, author_id),
+    CONSTRAINT fk_item FOREIGN KEY(item_id) REFERENCES items(id) ON DELETE CASCADE,
+    -- authors of items must not be deleted, the items would lose their authors unnoticed
+    CONSTRAINT fk_author FOREIGN KEY(author_id) REFERENCES authors(id) ON DELETE RESTRICT
+);
+
+CREATE INDEX IF NOT EXISTS idx_item_authors_author ON item_authors (author_id);
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS carts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- guest carts do not belong to any account until they are merged into an account cart
+    account_id UUID,
+    CONSTRAINT fk_account FOREIGN KEY(account_id) REFERENCES accounts(id) ON DELETE CASCADE
+);
+
+-- every account has a single cart
+CREATE UNIQUE INDEX IF NOT EXISTS carts_account_idx ON carts (account_id);
+
+CREATE TABLE IF NOT EXISTS cart_items (
+    cart_id UUID NOT NULL,
+    item_id UUID NOT NULL,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    quantity INTEGER NOT NULL,
+    PRIMARY KEY (cart_id, item_id),
+    CONSTRAINT fk_cart FOREIGN KEY(cart_id) REFERENCES carts(id) ON DELETE CASCADE,
+    CONSTRAINT fk_item FOREIGN KEY(item_id) REFERENCES items(id) ON DELETE CASCADE,
+    CONSTRAINT chk_cart_items_quantity CHECK (quantity > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_cart_items_item ON cart_items (item_id);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    key VARCHAR(255) PRIMARY KEY,
+    -- hash of the caller and the request the key was first used with
+    fingerprint CHAR(64) NOT NULL,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at timestamp NOT NULL,
+    -- the response is stored once the request succeeded, requests in progress have none
+    status_code INTEGER,
+    content_type VARCHAR(255),
+    body BYTEA
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
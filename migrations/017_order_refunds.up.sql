@@ -0,0 +1,32 @@
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS cancellation_reason TEXT;
+
+CREATE TABLE IF NOT EXISTS refunds (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    order_id UUID NOT NULL,
+    actor_id UUID,
+    reason TEXT,
+    amount_amount BIGINT NOT NULL,
+    amount_currency CHAR(3) NOT NULL DEFAULT 'EUR',
+    CONSTRAINT fk_order FOREIGN KEY(order_id) REFERENCES orders(id) ON DELETE CASCADE,
+    CONSTRAINT fk_actor FOREIGN KEY(actor_id) REFERENCES accounts(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_refunds_order_id ON refunds(order_id, created_at);
+
+CREATE TABLE IF NOT EXISTS refund_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    refund_id UUID NOT NULL,
+    order_item_id UUID NOT NULL,
+    quantity INTEGER NOT NULL,
+    amount_amount BIGINT NOT NULL,
+    amount_currency CHAR(3) NOT NULL DEFAULT 'EUR',
+    CONSTRAINT fk_refund FOREIGN KEY(refund_id) REFERENCES refunds(id) ON DELETE CASCADE,
+    CONSTRAINT fk_order_item FOREIGN KEY(order_item_id) REFERENCES order_items(id) ON DELETE CASCADE,
+    CONSTRAINT chk_refund_items_quantity CHECK (quantity > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_refund_items_order_item_id ON refund_items(order_item_id);
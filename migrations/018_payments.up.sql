@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS payments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    order_id UUID NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    -- reference identifies the payment at the provider, payments declined by the provider do not have any
+    reference VARCHAR(255),
+    status VARCHAR(20) NOT NULL,
+    amount_amount BIGINT NOT NULL,
+    amount_currency CHAR(3) NOT NULL DEFAULT 'EUR',
+    captured_amount BIGINT NOT NULL DEFAULT 0,
+    refunded_amount BIGINT NOT NULL DEFAULT 0,
+    failure_reason TEXT,
+    CONSTRAINT fk_order FOREIGN KEY(order_id) REFERENCES orders(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS payments_provider_reference_idx ON payments (provider, reference);
+
+-- an order is paid by a single payment, declined, failed, voided and refunded payments do not count
+CREATE UNIQUE INDEX IF NOT EXISTS payments_order_active_idx ON payments (order_id) WHERE status IN ('authorized', 'captured');
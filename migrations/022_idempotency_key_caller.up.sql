@@ -0,0 +1,4 @@
+-- keys are chosen by clients, so every account gets its own namespace of keys; anonymous callers share the nil account
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS account_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000';
+ALTER TABLE idempotency_keys DROP CONSTRAINT IF EXISTS idempotency_keys_pkey;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (account_id, key);
@@ -0,0 +1,4 @@
+-- operation sent to the provider whose outcome is not recorded yet, it is sent again with the same key when resumed
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS pending_operation VARCHAR(20);
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS pending_amount BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS pending_key VARCHAR(64);
@@ -0,0 +1,6 @@
+-- failed events are retried with backoff and parked after too many attempts, so they do not hold up the later ones
+ALTER TABLE outbox_events ADD COLUMN IF NOT EXISTS next_attempt_at timestamp;
+ALTER TABLE outbox_events ADD COLUMN IF NOT EXISTS parked_at timestamp;
+
+DROP INDEX IF EXISTS outbox_events_unpublished_idx;
+CREATE INDEX IF NOT EXISTS outbox_events_unpublished_idx ON outbox_events (created_at) WHERE published_at IS NULL AND parked_at IS NULL;
@@ -9,12 +9,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/fmiskovic/new-amz/internal/core/services"
 	"github.com/fmiskovic/new-amz/internal/handlers"
 	"github.com/fmiskovic/new-amz/internal/repositories"
 	"github.com/fmiskovic/new-amz/internal/validators"
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
@@ -23,7 +25,9 @@ func (s *HandlersTestSuite) TestHandleCreateAccount() {
 	e.Validator = validators.New()
 
 	repo := repositories.NewAccountRepository(s.testDb.BunDb)
-	svc := services.NewAccountService(repo)
+	uow := services.NewUnitOfWork(repositories.NewTxManager(s.testDb.BunDb))
+	orderService := services.NewOrderService(repositories.NewOrderRepository(s.testDb.BunDb), uow)
+	svc := services.NewAccountService(repo, uow, orderService.Create)
 	handler := handlers.New(
 		mappers.NewCreateAccountRequestMapper(),
 		mappers.NewCreateAccountResponseMapper(),
@@ -34,6 +38,7 @@ func (s *HandlersTestSuite) TestHandleCreateAccount() {
 		// given
 		cmd := &dtos.CreateAccountCommand{
 			Email:       "fake@mail.com",
+			Password:    "secret-password",
 			FullName:    "Fake Account",
 			DateOfBirth: time.Now(),
 			Location:    "Vienna/AUT",
@@ -62,9 +67,47 @@ func (s *HandlersTestSuite) TestHandleCreateAccount() {
 		s.Equal(cmd.Email, answer.Email)
 	})
 
+	s.Run("should create account together with its first order", func() {
+		// given
+		body := `{"email":"first.order@mail.com","password":"secret-password",` +
+			`"order":[{"item_id":"200cea28-b2b0-4051-9eb6-9a99e451af01","quantity":1}]}`
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		resp := httptest.NewRecorder()
+
+		// when
+		err := handler.Handle(e.NewContext(req, resp))
+
+		// then
+		s.Require().NoError(err)
+		s.Equal(http.StatusCreated, resp.Code)
+		answer := new(dtos.CreateAccountAnswer)
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(answer))
+		s.Require().NotNil(answer.Order)
+		s.Equal(answer.ID, answer.Order.AccountID)
+	})
+
+	s.Run("when first order fails should not create account", func() {
+		// given more than is in stock
+		body := `{"email":"rolled.back@mail.com","password":"secret-password",` +
+			`"order":[{"item_id":"200cea28-b2b0-4051-9eb6-9a99e451af01","quantity":1000000}]}`
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		// when
+		err := handler.Handle(e.NewContext(req, httptest.NewRecorder()))
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusConflict, err.(*echo.HTTPError).Code)
+		_, err = repo.GetByEmail(s.testDb.Ctx, "rolled.back@mail.com")
+		s.ErrorIs(err, repositories.ErrNotFound)
+	})
+
 	s.Run("when email is empty should fail to create account", func() {
 		cmd := &dtos.CreateAccountCommand{
 			Email:       " ",
+			Password:    "secret-password",
 			FullName:    "Fake Account",
 			DateOfBirth: time.Now(),
 			Location:    "Vienna/AUT",
@@ -94,7 +137,7 @@ func (s *HandlersTestSuite) TestHandleGetAccountOrders() {
 	e.Validator = validators.New()
 
 	repo := repositories.NewOrderRepository(s.testDb.BunDb)
-	svc := services.NewOrderService(repo)
+	svc := services.NewOrderService(repo, services.NewUnitOfWork(repositories.NewTxManager(s.testDb.BunDb)))
 	handler := handlers.New(
 		mappers.NewOrderSearchRequestMapper(),
 		mappers.NewOrderSearchResponseMapper(),
@@ -110,6 +153,7 @@ func (s *HandlersTestSuite) TestHandleGetAccountOrders() {
 
 		req := httptest.NewRequest(http.MethodGet, "/?"+q.Encode(), nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req = authenticate(req, accountId)
 		resp := httptest.NewRecorder()
 		c := e.NewContext(req, resp)
 		c.SetPath("/account/:id/orders")
@@ -162,6 +206,7 @@ func (s *HandlersTestSuite) TestHandleGetAccountOrders() {
 
 		req := httptest.NewRequest(http.MethodGet, "/?"+q.Encode(), nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req = authenticate(req, accountId)
 		resp := httptest.NewRecorder()
 		c := e.NewContext(req, resp)
 		c.SetPath("/account/:id/orders")
@@ -178,7 +223,7 @@ func (s *HandlersTestSuite) TestHandleGetAccountOrders() {
 		err = json.NewDecoder(resp.Body).Decode(page)
 		s.NoError(err)
 		s.Empty(page.Elements)
-		s.Equal(0, page.TotalElements)
+		s.Equal(0, *page.TotalElements)
 	})
 }
 
@@ -187,7 +232,9 @@ func (s *HandlersTestSuite) TestHandleGetAccountById() {
 	e.Validator = validators.New()
 
 	repo := repositories.NewAccountRepository(s.testDb.BunDb)
-	svc := services.NewAccountService(repo)
+	uow := services.NewUnitOfWork(repositories.NewTxManager(s.testDb.BunDb))
+	orderService := services.NewOrderService(repositories.NewOrderRepository(s.testDb.BunDb), uow)
+	svc := services.NewAccountService(repo, uow, orderService.Create)
 	handler := handlers.New(
 		mappers.NewGetAccountByIdRequestMapper(),
 		mappers.NewGetAccountByIdResponseMapper(),
@@ -200,6 +247,7 @@ func (s *HandlersTestSuite) TestHandleGetAccountById() {
 
 		req := httptest.NewRequest(http.MethodGet, "/", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req = authenticate(req, accountId)
 		resp := httptest.NewRecorder()
 		c := e.NewContext(req, resp)
 		c.SetPath("/account/:id")
@@ -240,11 +288,32 @@ func (s *HandlersTestSuite) TestHandleGetAccountById() {
 		s.Equal(http.StatusBadRequest, err.(*echo.HTTPError).Code)
 	})
 
-	s.Run("should return 500 when account id is non-existing", func() {
+	s.Run("should return 403 when account id belongs to another account", func() {
+		// given
+		accountId := "220cea28-b2b0-4051-9eb6-9a99e451af02"
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req = authenticate(req, "220cea28-b2b0-4051-9eb6-9a99e451af01")
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+		c.SetPath("/account/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(accountId)
+
+		// when
+		err := handler.Handle(c)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusForbidden, err.(*echo.HTTPError).Code)
+	})
+
+	s.Run("should return 404 when account id is non-existing", func() {
 		// given
 		accountId := "220cea28-b2b0-4051-9eb6-9a99e451af11"
 		req := httptest.NewRequest(http.MethodGet, "/", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req = authenticate(req, accountId)
 		resp := httptest.NewRecorder()
 		c := e.NewContext(req, resp)
 		c.SetPath("/account/:id")
@@ -256,7 +325,133 @@ func (s *HandlersTestSuite) TestHandleGetAccountById() {
 
 		// then
 		s.NotNil(err)
-		s.Equal(http.StatusInternalServerError, err.(*echo.HTTPError).Code)
+		s.Equal(http.StatusNotFound, err.(*echo.HTTPError).Code)
 	})
 
 }
+
+func (s *HandlersTestSuite) TestHandleManageAccount() {
+	e := echo.New()
+	e.Validator = validators.New()
+
+	repo := repositories.NewAccountRepository(s.testDb.BunDb)
+	uow := services.NewUnitOfWork(repositories.NewTxManager(s.testDb.BunDb))
+	orderService := services.NewOrderService(repositories.NewOrderRepository(s.testDb.BunDb), uow)
+	svc := services.NewAccountService(repo, uow, orderService.Create)
+	createHandler := handlers.New(
+		mappers.NewCreateAccountRequestMapper(),
+		mappers.NewCreateAccountResponseMapper(),
+		svc.Create,
+	)
+	updateHandler := handlers.New(
+		mappers.NewUpdateAccountRequestMapper(),
+		mappers.NewGetAccountByIdResponseMapper(),
+		svc.Update,
+	)
+	patchHandler := handlers.New(
+		mappers.NewPatchAccountRequestMapper(),
+		mappers.NewGetAccountByIdResponseMapper(),
+		svc.Patch,
+	)
+	deleteHandler := handlers.New(
+		mappers.NewDeleteAccountRequestMapper(),
+		mappers.NewDeleteAccountResponseMapper(),
+		svc.Delete,
+	)
+
+	newContext := func(method, accountId, contentType, body string) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(method, "/", strings.NewReader(body))
+		if contentType != "" {
+			req.Header.Set(echo.HeaderContentType, contentType)
+		}
+		req.Header.Set("If-Match", s.etagOf((*entities.Account)(nil), accountId))
+		req = authenticate(req, accountId)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+		c.SetPath("/account/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(accountId)
+		return c, resp
+	}
+
+	// given
+	cmd := `{"email":"manage@mail.com","password":"secret-password","full_name":"Manage Me","location":"Berlin/DEU"}`
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(cmd))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	resp := httptest.NewRecorder()
+	s.Require().NoError(createHandler.Handle(e.NewContext(req, resp)))
+	created := new(dtos.CreateAccountAnswer)
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(created))
+
+	s.Run("should replace account profile", func() {
+		// given
+		body := `{"email":"manage@mail.com","full_name":"Managed","location":"Vienna/AUT","gender":"Other"}`
+		c, resp := newContext(http.MethodPut, created.ID, echo.MIMEApplicationJSON, body)
+
+		// when
+		err := updateHandler.Handle(c)
+
+		// then
+		s.NoError(err)
+		s.Equal(http.StatusOK, resp.Code)
+		dto := new(dtos.AccountDto)
+		s.NoError(json.NewDecoder(resp.Body).Decode(dto))
+		s.Equal("Managed", dto.FullName)
+		s.Equal("Vienna/AUT", dto.Location)
+	})
+
+	s.Run("should return 409 when email is taken", func() {
+		// given
+		body := `{"email":"john@smith.com"}`
+		c, _ := newContext(http.MethodPut, created.ID, echo.MIMEApplicationJSON, body)
+
+		// when
+		err := updateHandler.Handle(c)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusConflict, err.(*echo.HTTPError).Code)
+	})
+
+	s.Run("should patch account profile", func() {
+		// given
+		c, resp := newContext(http.MethodPatch, created.ID, "application/merge-patch+json", `{"location":null}`)
+
+		// when
+		err := patchHandler.Handle(c)
+
+		// then
+		s.NoError(err)
+		s.Equal(http.StatusOK, resp.Code)
+		dto := new(dtos.AccountDto)
+		s.NoError(json.NewDecoder(resp.Body).Decode(dto))
+		s.Equal("Managed", dto.FullName)
+		s.Empty(dto.Location)
+	})
+
+	s.Run("should return 415 when patch content type is not supported", func() {
+		// given
+		c, _ := newContext(http.MethodPatch, created.ID, "text/plain", `{"location":null}`)
+
+		// when
+		err := patchHandler.Handle(c)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusUnsupportedMediaType, err.(*echo.HTTPError).Code)
+	})
+
+	s.Run("should soft delete account", func() {
+		// given
+		c, resp := newContext(http.MethodDelete, created.ID, "", "")
+
+		// when
+		err := deleteHandler.Handle(c)
+
+		// then
+		s.NoError(err)
+		s.Equal(http.StatusNoContent, resp.Code)
+		_, err = repo.GetById(s.testDb.Ctx, uuid.MustParse(created.ID))
+		s.NotNil(err)
+	})
+}
@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/fmiskovic/new-amz/internal/auth"
+	"github.com/fmiskovic/new-amz/internal/core/dtos"
+	"github.com/fmiskovic/new-amz/internal/core/services"
+	"github.com/fmiskovic/new-amz/internal/handlers"
+	"github.com/fmiskovic/new-amz/internal/handlers/mappers"
+	"github.com/fmiskovic/new-amz/internal/repositories"
+	"github.com/fmiskovic/new-amz/internal/validators"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+func (s *HandlersTestSuite) TestHandleLogin() {
+	e := echo.New()
+	e.Validator = validators.New()
+
+	jwt := auth.NewJWT("secret", time.Minute)
+	repo := repositories.NewAccountRepository(s.testDb.BunDb)
+	svc := services.NewAuthService(repo, jwt)
+	handler := handlers.New(
+		mappers.NewAuthLoginRequestMapper(),
+		mappers.NewAuthLoginResponseMapper(),
+		svc.Login,
+	)
+
+	s.Run("should issue access token", func() {
+		// given
+		cmd := &dtos.LoginCommand{
+			Email:    "john@smith.com",
+			Password: "secret-password",
+		}
+		b, err := json.Marshal(cmd)
+		if err != nil {
+			s.Error(err)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(b))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err = handler.Handle(c)
+
+		// then
+		s.NoError(err)
+		s.Equal(http.StatusOK, resp.Code)
+		token := new(dtos.TokenDto)
+		err = json.NewDecoder(resp.Body).Decode(token)
+		s.NoError(err)
+
+		principal, err := jwt.Verify(token.AccessToken)
+		s.NoError(err)
+		s.Equal("220cea28-b2b0-4051-9eb6-9a99e451af01", principal.AccountID.String())
+	})
+
+	s.Run("should return 401 when password is wrong", func() {
+		// given
+		cmd := &dtos.LoginCommand{
+			Email:    "john@smith.com",
+			Password: "wrong-password",
+		}
+		b, err := json.Marshal(cmd)
+		if err != nil {
+			s.Error(err)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(b))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err = handler.Handle(c)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusUnauthorized, err.(*echo.HTTPError).Code)
+	})
+}
+
+func (s *HandlersTestSuite) TestAuthMiddleware() {
+	e := echo.New()
+	jwt := auth.NewJWT("secret", time.Minute)
+	handler := auth.Middleware(jwt, nil)(func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	s.Run("should reject request without access token", func() {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		err := handler(c)
+
+		s.NotNil(err)
+		s.Equal(http.StatusUnauthorized, err.(*echo.HTTPError).Code)
+	})
+
+	s.Run("should pass request with valid access token", func() {
+		accountRepo := repositories.NewAccountRepository(s.testDb.BunDb)
+		account, err := accountRepo.GetByEmail(s.testDb.Ctx, "john@smith.com")
+		s.NoError(err)
+		token, _, err := jwt.Issue(account)
+		s.NoError(err)
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		err = handler(c)
+
+		s.NoError(err)
+		s.Equal(http.StatusOK, resp.Code)
+	})
+}
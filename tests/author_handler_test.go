@@ -0,0 +1,276 @@
+package tests
+
+import (
+	"encoding/json"
+	"github.com/fmiskovic/new-amz/internal/core/dtos"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/core/services"
+	"github.com/fmiskovic/new-amz/internal/handlers"
+	"github.com/fmiskovic/new-amz/internal/handlers/mappers"
+	"github.com/fmiskovic/new-amz/internal/repositories"
+	"github.com/fmiskovic/new-amz/internal/validators"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+func (s *HandlersTestSuite) TestHandleGetAuthorItems() {
+	e := echo.New()
+	e.Validator = validators.New()
+
+	repo := repositories.NewItemRepository(s.testDb.BunDb)
+	svc := services.NewItemService(repo)
+	handler := handlers.New(
+		mappers.NewAuthorItemsRequestMapper(),
+		mappers.NewItemGetPageResponseMapper(),
+		svc.GetPageByAuthor,
+	)
+
+	s.Run("should return items the author is credited on", func() {
+		// given
+		req := httptest.NewRequest(http.MethodGet, "/?size=10&sort=name", nil)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+		c.SetPath("/author/:id/items")
+		c.SetParamNames("id")
+		c.SetParamValues("250cea28-b2b0-4051-9eb6-9a99e451af01")
+
+		// when
+		err := handler.Handle(c)
+
+		// then
+		s.NoError(err)
+		s.Equal(http.StatusOK, resp.Code)
+		page := new(entities.Page[dtos.ItemDto])
+		s.NoError(json.NewDecoder(resp.Body).Decode(page))
+		s.Equal(2, *page.TotalElements)
+		s.Equal("Cool Book 1", page.Elements[0].Title)
+		s.Equal("Cool Book 2", page.Elements[1].Title)
+	})
+
+	s.Run("should return 404 when author does not exist", func() {
+		// given
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+		c.SetPath("/author/:id/items")
+		c.SetParamNames("id")
+		c.SetParamValues("250cea28-b2b0-4051-9eb6-9a99e451af99")
+
+		// when
+		err := handler.Handle(c)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusNotFound, err.(*echo.HTTPError).Code)
+	})
+}
+
+func (s *HandlersTestSuite) TestHandleManageAuthor() {
+	e := echo.New()
+	e.Validator = validators.New()
+
+	repo := repositories.NewAuthorRepository(s.testDb.BunDb)
+	svc := services.NewAuthorService(repo)
+	createHandler := handlers.New(
+		mappers.NewAuthorCreateRequestMapper(),
+		mappers.NewAuthorCreateResponseMapper(),
+		svc.Create,
+	)
+	updateHandler := handlers.New(
+		mappers.NewAuthorUpdateRequestMapper(),
+		mappers.NewAuthorGetByIdResponseMapper(),
+		svc.Update,
+	)
+	deleteHandler := handlers.New(
+		mappers.NewAuthorDeleteRequestMapper(),
+		mappers.NewAuthorDeleteResponseMapper(),
+		svc.DeleteById,
+	)
+	getHandler := handlers.New(
+		mappers.NewAuthorGetByIdRequestMapper(),
+		mappers.NewAuthorGetByIdResponseMapper(),
+		svc.GetById,
+	)
+
+	s.Run("admin should create, update and delete author", func() {
+		// given
+		body := `{"name":"New Author"}`
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req = authenticateAdmin(req)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err := createHandler.Handle(c)
+
+		// then
+		s.NoError(err)
+		s.Equal(http.StatusCreated, resp.Code)
+		created := new(dtos.AuthorDto)
+		s.NoError(json.NewDecoder(resp.Body).Decode(created))
+		s.NotEmpty(created.ID)
+		etag := resp.Header().Get("ETag")
+		s.Equal(`"1"`, etag)
+
+		// given
+		body = `{"name":"Renamed Author","biography":"Wrote a book at last"}`
+		req = httptest.NewRequest(http.MethodPut, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("If-Match", etag)
+		req = authenticateAdmin(req)
+		resp = httptest.NewRecorder()
+		c = e.NewContext(req, resp)
+		c.SetPath("/author/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(created.ID)
+
+		// when
+		err = updateHandler.Handle(c)
+
+		// then
+		s.NoError(err)
+		s.Equal(http.StatusOK, resp.Code)
+		updated := new(dtos.AuthorDto)
+		s.NoError(json.NewDecoder(resp.Body).Decode(updated))
+		s.Equal("Renamed Author", updated.Name)
+		s.Equal("Wrote a book at last", updated.Biography)
+		s.Equal(`"2"`, resp.Header().Get("ETag"))
+
+		// given
+		req = httptest.NewRequest(http.MethodDelete, "/", nil)
+		req.Header.Set("If-Match", resp.Header().Get("ETag"))
+		req = authenticateAdmin(req)
+		resp = httptest.NewRecorder()
+		c = e.NewContext(req, resp)
+		c.SetPath("/author/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(created.ID)
+
+		// when
+		err = deleteHandler.Handle(c)
+
+		// then
+		s.NoError(err)
+		s.Equal(http.StatusNoContent, resp.Code)
+	})
+
+	s.Run("should return 409 when deleting author credited on items", func() {
+		// given
+		req := httptest.NewRequest(http.MethodDelete, "/", nil)
+		req.Header.Set("If-Match", s.etagOf((*entities.Author)(nil), "250cea28-b2b0-4051-9eb6-9a99e451af01"))
+		req = authenticateAdmin(req)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+		c.SetPath("/author/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("250cea28-b2b0-4051-9eb6-9a99e451af01")
+
+		// when
+		err := deleteHandler.Handle(c)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusConflict, err.(*echo.HTTPError).Code)
+	})
+
+	s.Run("should answer conditional requests by ETag", func() {
+		// given
+		author := entities.NewAuthorBuilder().Name("Conditional Author").Build()
+		s.Require().Nil(repo.Create(s.testDb.Ctx, author))
+		newContext := func(method string, body string, header string, value string) (echo.Context, *httptest.ResponseRecorder) {
+			req := httptest.NewRequest(method, "/", strings.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			if value != "" {
+				req.Header.Set(header, value)
+			}
+			resp := httptest.NewRecorder()
+			c := e.NewContext(authenticateAdmin(req), resp)
+			c.SetPath("/author/:id")
+			c.SetParamNames("id")
+			c.SetParamValues(author.ID.String())
+			return c, resp
+		}
+
+		// when
+		c, resp := newContext(http.MethodGet, "", "", "")
+		err := getHandler.Handle(c)
+		// then
+		s.Require().NoError(err)
+		etag := resp.Header().Get("ETag")
+		s.NotEmpty(etag)
+
+		// when the representation has not changed
+		c, resp = newContext(http.MethodGet, "", "If-None-Match", etag)
+		err = getHandler.Handle(c)
+		// then
+		s.NoError(err)
+		s.Equal(http.StatusNotModified, resp.Code)
+		s.Empty(resp.Body.String())
+		s.Equal(etag, resp.Header().Get("ETag"))
+
+		// when updating without If-Match
+		c, _ = newContext(http.MethodPut, `{"name":"Lost Update"}`, "", "")
+		err = updateHandler.Handle(c)
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusPreconditionRequired, err.(*echo.HTTPError).Code)
+
+		// when updating with If-Match matching any version
+		c, _ = newContext(http.MethodPut, `{"name":"Lost Update"}`, "If-Match", "*")
+		err = updateHandler.Handle(c)
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusPreconditionRequired, err.(*echo.HTTPError).Code)
+
+		// when
+		c, resp = newContext(http.MethodPut, `{"name":"First Update"}`, "If-Match", etag)
+		err = updateHandler.Handle(c)
+		// then
+		s.Require().NoError(err)
+		s.NotEqual(etag, resp.Header().Get("ETag"))
+
+		// when updating with stale ETag
+		c, _ = newContext(http.MethodPut, `{"name":"Lost Update"}`, "If-Match", etag)
+		err = updateHandler.Handle(c)
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusPreconditionFailed, err.(*echo.HTTPError).Code)
+
+		// when deleting with stale ETag
+		c, _ = newContext(http.MethodDelete, "", "If-Match", etag)
+		err = deleteHandler.Handle(c)
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusPreconditionFailed, err.(*echo.HTTPError).Code)
+
+		// when the representation has changed
+		c, resp = newContext(http.MethodGet, "", "If-None-Match", etag)
+		err = getHandler.Handle(c)
+		// then
+		s.NoError(err)
+		s.Equal(http.StatusOK, resp.Code)
+		found := new(dtos.AuthorDto)
+		s.NoError(json.NewDecoder(resp.Body).Decode(found))
+		s.Equal("First Update", found.Name)
+	})
+
+	s.Run("should return 403 when caller is not admin", func() {
+		// given
+		body := `{"name":"New Author"}`
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req = authenticate(req, "220cea28-b2b0-4051-9eb6-9a99e451af01")
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err := createHandler.Handle(c)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusForbidden, err.(*echo.HTTPError).Code)
+	})
+}
@@ -0,0 +1,231 @@
+package tests
+
+import (
+	"encoding/json"
+	"github.com/fmiskovic/new-amz/internal/core/dtos"
+	"github.com/fmiskovic/new-amz/internal/core/services"
+	"github.com/fmiskovic/new-amz/internal/handlers"
+	"github.com/fmiskovic/new-amz/internal/handlers/mappers"
+	"github.com/fmiskovic/new-amz/internal/repositories"
+	"github.com/fmiskovic/new-amz/internal/validators"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+func (s *HandlersTestSuite) newCartService() services.CartService {
+	orderService := services.NewOrderService(repositories.NewOrderRepository(s.testDb.BunDb), services.NewUnitOfWork(repositories.NewTxManager(s.testDb.BunDb)))
+	return services.NewCartService(repositories.NewCartRepository(s.testDb.BunDb), orderService.Create)
+}
+
+func (s *HandlersTestSuite) TestHandleGuestCart() {
+	e := echo.New()
+	e.Validator = validators.New()
+
+	svc := s.newCartService()
+	createHandler := handlers.New(
+		mappers.NewCartCreateGuestRequestMapper(),
+		mappers.NewCartCreateGuestResponseMapper(),
+		svc.CreateGuest,
+	)
+	addItemHandler := handlers.New(
+		mappers.NewCartAddItemRequestMapper(),
+		mappers.NewCartGetResponseMapper(),
+		svc.AddItem,
+	)
+	removeItemHandler := handlers.New(
+		mappers.NewCartRemoveItemRequestMapper(),
+		mappers.NewCartGetResponseMapper(),
+		svc.RemoveItem,
+	)
+
+	s.Run("guest should create cart and add and remove items", func() {
+		// given
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err := createHandler.Handle(c)
+
+		// then
+		s.Require().NoError(err)
+		s.Equal(http.StatusCreated, resp.Code)
+		cart := new(dtos.CartDto)
+		s.NoError(json.NewDecoder(resp.Body).Decode(cart))
+		s.Empty(cart.AccountID)
+		s.Empty(cart.Items)
+
+		// given
+		body := `{"item_id":"200cea28-b2b0-4051-9eb6-9a99e451af01","quantity":2}`
+		req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		resp = httptest.NewRecorder()
+		c = e.NewContext(req, resp)
+		c.SetPath("/cart/guest/:id/items")
+		c.SetParamNames("id")
+		c.SetParamValues(cart.ID)
+
+		// when
+		err = addItemHandler.Handle(c)
+
+		// then
+		s.Require().NoError(err)
+		s.Equal(http.StatusOK, resp.Code)
+		cart = new(dtos.CartDto)
+		s.NoError(json.NewDecoder(resp.Body).Decode(cart))
+		s.Require().Len(cart.Items, 1)
+		s.Equal("Cool Book 1", cart.Items[0].Title)
+		s.Equal("15.00", cart.Items[0].LineTotal.Decimal())
+		s.True(cart.Items[0].InStock)
+
+		// given
+		req = httptest.NewRequest(http.MethodDelete, "/", nil)
+		resp = httptest.NewRecorder()
+		c = e.NewContext(req, resp)
+		c.SetPath("/cart/guest/:id/items/:itemId")
+		c.SetParamNames("id", "itemId")
+		c.SetParamValues(cart.ID, "200cea28-b2b0-4051-9eb6-9a99e451af01")
+
+		// when
+		err = removeItemHandler.Handle(c)
+
+		// then
+		s.Require().NoError(err)
+		cart = new(dtos.CartDto)
+		s.NoError(json.NewDecoder(resp.Body).Decode(cart))
+		s.Empty(cart.Items)
+	})
+
+	s.Run("should return 400 for invalid quantity", func() {
+		// given
+		body := `{"item_id":"200cea28-b2b0-4051-9eb6-9a99e451af01","quantity":0}`
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+		c.SetPath("/cart/guest/:id/items")
+		c.SetParamNames("id")
+		c.SetParamValues("270cea28-b2b0-4051-9eb6-9a99e451af02")
+
+		// when
+		err := addItemHandler.Handle(c)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	})
+
+	s.Run("should return 409 if item does not have enough units in stock", func() {
+		// given
+		body := `{"item_id":"200cea28-b2b0-4051-9eb6-9a99e451af03","quantity":1000}`
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+		c.SetPath("/cart/guest/:id/items")
+		c.SetParamNames("id")
+		c.SetParamValues("270cea28-b2b0-4051-9eb6-9a99e451af02")
+
+		// when
+		err := addItemHandler.Handle(c)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusConflict, err.(*echo.HTTPError).Code)
+	})
+
+	s.Run("should return 404 for unknown guest cart", func() {
+		// given
+		body := `{"item_id":"200cea28-b2b0-4051-9eb6-9a99e451af01","quantity":1}`
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+		c.SetPath("/cart/guest/:id/items")
+		c.SetParamNames("id")
+		c.SetParamValues("270cea28-b2b0-4051-9eb6-9a99e451af99")
+
+		// when
+		err := addItemHandler.Handle(c)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusNotFound, err.(*echo.HTTPError).Code)
+	})
+}
+
+func (s *HandlersTestSuite) TestHandleMergeAndCheckoutCart() {
+	e := echo.New()
+	e.Validator = validators.New()
+
+	svc := s.newCartService()
+	mergeHandler := handlers.New(
+		mappers.NewCartMergeRequestMapper(),
+		mappers.NewCartGetResponseMapper(),
+		svc.Merge,
+	)
+	checkoutHandler := handlers.New(
+		mappers.NewCartGetRequestMapper(),
+		mappers.NewOrderCreateResponseMapper(),
+		svc.Checkout,
+	)
+	accountId := "220cea28-b2b0-4051-9eb6-9a99e451af01"
+
+	s.Run("should merge guest cart into account cart after login", func() {
+		// given
+		body := `{"cart_id":"270cea28-b2b0-4051-9eb6-9a99e451af02"}`
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req = authenticate(req, accountId)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err := mergeHandler.Handle(c)
+
+		// then
+		s.Require().NoError(err)
+		s.Equal(http.StatusOK, resp.Code)
+		cart := new(dtos.CartDto)
+		s.NoError(json.NewDecoder(resp.Body).Decode(cart))
+		s.Equal(accountId, cart.AccountID)
+		quantities := map[string]int{}
+		for _, item := range cart.Items {
+			quantities[item.ItemID] = item.Quantity
+		}
+		s.Equal(map[string]int{
+			"200cea28-b2b0-4051-9eb6-9a99e451af01": 2,
+			"200cea28-b2b0-4051-9eb6-9a99e451af02": 2,
+			"200cea28-b2b0-4051-9eb6-9a99e451af03": 3,
+		}, quantities)
+	})
+
+	s.Run("should place order and empty the cart", func() {
+		// given
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req = authenticate(req, accountId)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err := checkoutHandler.Handle(c)
+
+		// then
+		s.Require().NoError(err)
+		s.Equal(http.StatusCreated, resp.Code)
+		answer := new(dtos.CreateOrderAnswer)
+		s.NoError(json.NewDecoder(resp.Body).Decode(answer))
+		s.Equal(accountId, answer.AccountID)
+		s.Len(answer.Items, 3)
+		s.Equal("pending", answer.Status)
+
+		// and then checking out again
+		resp = httptest.NewRecorder()
+		c = e.NewContext(req, resp)
+		err = checkoutHandler.Handle(c)
+		s.NotNil(err)
+		s.Equal(http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	})
+}
@@ -0,0 +1,285 @@
+package tests
+
+import (
+	"encoding/json"
+	"github.com/fmiskovic/new-amz/internal/core/dtos"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/core/services"
+	"github.com/fmiskovic/new-amz/internal/handlers"
+	"github.com/fmiskovic/new-amz/internal/handlers/mappers"
+	"github.com/fmiskovic/new-amz/internal/repositories"
+	"github.com/fmiskovic/new-amz/internal/validators"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+func (s *HandlersTestSuite) TestHandleGetCategories() {
+	e := echo.New()
+	e.Validator = validators.New()
+
+	repo := repositories.NewCategoryRepository(s.testDb.BunDb)
+	svc := services.NewCategoryService(repo)
+	topLevelHandler := handlers.New(
+		mappers.NewCategoryGetTopLevelRequestMapper(),
+		mappers.NewCategoryListResponseMapper(),
+		svc.GetTopLevel,
+	)
+	subtreeHandler := handlers.New(
+		mappers.NewCategoryGetByIdRequestMapper(),
+		mappers.NewCategoryGetByIdResponseMapper(),
+		svc.GetSubtree,
+	)
+
+	s.Run("should return top level categories", func() {
+		// given
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err := topLevelHandler.Handle(c)
+
+		// then
+		s.NoError(err)
+		s.Equal(http.StatusOK, resp.Code)
+		var categories []dtos.CategoryDto
+		s.NoError(json.NewDecoder(resp.Body).Decode(&categories))
+		s.Len(categories, 2)
+		s.Equal("Fiction", categories[0].Name)
+		s.Empty(categories[0].Children)
+	})
+
+	s.Run("should return category subtree", func() {
+		// given
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+		c.SetPath("/category/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("240cea28-b2b0-4051-9eb6-9a99e451af01")
+
+		// when
+		err := subtreeHandler.Handle(c)
+
+		// then
+		s.NoError(err)
+		s.Equal(http.StatusOK, resp.Code)
+		fiction := new(dtos.CategoryDto)
+		s.NoError(json.NewDecoder(resp.Body).Decode(fiction))
+		s.Len(fiction.Children, 2)
+		s.Equal("Fantasy", fiction.Children[0].Name)
+		s.Equal("Epic Fantasy", fiction.Children[0].Children[0].Name)
+	})
+
+	s.Run("given unknown category should return 404", func() {
+		// given
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+		c.SetPath("/category/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("240cea28-b2b0-4051-9eb6-9a99e451af99")
+
+		// when
+		err := subtreeHandler.Handle(c)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusNotFound, err.(*echo.HTTPError).Code)
+	})
+}
+
+func (s *HandlersTestSuite) TestHandleManageCategory() {
+	e := echo.New()
+	e.Validator = validators.New()
+
+	repo := repositories.NewCategoryRepository(s.testDb.BunDb)
+	svc := services.NewCategoryService(repo)
+	createHandler := handlers.New(
+		mappers.NewCategoryCreateRequestMapper(),
+		mappers.NewCategoryCreateResponseMapper(),
+		svc.Create,
+	)
+	updateHandler := handlers.New(
+		mappers.NewCategoryUpdateRequestMapper(),
+		mappers.NewCategoryGetByIdResponseMapper(),
+		svc.Update,
+	)
+	deleteHandler := handlers.New(
+		mappers.NewCategoryDeleteRequestMapper(),
+		mappers.NewCategoryDeleteResponseMapper(),
+		svc.DeleteById,
+	)
+
+	s.Run("admin should create and delete subcategory", func() {
+		// given
+		body := `{"name":"Horror","parent_id":"240cea28-b2b0-4051-9eb6-9a99e451af01"}`
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req = authenticateAdmin(req)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err := createHandler.Handle(c)
+
+		// then
+		s.NoError(err)
+		s.Equal(http.StatusCreated, resp.Code)
+		created := new(dtos.CategoryDto)
+		s.NoError(json.NewDecoder(resp.Body).Decode(created))
+		s.NotEmpty(created.ID)
+		s.Equal("240cea28-b2b0-4051-9eb6-9a99e451af01", created.ParentID)
+
+		// given
+		req = httptest.NewRequest(http.MethodDelete, "/", nil)
+		req.Header.Set("If-Match", resp.Header().Get("ETag"))
+		req = authenticateAdmin(req)
+		resp = httptest.NewRecorder()
+		c = e.NewContext(req, resp)
+		c.SetPath("/category/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(created.ID)
+
+		// when
+		err = deleteHandler.Handle(c)
+
+		// then
+		s.NoError(err)
+		s.Equal(http.StatusNoContent, resp.Code)
+	})
+
+	s.Run("should return 409 when name is taken within the parent", func() {
+		// given
+		body := `{"name":"FANTASY","parent_id":"240cea28-b2b0-4051-9eb6-9a99e451af01"}`
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req = authenticateAdmin(req)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err := createHandler.Handle(c)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusConflict, err.(*echo.HTTPError).Code)
+	})
+
+	s.Run("should return 400 when category is moved under its subcategory", func() {
+		// given
+		body := `{"name":"Fiction","parent_id":"240cea28-b2b0-4051-9eb6-9a99e451af03"}`
+		req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(body))
+		req.Header.Set("If-Match", s.etagOf((*entities.Category)(nil), "240cea28-b2b0-4051-9eb6-9a99e451af01"))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req = authenticateAdmin(req)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+		c.SetPath("/category/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("240cea28-b2b0-4051-9eb6-9a99e451af01")
+
+		// when
+		err := updateHandler.Handle(c)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	})
+
+	s.Run("should return 409 when deleting category with subcategories", func() {
+		// given
+		req := httptest.NewRequest(http.MethodDelete, "/", nil)
+		req.Header.Set("If-Match", s.etagOf((*entities.Category)(nil), "240cea28-b2b0-4051-9eb6-9a99e451af02"))
+		req = authenticateAdmin(req)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+		c.SetPath("/category/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("240cea28-b2b0-4051-9eb6-9a99e451af02")
+
+		// when
+		err := deleteHandler.Handle(c)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusConflict, err.(*echo.HTTPError).Code)
+	})
+
+	s.Run("should return 403 when caller is not admin", func() {
+		// given
+		body := `{"name":"Horror"}`
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req = authenticate(req, "220cea28-b2b0-4051-9eb6-9a99e451af01")
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err := createHandler.Handle(c)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusForbidden, err.(*echo.HTTPError).Code)
+	})
+}
+
+func (s *HandlersTestSuite) TestHandleAssignItemCategories() {
+	e := echo.New()
+	e.Validator = validators.New()
+
+	repo := repositories.NewCategoryRepository(s.testDb.BunDb)
+	svc := services.NewCategoryService(repo)
+	assignHandler := handlers.New(
+		mappers.NewItemAssignCategoriesRequestMapper(),
+		mappers.NewCategoryListResponseMapper(),
+		svc.AssignItem,
+	)
+	itemId := "200cea28-b2b0-4051-9eb6-9a99e451af05"
+
+	assign := func(body string) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req = authenticateAdmin(req)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+		c.SetPath("/item/:id/categories")
+		c.SetParamNames("id")
+		c.SetParamValues(itemId)
+		return resp, assignHandler.Handle(c)
+	}
+	defer func() { _, _ = assign(`{"category_ids":[]}`) }()
+
+	s.Run("admin should assign categories to the item", func() {
+		// when
+		resp, err := assign(`{"category_ids":["240cea28-b2b0-4051-9eb6-9a99e451af04"]}`)
+
+		// then
+		s.NoError(err)
+		s.Equal(http.StatusOK, resp.Code)
+		var categories []dtos.CategoryDto
+		s.NoError(json.NewDecoder(resp.Body).Decode(&categories))
+		s.Len(categories, 1)
+		s.Equal("Science Fiction", categories[0].Name)
+	})
+
+	s.Run("should return 400 when category id is invalid", func() {
+		// when
+		_, err := assign(`{"category_ids":["fiction"]}`)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	})
+
+	s.Run("should return 404 when category does not exist", func() {
+		// when
+		_, err := assign(`{"category_ids":["240cea28-b2b0-4051-9eb6-9a99e451af99"]}`)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusNotFound, err.(*echo.HTTPError).Code)
+	})
+}
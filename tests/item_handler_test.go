@@ -13,6 +13,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 )
 
 func (s *HandlersTestSuite) TestHandleGetItemsPage() {
@@ -48,6 +49,84 @@ func (s *HandlersTestSuite) TestHandleGetItemsPage() {
 		s.NotEmpty(page.Elements[0].ID)
 	})
 
+	s.Run("given category should return items of the category and its subcategories", func() {
+		// given
+		q := make(url.Values)
+		q.Set("category", "240cea28-b2b0-4051-9eb6-9a99e451af02")
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/item?"+q.Encode(), nil)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err := handler.Handle(c)
+
+		// then
+		s.NoError(err)
+		page := &entities.Page[dtos.ItemDto]{}
+		s.NoError(json.NewDecoder(resp.Body).Decode(page))
+		s.Equal(2, *page.TotalElements)
+		s.Contains(resp.Header().Get("Link"), "category=240cea28-b2b0-4051-9eb6-9a99e451af02")
+	})
+
+	s.Run("given invalid category should return 400", func() {
+		// given
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/item?category=fiction", nil)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err := handler.Handle(c)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	})
+
+	s.Run("given out of range page request should return 400", func() {
+		for _, query := range []string{"size=0", "size=101", "size=-1", "offset=-10", "size=ten"} {
+			// given
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/item?"+query, nil)
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			// when
+			err := handler.Handle(c)
+
+			// then
+			s.NotNil(err, query)
+			s.Equal(http.StatusBadRequest, err.(*echo.HTTPError).Code, query)
+		}
+	})
+
+	s.Run("given second page request should link adjacent pages", func() {
+		// given
+		q := make(url.Values)
+		q.Set("size", "2")
+		q.Set("offset", "2")
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/item?"+q.Encode(), nil)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err := handler.Handle(c)
+
+		// then
+		s.NoError(err)
+		page := &entities.Page[dtos.ItemDto]{}
+		err = json.NewDecoder(resp.Body).Decode(page)
+		s.NoError(err)
+		s.Equal(1, page.Number)
+		s.Equal(2, page.Size)
+		s.Equal(3, *page.TotalPages)
+		s.True(page.HasNext)
+		s.True(page.HasPrev)
+		s.Equal(`<http://example.com/api/v1/item?offset=0&size=2>; rel="first", `+
+			`<http://example.com/api/v1/item?offset=0&size=2>; rel="prev", `+
+			`<http://example.com/api/v1/item?offset=4&size=2>; rel="next", `+
+			`<http://example.com/api/v1/item?offset=4&size=2>; rel="last"`, resp.Header().Get("Link"))
+	})
+
 	s.Run("given page request should return items page", func() {
 		// given
 		q := make(url.Values)
@@ -72,6 +151,272 @@ func (s *HandlersTestSuite) TestHandleGetItemsPage() {
 		s.NotEmpty(page.Elements)
 		s.NotEmpty(page.Elements[0].ID)
 	})
+
+	s.Run("given sort by price should return items page sorted by price", func() {
+		// given
+		q := make(url.Values)
+		q.Set("sort", "price desc, title")
+
+		req := httptest.NewRequest(http.MethodGet, "/?"+q.Encode(), nil)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err := handler.Handle(c)
+
+		// then
+		s.NoError(err)
+		page := &entities.Page[dtos.ItemDto]{}
+		err = json.NewDecoder(resp.Body).Decode(page)
+		s.NoError(err)
+		for i := 1; i < len(page.Elements); i++ {
+			s.GreaterOrEqual(page.Elements[i-1].Price.Amount, page.Elements[i].Price.Amount)
+		}
+	})
+
+	s.Run("given cursor should return cursor page", func() {
+		// given
+		q := make(url.Values)
+		q.Set("size", "2")
+		q.Set("cursor", "")
+		q.Set("count", "false")
+
+		req := httptest.NewRequest(http.MethodGet, "/?"+q.Encode(), nil)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err := handler.Handle(c)
+
+		// then
+		s.NoError(err)
+		page := &entities.CursorPage[dtos.ItemDto]{}
+		err = json.NewDecoder(resp.Body).Decode(page)
+		s.NoError(err)
+		s.Len(page.Elements, 2)
+		s.NotEmpty(page.Next)
+		s.Empty(page.Prev)
+		s.True(page.HasNext)
+		s.Nil(page.TotalElements)
+		s.Contains(resp.Header().Get("Link"), `rel="next"`)
+	})
+
+	s.Run("given invalid cursor should return 400", func() {
+		// given
+		q := make(url.Values)
+		q.Set("cursor", "bogus")
+
+		req := httptest.NewRequest(http.MethodGet, "/?"+q.Encode(), nil)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err := handler.Handle(c)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	})
+
+	s.Run("given non sortable property should return 400", func() {
+		// given
+		q := make(url.Values)
+		q.Set("sort", "(SELECT 1) DESC")
+
+		req := httptest.NewRequest(http.MethodGet, "/?"+q.Encode(), nil)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err := handler.Handle(c)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	})
+
+	s.Run("given unsupported direction should return 400", func() {
+		// given
+		q := make(url.Values)
+		q.Set("sort", "title DOWN")
+
+		req := httptest.NewRequest(http.MethodGet, "/?"+q.Encode(), nil)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err := handler.Handle(c)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	})
+}
+
+func (s *HandlersTestSuite) TestHandleSearchItems() {
+	e := echo.New()
+	e.Validator = validators.New()
+
+	repo := repositories.NewItemRepository(s.testDb.BunDb)
+	svc := services.NewItemService(repo)
+	handler := handlers.New(
+		mappers.NewItemSearchRequestMapper(),
+		mappers.NewItemSearchResponseMapper(),
+		svc.Search,
+	)
+
+	s.Run("should return matching items", func() {
+		// given
+		q := make(url.Values)
+		q.Set("q", "cool")
+		q.Set("size", "2")
+
+		req := httptest.NewRequest(http.MethodGet, "/?"+q.Encode(), nil)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err := handler.Handle(c)
+
+		// then
+		s.NoError(err)
+		s.Equal(http.StatusOK, resp.Code)
+		page := &entities.Page[dtos.ItemMatchDto]{}
+		err = json.NewDecoder(resp.Body).Decode(page)
+		s.NoError(err)
+		s.Len(page.Elements, 2)
+		s.True(page.HasNext)
+		s.NotEmpty(page.Elements[0].ID)
+		s.Contains(page.Elements[0].Highlight.Title, "<mark>Cool</mark>")
+	})
+
+	s.Run("given fuzzy mode should return items with similar names", func() {
+		// given
+		q := make(url.Values)
+		q.Set("q", "Kool Bok 2")
+		q.Set("mode", "fuzzy")
+
+		req := httptest.NewRequest(http.MethodGet, "/?"+q.Encode(), nil)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err := handler.Handle(c)
+
+		// then
+		s.NoError(err)
+		page := &entities.Page[dtos.ItemMatchDto]{}
+		err = json.NewDecoder(resp.Body).Decode(page)
+		s.NoError(err)
+		s.NotEmpty(page.Elements)
+		s.Equal("Cool Book 2", page.Elements[0].Title)
+		s.Nil(page.Elements[0].Highlight)
+	})
+
+	s.Run("given unknown mode should return 400", func() {
+		// given
+		q := make(url.Values)
+		q.Set("q", "cool")
+		q.Set("mode", "magic")
+
+		req := httptest.NewRequest(http.MethodGet, "/?"+q.Encode(), nil)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err := handler.Handle(c)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	})
+
+	s.Run("given no query should return 400", func() {
+		// given
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err := handler.Handle(c)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	})
+
+	s.Run("given sort should return 400", func() {
+		// given
+		q := make(url.Values)
+		q.Set("q", "cool")
+		q.Set("sort", "price DESC")
+
+		req := httptest.NewRequest(http.MethodGet, "/?"+q.Encode(), nil)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err := handler.Handle(c)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	})
+}
+
+func (s *HandlersTestSuite) TestHandleSuggestItems() {
+	e := echo.New()
+	e.Validator = validators.New()
+
+	repo := repositories.NewItemRepository(s.testDb.BunDb)
+	svc := services.NewItemService(repo)
+	handler := handlers.New(
+		mappers.NewItemSuggestRequestMapper(),
+		mappers.NewItemSuggestResponseMapper(),
+		svc.Suggest,
+	)
+
+	s.Run("should suggest closest item names", func() {
+		// given
+		q := make(url.Values)
+		q.Set("q", "cool bok 1")
+		q.Set("limit", "2")
+
+		req := httptest.NewRequest(http.MethodGet, "/?"+q.Encode(), nil)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err := handler.Handle(c)
+
+		// then
+		s.NoError(err)
+		s.Equal(http.StatusOK, resp.Code)
+		got := dtos.ItemSuggestionsDto{}
+		err = json.NewDecoder(resp.Body).Decode(&got)
+		s.NoError(err)
+		s.Equal("cool bok 1", got.Query)
+		s.Len(got.Suggestions, 2)
+		s.Equal("Cool Book 1", got.Suggestions[0])
+	})
+
+	s.Run("given limit out of range should return 400", func() {
+		// given
+		q := make(url.Values)
+		q.Set("q", "cool")
+		q.Set("limit", "100")
+
+		req := httptest.NewRequest(http.MethodGet, "/?"+q.Encode(), nil)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err := handler.Handle(c)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	})
 }
 
 func (s *HandlersTestSuite) TestHandleGetItemById() {
@@ -132,3 +477,304 @@ func (s *HandlersTestSuite) TestHandleGetItemById() {
 		s.Equal(http.StatusBadRequest, err.(*echo.HTTPError).Code)
 	})
 }
+
+func (s *HandlersTestSuite) TestHandleGetItemByISBN() {
+	e := echo.New()
+	e.Validator = validators.New()
+
+	repo := repositories.NewItemRepository(s.testDb.BunDb)
+	svc := services.NewItemService(repo)
+	handler := handlers.New(
+		mappers.NewItemGetByISBNRequestMapper(),
+		mappers.NewItemGetByIdResponseMapper(),
+		svc.GetByISBN,
+	)
+
+	s.Run("should get book by its ISBN-10", func() {
+		// given
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+		c.SetPath("/item/isbn/:isbn")
+		c.SetParamNames("isbn")
+		c.SetParamValues("0-306-40615-2")
+
+		// when
+		err := handler.Handle(c)
+
+		// then
+		s.NoError(err)
+		s.Equal(http.StatusOK, resp.Code)
+		dto := new(dtos.ItemDto)
+		s.NoError(json.NewDecoder(resp.Body).Decode(dto))
+		s.Equal("200cea28-b2b0-4051-9eb6-9a99e451af01", dto.ID)
+		s.Equal("9780306406157", dto.ISBN)
+		s.Equal("2001-05-01", dto.PublicationDate)
+		s.Equal("Cool Press", dto.Publisher.Name)
+		s.Len(dto.Authors, 2)
+		s.Equal("Jane Writer", dto.Authors[0].Name)
+	})
+
+	s.Run("should return 400 when isbn check digit is wrong", func() {
+		// given
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+		c.SetPath("/item/isbn/:isbn")
+		c.SetParamNames("isbn")
+		c.SetParamValues("978-0-306-40615-8")
+
+		// when
+		err := handler.Handle(c)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	})
+
+	s.Run("should return 404 when no item has the isbn", func() {
+		// given
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+		c.SetPath("/item/isbn/:isbn")
+		c.SetParamNames("isbn")
+		c.SetParamValues("9780747532699")
+
+		// when
+		err := handler.Handle(c)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusNotFound, err.(*echo.HTTPError).Code)
+	})
+}
+
+func (s *HandlersTestSuite) TestHandleManageItem() {
+	e := echo.New()
+	e.Validator = validators.New()
+
+	repo := repositories.NewItemRepository(s.testDb.BunDb)
+	svc := services.NewItemService(repo)
+	createHandler := handlers.New(
+		mappers.NewItemCreateRequestMapper(),
+		mappers.NewItemCreateResponseMapper(),
+		svc.Create,
+	)
+	updateHandler := handlers.New(
+		mappers.NewItemUpdateRequestMapper(),
+		mappers.NewItemGetByIdResponseMapper(),
+		svc.Update,
+	)
+	deleteHandler := handlers.New(
+		mappers.NewItemDeleteRequestMapper(),
+		mappers.NewItemDeleteResponseMapper(),
+		svc.DeleteById,
+	)
+
+	s.Run("admin should create, update and delete item", func() {
+		// given
+		body := `{"name":"New Book","Description":"A brand new book","Price":{"amount":"19.99","currency":"EUR"},"stock":3}`
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req = authenticateAdmin(req)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err := createHandler.Handle(c)
+
+		// then
+		s.NoError(err)
+		s.Equal(http.StatusCreated, resp.Code)
+		created := new(dtos.ItemDto)
+		s.NoError(json.NewDecoder(resp.Body).Decode(created))
+		s.NotEmpty(created.ID)
+		s.Equal(entities.NewMoney(1999, entities.EUR), created.Price)
+
+		// given
+		body = `{"name":"Renamed Book","Price":{"amount":"9.99","currency":"EUR"},"stock":7}`
+		req = httptest.NewRequest(http.MethodPut, "/", strings.NewReader(body))
+		req.Header.Set("If-Match", resp.Header().Get("ETag"))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req = authenticateAdmin(req)
+		resp = httptest.NewRecorder()
+		c = e.NewContext(req, resp)
+		c.SetPath("/item/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(created.ID)
+
+		// when
+		err = updateHandler.Handle(c)
+
+		// then
+		s.NoError(err)
+		s.Equal(http.StatusOK, resp.Code)
+		updated := new(dtos.ItemDto)
+		s.NoError(json.NewDecoder(resp.Body).Decode(updated))
+		s.Equal(created.ID, updated.ID)
+		s.Equal("Renamed Book", updated.Title)
+		s.Equal(7, updated.Stock)
+
+		// given
+		req = httptest.NewRequest(http.MethodDelete, "/", nil)
+		req.Header.Set("If-Match", resp.Header().Get("ETag"))
+		req = authenticateAdmin(req)
+		resp = httptest.NewRecorder()
+		c = e.NewContext(req, resp)
+		c.SetPath("/item/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(created.ID)
+
+		// when
+		err = deleteHandler.Handle(c)
+
+		// then
+		s.NoError(err)
+		s.Equal(http.StatusNoContent, resp.Code)
+	})
+
+	s.Run("admin should create book with its details", func() {
+		// given
+		body := `{"name":"New Novel","Price":{"amount":"19.99","currency":"EUR"},"isbn":"0-8044-2957-X",` +
+			`"publisher_id":"260cea28-b2b0-4051-9eb6-9a99e451af02","edition":"1st","publication_date":"2024-03-01",` +
+			`"page_count":412,"language":"en-GB","author_ids":["250cea28-b2b0-4051-9eb6-9a99e451af02"]}`
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req = authenticateAdmin(req)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err := createHandler.Handle(c)
+
+		// then
+		s.NoError(err)
+		s.Equal(http.StatusCreated, resp.Code)
+		created := new(dtos.ItemDto)
+		s.NoError(json.NewDecoder(resp.Body).Decode(created))
+		s.Equal("9780804429573", created.ISBN)
+		s.Equal("2024-03-01", created.PublicationDate)
+		s.Equal(412, created.PageCount)
+		s.Equal("Small Press", created.Publisher.Name)
+		s.Equal([]string{"250cea28-b2b0-4051-9eb6-9a99e451af02"}, created.AuthorIDs)
+		s.Equal("Joe Coauthor", created.Authors[0].Name)
+
+		// given
+		req = httptest.NewRequest(http.MethodDelete, "/", nil)
+		req.Header.Set("If-Match", resp.Header().Get("ETag"))
+		req = authenticateAdmin(req)
+		resp = httptest.NewRecorder()
+		c = e.NewContext(req, resp)
+		c.SetPath("/item/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(created.ID)
+
+		// then
+		s.NoError(deleteHandler.Handle(c))
+	})
+
+	s.Run("should return 400 when book details are invalid", func() {
+		// given
+		body := `{"name":"Bad Book","Price":{"amount":"1.99","currency":"EUR"},"isbn":"978-0-306-40615-8","publication_date":"01.03.2024","language":"english!"}`
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req = authenticateAdmin(req)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err := createHandler.Handle(c)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	})
+
+	s.Run("should return 409 when isbn is taken", func() {
+		// given
+		body := `{"name":"Copy","Price":{"amount":"1.99","currency":"EUR"},"isbn":"978-0-306-40615-7"}`
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req = authenticateAdmin(req)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err := createHandler.Handle(c)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusConflict, err.(*echo.HTTPError).Code)
+	})
+
+	s.Run("should return 400 when price is not positive", func() {
+		// given
+		body := `{"name":"Free Book","Price":{"amount":"0","currency":"EUR"}}`
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req = authenticateAdmin(req)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err := createHandler.Handle(c)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	})
+
+	s.Run("should return 400 when title is empty", func() {
+		// given
+		body := `{"Price":{"amount":"5.00","currency":"EUR"}}`
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req = authenticateAdmin(req)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err := createHandler.Handle(c)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	})
+
+	s.Run("should return 403 when caller is not admin", func() {
+		// given
+		body := `{"name":"New Book","Price":{"amount":"19.99","currency":"EUR"}}`
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req = authenticate(req, "220cea28-b2b0-4051-9eb6-9a99e451af01")
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err := createHandler.Handle(c)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusForbidden, err.(*echo.HTTPError).Code)
+	})
+
+	s.Run("should return 409 when deleting item which is part of an order", func() {
+		// given
+		req := httptest.NewRequest(http.MethodDelete, "/", nil)
+		req.Header.Set("If-Match", s.etagOf((*entities.Item)(nil), "200cea28-b2b0-4051-9eb6-9a99e451af01"))
+		req = authenticateAdmin(req)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+		c.SetPath("/item/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("200cea28-b2b0-4051-9eb6-9a99e451af01")
+
+		// when
+		err := deleteHandler.Handle(c)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusConflict, err.(*echo.HTTPError).Code)
+	})
+}
@@ -19,7 +19,7 @@ func (s *HandlersTestSuite) TestHandleCreateOrder() {
 	e.Validator = validators.New()
 
 	repo := repositories.NewOrderRepository(s.testDb.BunDb)
-	svc := services.NewOrderService(repo)
+	svc := services.NewOrderService(repo, services.NewUnitOfWork(repositories.NewTxManager(s.testDb.BunDb)))
 	handler := handlers.New(
 		mappers.NewOrderCreateRequestMapper(),
 		mappers.NewOrderCreateResponseMapper(),
@@ -43,6 +43,7 @@ func (s *HandlersTestSuite) TestHandleCreateOrder() {
 		}
 		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(b))
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req = authenticate(req, cmd.AccountID)
 
 		resp := httptest.NewRecorder()
 		c := e.NewContext(req, resp)
@@ -61,7 +62,7 @@ func (s *HandlersTestSuite) TestHandleCreateOrder() {
 		s.NotEmpty(answer.Items)
 	})
 
-	s.Run("should return 500 when non-existing account id", func() {
+	s.Run("should return 404 when non-existing account id", func() {
 		// given
 		cmd := &dtos.CreateOrderCommand{
 			AccountID: "220cea28-b2b0-4051-9eb6-9a99e451af11",
@@ -78,6 +79,7 @@ func (s *HandlersTestSuite) TestHandleCreateOrder() {
 		}
 		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(b))
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req = authenticate(req, cmd.AccountID)
 
 		resp := httptest.NewRecorder()
 		c := e.NewContext(req, resp)
@@ -85,10 +87,38 @@ func (s *HandlersTestSuite) TestHandleCreateOrder() {
 		// when
 		err = handler.Handle(c)
 		s.NotNil(err)
-		s.Equal(http.StatusInternalServerError, err.(*echo.HTTPError).Code)
+		s.Equal(http.StatusNotFound, err.(*echo.HTTPError).Code)
 	})
 
-	s.Run("should return 500 when non-existing item id", func() {
+	s.Run("should return 409 when item is out of stock", func() {
+		// given
+		cmd := &dtos.CreateOrderCommand{
+			AccountID: "220cea28-b2b0-4051-9eb6-9a99e451af01",
+			Items: []dtos.OrderItemDto{
+				{
+					ItemID:   "200cea28-b2b0-4051-9eb6-9a99e451af02",
+					Quantity: 1000,
+				},
+			},
+		}
+		b, err := json.Marshal(cmd)
+		if err != nil {
+			s.Error(err)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(b))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req = authenticate(req, cmd.AccountID)
+
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err = handler.Handle(c)
+		s.NotNil(err)
+		s.Equal(http.StatusConflict, err.(*echo.HTTPError).Code)
+	})
+
+	s.Run("should return 404 when non-existing item id", func() {
 		// given
 		cmd := &dtos.CreateOrderCommand{
 			AccountID: "220cea28-b2b0-4051-9eb6-9a99e451af01",
@@ -105,6 +135,7 @@ func (s *HandlersTestSuite) TestHandleCreateOrder() {
 		}
 		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(b))
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req = authenticate(req, cmd.AccountID)
 
 		resp := httptest.NewRecorder()
 		c := e.NewContext(req, resp)
@@ -112,7 +143,7 @@ func (s *HandlersTestSuite) TestHandleCreateOrder() {
 		// when
 		err = handler.Handle(c)
 		s.NotNil(err)
-		s.Equal(http.StatusInternalServerError, err.(*echo.HTTPError).Code)
+		s.Equal(http.StatusNotFound, err.(*echo.HTTPError).Code)
 	})
 }
 
@@ -121,7 +152,7 @@ func (s *HandlersTestSuite) TestHandleGetOrderById() {
 	e.Validator = validators.New()
 
 	repo := repositories.NewOrderRepository(s.testDb.BunDb)
-	svc := services.NewOrderService(repo)
+	svc := services.NewOrderService(repo, services.NewUnitOfWork(repositories.NewTxManager(s.testDb.BunDb)))
 	handler := handlers.New(
 		mappers.NewOrderGetByIdRequestMapper(),
 		mappers.NewOrderGetByIdResponseMapper(),
@@ -133,6 +164,7 @@ func (s *HandlersTestSuite) TestHandleGetOrderById() {
 
 		req := httptest.NewRequest(http.MethodGet, "/", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req = authenticate(req, "220cea28-b2b0-4051-9eb6-9a99e451af01")
 
 		resp := httptest.NewRecorder()
 		c := e.NewContext(req, resp)
@@ -175,7 +207,7 @@ func (s *HandlersTestSuite) TestHandleGetOrderById() {
 		s.Equal(http.StatusBadRequest, err.(*echo.HTTPError).Code)
 	})
 
-	s.Run("should return 500 when non-existing id", func() {
+	s.Run("should return 404 when non-existing id", func() {
 		orderId := "210cea28-b2b0-4051-9eb6-9a99e451af11"
 
 		req := httptest.NewRequest(http.MethodGet, "/", nil)
@@ -192,6 +224,155 @@ func (s *HandlersTestSuite) TestHandleGetOrderById() {
 
 		// then
 		s.NotNil(err)
-		s.Equal(http.StatusInternalServerError, err.(*echo.HTTPError).Code)
+		s.Equal(http.StatusNotFound, err.(*echo.HTTPError).Code)
+	})
+}
+
+func (s *HandlersTestSuite) TestHandleCancelOrder() {
+	e := echo.New()
+	e.Validator = validators.New()
+
+	repo := repositories.NewOrderRepository(s.testDb.BunDb)
+	svc := services.NewOrderService(repo, services.NewUnitOfWork(repositories.NewTxManager(s.testDb.BunDb)))
+	handler := handlers.New(
+		mappers.NewOrderCancelRequestMapper(),
+		mappers.NewOrderGetByIdResponseMapper(),
+		svc.Cancel,
+	)
+	orderId := "210cea28-b2b0-4051-9eb6-9a99e451af03"
+	accountId := "220cea28-b2b0-4051-9eb6-9a99e451af03"
+
+	newContext := func(body string, resp *httptest.ResponseRecorder) echo.Context {
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req = authenticate(req, accountId)
+		c := e.NewContext(req, resp)
+		c.SetPath("/order/:id/cancel")
+		c.SetParamNames("id")
+		c.SetParamValues(orderId)
+		return c
+	}
+
+	s.Run("should return 400 without reason", func() {
+		// when
+		err := handler.Handle(newContext(`{}`, httptest.NewRecorder()))
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	})
+
+	s.Run("should cancel own order and refuse cancelling it again", func() {
+		// given
+		resp := httptest.NewRecorder()
+
+		// when
+		err := handler.Handle(newContext(`{"reason":"ordered by mistake"}`, resp))
+
+		// then
+		s.Require().Nil(err)
+		s.Equal(http.StatusOK, resp.Code)
+		dto := new(dtos.OrderDto)
+		s.Nil(json.NewDecoder(resp.Body).Decode(dto))
+		s.Equal("cancelled", dto.Status)
+		s.Equal("ordered by mistake", dto.CancellationReason)
+
+		// when
+		err = handler.Handle(newContext(`{"reason":"again"}`, httptest.NewRecorder()))
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusConflict, err.(*echo.HTTPError).Code)
+	})
+}
+
+func (s *HandlersTestSuite) TestHandleRefundOrder() {
+	e := echo.New()
+	e.Validator = validators.New()
+
+	repo := repositories.NewOrderRepository(s.testDb.BunDb)
+	svc := services.NewOrderService(repo, services.NewUnitOfWork(repositories.NewTxManager(s.testDb.BunDb)))
+	transitionHandler := handlers.New(
+		mappers.NewOrderTransitionRequestMapper(),
+		mappers.NewOrderTransitionResponseMapper(),
+		svc.Transition,
+	)
+	refundHandler := handlers.New(
+		mappers.NewOrderRefundRequestMapper(),
+		mappers.NewOrderRefundResponseMapper(),
+		svc.Refund,
+	)
+	getRefundsHandler := handlers.New(
+		mappers.NewOrderGetByIdRequestMapper(),
+		mappers.NewOrderGetRefundsResponseMapper(),
+		svc.GetRefunds,
+	)
+	orderId := "210cea28-b2b0-4051-9eb6-9a99e451af02"
+	orderItemId := "230cea28-b2b0-4051-9eb6-9a99e451af03"
+
+	newContext := func(req *http.Request, resp *httptest.ResponseRecorder) echo.Context {
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		c := e.NewContext(req, resp)
+		c.SetParamNames("id")
+		c.SetParamValues(orderId)
+		return c
+	}
+
+	s.Run("should refund order line partially and list refunds", func() {
+		// given
+		req := authenticateAdmin(httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"status":"paid"}`)))
+		s.Require().Nil(transitionHandler.Handle(newContext(req, httptest.NewRecorder())))
+
+		body := `{"reason":"one copy damaged","items":[{"order_item_id":"` + orderItemId + `","quantity":1}]}`
+		req = authenticateAdmin(httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body)))
+		resp := httptest.NewRecorder()
+
+		// when
+		err := refundHandler.Handle(newContext(req, resp))
+
+		// then
+		s.Require().Nil(err)
+		s.Equal(http.StatusCreated, resp.Code)
+		refund := new(dtos.RefundDto)
+		s.Nil(json.NewDecoder(resp.Body).Decode(refund))
+		s.Equal("6.99", refund.Amount.Decimal())
+		s.Len(refund.Items, 1)
+
+		// when
+		req = authenticate(httptest.NewRequest(http.MethodGet, "/", nil), "220cea28-b2b0-4051-9eb6-9a99e451af02")
+		resp = httptest.NewRecorder()
+		err = getRefundsHandler.Handle(newContext(req, resp))
+
+		// then
+		s.Require().Nil(err)
+		var refunds []dtos.RefundDto
+		s.Nil(json.NewDecoder(resp.Body).Decode(&refunds))
+		s.Len(refunds, 1)
+		s.Equal("one copy damaged", refunds[0].Reason)
+	})
+
+	s.Run("should return 400 when refunding more than was ordered", func() {
+		// given
+		body := `{"items":[{"order_item_id":"` + orderItemId + `","quantity":2}]}`
+		req := authenticateAdmin(httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body)))
+
+		// when
+		err := refundHandler.Handle(newContext(req, httptest.NewRecorder()))
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	})
+
+	s.Run("should return 403 when customer refunds order", func() {
+		// given
+		req := authenticate(httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{}`)), "220cea28-b2b0-4051-9eb6-9a99e451af02")
+
+		// when
+		err := refundHandler.Handle(newContext(req, httptest.NewRecorder()))
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusForbidden, err.(*echo.HTTPError).Code)
 	})
 }
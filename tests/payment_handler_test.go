@@ -0,0 +1,177 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/fmiskovic/new-amz/internal/core/dtos"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/core/services"
+	"github.com/fmiskovic/new-amz/internal/handlers"
+	"github.com/fmiskovic/new-amz/internal/handlers/mappers"
+	"github.com/fmiskovic/new-amz/internal/payments"
+	"github.com/fmiskovic/new-amz/internal/repositories"
+	"github.com/fmiskovic/new-amz/internal/signature"
+	"github.com/fmiskovic/new-amz/internal/validators"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"time"
+)
+
+func (s *HandlersTestSuite) TestHandlePayments() {
+	e := echo.New()
+	e.Validator = validators.New()
+
+	orderRepo := repositories.NewOrderRepository(s.testDb.BunDb)
+	svc := services.NewPaymentService(repositories.NewPaymentRepository(s.testDb.BunDb), orderRepo, payments.NewFakeGateway())
+	authorizeHandler := handlers.New(
+		mappers.NewPaymentAuthorizeRequestMapper(),
+		mappers.NewPaymentAuthorizeResponseMapper(),
+		svc.Authorize,
+	)
+	captureHandler := handlers.New(
+		mappers.NewPaymentAmountRequestMapper(),
+		mappers.NewPaymentResponseMapper(),
+		svc.Capture,
+	)
+	webhookHandler := handlers.New(
+		mappers.NewPaymentEventRequestMapper(),
+		mappers.NewPaymentResponseMapper(),
+		svc.HandleEvent,
+	)
+	secret := []byte("secret")
+	webhook := payments.VerifySignature(secret)(webhookHandler.Handle)
+	accountId := "220cea28-b2b0-4051-9eb6-9a99e451af01"
+
+	placeOrder := func() string {
+		order := entities.NewOrderBuilder().
+			AccountID(uuid.MustParse(accountId)).
+			OrderItems([]*entities.OrderItem{
+				entities.NewOrderItemBuilder().ItemID(uuid.MustParse("200cea28-b2b0-4051-9eb6-9a99e451af01")).Quantity(1).Build(),
+			}).
+			Build()
+		s.Require().Nil(orderRepo.Create(s.testDb.Ctx, order))
+		return order.ID.String()
+	}
+	newContext := func(req *http.Request, resp *httptest.ResponseRecorder, id string) echo.Context {
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		c := e.NewContext(req, resp)
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		return c
+	}
+	authorizePayment := func(orderId, token string) (*dtos.PaymentDto, error) {
+		body := `{"token":"` + token + `"}`
+		req := authenticate(httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body)), accountId)
+		resp := httptest.NewRecorder()
+		if err := authorizeHandler.Handle(newContext(req, resp, orderId)); err != nil {
+			return nil, err
+		}
+		s.Equal(http.StatusCreated, resp.Code)
+		payment := new(dtos.PaymentDto)
+		s.Require().Nil(json.NewDecoder(resp.Body).Decode(payment))
+		return payment, nil
+	}
+	orderStatus := func(orderId string) entities.OrderStatus {
+		order, err := orderRepo.GetById(s.testDb.Ctx, uuid.MustParse(orderId))
+		s.Require().Nil(err)
+		return order.Status
+	}
+
+	s.Run("should authorize payment and capture it into paid order", func() {
+		// given
+		orderId := placeOrder()
+		payment, err := authorizePayment(orderId, "tok_visa")
+		s.Require().Nil(err)
+		s.Equal("authorized", payment.Status)
+		s.Equal("7.50", payment.Amount.Decimal())
+
+		// when authorizing again
+		_, err = authorizePayment(orderId, "tok_visa")
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusConflict, err.(*echo.HTTPError).Code)
+
+		// when
+		req := authenticateAdmin(httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{}`)))
+		resp := httptest.NewRecorder()
+		err = captureHandler.Handle(newContext(req, resp, payment.ID))
+
+		// then
+		s.Require().Nil(err)
+		s.Equal(http.StatusOK, resp.Code)
+		captured := new(dtos.PaymentDto)
+		s.Nil(json.NewDecoder(resp.Body).Decode(captured))
+		s.Equal("captured", captured.Status)
+		s.Equal("7.50", captured.Captured.Decimal())
+		s.Equal(entities.ORDER_PAID, orderStatus(orderId))
+	})
+
+	s.Run("should return 409 and record failed payment when payment is declined", func() {
+		// given
+		orderId := placeOrder()
+
+		// when
+		_, err := authorizePayment(orderId, payments.FakeTokenDeclined)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusConflict, err.(*echo.HTTPError).Code)
+		s.Equal(entities.ORDER_PENDING, orderStatus(orderId))
+	})
+
+	s.Run("should return 403 when customer captures payment", func() {
+		// given
+		payment, err := authorizePayment(placeOrder(), "tok_visa")
+		s.Require().Nil(err)
+		req := authenticate(httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{}`)), accountId)
+
+		// when
+		err = captureHandler.Handle(newContext(req, httptest.NewRecorder(), payment.ID))
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusForbidden, err.(*echo.HTTPError).Code)
+	})
+
+	s.Run("should apply signed webhook event once and reject unsigned one", func() {
+		// given
+		orderId := placeOrder()
+		payment, err := authorizePayment(orderId, "tok_visa")
+		s.Require().Nil(err)
+		body := []byte(`{"id":"evt_1","type":"payment.captured","reference":"` + payment.Reference + `"}`)
+		signed := func() *http.Request {
+			now := time.Now()
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+			req.Header.Set(payments.HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+			req.Header.Set(payments.HeaderSignature, signature.Sign(secret, now, body))
+			return req
+		}
+
+		// when unsigned
+		err = webhook(newContext(httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body)), httptest.NewRecorder(), ""))
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusUnauthorized, err.(*echo.HTTPError).Code)
+		s.Equal(entities.ORDER_PENDING, orderStatus(orderId))
+
+		// when
+		resp := httptest.NewRecorder()
+		err = webhook(newContext(signed(), resp, ""))
+		// then
+		s.Require().Nil(err)
+		s.Equal(http.StatusOK, resp.Code)
+		s.Equal(entities.ORDER_PAID, orderStatus(orderId))
+
+		// when delivered again
+		resp = httptest.NewRecorder()
+		err = webhook(newContext(signed(), resp, ""))
+		// then
+		s.Require().Nil(err)
+		captured := new(dtos.PaymentDto)
+		s.Nil(json.NewDecoder(resp.Body).Decode(captured))
+		s.Equal("captured", captured.Status)
+	})
+}
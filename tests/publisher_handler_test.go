@@ -0,0 +1,115 @@
+package tests
+
+import (
+	"encoding/json"
+	"github.com/fmiskovic/new-amz/internal/core/dtos"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/core/services"
+	"github.com/fmiskovic/new-amz/internal/handlers"
+	"github.com/fmiskovic/new-amz/internal/handlers/mappers"
+	"github.com/fmiskovic/new-amz/internal/repositories"
+	"github.com/fmiskovic/new-amz/internal/validators"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+func (s *HandlersTestSuite) TestHandleGetPublishersPage() {
+	e := echo.New()
+	e.Validator = validators.New()
+
+	repo := repositories.NewPublisherRepository(s.testDb.BunDb)
+	svc := services.NewPublisherService(repo)
+	handler := handlers.New(
+		mappers.NewPublisherGetPageRequestMapper(),
+		mappers.NewPublisherGetPageResponseMapper(),
+		svc.GetPage,
+	)
+
+	s.Run("should return page of publishers", func() {
+		// given
+		req := httptest.NewRequest(http.MethodGet, "/?sort=name", nil)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err := handler.Handle(c)
+
+		// then
+		s.NoError(err)
+		s.Equal(http.StatusOK, resp.Code)
+		page := new(entities.Page[dtos.PublisherDto])
+		s.NoError(json.NewDecoder(resp.Body).Decode(page))
+		s.Equal(2, *page.TotalElements)
+		s.Equal("Cool Press", page.Elements[0].Name)
+	})
+
+	s.Run("should return 400 when sorted by unknown property", func() {
+		// given
+		req := httptest.NewRequest(http.MethodGet, "/?sort=price", nil)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err := handler.Handle(c)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	})
+}
+
+func (s *HandlersTestSuite) TestHandleManagePublisher() {
+	e := echo.New()
+	e.Validator = validators.New()
+
+	repo := repositories.NewPublisherRepository(s.testDb.BunDb)
+	svc := services.NewPublisherService(repo)
+	createHandler := handlers.New(
+		mappers.NewPublisherCreateRequestMapper(),
+		mappers.NewPublisherCreateResponseMapper(),
+		svc.Create,
+	)
+	deleteHandler := handlers.New(
+		mappers.NewPublisherDeleteRequestMapper(),
+		mappers.NewPublisherDeleteResponseMapper(),
+		svc.DeleteById,
+	)
+
+	s.Run("should return 409 when name is taken", func() {
+		// given
+		body := `{"name":"cool press"}`
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req = authenticateAdmin(req)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+
+		// when
+		err := createHandler.Handle(c)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusConflict, err.(*echo.HTTPError).Code)
+	})
+
+	s.Run("should return 409 when deleting publisher of items", func() {
+		// given
+		req := httptest.NewRequest(http.MethodDelete, "/", nil)
+		req.Header.Set("If-Match", s.etagOf((*entities.Publisher)(nil), "260cea28-b2b0-4051-9eb6-9a99e451af01"))
+		req = authenticateAdmin(req)
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+		c.SetPath("/publisher/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("260cea28-b2b0-4051-9eb6-9a99e451af01")
+
+		// when
+		err := deleteHandler.Handle(c)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusConflict, err.(*echo.HTTPError).Code)
+	})
+}
@@ -1,8 +1,13 @@
 package tests
 
 import (
+	"fmt"
+	"github.com/fmiskovic/new-amz/internal/core"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
 	"github.com/fmiskovic/new-amz/internal/testcontainers"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/suite"
+	"net/http"
 	"testing"
 )
 
@@ -29,3 +34,26 @@ func TestHandlersSuite(t *testing.T) {
 	}
 	suite.Run(t, new(HandlersTestSuite))
 }
+
+// authenticate attaches the principal of the given account to the request context, as the auth middleware does.
+func authenticate(req *http.Request, accountId string) *http.Request {
+	ctx := core.WithPrincipal(req.Context(), core.Principal{AccountID: uuid.MustParse(accountId)})
+	return req.WithContext(ctx)
+}
+
+// authenticateAdmin sets the fixture administrator as the principal of the request.
+func authenticateAdmin(req *http.Request) *http.Request {
+	ctx := core.WithPrincipal(req.Context(), core.Principal{
+		AccountID: uuid.MustParse("220cea28-b2b0-4051-9eb6-9a99e451af04"),
+		Role:      entities.ADMIN,
+	})
+	return req.WithContext(ctx)
+}
+
+// etagOf returns the ETag of the current version of the stored entity, as its If-Match header.
+func (s *HandlersTestSuite) etagOf(model any, id string) string {
+	var version int64
+	err := s.testDb.BunDb.NewSelect().Model(model).Column("version").Where("id = ?", id).Scan(s.testDb.Ctx, &version)
+	s.Require().NoError(err)
+	return fmt.Sprintf(`"%d"`, version)
+}
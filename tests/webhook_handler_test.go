@@ -0,0 +1,204 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	"github.com/fmiskovic/new-amz/internal/core/dtos"
+	"github.com/fmiskovic/new-amz/internal/core/entities"
+	"github.com/fmiskovic/new-amz/internal/core/services"
+	"github.com/fmiskovic/new-amz/internal/handlers"
+	"github.com/fmiskovic/new-amz/internal/handlers/mappers"
+	"github.com/fmiskovic/new-amz/internal/repositories"
+	"github.com/fmiskovic/new-amz/internal/signature"
+	"github.com/fmiskovic/new-amz/internal/validators"
+	"github.com/fmiskovic/new-amz/internal/webhooks"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *HandlersTestSuite) TestHandleWebhooks() {
+	e := echo.New()
+	e.Validator = validators.New()
+
+	svc := services.NewWebhookService(
+		repositories.NewWebhookSubscriptionRepository(s.testDb.BunDb),
+		repositories.NewWebhookDeliveryRepository(s.testDb.BunDb),
+		webhooks.NewHTTPSender(time.Second),
+	)
+	createHandler := handlers.New(
+		mappers.NewWebhookCreateRequestMapper(),
+		mappers.NewWebhookCreateResponseMapper(),
+		svc.Create,
+	)
+	deliveriesHandler := handlers.New(
+		mappers.NewWebhookDeliveriesRequestMapper(),
+		mappers.NewWebhookDeliveriesResponseMapper(),
+		svc.GetDeliveries,
+	)
+	redeliverHandler := handlers.New(
+		mappers.NewWebhookRedeliverRequestMapper(),
+		mappers.NewWebhookDeliveryResponseMapper(),
+		svc.Redeliver,
+	)
+
+	// receiver records signed requests and answers with the configured status code
+	var status atomic.Int32
+	var received atomic.Int32
+	var sig, eventType string
+	var body []byte
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		sig = r.Header.Get(webhooks.HeaderSignature)
+		err := signature.Verify([]byte("whsec_0123456789abcdef"), r.Header.Get(webhooks.HeaderTimestamp), sig, body, time.Now())
+		if err != nil {
+			sig = ""
+		}
+		eventType = r.Header.Get("X-Event-Type")
+		received.Add(1)
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer receiver.Close()
+
+	createSubscription := func(req *http.Request) (*dtos.WebhookSubscriptionDto, error) {
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		resp := httptest.NewRecorder()
+		if err := createHandler.Handle(e.NewContext(req, resp)); err != nil {
+			return nil, err
+		}
+		s.Equal(http.StatusCreated, resp.Code)
+		subscription := new(dtos.WebhookSubscriptionDto)
+		s.Require().Nil(json.NewDecoder(resp.Body).Decode(subscription))
+		return subscription, nil
+	}
+	subscriptionBody := `{"url":"` + receiver.URL + `","event_types":["order.status_changed"],"secret":"whsec_0123456789abcdef"}`
+	publish := func() entities.Event {
+		event, err := entities.NewEvent(entities.EVENT_ORDER_STATUS_CHANGED, uuid.New(), map[string]string{"status": "shipped"})
+		s.Require().Nil(err)
+		s.Require().Nil(svc.Publish(s.testDb.Ctx, *event))
+		_, err = svc.Deliver(s.testDb.Ctx, 100)
+		s.Require().Nil(err)
+		return *event
+	}
+	getDeliveries := func(subscriptionId, status string) entities.Page[dtos.WebhookDeliveryDto] {
+		req := authenticateAdmin(httptest.NewRequest(http.MethodGet, "/?status="+status, nil))
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+		c.SetParamNames("id")
+		c.SetParamValues(subscriptionId)
+		s.Require().Nil(deliveriesHandler.Handle(c))
+		s.Equal(http.StatusOK, resp.Code)
+		var page entities.Page[dtos.WebhookDeliveryDto]
+		s.Require().Nil(json.NewDecoder(resp.Body).Decode(&page))
+		return page
+	}
+
+	s.Run("should deliver signed event to subscribed webhook and log the delivery", func() {
+		// given
+		status.Store(http.StatusOK)
+		subscription, err := createSubscription(authenticateAdmin(httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(subscriptionBody))))
+		s.Require().Nil(err)
+		s.Equal("whsec_0123456789abcdef", subscription.Secret)
+
+		// when
+		event := publish()
+
+		// then
+		s.NotEmpty(sig)
+		s.Equal("order.status_changed", eventType)
+		received := new(entities.Event)
+		s.Nil(json.Unmarshal(body, received))
+		s.Equal(event.ID, received.ID)
+
+		page := getDeliveries(subscription.ID, "delivered")
+		s.Require().Len(page.Elements, 1)
+		s.Equal(event.ID.String(), page.Elements[0].EventID)
+		s.Equal(http.StatusOK, page.Elements[0].LastStatusCode)
+		s.NotNil(page.Elements[0].DeliveredAt)
+	})
+
+	s.Run("should retry failed delivery later and redeliver it on demand", func() {
+		// given
+		status.Store(http.StatusServiceUnavailable)
+		subscription, err := createSubscription(authenticateAdmin(httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(subscriptionBody))))
+		s.Require().Nil(err)
+		publish()
+		page := getDeliveries(subscription.ID, "pending")
+		s.Require().Len(page.Elements, 1)
+		delivery := page.Elements[0]
+		s.Equal(1, delivery.Attempts)
+		s.Equal(http.StatusServiceUnavailable, delivery.LastStatusCode)
+		s.Require().NotNil(delivery.NextAttemptAt)
+		s.True(delivery.NextAttemptAt.After(time.Now()))
+
+		// when delivering again, the delivery is not due yet
+		before := received.Load()
+		_, err = svc.Deliver(s.testDb.Ctx, 100)
+		// then
+		s.Nil(err)
+		s.Equal(before, received.Load())
+
+		// when
+		status.Store(http.StatusNoContent)
+		req := authenticateAdmin(httptest.NewRequest(http.MethodPost, "/", nil))
+		resp := httptest.NewRecorder()
+		c := e.NewContext(req, resp)
+		c.SetParamNames("id", "deliveryId")
+		c.SetParamValues(subscription.ID, delivery.ID)
+		err = redeliverHandler.Handle(c)
+
+		// then
+		s.Require().Nil(err)
+		s.Equal(http.StatusOK, resp.Code)
+		redelivered := new(dtos.WebhookDeliveryDto)
+		s.Nil(json.NewDecoder(resp.Body).Decode(redelivered))
+		s.Equal("delivered", redelivered.Status)
+		s.Equal(2, redelivered.Attempts)
+		s.Len(getDeliveries(subscription.ID, "pending").Elements, 0)
+	})
+
+	s.Run("should return 404 when delivery belongs to another subscription", func() {
+		// given
+		status.Store(http.StatusOK)
+		subscription, err := createSubscription(authenticateAdmin(httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(subscriptionBody))))
+		s.Require().Nil(err)
+		publish()
+		delivery := getDeliveries(subscription.ID, "").Elements[0]
+
+		// when
+		req := authenticateAdmin(httptest.NewRequest(http.MethodPost, "/", nil))
+		c := e.NewContext(req, httptest.NewRecorder())
+		c.SetParamNames("id", "deliveryId")
+		c.SetParamValues(uuid.NewString(), delivery.ID)
+		err = redeliverHandler.Handle(c)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusNotFound, err.(*echo.HTTPError).Code)
+	})
+
+	s.Run("should return 400 when subscription is invalid", func() {
+		// when
+		body := `{"url":"not a url","event_types":["account.deleted"]}`
+		_, err := createSubscription(authenticateAdmin(httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body))))
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	})
+
+	s.Run("should return 403 when customer manages webhooks", func() {
+		// when
+		req := authenticate(httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(subscriptionBody)), "220cea28-b2b0-4051-9eb6-9a99e451af01")
+		_, err := createSubscription(req)
+
+		// then
+		s.NotNil(err)
+		s.Equal(http.StatusForbidden, err.(*echo.HTTPError).Code)
+	})
+}